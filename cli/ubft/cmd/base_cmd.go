@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -22,6 +23,7 @@ type (
 	UnicityBFTApp struct {
 		baseCmd    *cobra.Command
 		baseConfig *baseFlags
+		obsF       Factory
 	}
 
 	Factory interface {
@@ -40,7 +42,7 @@ type (
 
 func New(obsF Factory, opts ...interface{}) *UnicityBFTApp {
 	baseCmd, baseConfig := newBaseCmd(obsF)
-	app := &UnicityBFTApp{baseCmd: baseCmd, baseConfig: baseConfig}
+	app := &UnicityBFTApp{baseCmd: baseCmd, baseConfig: baseConfig, obsF: obsF}
 	app.AddSubcommands(opts)
 	app.addPartition(NewMoneyPartition())
 	app.addPartition(NewTokensPartition())
@@ -66,6 +68,27 @@ func (a *UnicityBFTApp) AddSubcommands(opts []interface{}) {
 	a.baseCmd.AddCommand(newShardNodeCmd(a.baseConfig, convertOptsToRunnable(opts)))
 	a.baseCmd.AddCommand(newShardConfCmd(a.baseConfig))
 	a.baseCmd.AddCommand(newNodeIDCmd(a.baseConfig))
+	a.baseCmd.AddCommand(newKeysCmd(a.baseConfig))
+	a.baseCmd.AddCommand(newRemoteSignerCmd(a.baseConfig))
+	a.baseCmd.AddCommand(newPredicateCmd(a.baseConfig))
+	a.baseCmd.AddCommand(newSwapCmd(a.baseConfig))
+	a.baseCmd.AddCommand(newTxCmd(a.baseConfig))
+	a.baseCmd.AddCommand(newDecodeCmd(a.baseConfig))
+	a.baseCmd.AddCommand(newVerifyProofCmd(a.baseConfig))
+	a.baseCmd.AddCommand(newDevnetCmd(a.baseConfig, a.newNodeApp(opts)))
+}
+
+/*
+newNodeApp returns function which creates a new instance of the application with
+the same options and registered partitions as "a". Used to run multiple nodes in
+a single process.
+*/
+func (a *UnicityBFTApp) newNodeApp(opts []interface{}) func() *UnicityBFTApp {
+	return func() *UnicityBFTApp {
+		app := New(a.obsF, opts...)
+		maps.Copy(app.baseConfig.partitions, a.baseConfig.partitions)
+		return app
+	}
 }
 
 func (a *UnicityBFTApp) RegisterPartition(partition Partition) error {
@@ -0,0 +1,476 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/unicitynetwork/bft-core/state"
+	"github.com/unicitynetwork/bft-go-base/txsystem/fc"
+	"github.com/unicitynetwork/bft-go-base/txsystem/fc/permissioned"
+	"github.com/unicitynetwork/bft-go-base/txsystem/money"
+	"github.com/unicitynetwork/bft-go-base/txsystem/nop"
+	"github.com/unicitynetwork/bft-go-base/txsystem/orchestration"
+	"github.com/unicitynetwork/bft-go-base/txsystem/tokens"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+// data types supported by the decode command
+const (
+	decodeTypeBlock         = "block"
+	decodeTypeTxOrder       = "tx"
+	decodeTypeTxRecord      = "tx-record"
+	decodeTypeTxProof       = "tx-proof"
+	decodeTypeTxRecordProof = "tx-record-proof"
+	decodeTypeUC            = "uc"
+	decodeTypeUnitProof     = "unit-proof"
+	decodeTypeState         = "state"
+)
+
+type (
+	decodeFlags struct {
+		*baseFlags
+		shardConfFlags
+
+		Type string
+	}
+
+	/*
+		cborDecoder converts CBOR encoded data structures into values which
+		encode into human readable JSON.
+	*/
+	cborDecoder struct {
+		shardConf *types.PartitionDescriptionRecord // optional, used to decode unit data and tx attributes
+	}
+
+	// decodedData is the output of the decode command
+	decodedData struct {
+		Type string `json:"type"`
+		Data any    `json:"data"`
+	}
+
+	decodedState struct {
+		Header      any   `json:"header"`
+		NodeRecords []any `json:"nodeRecords"`
+	}
+
+	// jsonObject is JSON object which preserves the order of the fields
+	jsonObject []jsonField
+
+	jsonField struct {
+		name  string
+		value any
+	}
+)
+
+var decodeTypes = map[string]func() any{
+	decodeTypeBlock:         func() any { return &types.Block{} },
+	decodeTypeTxOrder:       func() any { return &types.TransactionOrder{} },
+	decodeTypeTxRecord:      func() any { return &types.TransactionRecord{} },
+	decodeTypeTxProof:       func() any { return &types.TxProof{} },
+	decodeTypeTxRecordProof: func() any { return &types.TxRecordProof{} },
+	decodeTypeUC:            func() any { return &types.UnicityCertificate{} },
+	decodeTypeUnitProof:     func() any { return &types.UnitStateProof{} },
+}
+
+// data types which are encoded as tagged CBOR
+var decodeTags = map[types.CborTag]string{
+	types.TransactionOrderTag:   decodeTypeTxOrder,
+	types.TransactionRecordTag:  decodeTypeTxRecord,
+	types.TxProofTag:            decodeTypeTxProof,
+	types.UnicityCertificateTag: decodeTypeUC,
+	types.UnitStateProofTag:     decodeTypeUnitProof,
+}
+
+// partition type of the transactions when shard conf is not given
+var defaultPartitionTypes = map[types.PartitionID]types.PartitionTypeID{
+	money.DefaultPartitionID:         money.PartitionTypeID,
+	tokens.DefaultPartitionID:        tokens.PartitionTypeID,
+	orchestration.DefaultPartitionID: orchestration.PartitionTypeID,
+}
+
+var txAttributes = map[types.PartitionTypeID]map[uint16]func() any{
+	money.PartitionTypeID: {
+		money.TransactionTypeTransfer:       func() any { return &money.TransferAttributes{} },
+		money.TransactionTypeSplit:          func() any { return &money.SplitAttributes{} },
+		money.TransactionTypeTransDC:        func() any { return &money.TransferDCAttributes{} },
+		money.TransactionTypeSwapDC:         func() any { return &money.SwapDCAttributes{} },
+		fc.TransactionTypeTransferFeeCredit: func() any { return &fc.TransferFeeCreditAttributes{} },
+		fc.TransactionTypeReclaimFeeCredit:  func() any { return &fc.ReclaimFeeCreditAttributes{} },
+	},
+	tokens.PartitionTypeID: {
+		tokens.TransactionTypeDefineFT:    func() any { return &tokens.DefineFungibleTokenAttributes{} },
+		tokens.TransactionTypeDefineNFT:   func() any { return &tokens.DefineNonFungibleTokenAttributes{} },
+		tokens.TransactionTypeMintFT:      func() any { return &tokens.MintFungibleTokenAttributes{} },
+		tokens.TransactionTypeMintNFT:     func() any { return &tokens.MintNonFungibleTokenAttributes{} },
+		tokens.TransactionTypeTransferFT:  func() any { return &tokens.TransferFungibleTokenAttributes{} },
+		tokens.TransactionTypeTransferNFT: func() any { return &tokens.TransferNonFungibleTokenAttributes{} },
+		tokens.TransactionTypeSplitFT:     func() any { return &tokens.SplitFungibleTokenAttributes{} },
+		tokens.TransactionTypeBurnFT:      func() any { return &tokens.BurnFungibleTokenAttributes{} },
+		tokens.TransactionTypeJoinFT:      func() any { return &tokens.JoinFungibleTokenAttributes{} },
+		tokens.TransactionTypeUpdateNFT:   func() any { return &tokens.UpdateNonFungibleTokenAttributes{} },
+	},
+	orchestration.PartitionTypeID: {
+		orchestration.TransactionTypeAddVAR: func() any { return &orchestration.AddVarAttributes{} },
+	},
+}
+
+// transactions supported by all the partition types
+var commonTxAttributes = map[uint16]func() any{
+	fc.TransactionTypeAddFeeCredit:              func() any { return &fc.AddFeeCreditAttributes{} },
+	fc.TransactionTypeCloseFeeCredit:            func() any { return &fc.CloseFeeCreditAttributes{} },
+	permissioned.TransactionTypeSetFeeCredit:    func() any { return &permissioned.SetFeeCreditAttributes{} },
+	permissioned.TransactionTypeDeleteFeeCredit: func() any { return &permissioned.DeleteFeeCreditAttributes{} },
+	nop.TransactionTypeNOP:                      func() any { return &nop.Attributes{} },
+}
+
+var (
+	typeRawCBOR       = reflect.TypeFor[types.RawCBOR]()
+	typeTxOrder       = reflect.TypeFor[*types.TransactionOrder]()
+	typeNodeRecord    = reflect.TypeFor[*state.NodeRecord]()
+	typeTextMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func newDecodeCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &decodeFlags{baseFlags: baseFlags}
+	var cmd = &cobra.Command{
+		Use:   "decode <data>",
+		Short: "Decodes CBOR encoded data structure and prints it as JSON",
+		Long: `Decodes CBOR encoded data structure (block, transaction order, transaction
+record, transaction proof, unicity certificate, unit state proof or state file)
+and prints it as JSON. Input is hex encoded (0x prefixed) or, when prefixed with
+"@", read from the file, ie "decode @block.cbor". The type of the data is detected
+automatically unless given with the "--type" flag.
+
+Transaction attributes and unit data are decoded according to the partition type
+in the shard conf, when not given default partition IDs are assumed. Byte strings
+are printed as hex, embedded CBOR which can't be decoded as known type is printed
+as generic CBOR data items. Checksum of the state file is verified.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return decode(cmd.OutOrStdout(), flags, args[0])
+		},
+	}
+	cmd.Flags().StringVar(&flags.Type, "type", "", fmt.Sprintf("type of the data, one of %s", strings.Join(decodeTypeNames(), ", ")))
+	flags.addShardConfFlags(cmd)
+	return cmd
+}
+
+func decode(out io.Writer, flags *decodeFlags, input string) error {
+	data, err := decodeHexOrFile(input)
+	if err != nil {
+		return fmt.Errorf("reading input: %w", err)
+	}
+	// files may contain hex encoded data too
+	if bytes.HasPrefix(data, []byte("0x")) {
+		if data, err = hex.Decode(bytes.TrimSpace(data)); err != nil {
+			return fmt.Errorf("decoding hex input: %w", err)
+		}
+	}
+
+	d := &cborDecoder{}
+	if flags.ShardConfFile != "" {
+		if d.shardConf, err = flags.loadShardConf(flags.baseFlags); err != nil {
+			return fmt.Errorf("loading shard conf: %w", err)
+		}
+	}
+
+	dataType := flags.Type
+	if dataType == "" {
+		if dataType, err = d.detectType(data); err != nil {
+			return err
+		}
+	}
+	v, err := d.decode(dataType, data)
+	if err != nil {
+		return fmt.Errorf("decoding %s: %w", dataType, err)
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(decodedData{Type: dataType, Data: v})
+}
+
+func decodeTypeNames() []string {
+	names := append(slices.Collect(maps.Keys(decodeTypes)), decodeTypeState)
+	slices.Sort(names)
+	return names
+}
+
+/*
+detectType returns the type of the CBOR data in "data". Tagged data structures
+are recognised by the tag, the rest by trying to decode them.
+*/
+func (d *cborDecoder) detectType(data []byte) (string, error) {
+	var tag cbor.RawTag
+	if err := types.Cbor.Unmarshal(data, &tag); err == nil {
+		if name, ok := decodeTags[tag.Number]; ok {
+			return name, nil
+		}
+		return "", fmt.Errorf("unsupported CBOR tag %d", tag.Number)
+	}
+
+	for _, name := range []string{decodeTypeBlock, decodeTypeTxRecordProof} {
+		if err := types.Cbor.Unmarshal(data, decodeTypes[name]()); err == nil {
+			return name, nil
+		}
+	}
+	if err := state.DecodeStateFile(bytes.NewReader(data), nil, nil); err == nil {
+		return decodeTypeState, nil
+	}
+	return "", fmt.Errorf("unrecognised data, use the --type flag to see the decoding error")
+}
+
+func (d *cborDecoder) decode(dataType string, data []byte) (any, error) {
+	if dataType == decodeTypeState {
+		return d.decodeState(data)
+	}
+	newValue, ok := decodeTypes[dataType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported data type, expected one of %s", strings.Join(decodeTypeNames(), ", "))
+	}
+	v := newValue()
+	if err := types.Cbor.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	return d.toJSON(reflect.ValueOf(v))
+}
+
+func (d *cborDecoder) decodeState(data []byte) (any, error) {
+	var res decodedState
+	err := state.DecodeStateFile(bytes.NewReader(data),
+		func(h *state.Header) (err error) {
+			res.Header, err = d.toJSON(reflect.ValueOf(h))
+			return err
+		},
+		func(nr *state.NodeRecord) error {
+			v, err := d.toJSON(reflect.ValueOf(nr))
+			if err != nil {
+				return err
+			}
+			res.NodeRecords = append(res.NodeRecords, v)
+			return nil
+		},
+	)
+	return res, err
+}
+
+/*
+toJSON converts "v" into value which encodes into human readable JSON: byte
+strings are hex encoded, order of the struct fields is preserved and embedded
+CBOR is decoded.
+*/
+func (d *cborDecoder) toJSON(v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	switch v.Type() {
+	case typeRawCBOR:
+		return d.rawCBOR(v.Interface().(types.RawCBOR)), nil
+	case typeTxOrder:
+		return d.txOrder(v.Interface().(*types.TransactionOrder))
+	case typeNodeRecord:
+		return d.nodeRecord(v.Interface().(*state.NodeRecord))
+	}
+	if v.Type().Implements(typeTextMarshaler) {
+		s, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(s), err
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return d.toJSON(v.Elem())
+	case reflect.Struct:
+		return d.structToJSON(v, nil)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(hex.Encode(v.Bytes())), nil
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			item, err := d.toJSON(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	case reflect.Map:
+		items := make(map[string]any, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			key, err := d.toJSON(it.Key())
+			if err != nil {
+				return nil, err
+			}
+			if items[fmt.Sprint(key)], err = d.toJSON(it.Value()); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return v.Interface(), nil
+	}
+}
+
+/*
+structToJSON appends exported fields of the struct "v" to "obj", field name is
+taken from the JSON tag when present. Fields of the embedded structs are added
+to the same object.
+*/
+func (d *cborDecoder) structToJSON(v reflect.Value, obj jsonObject) (jsonObject, error) {
+	for i := range v.NumField() {
+		f := v.Type().Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			var err error
+			if obj, err = d.structToJSON(v.Field(i), obj); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		value, err := d.toJSON(v.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		obj = append(obj, jsonField{name: name, value: value})
+	}
+	return obj, nil
+}
+
+/*
+rawCBOR decodes embedded CBOR: tagged data structures of known type are decoded
+into the type, the rest as generic CBOR data items. Hex encoded bytes are
+returned when the data is not valid CBOR.
+*/
+func (d *cborDecoder) rawCBOR(data types.RawCBOR) any {
+	var tag cbor.RawTag
+	if err := types.Cbor.Unmarshal(data, &tag); err == nil {
+		if name, ok := decodeTags[tag.Number]; ok {
+			if v, err := d.decode(name, data); err == nil {
+				return v
+			}
+		}
+	}
+	var v any
+	if err := types.Cbor.Unmarshal(data, &v); err == nil {
+		if res, err := d.toJSON(reflect.ValueOf(v)); err == nil {
+			return res
+		}
+	}
+	return string(hex.Encode(data))
+}
+
+// txOrder converts transaction order into JSON, attributes are decoded according to the transaction type
+func (d *cborDecoder) txOrder(tx *types.TransactionOrder) (any, error) {
+	obj, err := d.structToJSON(reflect.ValueOf(tx).Elem(), nil)
+	if err != nil {
+		return nil, err
+	}
+	newAttr := d.txAttributes(tx.PartitionID, tx.Type)
+	if newAttr == nil {
+		return obj, nil
+	}
+	attr := newAttr()
+	if err := tx.UnmarshalAttributes(attr); err != nil {
+		return nil, fmt.Errorf("decoding attributes of transaction type %d: %w", tx.Type, err)
+	}
+	v, err := d.toJSON(reflect.ValueOf(attr))
+	if err != nil {
+		return nil, err
+	}
+	return obj.set("Attributes", v), nil
+}
+
+// txAttributes returns constructor of the attributes of the transaction type or nil when the type is unknown
+func (d *cborDecoder) txAttributes(partitionID types.PartitionID, txType uint16) func() any {
+	partitionTypeID := defaultPartitionTypes[partitionID]
+	if d.shardConf != nil && d.shardConf.PartitionID == partitionID {
+		partitionTypeID = d.shardConf.PartitionTypeID
+	}
+	if newAttr, ok := txAttributes[partitionTypeID][txType]; ok {
+		return newAttr
+	}
+	return commonTxAttributes[txType]
+}
+
+// nodeRecord converts state file node record into JSON, unit data is decoded when shard conf is known
+func (d *cborDecoder) nodeRecord(nr *state.NodeRecord) (any, error) {
+	obj, err := d.structToJSON(reflect.ValueOf(nr).Elem(), nil)
+	if err != nil || d.shardConf == nil {
+		return obj, err
+	}
+	udc, err := unitDataConstructor(d.shardConf)
+	if err != nil {
+		return nil, err
+	}
+	unitData, err := udc(nr.UnitID)
+	if err != nil {
+		return nil, fmt.Errorf("creating unit data for unit %s: %w", nr.UnitID, err)
+	}
+	if err := types.Cbor.Unmarshal(nr.UnitData, &unitData); err != nil {
+		return nil, fmt.Errorf("decoding data of unit %s: %w", nr.UnitID, err)
+	}
+	v, err := d.toJSON(reflect.ValueOf(unitData))
+	if err != nil {
+		return nil, err
+	}
+	return obj.set("UnitData", v), nil
+}
+
+// set replaces the value of the field "name"
+func (obj jsonObject) set(name string, value any) jsonObject {
+	for i := range obj {
+		if obj[i].name == name {
+			obj[i].value = value
+		}
+	}
+	return obj
+}
+
+func (obj jsonObject) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i, f := range obj {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
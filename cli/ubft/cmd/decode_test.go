@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	testcertificates "github.com/unicitynetwork/bft-core/internal/testutils/certificates"
+	testobserve "github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	testsig "github.com/unicitynetwork/bft-core/internal/testutils/sig"
+	testtransaction "github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
+	"github.com/unicitynetwork/bft-go-base/predicates/templates"
+	"github.com/unicitynetwork/bft-go-base/txsystem/money"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+func TestDecode(t *testing.T) {
+	signer, _ := testsig.CreateSignerAndVerifier(t)
+	ir := &types.InputRecord{Version: 1, RoundNumber: 5, Hash: []byte{1}, PreviousHash: []byte{2}, SummaryValue: []byte{3}}
+	uc := testcertificates.CreateUnicityCertificate(t, signer, ir, defaultMoneyShardConf, 7, nil, nil)
+	ucBytes, err := types.Cbor.Marshal(uc)
+	require.NoError(t, err)
+
+	txo := testtransaction.NewTransactionOrder(t,
+		testtransaction.WithPartitionID(money.DefaultPartitionID),
+		testtransaction.WithTransactionType(money.TransactionTypeTransfer),
+		testtransaction.WithAttributes(&money.TransferAttributes{TargetValue: 42, NewOwnerPredicate: templates.AlwaysTrueBytes(), Counter: 3}),
+	)
+	txBytes, err := types.Cbor.Marshal(txo)
+	require.NoError(t, err)
+	txRecord := &types.TransactionRecord{Version: 1, TransactionOrder: txBytes, ServerMetadata: &types.ServerMetadata{ActualFee: 1, SuccessIndicator: types.TxStatusSuccessful}}
+	txRecordBytes, err := types.Cbor.Marshal(txRecord)
+	require.NoError(t, err)
+
+	decode := func(t *testing.T, args ...string) (map[string]any, error) {
+		cmd := New(testobserve.NewFactory(t))
+		out := &bytes.Buffer{}
+		cmd.baseCmd.SetOut(out)
+		cmd.baseCmd.SetArgs(append([]string{"decode", "--home", t.TempDir()}, args...))
+		if err := cmd.Execute(context.Background()); err != nil {
+			return nil, err
+		}
+		var res map[string]any
+		require.NoError(t, json.Unmarshal(out.Bytes(), &res), out.String())
+		return res, nil
+	}
+	mustDecode := func(t *testing.T, args ...string) (string, map[string]any) {
+		res, err := decode(t, args...)
+		require.NoError(t, err)
+		return res["type"].(string), res["data"].(map[string]any)
+	}
+	// returns value of the field with path "keys" in JSON object "obj"
+	field := func(obj map[string]any, keys ...string) any {
+		var v any = obj
+		for _, k := range keys {
+			v = v.(map[string]any)[k]
+		}
+		return v
+	}
+
+	t.Run("transaction order", func(t *testing.T) {
+		typ, data := mustDecode(t, string(hex.Encode(txBytes)))
+		require.Equal(t, decodeTypeTxOrder, typ)
+		require.EqualValues(t, money.TransactionTypeTransfer, data["Type"])
+		require.EqualValues(t, 42, field(data, "Attributes", "TargetValue"))
+		require.Equal(t, string(hex.Encode(templates.AlwaysTrueBytes())), field(data, "Attributes", "NewOwnerPredicate"))
+	})
+
+	t.Run("unicity certificate from file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "uc.cbor")
+		require.NoError(t, os.WriteFile(file, ucBytes, 0600))
+		typ, data := mustDecode(t, "@"+file)
+		require.Equal(t, decodeTypeUC, typ)
+		require.EqualValues(t, 5, field(data, "inputRecord", "roundNumber"))
+		require.EqualValues(t, 7, field(data, "unicitySeal", "rootChainRoundNumber"))
+	})
+
+	t.Run("block", func(t *testing.T) {
+		block := &types.Block{
+			Header:             &types.Header{Version: 1, PartitionID: money.DefaultPartitionID, ProposerID: "test"},
+			Transactions:       []*types.TransactionRecord{txRecord},
+			UnicityCertificate: ucBytes,
+		}
+		blockBytes, err := types.Cbor.Marshal(block)
+		require.NoError(t, err)
+		typ, data := mustDecode(t, string(hex.Encode(blockBytes)))
+		require.Equal(t, decodeTypeBlock, typ)
+		require.Equal(t, "test", field(data, "Header", "ProposerID"))
+		tx := data["Transactions"].([]any)[0].(map[string]any)
+		require.EqualValues(t, 42, field(tx, "TransactionOrder", "Attributes", "TargetValue"))
+		require.EqualValues(t, 5, field(data, "UnicityCertificate", "inputRecord", "roundNumber"))
+	})
+
+	t.Run("transaction record proof", func(t *testing.T) {
+		proof := &types.TxRecordProof{
+			TxRecord: txRecord,
+			TxProof:  &types.TxProof{Version: 1, BlockHeaderHash: []byte{1, 2}, UnicityCertificate: ucBytes},
+		}
+		proofBytes, err := types.Cbor.Marshal(proof)
+		require.NoError(t, err)
+		typ, data := mustDecode(t, string(hex.Encode(proofBytes)))
+		require.Equal(t, decodeTypeTxRecordProof, typ)
+		require.EqualValues(t, 1, field(data, "TxRecord", "ServerMetadata", "ActualFee"))
+		require.Equal(t, "0x0102", field(data, "TxProof", "BlockHeaderHash"))
+		require.EqualValues(t, 5, field(data, "TxProof", "UnicityCertificate", "inputRecord", "roundNumber"))
+
+		// tagged record
+		typ, data = mustDecode(t, string(hex.Encode(txRecordBytes)))
+		require.Equal(t, decodeTypeTxRecord, typ)
+		require.EqualValues(t, 42, field(data, "TransactionOrder", "Attributes", "TargetValue"))
+	})
+
+	t.Run("state file", func(t *testing.T) {
+		homeDir := writeShardConf(t, defaultMoneyShardConf)
+		cmd := New(testobserve.NewFactory(t))
+		cmd.baseCmd.SetArgs([]string{"shard-conf", "genesis", "--home", homeDir})
+		require.NoError(t, cmd.Execute(context.Background()))
+		stateFile := "@" + filepath.Join(homeDir, StateFileName)
+
+		typ, data := mustDecode(t, stateFile, "--shard-conf", filepath.Join(homeDir, shardConfFileName))
+		require.Equal(t, decodeTypeState, typ)
+		records := data["nodeRecords"].([]any)
+		require.EqualValues(t, len(records), field(data, "header", "NodeRecordCount"))
+		values := map[string]any{}
+		for _, r := range records {
+			values[r.(map[string]any)["UnitID"].(string)] = field(r.(map[string]any), "UnitData", "value")
+		}
+		require.EqualValues(t, 500, values[string(hex.Encode(moneyPartitionInitialBillID))])
+
+		// without shard conf unit data is decoded as generic CBOR
+		_, data = mustDecode(t, stateFile)
+		require.Len(t, data["nodeRecords"].([]any)[0].(map[string]any)["UnitData"], 4)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := decode(t, "0x01")
+		require.EqualError(t, err, "unrecognised data, use the --type flag to see the decoding error")
+		_, err = decode(t, "0x01", "--type", "foo")
+		require.ErrorContains(t, err, "decoding foo: unsupported data type, expected one of block, state, tx,")
+		_, err = decode(t, string(hex.Encode(txBytes)), "--type", decodeTypeUC)
+		require.ErrorContains(t, err, "decoding uc: ")
+		_, err = decode(t, "0x01", "--type", decodeTypeState)
+		require.ErrorContains(t, err, "decoding state: unable to decode header")
+	})
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/unicitynetwork/bft-core/partition"
+	"github.com/unicitynetwork/bft-go-base/util"
+)
+
+const (
+	devnetDirName     = "devnet"
+	devnetNetworkID   = 3
+	devnetEpochStart  = 10
+	devnetLogFileName = "debug.log"
+)
+
+type (
+	devnetFlags struct {
+		*baseFlags
+
+		RootNodes  uint
+		ShardNodes []uint // number of nodes, indexed like devnetPartitions
+		PortOffset uint
+	}
+
+	devnetPartition struct {
+		name            string
+		partitionID     uint32
+		partitionTypeID uint32
+		p2pPort         uint // port of the first node, following nodes use consecutive ports
+		rpcPort         uint
+	}
+
+	devnet struct {
+		flags  *devnetFlags
+		dir    string
+		newApp func() *UnicityBFTApp
+		out    io.Writer
+	}
+)
+
+// partitions started by the devnet, IDs and ports are the same as used by the setup scripts
+var devnetPartitions = []devnetPartition{
+	{name: "money", partitionID: 1, partitionTypeID: 1, p2pPort: 26666, rpcPort: 26866},
+	{name: "tokens", partitionID: 2, partitionTypeID: 2, p2pPort: 28666, rpcPort: 28866},
+	{name: "orchestration", partitionID: 4, partitionTypeID: 4, p2pPort: 30666, rpcPort: 30866},
+}
+
+const (
+	devnetRootP2PPort = 26662
+	devnetRootRPCPort = 25866
+)
+
+func newDevnetCmd(baseFlags *baseFlags, newApp func() *UnicityBFTApp) *cobra.Command {
+	flags := &devnetFlags{baseFlags: baseFlags, ShardNodes: make([]uint, len(devnetPartitions))}
+	var cmd = &cobra.Command{
+		Use:   "devnet",
+		Short: "Runs local development network",
+		Long: `Runs local development network (root chain and shard nodes) in a single process.
+
+On the first run node keys, trust base, shard configurations and genesis states
+are generated into the "$UBFT_HOME/devnet" directory, following runs reuse the
+existing configuration and databases (delete the directory to start from scratch).
+Each node logs into the "debug.log" file in its home directory. Network is shut
+down when the command is interrupted (Ctrl-C).`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d := &devnet{
+				flags:  flags,
+				dir:    filepath.Join(flags.HomeDir, devnetDirName),
+				newApp: newApp,
+				out:    cmd.OutOrStdout(),
+			}
+			return d.run(cmd.Context())
+		},
+	}
+	cmd.Flags().UintVar(&flags.RootNodes, "root", 3, "number of root nodes")
+	for i, p := range devnetPartitions {
+		cmd.Flags().UintVar(&flags.ShardNodes[i], p.name, 3, fmt.Sprintf("number of %s partition nodes", p.name))
+	}
+	cmd.Flags().UintVar(&flags.PortOffset, "port-offset", 0, "value added to all the p2p and RPC port numbers")
+	return cmd
+}
+
+func (d *devnet) run(ctx context.Context) error {
+	if d.flags.RootNodes == 0 {
+		return fmt.Errorf("at least one root node is required")
+	}
+	trustBaseFile := filepath.Join(d.dir, trustBaseFileName)
+	generated := !util.FileExists(trustBaseFile)
+	if generated {
+		if err := d.generate(ctx); err != nil {
+			return fmt.Errorf("generating devnet configuration: %w", err)
+		}
+		fmt.Fprintf(d.out, "generated devnet configuration into %s\n", d.dir)
+	}
+
+	keyConf, err := util.ReadJsonFile(filepath.Join(d.nodeHome("root", 1), keyConfFileName), &partition.KeyConf{})
+	if err != nil {
+		return fmt.Errorf("loading root node keys: %w", err)
+	}
+	bootNodeID, err := keyConf.NodeID()
+	if err != nil {
+		return fmt.Errorf("calculating root node ID: %w", err)
+	}
+	bootNode := fmt.Sprintf("/ip4/127.0.0.1/tcp/%d/p2p/%s", d.port(devnetRootP2PPort, 1), bootNodeID)
+
+	g, ctx := errgroup.WithContext(ctx)
+	start := func(name string, args ...string) {
+		g.Go(func() error {
+			if err := d.exec(ctx, args...); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			return nil
+		})
+	}
+
+	for i := uint(1); i <= d.flags.RootNodes; i++ {
+		home := d.nodeHome("root", i)
+		rpcAddress := fmt.Sprintf("localhost:%d", d.port(devnetRootRPCPort, i))
+		args := []string{"root-node", "run",
+			"--home", home,
+			"--address", fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", d.port(devnetRootP2PPort, i)),
+			"--trust-base", trustBaseFile,
+			"--rpc-server-address", rpcAddress,
+			"--log-file", filepath.Join(home, devnetLogFileName),
+		}
+		if i > 1 {
+			args = append(args, "--bootnodes", bootNode)
+		}
+		// shard confs are stored in the orchestration database on the first run
+		if generated {
+			for j, p := range devnetPartitions {
+				if d.flags.ShardNodes[j] > 0 {
+					args = append(args, "--shard-conf", d.shardConfFile(p))
+				}
+			}
+		}
+		start(filepath.Base(home), args...)
+		fmt.Fprintf(d.out, "%s: http://%s\n", filepath.Base(home), rpcAddress)
+	}
+
+	for i, p := range devnetPartitions {
+		for j := uint(1); j <= d.flags.ShardNodes[i]; j++ {
+			home := d.nodeHome(p.name, j)
+			rpcAddress := fmt.Sprintf("localhost:%d", d.port(p.rpcPort, j))
+			start(filepath.Base(home), "shard-node", "run",
+				"--home", home,
+				"--trust-base", trustBaseFile,
+				"--shard-conf", d.shardConfFile(p),
+				"--address", fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", d.port(p.p2pPort, j)),
+				"--bootnodes", bootNode,
+				"--rpc-server-address", rpcAddress,
+				"--with-get-units=true",
+				"--log-file", filepath.Join(home, devnetLogFileName),
+			)
+			fmt.Fprintf(d.out, "%s: http://%s/rpc\n", filepath.Base(home), rpcAddress)
+		}
+	}
+
+	return g.Wait()
+}
+
+/*
+generate creates node keys, shard configurations, genesis states and the trust
+base the same way as the "setup-nodes.sh" script.
+*/
+func (d *devnet) generate(ctx context.Context) error {
+	for i, p := range devnetPartitions {
+		if d.flags.ShardNodes[i] == 0 {
+			continue
+		}
+		nodeInfos, err := d.initNodes(ctx, p.name, d.flags.ShardNodes[i])
+		if err != nil {
+			return err
+		}
+		args := []string{"shard-conf", "generate",
+			"--home", d.dir,
+			"--network-id", strconv.Itoa(devnetNetworkID),
+			"--partition-id", strconv.FormatUint(uint64(p.partitionID), 10),
+			"--partition-type-id", strconv.FormatUint(uint64(p.partitionTypeID), 10),
+			"--epoch-start", strconv.Itoa(devnetEpochStart),
+		}
+		if err := d.exec(ctx, append(args, nodeInfos...)...); err != nil {
+			return fmt.Errorf("generating %s shard conf: %w", p.name, err)
+		}
+		for j := uint(1); j <= d.flags.ShardNodes[i]; j++ {
+			if err := d.exec(ctx, "shard-conf", "genesis", "--home", d.nodeHome(p.name, j), "--shard-conf", d.shardConfFile(p)); err != nil {
+				return fmt.Errorf("generating %s genesis state: %w", p.name, err)
+			}
+		}
+	}
+
+	nodeInfos, err := d.initNodes(ctx, "root", d.flags.RootNodes)
+	if err != nil {
+		return err
+	}
+	args := []string{"trust-base", "generate", "--home", d.dir, "--network-id", strconv.Itoa(devnetNetworkID)}
+	if err := d.exec(ctx, append(args, nodeInfos...)...); err != nil {
+		return fmt.Errorf("generating trust base: %w", err)
+	}
+	for i := uint(1); i <= d.flags.RootNodes; i++ {
+		if err := d.exec(ctx, "trust-base", "sign", "--home", d.nodeHome("root", i), "--trust-base", filepath.Join(d.dir, trustBaseFileName)); err != nil {
+			return fmt.Errorf("signing trust base: %w", err)
+		}
+	}
+	return nil
+}
+
+/*
+initNodes generates keys for "count" nodes and returns "--node-info" arguments
+for the generated node info files.
+*/
+func (d *devnet) initNodes(ctx context.Context, name string, count uint) ([]string, error) {
+	var nodeInfos []string
+	for i := uint(1); i <= count; i++ {
+		home := d.nodeHome(name, i)
+		if err := d.exec(ctx, "shard-node", "init", "--home", home, "--generate"); err != nil {
+			return nil, fmt.Errorf("initializing %s node %d: %w", name, i, err)
+		}
+		nodeInfos = append(nodeInfos, "--node-info", filepath.Join(home, nodeInfoFileName))
+	}
+	return nodeInfos, nil
+}
+
+// exec runs the command in a new instance of the application
+func (d *devnet) exec(ctx context.Context, args ...string) error {
+	app := d.newApp()
+	app.baseCmd.SetArgs(args)
+	return app.Execute(ctx)
+}
+
+func (d *devnet) nodeHome(name string, idx uint) string {
+	return filepath.Join(d.dir, fmt.Sprintf("%s%d", name, idx))
+}
+
+func (d *devnet) shardConfFile(p devnetPartition) string {
+	return filepath.Join(d.dir, p.shardConfFileName())
+}
+
+func (p devnetPartition) shardConfFileName() string {
+	return fmt.Sprintf("shard-conf-%d_0.json", p.partitionID)
+}
+
+// port returns port number of the node "idx" (1 based) when the first node uses "base"
+func (d *devnet) port(base, idx uint) uint {
+	return base + d.flags.PortOffset + idx - 1
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	testobserve "github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	"github.com/unicitynetwork/bft-core/rpc"
+	"github.com/unicitynetwork/bft-core/rpc/client"
+)
+
+func TestDevnet(t *testing.T) {
+	dn := startTestDevnet(t, 1)
+
+	require.FileExists(t, filepath.Join(dn.dir, trustBaseFileName))
+	require.FileExists(t, filepath.Join(dn.dir, "shard-conf-1_0.json"))
+	require.FileExists(t, filepath.Join(dn.dir, "money1", StateFileName))
+	require.Contains(t, dn.out.String(), "generated devnet configuration into "+dn.dir)
+	require.Contains(t, dn.out.String(), "money1: "+dn.rpcURL(0))
+
+	// health and readiness of the root node and the shard node
+	for _, baseURL := range []string{
+		fmt.Sprintf("http://localhost:%d/api/v1", devnetRootRPCPort+dn.portOffset),
+		fmt.Sprintf("http://localhost:%d/api/v1", devnetPartitions[0].rpcPort+dn.portOffset),
+	} {
+		require.Eventually(t, func() bool {
+			resp, err := http.Get(baseURL + "/ready")
+			if err != nil {
+				return false
+			}
+			defer resp.Body.Close()
+			return resp.StatusCode == http.StatusOK
+		}, 10*time.Second, 100*time.Millisecond, baseURL)
+
+		resp, err := http.Get(baseURL + "/health")
+		require.NoError(t, err)
+		health := &rpc.NodeHealth{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(health))
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, rpc.HealthStatusNormal, health.Status, baseURL)
+		require.Equal(t, rpc.RoleLeader, health.Role, baseURL)
+		require.Equal(t, 1, health.ValidatorCount, baseURL)
+		require.True(t, health.Ready, baseURL)
+	}
+
+	require.ErrorIs(t, dn.stop(), context.Canceled)
+}
+
+func TestDevnet_NoRootNodes(t *testing.T) {
+	cmd := New(testobserve.NewFactory(t))
+	cmd.baseCmd.SetArgs([]string{"devnet", "--home", t.TempDir(), "--root", "0"})
+	require.EqualError(t, cmd.Execute(context.Background()), "at least one root node is required")
+}
+
+type testDevnet struct {
+	dir        string
+	portOffset uint
+	out        *syncBuffer
+	stop       func() error
+}
+
+/*
+startTestDevnet starts devnet with one root node and "shardNodes" nodes of the
+partitions (indexed like devnetPartitions, missing counts are zero) and waits
+until RPC servers of the first nodes are up.
+*/
+func startTestDevnet(t *testing.T, shardNodes ...uint) *testDevnet {
+	homeDir := t.TempDir()
+	dn := &testDevnet{
+		dir: filepath.Join(homeDir, devnetDirName),
+		// random offset so that tests running in parallel do not use the same ports
+		portOffset: 10000 + uint(time.Now().UnixNano()%20000),
+		out:        &syncBuffer{},
+	}
+
+	nodeCount := func(idx int) uint {
+		if idx < len(shardNodes) {
+			return shardNodes[idx]
+		}
+		return 0
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		cmd := New(testobserve.NewFactory(t))
+		cmd.baseCmd.SetOut(dn.out)
+		args := []string{"devnet", "--home", homeDir, "--root", "1",
+			"--port-offset", strconv.FormatUint(uint64(dn.portOffset), 10),
+		}
+		for i, p := range devnetPartitions {
+			args = append(args, "--"+p.name, strconv.FormatUint(uint64(nodeCount(i)), 10))
+		}
+		cmd.baseCmd.SetArgs(args)
+		done <- cmd.Execute(ctx)
+	}()
+	dn.stop = sync.OnceValue(func() error {
+		cancel()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(10 * time.Second):
+			return fmt.Errorf("devnet didn't shut down")
+		}
+	})
+	t.Cleanup(func() { _ = dn.stop() })
+
+	for i := range devnetPartitions {
+		if nodeCount(i) == 0 {
+			continue
+		}
+		rpcURL := dn.rpcURL(i)
+		require.Eventually(t, func() bool {
+			c, err := client.New(ctx, rpcURL)
+			if err != nil {
+				return false
+			}
+			defer c.Close()
+			_, err = c.GetRoundInfo(ctx)
+			return err == nil
+		}, 20*time.Second, 100*time.Millisecond)
+	}
+	return dn
+}
+
+// rpcURL returns RPC URL of the first node of the partition "idx" (in devnetPartitions)
+func (dn *testDevnet) rpcURL(idx int) string {
+	return fmt.Sprintf("http://localhost:%d/rpc", devnetPartitions[idx].rpcPort+dn.portOffset)
+}
+
+// syncBuffer is bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
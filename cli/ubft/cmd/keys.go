@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/unicitynetwork/bft-core/partition"
+	"github.com/unicitynetwork/bft-go-base/util"
+)
+
+const (
+	// names of the environment variables (without prefix) holding the key configuration passphrases
+	envKeyPassphrase    = "key_passphrase"
+	envNewKeyPassphrase = "new_key_passphrase"
+)
+
+type keysFlags struct {
+	*baseFlags
+	keyConfFlags
+	NewPassphraseFD int // file descriptor to read the new passphrase from
+}
+
+func newKeysCmd(baseFlags *baseFlags) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "keys",
+		Short: "Manages the encryption of the key configuration",
+	}
+	cmd.AddCommand(keysEncryptCmd(baseFlags))
+	cmd.AddCommand(keysDecryptCmd(baseFlags))
+	cmd.AddCommand(keysRotateCmd(baseFlags))
+	return cmd
+}
+
+func keysEncryptCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &keysFlags{baseFlags: baseFlags}
+	var cmd = &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypts the key configuration with a passphrase",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return keysEncrypt(flags)
+		},
+	}
+	flags.addKeyConfFlags(cmd, false)
+	return cmd
+}
+
+func keysDecryptCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &keysFlags{baseFlags: baseFlags}
+	var cmd = &cobra.Command{
+		Use:   "decrypt",
+		Short: "Decrypts the key configuration, ie stores the keys unencrypted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return keysDecrypt(flags)
+		},
+	}
+	flags.addKeyConfFlags(cmd, false)
+	return cmd
+}
+
+func keysRotateCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &keysFlags{baseFlags: baseFlags}
+	var cmd = &cobra.Command{
+		Use:   "rotate",
+		Short: "Re-encrypts the key configuration with a new passphrase",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return keysRotate(flags)
+		},
+	}
+	flags.addKeyConfFlags(cmd, false)
+	cmd.Flags().IntVar(&flags.NewPassphraseFD, "new-key-passphrase-fd", -1,
+		fmt.Sprintf("file descriptor to read the new passphrase from (default: $%s or prompt)", envKey(envNewKeyPassphrase)))
+	return cmd
+}
+
+func keysEncrypt(flags *keysFlags) error {
+	keyConfPath := flags.PathWithDefault(flags.KeyConfFile, keyConfFileName)
+	ekc, err := readEncryptedKeyConf(keyConfPath)
+	if err != nil {
+		return err
+	}
+	if ekc != nil {
+		return fmt.Errorf("key configuration %q is already encrypted", keyConfPath)
+	}
+	keyConf, err := flags.loadKeyConf(flags.baseFlags, false)
+	if err != nil {
+		return err
+	}
+	passphrase, err := readPassphrase(flags.PassphraseFD, envKeyPassphrase, "New passphrase: ", true)
+	if err != nil {
+		return fmt.Errorf("reading passphrase: %w", err)
+	}
+	return writeEncryptedKeyConf(keyConfPath, keyConf, passphrase)
+}
+
+func keysDecrypt(flags *keysFlags) error {
+	keyConfPath := flags.PathWithDefault(flags.KeyConfFile, keyConfFileName)
+	ekc, err := readEncryptedKeyConf(keyConfPath)
+	if err != nil {
+		return err
+	}
+	if ekc == nil {
+		return fmt.Errorf("key configuration %q is not encrypted", keyConfPath)
+	}
+	keyConf, err := flags.loadKeyConf(flags.baseFlags, false)
+	if err != nil {
+		return err
+	}
+	return writeKeyConfFile(keyConfPath, keyConf)
+}
+
+func keysRotate(flags *keysFlags) error {
+	keyConfPath := flags.PathWithDefault(flags.KeyConfFile, keyConfFileName)
+	ekc, err := readEncryptedKeyConf(keyConfPath)
+	if err != nil {
+		return err
+	}
+	if ekc == nil {
+		return fmt.Errorf("key configuration %q is not encrypted", keyConfPath)
+	}
+	keyConf, err := flags.loadKeyConf(flags.baseFlags, false)
+	if err != nil {
+		return err
+	}
+	passphrase, err := readPassphrase(flags.NewPassphraseFD, envNewKeyPassphrase, "New passphrase: ", true)
+	if err != nil {
+		return fmt.Errorf("reading new passphrase: %w", err)
+	}
+	return writeEncryptedKeyConf(keyConfPath, keyConf, passphrase)
+}
+
+// readEncryptedKeyConf returns the encrypted key configuration, nil when the key configuration is not encrypted.
+func readEncryptedKeyConf(path string) (*partition.EncryptedKeyConf, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %q: %w", path, err)
+	}
+	ekc, err := partition.ParseEncryptedKeyConf(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %q: %w", path, err)
+	}
+	return ekc, nil
+}
+
+func writeEncryptedKeyConf(path string, keyConf *partition.KeyConf, passphrase []byte) error {
+	ekc, err := partition.EncryptKeyConf(keyConf, passphrase)
+	if err != nil {
+		return fmt.Errorf("encrypting key configuration: %w", err)
+	}
+	return writeKeyConfFile(path, ekc)
+}
+
+// writeKeyConfFile replaces the key configuration file, the file is never left partially written.
+func writeKeyConfFile(path string, conf any) error {
+	tmpPath := path + ".tmp"
+	if err := util.WriteJsonFile(tmpPath, conf); err != nil {
+		return fmt.Errorf("writing key configuration: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return errors.Join(fmt.Errorf("replacing key configuration: %w", err), os.Remove(tmpPath))
+	}
+	return nil
+}
+
+/*
+readPassphrase returns the passphrase read from the file descriptor fd (unless fd
+is negative), from the environment variable envName or from the terminal prompt.
+Passphrase read from the file descriptor ends with the first line break.
+*/
+func readPassphrase(fd int, envName, prompt string, confirm bool) ([]byte, error) {
+	if fd >= 0 {
+		f := os.NewFile(uintptr(fd), fmt.Sprintf("fd %d", fd))
+		if f == nil {
+			return nil, fmt.Errorf("invalid file descriptor %d", fd)
+		}
+		defer f.Close()
+		line, err := bufio.NewReader(f).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("reading passphrase from file descriptor %d: %w", fd, err)
+		}
+		return nonEmptyPassphrase([]byte(strings.TrimRight(line, "\r\n")))
+	}
+
+	if v, ok := os.LookupEnv(envKey(envName)); ok {
+		return nonEmptyPassphrase([]byte(v))
+	}
+
+	stdin := int(os.Stdin.Fd()) // #nosec G115 file descriptor fits into int
+	if !term.IsTerminal(stdin) {
+		return nil, fmt.Errorf("passphrase is required, set it with $%s or run in terminal", envKey(envName))
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(stdin)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("reading passphrase from terminal: %w", err)
+	}
+	if confirm {
+		fmt.Fprint(os.Stderr, "Repeat passphrase: ")
+		repeated, err := term.ReadPassword(stdin)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("reading passphrase from terminal: %w", err)
+		}
+		if !bytes.Equal(passphrase, repeated) {
+			return nil, errors.New("passphrases do not match")
+		}
+	}
+	return nonEmptyPassphrase(passphrase)
+}
+
+func nonEmptyPassphrase(passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase is empty")
+	}
+	return passphrase, nil
+}
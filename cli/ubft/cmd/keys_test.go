@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	testobserve "github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	"github.com/unicitynetwork/bft-core/partition"
+)
+
+func TestKeys_EncryptDecryptRotate(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, keyConfFileName)
+	flags := &keyConfFlags{KeyConfFile: file, PassphraseFD: -1}
+	keyConf, err := flags.loadKeyConf(&baseFlags{}, true)
+	require.NoError(t, err)
+
+	runKeysCmd := func(args ...string) error {
+		cmd := New(testobserve.NewFactory(t))
+		cmd.baseCmd.SetArgs(append(args, "--key-conf", file))
+		return cmd.Execute(context.Background())
+	}
+	requireKeyConf := func(t *testing.T, encrypted bool) {
+		t.Helper()
+		ekc, err := readEncryptedKeyConf(file)
+		require.NoError(t, err)
+		require.Equal(t, encrypted, ekc != nil)
+		loaded, err := flags.loadKeyConf(&baseFlags{}, false)
+		require.NoError(t, err)
+		require.Equal(t, keyConf, loaded)
+	}
+
+	t.Setenv(envKey(envKeyPassphrase), "secret")
+	require.NoError(t, runKeysCmd("keys", "encrypt"))
+	requireKeyConf(t, true)
+	require.ErrorContains(t, runKeysCmd("keys", "encrypt"), "is already encrypted")
+
+	// the node commands load the encrypted keys
+	require.NoError(t, runKeysCmd("node-id"))
+
+	t.Setenv(envKey(envKeyPassphrase), "wrong")
+	require.ErrorIs(t, runKeysCmd("node-id"), partition.ErrInvalidPassphrase)
+
+	t.Setenv(envKey(envKeyPassphrase), "secret")
+	t.Setenv(envKey(envNewKeyPassphrase), "new secret")
+	require.NoError(t, runKeysCmd("keys", "rotate"))
+	require.ErrorIs(t, runKeysCmd("node-id"), partition.ErrInvalidPassphrase)
+	t.Setenv(envKey(envKeyPassphrase), "new secret")
+	requireKeyConf(t, true)
+
+	require.NoError(t, runKeysCmd("keys", "decrypt"))
+	requireKeyConf(t, false)
+	require.ErrorContains(t, runKeysCmd("keys", "decrypt"), "is not encrypted")
+	require.ErrorContains(t, runKeysCmd("keys", "rotate"), "is not encrypted")
+}
+
+func Test_readPassphrase(t *testing.T) {
+	t.Run("file descriptor", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		_, err = w.WriteString("pass phrase\nignored")
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		t.Setenv(envKey(envKeyPassphrase), "from env")
+		passphrase, err := readPassphrase(int(r.Fd()), envKeyPassphrase, "", false)
+		require.NoError(t, err)
+		require.Equal(t, "pass phrase", string(passphrase))
+	})
+
+	t.Run("empty passphrase from file descriptor", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		_, err = readPassphrase(int(r.Fd()), envKeyPassphrase, "", false)
+		require.EqualError(t, err, "passphrase is empty")
+	})
+
+	t.Run("environment variable", func(t *testing.T) {
+		t.Setenv(envKey(envKeyPassphrase), "from env")
+		passphrase, err := readPassphrase(-1, envKeyPassphrase, "", false)
+		require.NoError(t, err)
+		require.Equal(t, "from env", string(passphrase))
+	})
+}
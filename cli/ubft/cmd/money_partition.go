@@ -53,21 +53,29 @@ func (p *MoneyPartition) NewGenesisState(pdr *types.PartitionDescriptionRecord)
 }
 
 func (p *MoneyPartition) CreateTxSystem(flags *ShardNodeRunFlags, nodeConf *partition.NodeConf) (txsystem.TransactionSystem, error) {
-	stateFilePath := flags.PathWithDefault(flags.StateFile, StateFileName)
-	state, header, err := loadStateFile(stateFilePath, func(ui types.UnitID) (types.UnitData, error) {
+	s, header, err := flags.loadState(func(ui types.UnitID) (types.UnitData, error) {
 		return moneysdk.NewUnitData(ui, nodeConf.ShardConf())
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load state file: %w", err)
 	}
 
-	txs, err := money.NewTxSystem(
-		nodeConf.ShardConf(),
-		nodeConf.Observability(),
-		money.WithHashAlgorithm(nodeConf.HashAlgorithm()),
-		money.WithTrustBase(nodeConf.TrustBase()),
-		money.WithState(state),
+	newTxSystem := func(s *state.State, observe txsystem.Observability, opts ...money.Option) (*txsystem.GenericTxSystem, error) {
+		return money.NewTxSystem(
+			nodeConf.ShardConf(),
+			observe,
+			append([]money.Option{
+				money.WithHashAlgorithm(nodeConf.HashAlgorithm()),
+				money.WithTrustBase(nodeConf.TrustBase()),
+				money.WithState(s),
+			}, opts...)...,
+		)
+	}
+	txs, err := newTxSystem(s, nodeConf.Observability(),
 		money.WithExecutedTransactions(header.ExecutedTransactions),
+		money.WithParallelExecution(int(flags.TxWorkers), func(s *state.State, observe txsystem.Observability) (*txsystem.GenericTxSystem, error) {
+			return newTxSystem(s, observe)
+		}),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create money tx system: %w", err)
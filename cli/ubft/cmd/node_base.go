@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/spf13/cobra"
 
+	"github.com/unicitynetwork/bft-core/partition"
+	"github.com/unicitynetwork/bft-core/remotesigner"
 	"github.com/unicitynetwork/bft-core/rpc"
+	abcrypto "github.com/unicitynetwork/bft-go-base/crypto"
 )
 
 type (
@@ -15,6 +20,7 @@ type (
 		BootstrapAddresses         []string // bootstrap addresses (libp2p multiaddress format)
 		BootstrapConnectRetryCount int
 		BootstrapConnectRetryDelay int
+		TracePropagation           bool // send trace context along with the p2p messages
 	}
 
 	rpcFlags struct {
@@ -22,6 +28,11 @@ type (
 		StateRpcRateLimit         int
 		StateRpcResponseItemLimit int
 	}
+
+	remoteSignerFlags struct {
+		RemoteSigner    string // address of the remote signer, signing key of the key conf is used when empty
+		RemoteSignerKey []byte // public key of the remote signer key
+	}
 )
 
 func (f *p2pFlags) addP2PFlags(cmd *cobra.Command) {
@@ -30,6 +41,7 @@ func (f *p2pFlags) addP2PFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSliceVar(&f.BootstrapAddresses, "bootnodes", nil, "addresses of bootstrap nodes (libp2p multiaddress format)")
 	cmd.Flags().IntVar(&f.BootstrapConnectRetryCount, "bootnode-connect-retry-count", 10, "number of times to retry connecting to bootstrap nodes")
 	cmd.Flags().IntVar(&f.BootstrapConnectRetryDelay, "bootnode-connect-retry-delay", 1, "delay in seconds between retries for connecting to bootstrap nodes")
+	cmd.Flags().BoolVar(&f.TracePropagation, "trace-propagation", false, "send trace context along with the p2p messages, all the nodes must support it")
 }
 
 func (f *rpcFlags) addRPCFlags(cmd *cobra.Command) {
@@ -66,6 +78,26 @@ func (f *rpcFlags) addRPCFlags(cmd *cobra.Command) {
 	)
 }
 
+func (f *remoteSignerFlags) addRemoteSignerFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.RemoteSigner, "remote-signer", "",
+		`address of the remote signer, "unix://" followed by the socket path (TCP is not supported), `+
+			"the signing key of the key configuration is used when empty")
+	cmd.Flags().BytesHexVar(&f.RemoteSignerKey, "remote-signer-key", nil,
+		"public key (hex) of the remote signer key, required when the remote signer has more than one key")
+}
+
+// signer returns the remote signer when configured, otherwise the signer of the key configuration.
+func (f *remoteSignerFlags) signer(ctx context.Context, keyConf *partition.KeyConf) (abcrypto.Signer, error) {
+	if f.RemoteSigner == "" {
+		return keyConf.Signer()
+	}
+	signer, err := remotesigner.NewClient(ctx, f.RemoteSigner, f.RemoteSignerKey)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to remote signer: %w", err)
+	}
+	return signer, nil
+}
+
 func hideFlags(cmd *cobra.Command, flags ...string) {
 	for _, flag := range flags {
 		if err := cmd.Flags().MarkHidden(flag); err != nil {
@@ -54,8 +54,7 @@ func (p *OrchestrationPartition) NewGenesisState(pdr *types.PartitionDescription
 }
 
 func (p *OrchestrationPartition) CreateTxSystem(flags *ShardNodeRunFlags, nodeConf *partition.NodeConf) (txsystem.TransactionSystem, error) {
-	stateFilePath := flags.PathWithDefault(flags.StateFile, StateFileName)
-	state, header, err := loadStateFile(stateFilePath, func(ui types.UnitID) (types.UnitData, error) {
+	s, header, err := flags.loadState(func(ui types.UnitID) (types.UnitData, error) {
 		return moneysdk.NewUnitData(ui, nodeConf.ShardConf())
 	})
 	if err != nil {
@@ -67,13 +66,22 @@ func (p *OrchestrationPartition) CreateTxSystem(flags *ShardNodeRunFlags, nodeCo
 		return nil, fmt.Errorf("failed to validate orchestration partition params: %w", err)
 	}
 
-	txs, err := orchestration.NewTxSystem(
-		*nodeConf.ShardConf(),
-		nodeConf.Observability(),
-		orchestration.WithHashAlgorithm(nodeConf.HashAlgorithm()),
-		orchestration.WithState(state),
-		orchestration.WithOwnerPredicate(params.OwnerPredicate),
+	newTxSystem := func(s *state.State, observe txsystem.Observability, opts ...orchestration.Option) (*txsystem.GenericTxSystem, error) {
+		return orchestration.NewTxSystem(
+			*nodeConf.ShardConf(),
+			observe,
+			append([]orchestration.Option{
+				orchestration.WithHashAlgorithm(nodeConf.HashAlgorithm()),
+				orchestration.WithState(s),
+				orchestration.WithOwnerPredicate(params.OwnerPredicate),
+			}, opts...)...,
+		)
+	}
+	txs, err := newTxSystem(s, nodeConf.Observability(),
 		orchestration.WithExecutedTransactions(header.ExecutedTransactions),
+		orchestration.WithParallelExecution(int(flags.TxWorkers), func(s *state.State, observe txsystem.Observability) (*txsystem.GenericTxSystem, error) {
+			return newTxSystem(s, observe)
+		}),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create money tx system: %w", err)
@@ -81,7 +89,7 @@ func (p *OrchestrationPartition) CreateTxSystem(flags *ShardNodeRunFlags, nodeCo
 	return txs, err
 }
 
-func loadStateFile(stateFilePath string, unitDataConstructor state.UnitDataConstructor) (*state.State, *state.Header, error) {
+func loadStateFile(stateFilePath string, unitDataConstructor state.UnitDataConstructor, opts ...state.Option) (*state.State, *state.Header, error) {
 	if !util.FileExists(stateFilePath) {
 		return nil, nil, fmt.Errorf("state file '%s' not found", stateFilePath)
 	}
@@ -92,7 +100,7 @@ func loadStateFile(stateFilePath string, unitDataConstructor state.UnitDataConst
 	}
 	defer stateFile.Close()
 
-	s, header, err := state.NewRecoveredState(stateFile, unitDataConstructor)
+	s, header, err := state.NewRecoveredState(stateFile, unitDataConstructor, opts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to build state tree from state file: %w", err)
 	}
@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/experimental"
+
+	"github.com/unicitynetwork/bft-core/observability"
+	"github.com/unicitynetwork/bft-core/predicates"
+	"github.com/unicitynetwork/bft-core/predicates/templates"
+	"github.com/unicitynetwork/bft-core/predicates/wasm"
+	"github.com/unicitynetwork/bft-core/predicates/wasm/wvm"
+	"github.com/unicitynetwork/bft-core/predicates/wasm/wvm/encoder"
+	"github.com/unicitynetwork/bft-core/state"
+	"github.com/unicitynetwork/bft-core/txsystem/fc"
+	tokenc "github.com/unicitynetwork/bft-core/txsystem/tokens/encoder"
+	txtypes "github.com/unicitynetwork/bft-core/txsystem/types"
+	sdkpredicates "github.com/unicitynetwork/bft-go-base/predicates"
+	sdkwasm "github.com/unicitynetwork/bft-go-base/predicates/wasm"
+	moneysdk "github.com/unicitynetwork/bft-go-base/txsystem/money"
+	orchestrationsdk "github.com/unicitynetwork/bft-go-base/txsystem/orchestration"
+	tokenssdk "github.com/unicitynetwork/bft-go-base/txsystem/tokens"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+type (
+	predicateRunFlags struct {
+		*baseFlags
+		shardConfFlags
+		trustBaseFlags
+
+		Predicate     string // CBOR encoded predicate
+		WasmFile      string // raw WASM binary, alternative to Predicate
+		Entrypoint    string // entrypoint of the WASM predicate
+		PredicateConf string // configuration of the WASM predicate
+		TxOrder       string // CBOR encoded transaction order
+		Args          string // predicate arguments, ie owner proof
+		StateFile     string // unit data fixtures
+		Round         uint64
+		Timestamp     uint64
+		Gas           uint64
+	}
+
+	/*
+		predicateEnv is the "tx system" predicates are evaluated against, units
+		are loaded from the state file given by user.
+	*/
+	predicateEnv struct {
+		state *state.State
+		uc    *types.UnicityCertificate
+		round uint64
+	}
+
+	// buys gas units one to one, cost is calculated like the fee credit module does
+	gasBudget struct{}
+
+	/*
+		predicateProfiler is wazero function listener which counts host API calls
+		and records the result of the WASM predicate's entrypoint.
+	*/
+	predicateProfiler struct {
+		hostCalls map[string]int
+		depth     int
+		result    []uint64
+	}
+)
+
+func newPredicateCmd(baseFlags *baseFlags) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "predicate",
+		Short: "Tools to work with predicates",
+	}
+	cmd.AddCommand(predicateRunCmd(baseFlags))
+	return cmd
+}
+
+func predicateRunCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &predicateRunFlags{baseFlags: baseFlags}
+	var cmd = &cobra.Command{
+		Use:   "run",
+		Short: "Executes a predicate offline and reports result and gas usage",
+		Long: `Executes a predicate (template or WASM) against given transaction order and
+arguments and prints the result, gas used, host API calls made and the
+predicate log output. Binary inputs are hex encoded (0x prefixed) or, when
+prefixed with "@", read from the file, ie "--tx @tx.cbor".`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return predicateRun(cmd.Context(), cmd.OutOrStdout(), flags)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Predicate, "predicate", "", "CBOR encoded predicate")
+	cmd.Flags().StringVar(&flags.WasmFile, "wasm", "", "path to WASM binary of the predicate")
+	cmd.Flags().StringVar(&flags.Entrypoint, "entrypoint", "", "name of the function to call in the WASM predicate")
+	cmd.Flags().StringVar(&flags.PredicateConf, "predicate-conf", "", "configuration of the WASM predicate")
+	cmd.MarkFlagsOneRequired("predicate", "wasm")
+	cmd.MarkFlagsMutuallyExclusive("predicate", "wasm")
+	cmd.MarkFlagsRequiredTogether("wasm", "entrypoint")
+	cmd.Flags().StringVar(&flags.TxOrder, "tx", "", "CBOR encoded transaction order")
+	cmd.Flags().StringVar(&flags.Args, "args", "", "predicate arguments (ie owner proof)")
+	cmd.Flags().StringVar(&flags.StateFile, "state", "", "path to state file with the units predicate can access (requires shard conf)")
+	cmd.Flags().Uint64Var(&flags.Round, "round", 0, "current round number (default: round of the committed UC + 1)")
+	cmd.Flags().Uint64Var(&flags.Timestamp, "timestamp", 0, "timestamp of the committed UC when state file is not used (default: current time)")
+	cmd.Flags().Uint64Var(&flags.Gas, "gas", 100*fc.GasUnitsPerTema, "gas available for the predicate")
+	flags.addShardConfFlags(cmd)
+	flags.addTrustBaseFlags(cmd)
+	return cmd
+}
+
+func predicateRun(ctx context.Context, out io.Writer, flags *predicateRunFlags) error {
+	predicate, err := flags.predicate()
+	if err != nil {
+		return err
+	}
+	txo := &types.TransactionOrder{Version: 1}
+	if flags.TxOrder != "" {
+		data, err := decodeHexOrFile(flags.TxOrder)
+		if err != nil {
+			return fmt.Errorf("reading transaction order: %w", err)
+		}
+		if err := types.Cbor.Unmarshal(data, txo); err != nil {
+			return fmt.Errorf("decoding transaction order: %w", err)
+		}
+	}
+	args, err := decodeHexOrFile(flags.Args)
+	if err != nil {
+		return fmt.Errorf("reading predicate arguments: %w", err)
+	}
+	partitionID := txo.PartitionID
+	var shardConf *types.PartitionDescriptionRecord
+	if flags.StateFile != "" {
+		if shardConf, err = flags.loadShardConf(flags.baseFlags); err != nil {
+			return err
+		}
+		partitionID = shardConf.PartitionID
+	}
+	env, err := flags.newPredicateEnv(shardConf)
+	if err != nil {
+		return err
+	}
+
+	logBuf := &bytes.Buffer{}
+	log := slog.New(slog.NewTextHandler(logBuf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	profiler := &predicateProfiler{hostCalls: make(map[string]int)}
+	executor, err := flags.newPredicateExecutor(partitionID, observability.WithLogger(flags.observe, log), profiler)
+	if err != nil {
+		return err
+	}
+
+	execCtx := txtypes.NewExecutionContext(env, gasBudget{}, flags.Gas)
+	execCtx.WithExArg(txo.AuthProofSigBytes)
+	res, evalErr := executor.Execute(ctx, predicate, args, txo, execCtx)
+
+	switch {
+	case evalErr != nil:
+		fmt.Fprintf(out, "result: error: %v\n", evalErr)
+	default:
+		fmt.Fprintf(out, "result: %t\n", res)
+	}
+	if len(profiler.result) == 1 {
+		er, code := wvm.PredicateEvalResult(profiler.result[0])
+		fmt.Fprintf(out, "result code: %#x (%s, code %#x)\n", profiler.result[0], evalResultString(er), code)
+	}
+	gasUsed := flags.Gas - execCtx.GasAvailable()
+	fmt.Fprintf(out, "gas used: %d (cost %d)\n", gasUsed, execCtx.CalculateCost())
+	if len(profiler.hostCalls) > 0 {
+		fmt.Fprintln(out, "host calls:")
+		for _, name := range slices.Sorted(maps.Keys(profiler.hostCalls)) {
+			fmt.Fprintf(out, "  %s: %d\n", name, profiler.hostCalls[name])
+		}
+	}
+	if logBuf.Len() > 0 {
+		fmt.Fprintln(out, "log:")
+		_, err = io.Copy(out, logBuf)
+	}
+	return err
+}
+
+/*
+predicate returns the predicate to execute, either decoded from the "predicate"
+flag or created from the WASM binary.
+*/
+func (f *predicateRunFlags) predicate() (types.PredicateBytes, error) {
+	if f.WasmFile == "" {
+		predicate, err := decodeHexOrFile(f.Predicate)
+		if err != nil {
+			return nil, fmt.Errorf("reading predicate: %w", err)
+		}
+		return predicate, nil
+	}
+
+	code, err := os.ReadFile(filepath.Clean(f.WasmFile))
+	if err != nil {
+		return nil, fmt.Errorf("reading WASM binary: %w", err)
+	}
+	conf, err := decodeHexOrFile(f.PredicateConf)
+	if err != nil {
+		return nil, fmt.Errorf("reading predicate configuration: %w", err)
+	}
+	params, err := types.Cbor.Marshal(sdkwasm.PredicateParams{Entrypoint: f.Entrypoint, Args: conf})
+	if err != nil {
+		return nil, fmt.Errorf("encoding WASM predicate parameters: %w", err)
+	}
+	predicate, err := types.Cbor.Marshal(&sdkpredicates.Predicate{Tag: sdkwasm.PredicateEngineID, Code: code, Params: params})
+	if err != nil {
+		return nil, fmt.Errorf("encoding WASM predicate: %w", err)
+	}
+	return predicate, nil
+}
+
+func (f *predicateRunFlags) newPredicateEnv(shardConf *types.PartitionDescriptionRecord) (*predicateEnv, error) {
+	env := &predicateEnv{}
+	if f.StateFile != "" {
+		newUnitData, err := unitDataConstructor(shardConf)
+		if err != nil {
+			return nil, err
+		}
+		if env.state, _, err = loadStateFile(f.StateFile, newUnitData); err != nil {
+			return nil, err
+		}
+		env.uc = env.state.CommittedUC()
+	}
+	if env.uc == nil {
+		timestamp := f.Timestamp
+		if timestamp == 0 {
+			timestamp = types.NewTimestamp()
+		}
+		env.uc = &types.UnicityCertificate{
+			Version:     1,
+			InputRecord: &types.InputRecord{Version: 1},
+			UnicitySeal: &types.UnicitySeal{Version: 1, Timestamp: timestamp},
+		}
+	}
+	env.round = f.Round
+	if env.round == 0 {
+		env.round = env.uc.GetRoundNumber() + 1
+	}
+	return env, nil
+}
+
+/*
+newPredicateExecutor creates predicate executor the same way as partitions do,
+WASM engine is able to call templates.
+*/
+func (f *predicateRunFlags) newPredicateExecutor(partitionID types.PartitionID, obs Observability, profiler *predicateProfiler) (predicates.PredicateEngines, error) {
+	templateEng, err := templates.New(obs)
+	if err != nil {
+		return nil, fmt.Errorf("creating predicate templates executor: %w", err)
+	}
+	tpe, err := predicates.Dispatcher(templateEng)
+	if err != nil {
+		return nil, fmt.Errorf("creating predicate executor for WASM engine: %w", err)
+	}
+	// only tokens partition supports WASM predicates, others get generic encoder
+	enc, err := encoder.New(partitionID, tokenc.RegisterTxAttributeEncoders, tokenc.RegisterUnitDataEncoders, tokenc.RegisterAuthProof)
+	if err != nil {
+		return nil, fmt.Errorf("creating encoders for WASM predicate engine: %w", err)
+	}
+	wasmEng, err := wasm.New(enc, tpe.Execute, f, obs, wvm.WithFunctionListener(profiler))
+	if err != nil {
+		return nil, fmt.Errorf("creating predicate WASM executor: %w", err)
+	}
+	return predicates.Dispatcher(templateEng, wasmEng)
+}
+
+// TrustBase implements wvm.Orchestration using the trust base file.
+func (f *predicateRunFlags) TrustBase(epoch uint64) (types.RootTrustBase, error) {
+	tb, err := f.loadTrustBase(f.baseFlags)
+	if err != nil {
+		return nil, err
+	}
+	if tb.Epoch != epoch {
+		return nil, fmt.Errorf("trust base is for epoch %d, requested epoch %d", tb.Epoch, epoch)
+	}
+	return tb, nil
+}
+
+func unitDataConstructor(shardConf *types.PartitionDescriptionRecord) (state.UnitDataConstructor, error) {
+	var newUnitData func(types.UnitID, *types.PartitionDescriptionRecord) (types.UnitData, error)
+	switch shardConf.PartitionTypeID {
+	case moneysdk.PartitionTypeID:
+		newUnitData = moneysdk.NewUnitData
+	case tokenssdk.PartitionTypeID:
+		newUnitData = tokenssdk.NewUnitData
+	case orchestrationsdk.PartitionTypeID:
+		newUnitData = orchestrationsdk.NewUnitData
+	default:
+		return nil, fmt.Errorf("unsupported partition type %d", shardConf.PartitionTypeID)
+	}
+	return func(id types.UnitID) (types.UnitData, error) {
+		return newUnitData(id, shardConf)
+	}, nil
+}
+
+/*
+decodeHexOrFile returns the content of the file when "s" starts with "@",
+otherwise "s" is decoded as 0x prefixed hex string.
+*/
+func decodeHexOrFile(s string) ([]byte, error) {
+	if name, ok := strings.CutPrefix(s, "@"); ok {
+		return os.ReadFile(filepath.Clean(name))
+	}
+	return hex.Decode([]byte(s))
+}
+
+func evalResultString(er wvm.EvalResult) string {
+	switch er {
+	case wvm.EvalResultTrue:
+		return "true"
+	case wvm.EvalResultFalse:
+		return "false"
+	default:
+		return "error"
+	}
+}
+
+func (env *predicateEnv) GetUnit(id types.UnitID, committed bool) (state.Unit, error) {
+	if env.state == nil {
+		return nil, fmt.Errorf("unit %s not found, state file not provided", id)
+	}
+	return env.state.GetUnit(id, committed)
+}
+
+func (env *predicateEnv) CommittedUC() *types.UnicityCertificate { return env.uc }
+
+func (env *predicateEnv) CurrentRound() uint64 { return env.round }
+
+func (gasBudget) BuyGas(gas uint64) uint64 { return gas }
+
+func (gasBudget) CalculateCost(gasUsed uint64) uint64 {
+	return (gasUsed + fc.GasUnitsPerTema/2) / fc.GasUnitsPerTema
+}
+
+func (p *predicateProfiler) NewFunctionListener(api.FunctionDefinition) experimental.FunctionListener {
+	return p
+}
+
+func (p *predicateProfiler) Before(ctx context.Context, mod api.Module, def api.FunctionDefinition, params []uint64, stackIterator experimental.StackIterator) {
+	if def.GoFunction() != nil {
+		p.hostCalls[def.DebugName()]++
+		return
+	}
+	p.depth++
+}
+
+func (p *predicateProfiler) After(ctx context.Context, mod api.Module, def api.FunctionDefinition, results []uint64) {
+	if def.GoFunction() != nil {
+		return
+	}
+	if p.depth--; p.depth == 0 {
+		p.result = slices.Clone(results)
+	}
+}
+
+func (p *predicateProfiler) Abort(ctx context.Context, mod api.Module, def api.FunctionDefinition, err error) {
+	if def.GoFunction() == nil {
+		p.depth--
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	testobserve "github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	testsig "github.com/unicitynetwork/bft-core/internal/testutils/sig"
+	testtransaction "github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
+	"github.com/unicitynetwork/bft-go-base/predicates/templates"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+func TestPredicateRun_Template(t *testing.T) {
+	runCmd := func(t *testing.T, args ...string) string {
+		cmd := New(testobserve.NewFactory(t))
+		out := &bytes.Buffer{}
+		cmd.baseCmd.SetOut(out)
+		cmd.baseCmd.SetArgs(append([]string{"predicate", "run", "--home", t.TempDir()}, args...))
+		require.NoError(t, cmd.Execute(context.Background()))
+		return out.String()
+	}
+
+	t.Run("always true", func(t *testing.T) {
+		out := runCmd(t, "--predicate", string(hex.Encode(templates.AlwaysTrueBytes())))
+		require.Contains(t, out, "result: true\n")
+		require.Contains(t, out, "gas used: 100 (cost 0)\n")
+	})
+
+	t.Run("always false", func(t *testing.T) {
+		out := runCmd(t, "--predicate", string(hex.Encode(templates.AlwaysFalseBytes())))
+		require.Contains(t, out, "result: false\n")
+	})
+
+	t.Run("p2pkh", func(t *testing.T) {
+		signer, verifier := testsig.CreateSignerAndVerifier(t)
+		pubKey, err := verifier.MarshalPublicKey()
+		require.NoError(t, err)
+		pkh := sha256.Sum256(pubKey)
+
+		txo := testtransaction.NewTransactionOrder(t)
+		txBytes, err := types.Cbor.Marshal(txo)
+		require.NoError(t, err)
+		dir := t.TempDir()
+		txFile := filepath.Join(dir, "tx.cbor")
+		require.NoError(t, os.WriteFile(txFile, txBytes, 0600))
+		ownerProof := testsig.NewAuthProofSignature(t, txo, signer)
+		predicate := hex.Encode(templates.NewP2pkh256BytesFromKeyHash(pkh[:]))
+
+		out := runCmd(t, "--predicate", string(predicate), "--tx", "@"+txFile, "--args", string(hex.Encode(ownerProof)))
+		require.Contains(t, out, "result: true\n")
+		require.Contains(t, out, "gas used: 1000 (cost 1)\n")
+
+		// owner proof signed by someone else
+		otherSigner, _ := testsig.CreateSignerAndVerifier(t)
+		ownerProof = testsig.NewAuthProofSignature(t, txo, otherSigner)
+		out = runCmd(t, "--predicate", string(predicate), "--tx", "@"+txFile, "--args", string(hex.Encode(ownerProof)))
+		require.Contains(t, out, "result: false\n")
+
+		// missing owner proof
+		out = runCmd(t, "--predicate", string(predicate), "--tx", "@"+txFile)
+		require.Contains(t, out, "result: error: executing predicate: failed to decode P2PKH256 signature")
+	})
+
+	t.Run("out of gas", func(t *testing.T) {
+		out := runCmd(t, "--predicate", string(hex.Encode(templates.AlwaysTrueBytes())), "--gas", "10")
+		require.Contains(t, out, "result: error: executing predicate: out of gas\n")
+	})
+}
+
+func TestPredicateRun_InvalidInput(t *testing.T) {
+	cmd := New(testobserve.NewFactory(t))
+	cmd.baseCmd.SetArgs([]string{"predicate", "run", "--home", t.TempDir()})
+	require.EqualError(t, cmd.Execute(context.Background()), `at least one of the flags in the group [predicate wasm] is required`)
+
+	cmd = New(testobserve.NewFactory(t))
+	cmd.baseCmd.SetArgs([]string{"predicate", "run", "--home", t.TempDir(), "--predicate", "0x01", "--wasm", "p.wasm", "--entrypoint", "main"})
+	require.ErrorContains(t, cmd.Execute(context.Background()), `if any flags in the group [predicate wasm] are set none of the others can be`)
+
+	cmd = New(testobserve.NewFactory(t))
+	cmd.baseCmd.SetArgs([]string{"predicate", "run", "--home", t.TempDir(), "--predicate", "01"})
+	require.EqualError(t, cmd.Execute(context.Background()), `reading predicate: hex string without 0x prefix`)
+
+	cmd = New(testobserve.NewFactory(t))
+	cmd.baseCmd.SetArgs([]string{"predicate", "run", "--home", t.TempDir(), "--predicate", "0x01", "--state", "state.cbor"})
+	require.ErrorContains(t, cmd.Execute(context.Background()), `shard-conf.json`)
+}
+
+func Test_decodeHexOrFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data")
+	require.NoError(t, os.WriteFile(file, []byte{1, 2, 3}, 0600))
+
+	data, err := decodeHexOrFile("@" + file)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, data)
+
+	data, err = decodeHexOrFile("0x0102")
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, data)
+
+	data, err = decodeHexOrFile("")
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	_, err = decodeHexOrFile("@" + file + ".missing")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
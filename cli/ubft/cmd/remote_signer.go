@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ainvaltin/httpsrv"
+	"github.com/spf13/cobra"
+
+	"github.com/unicitynetwork/bft-core/remotesigner"
+	abcrypto "github.com/unicitynetwork/bft-go-base/crypto"
+)
+
+const (
+	remoteSignerStoreFileName  = "signer.db"
+	remoteSignerSocketFileName = "signer.sock"
+)
+
+type remoteSignerRunFlags struct {
+	*baseFlags
+	KeyConfFiles []string
+	PassphraseFD int
+	Address      string
+	StoreFile    string // path to the Bolt DB of the last signed rounds
+}
+
+func newRemoteSignerCmd(baseFlags *baseFlags) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "remote-signer",
+		Short: "Tools to run a remote signer",
+	}
+	cmd.AddCommand(remoteSignerRunCmd(baseFlags))
+	return cmd
+}
+
+func remoteSignerRunCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &remoteSignerRunFlags{baseFlags: baseFlags}
+	var cmd = &cobra.Command{
+		Use:   "run",
+		Short: "Runs the signer process",
+		Long: `Runs the signer process which signs with the signing keys of the key configurations
+on behalf of the nodes started with "--remote-signer". The signer refuses to sign messages
+conflicting with the messages it has already signed for the same or later round.
+
+The signer API is not authenticated so it's served only on the unix socket which is
+accessible only by the user running the signer, the nodes must run on the same host.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return remoteSignerRun(cmd.Context(), flags)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&flags.KeyConfFiles, "key-conf", "k", nil,
+		fmt.Sprintf("paths to the key configuration files whose signing keys are served (default: %s)", filepath.Join("$UBFT_HOME", keyConfFileName)))
+	cmd.Flags().IntVar(&flags.PassphraseFD, "key-passphrase-fd", -1,
+		fmt.Sprintf("file descriptor to read the passphrase of the encrypted key configurations from (default: $%s or prompt)", envKey(envKeyPassphrase)))
+	cmd.Flags().StringVar(&flags.Address, "address", "",
+		fmt.Sprintf(`address to listen on, "unix://" followed by the socket path, TCP is not supported as the signer API is not authenticated (default: unix://%s)`,
+			filepath.Join("$UBFT_HOME", remoteSignerSocketFileName)))
+	cmd.Flags().StringVar(&flags.StoreFile, "signer-db", "",
+		fmt.Sprintf("path to the database of the last signed rounds (default: %s)", filepath.Join("$UBFT_HOME", remoteSignerStoreFileName)))
+	return cmd
+}
+
+func remoteSignerRun(ctx context.Context, flags *remoteSignerRunFlags) error {
+	keyConfFiles := flags.KeyConfFiles
+	if len(keyConfFiles) == 0 {
+		keyConfFiles = []string{flags.PathWithDefault("", keyConfFileName)}
+	}
+	// the same passphrase is used for all the encrypted key configurations
+	passphrase := sync.OnceValues(func() ([]byte, error) {
+		return readPassphrase(flags.PassphraseFD, envKeyPassphrase, "Key configuration passphrase: ", false)
+	})
+	var signers []abcrypto.Signer
+	for _, path := range keyConfFiles {
+		keyConf, err := readKeyConfFile(path, passphrase)
+		if err != nil {
+			return err
+		}
+		signer, err := keyConf.Signer()
+		if err != nil {
+			return fmt.Errorf("key configuration %q: %w", path, err)
+		}
+		signers = append(signers, signer)
+	}
+
+	db, err := flags.initStore(flags.StoreFile, remoteSignerStoreFileName)
+	if err != nil {
+		return err
+	}
+	log := flags.observe.Logger()
+	srv, err := remotesigner.NewServer(signers, db, log)
+	if err != nil {
+		return fmt.Errorf("creating remote signer: %w", err)
+	}
+
+	address := flags.Address
+	if address == "" {
+		address = "unix://" + flags.PathWithDefault("", remoteSignerSocketFileName)
+	}
+	listener, err := remotesigner.Listen(address)
+	if err != nil {
+		return fmt.Errorf("listening on %q: %w", address, err)
+	}
+	log.InfoContext(ctx, fmt.Sprintf("remote signer listening on %s", address))
+	return httpsrv.Run(ctx,
+		&http.Server{
+			Handler:           srv.Handler(),
+			ReadTimeout:       3 * time.Second,
+			ReadHeaderTimeout: time.Second,
+			WriteTimeout:      5 * time.Second,
+			IdleTimeout:       30 * time.Second,
+		},
+		httpsrv.Listener(listener))
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	testobserve "github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	"github.com/unicitynetwork/bft-core/network/protocol/certification"
+	"github.com/unicitynetwork/bft-core/remotesigner"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+func TestRemoteSignerRun(t *testing.T) {
+	homeDir := t.TempDir()
+	flags := &keyConfFlags{KeyConfFile: filepath.Join(homeDir, keyConfFileName), PassphraseFD: -1}
+	keyConf, err := flags.loadKeyConf(&baseFlags{}, true)
+	require.NoError(t, err)
+	signer, err := keyConf.Signer()
+	require.NoError(t, err)
+	verifier, err := signer.Verifier()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		cmd := New(testobserve.NewFactory(t))
+		cmd.baseCmd.SetArgs([]string{"remote-signer", "run", "--home", homeDir})
+		done <- cmd.Execute(ctx)
+	}()
+
+	address := "unix://" + filepath.Join(homeDir, remoteSignerSocketFileName)
+	var client *remotesigner.Client
+	require.Eventually(t, func() bool {
+		client, err = remotesigner.NewClient(ctx, address, nil)
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// node side signer, as configured with "--remote-signer"
+	rsFlags := &remoteSignerFlags{RemoteSigner: address}
+	nodeSigner, err := rsFlags.signer(ctx, keyConf)
+	require.NoError(t, err)
+	require.IsType(t, client, nodeSigner)
+
+	req := &certification.BlockCertificationRequest{PartitionID: 1, NodeID: "node1", InputRecord: &types.InputRecord{Version: 1, RoundNumber: 1}}
+	require.NoError(t, req.Sign(remotesigner.ForMessage(nodeSigner, remotesigner.KindCertificationRequest, 1, req)))
+	bs, err := req.Bytes()
+	require.NoError(t, err)
+	require.NoError(t, verifier.VerifyBytes(req.Signature, bs))
+	req.InputRecord.Hash = []byte{1}
+	require.ErrorIs(t, req.Sign(remotesigner.ForMessage(nodeSigner, remotesigner.KindCertificationRequest, 1, req)), remotesigner.ErrDoubleSign)
+
+	cancel()
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("remote signer did not stop")
+	}
+}
+
+func TestRemoteSignerRun_tcpAddress(t *testing.T) {
+	homeDir := t.TempDir()
+	flags := &keyConfFlags{KeyConfFile: filepath.Join(homeDir, keyConfFileName), PassphraseFD: -1}
+	_, err := flags.loadKeyConf(&baseFlags{}, true)
+	require.NoError(t, err)
+
+	cmd := New(testobserve.NewFactory(t))
+	cmd.baseCmd.SetArgs([]string{"remote-signer", "run", "--home", homeDir, "--address", "127.0.0.1:0"})
+	require.ErrorContains(t, cmd.Execute(context.Background()), `listening on "127.0.0.1:0": invalid remote signer address "127.0.0.1:0": must be "unix://" followed by the socket path, TCP is not supported`)
+}
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/ainvaltin/httpsrv"
@@ -21,6 +23,7 @@ import (
 	"github.com/unicitynetwork/bft-core/logger"
 	"github.com/unicitynetwork/bft-core/network"
 	"github.com/unicitynetwork/bft-core/network/protocol/abdrc"
+	"github.com/unicitynetwork/bft-core/network/protocol/certification"
 	"github.com/unicitynetwork/bft-core/observability"
 	"github.com/unicitynetwork/bft-core/partition"
 	"github.com/unicitynetwork/bft-core/rootchain"
@@ -28,6 +31,7 @@ import (
 	"github.com/unicitynetwork/bft-core/rootchain/consensus/storage"
 	"github.com/unicitynetwork/bft-core/rootchain/consensus/trustbase"
 	"github.com/unicitynetwork/bft-core/rootchain/partitions"
+	"github.com/unicitynetwork/bft-core/rpc"
 	abcrypto "github.com/unicitynetwork/bft-go-base/crypto"
 	"github.com/unicitynetwork/bft-go-base/types"
 	"github.com/unicitynetwork/bft-go-base/util"
@@ -37,6 +41,7 @@ const (
 	rootStoreFileName          = "rootchain.db"
 	trustBaseStoreFileName     = "trustbase.db"
 	orchestrationStoreFileName = "orchestration.db"
+	certificateStoreFileName   = "certificates.db"
 	defaultNetworkTimeout      = 300 * time.Millisecond
 )
 
@@ -46,15 +51,19 @@ type (
 		keyConfFlags
 		trustBaseFlags
 		p2pFlags
+		remoteSignerFlags
 
 		RootStoreFile          string // path to Bolt storage file
 		TrustBaseStoreFile     string
 		OrchestrationStoreFile string
+		CertificateStoreFile   string   // path to the certificate archive
+		CertificateKeepRounds  uint64   // number of root rounds the certificates are archived for, 0 keeps all
 		ShardConfFiles         []string // paths to shard conf files
 
 		BlockRate        uint32
 		MaxRequests      uint   // validator partition certification request channel capacity
 		RPCServerAddress string // address on which http server is exposed with metrics endpoint
+		ReadyMaxUCAgeMs  uint32 // readiness fails when the last commit is older
 	}
 )
 
@@ -66,6 +75,7 @@ func newRootNodeCmd(baseFlags *baseFlags) *cobra.Command {
 
 	cmd.AddCommand(rootNodeInitCmd(baseFlags))
 	cmd.AddCommand(rootNodeRunCmd(baseFlags))
+	cmd.AddCommand(rootNodeSafetyCmd(baseFlags))
 	return cmd
 }
 
@@ -98,10 +108,13 @@ func rootNodeRunCmd(baseFlags *baseFlags) *cobra.Command {
 	flags.addKeyConfFlags(cmd, false)
 	flags.addTrustBaseFlags(cmd)
 	flags.addP2PFlags(cmd)
+	flags.addRemoteSignerFlags(cmd)
 
 	cmd.Flags().UintVar(&flags.MaxRequests, "max-requests", 1000, "request buffer capacity")
 	cmd.Flags().StringVar(&flags.RPCServerAddress, "rpc-server-address", "",
 		`Specifies the TCP address for the RPC server to listen on, in the form "host:port". RPC server isn't initialised if address is empty.`)
+	cmd.Flags().Uint32Var(&flags.ReadyMaxUCAgeMs, "ready-max-uc-age", uint32(rpc.DefaultReadyMaxUCAge.Milliseconds()),
+		"readiness check fails when the latest committed round is older (in ms)")
 
 	cmd.Flags().StringVar(&flags.RootStoreFile, "root-db", "",
 		fmt.Sprintf("path to the root database (default: %s)", filepath.Join("$UBFT_HOME", rootStoreFileName)))
@@ -109,6 +122,10 @@ func rootNodeRunCmd(baseFlags *baseFlags) *cobra.Command {
 		fmt.Sprintf("path to the trust base database (default: %s)", filepath.Join("$UBFT_HOME", trustBaseStoreFileName)))
 	cmd.Flags().StringVar(&flags.OrchestrationStoreFile, "orchestration-db", "",
 		fmt.Sprintf("path to the orchestration database (default: %s)", filepath.Join("$UBFT_HOME", orchestrationStoreFileName)))
+	cmd.Flags().StringVar(&flags.CertificateStoreFile, "certificate-archive-db", "",
+		fmt.Sprintf("path to the certificate archive database (default: %s)", filepath.Join("$UBFT_HOME", certificateStoreFileName)))
+	cmd.Flags().Uint64Var(&flags.CertificateKeepRounds, "certificate-archive-rounds", 0,
+		"number of root rounds the certificates are kept in the archive, 0 keeps all the certificates")
 
 	cmd.Flags().StringSliceVarP(&flags.ShardConfFiles, "shard-conf", "", []string{}, "path to shard conf files")
 	cmd.Flags().Uint32Var(&flags.BlockRate, "block-rate", consensus.BlockRate, "block rate (consensus parameter)")
@@ -146,7 +163,7 @@ func rootNodeRun(ctx context.Context, flags *rootNodeRunFlags) error {
 	if err != nil {
 		return fmt.Errorf("creating partition host: %w", err)
 	}
-	partitionNet, err := network.NewLibP2PRootChainNetwork(host, flags.MaxRequests, defaultNetworkTimeout, obs)
+	partitionNet, err := network.NewLibP2PRootChainNetwork(host, flags.MaxRequests, defaultNetworkTimeout, obs, network.WithTracePropagation(flags.TracePropagation))
 	if err != nil {
 		return fmt.Errorf("partition network initialization failed: %w", err)
 	}
@@ -155,6 +172,13 @@ func rootNodeRun(ctx context.Context, flags *rootNodeRunFlags) error {
 	if err != nil {
 		return err
 	}
+	watermark, err := rootStore.GetSafetyWatermark()
+	if err != nil {
+		return err
+	}
+	if watermark > 0 {
+		log.InfoContext(ctx, fmt.Sprintf("imported safety watermark %d, not signing for the round or earlier", watermark))
+	}
 	trustBaseStore, err := flags.initStore(flags.TrustBaseStoreFile, trustBaseStoreFileName)
 	if err != nil {
 		return err
@@ -166,7 +190,7 @@ func rootNodeRun(ctx context.Context, flags *rootNodeRunFlags) error {
 		return fmt.Errorf("root trust base init failed: %w", err)
 	}
 
-	signer, err := keyConf.Signer()
+	signer, err := flags.signer(ctx, keyConf)
 	if err != nil {
 		return err
 	}
@@ -178,7 +202,7 @@ func rootNodeRun(ctx context.Context, flags *rootNodeRunFlags) error {
 		return fmt.Errorf("root node key not found in trust base: %w", err)
 	}
 
-	rootNet, err := network.NewLibP2RootConsensusNetwork(host, flags.MaxRequests, defaultNetworkTimeout, obs)
+	rootNet, err := network.NewLibP2RootConsensusNetwork(host, flags.MaxRequests, defaultNetworkTimeout, obs, network.WithTracePropagation(flags.TracePropagation))
 	if err != nil {
 		return fmt.Errorf("failed initiate root network, %w", err)
 	}
@@ -193,6 +217,15 @@ func rootNodeRun(ctx context.Context, flags *rootNodeRunFlags) error {
 		return fmt.Errorf("failed to load shard conf files: %w", err)
 	}
 
+	certificateStore, err := flags.initStore(flags.CertificateStoreFile, certificateStoreFileName)
+	if err != nil {
+		return err
+	}
+	certArchive, err := storage.NewCertificateArchive(certificateStore, flags.CertificateKeepRounds)
+	if err != nil {
+		return fmt.Errorf("creating certificate archive: %w", err)
+	}
+
 	consensusParams := consensus.NewConsensusParams()
 	consensusParams.BlockRate = time.Duration(flags.BlockRate) * time.Millisecond
 
@@ -205,6 +238,7 @@ func rootNodeRun(ctx context.Context, flags *rootNodeRunFlags) error {
 		rootStore,
 		obs,
 		consensus.WithConsensusParams(*consensusParams),
+		consensus.WithCertificateArchive(certArchive),
 	)
 	if err != nil {
 		return fmt.Errorf("failed initiate distributed consensus manager: %w", err)
@@ -237,6 +271,11 @@ func rootNodeRun(ctx context.Context, flags *rootNodeRunFlags) error {
 		}
 		mux.HandleFunc("PUT /api/v1/configurations", putShardConfigHandler(orchestration.AddShardConfig))
 		mux.HandleFunc("GET /api/v1/roundInfo", getRoundInfoHandler(cm.GetState, obs))
+		mux.HandleFunc("GET /api/v1/certificates/{partition}/{shard}", getCertificateHandler(certArchive, obs))
+		health := rootNodeHealth(cm.Status, host)
+		maxUCAge := time.Duration(flags.ReadyMaxUCAgeMs) * time.Millisecond
+		mux.HandleFunc("GET /api/v1/health", rpc.HealthHandler(health, maxUCAge, log))
+		mux.HandleFunc("GET /api/v1/ready", rpc.ReadyHandler(health, maxUCAge, log))
 		return httpsrv.Run(ctx,
 			&http.Server{
 				Addr:              flags.RPCServerAddress,
@@ -337,6 +376,32 @@ func putShardConfigHandler(addShardConfFn func(shardConf *types.PartitionDescrip
 	}
 }
 
+// rootNodeHealth returns the health source of the root node, all root nodes are validators
+func rootNodeHealth(status func() *consensus.Status, host *network.Peer) rpc.HealthSource {
+	return func() *rpc.NodeHealth {
+		s := status()
+		health := &rpc.NodeHealth{
+			Status:         rpc.HealthStatusNormal,
+			Role:           rpc.RoleValidator,
+			CommittedRound: s.CommittedRound,
+			LastUCTime:     s.CommitTime,
+			PeerCount:      len(host.Network().Peers()),
+			ValidatorCount: s.RootNodeCount,
+		}
+		if s.IsLeader {
+			health.Role = rpc.RoleLeader
+		}
+		if s.InRecovery {
+			health.Status = rpc.HealthStatusRecovering
+			health.Recovery = &rpc.RecoveryStatus{
+				TargetRound:  s.RecoveryRound,
+				CurrentRound: s.CommittedRound,
+			}
+		}
+		return health
+	}
+}
+
 func parseShardConf(r io.ReadCloser) (*types.PartitionDescriptionRecord, error) {
 	defer r.Close()
 	var shardConf *types.PartitionDescriptionRecord
@@ -402,3 +467,58 @@ func getRoundInfoHandler(getState func() (*abdrc.StateMsg, error), obs Observabi
 		}
 	}
 }
+
+/*
+getCertificateHandler returns the archived certificate of the shard. The certificate
+is looked up either by the "shardRound" or by the "rootRound" query parameter, in the
+latter case the certificate of the shard which was valid in the root round is returned.
+*/
+func getCertificateHandler(archive *storage.CertificateArchive, obs Observability) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		partitionID, err := strconv.ParseUint(r.PathValue("partition"), 10, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid partition ID: %v", err), http.StatusBadRequest)
+			return
+		}
+		var shardID types.ShardID
+		if err := shardID.UnmarshalText([]byte(r.PathValue("shard"))); err != nil {
+			http.Error(w, fmt.Sprintf("invalid shard ID: %v", err), http.StatusBadRequest)
+			return
+		}
+		query := r.URL.Query()
+		if query.Has("shardRound") == query.Has("rootRound") {
+			http.Error(w, "either shardRound or rootRound must be specified", http.StatusBadRequest)
+			return
+		}
+
+		var cr *certification.CertificationResponse
+		var round uint64
+		if query.Has("shardRound") {
+			if round, err = strconv.ParseUint(query.Get("shardRound"), 10, 64); err != nil {
+				http.Error(w, fmt.Sprintf("invalid shard round: %v", err), http.StatusBadRequest)
+				return
+			}
+			cr, err = archive.ByShardRound(types.PartitionID(partitionID), shardID, round)
+		} else {
+			if round, err = strconv.ParseUint(query.Get("rootRound"), 10, 64); err != nil {
+				http.Error(w, fmt.Sprintf("invalid root round: %v", err), http.StatusBadRequest)
+				return
+			}
+			cr, err = archive.ByRootRound(types.PartitionID(partitionID), shardID, round)
+		}
+		if err != nil {
+			if errors.Is(err, storage.ErrCertificateNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			obs.Logger().Warn(fmt.Sprintf("GET certificates request: failed to load certificate: %v", err))
+			http.Error(w, "failed to load certificate", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(cr); err != nil {
+			obs.Logger().Warn(fmt.Sprintf("GET certificates request: failed to write response: %v", err))
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unicitynetwork/bft-core/rootchain/consensus/storage"
+	"github.com/unicitynetwork/bft-go-base/util"
+)
+
+const safetyStateVersion = 1
+
+type (
+	rootNodeSafetyFlags struct {
+		*baseFlags
+		keyConfFlags
+		RootStoreFile string // path to Bolt storage file
+	}
+
+	// safetyState is the exported safety state of the root validator.
+	safetyState struct {
+		Version           uint32 `json:"version"`
+		NodeID            string `json:"nodeId"`
+		HighestVotedRound uint64 `json:"highestVotedRound,string"`
+		HighestQcRound    uint64 `json:"highestQcRound,string"`
+	}
+)
+
+func rootNodeSafetyCmd(baseFlags *baseFlags) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "safety",
+		Short: "Exports and imports the safety state (highest voted round) of the root validator",
+		Long: `Exports and imports the safety state (highest voted round) of the root validator.
+When the validator is moved to a new machine or its database is rebuilt, import the state
+exported from the old database (the node must not be running). The node will not sign
+anything for the imported voted round or any earlier round.`,
+	}
+	cmd.AddCommand(rootNodeSafetyExportCmd(baseFlags))
+	cmd.AddCommand(rootNodeSafetyImportCmd(baseFlags))
+	return cmd
+}
+
+func rootNodeSafetyExportCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &rootNodeSafetyFlags{baseFlags: baseFlags}
+	var cmd = &cobra.Command{
+		Use:   "export FILE",
+		Short: "Exports the safety state to a file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rootNodeSafetyExport(flags, args[0])
+		},
+	}
+	flags.addRootNodeSafetyFlags(cmd)
+	return cmd
+}
+
+func rootNodeSafetyImportCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &rootNodeSafetyFlags{baseFlags: baseFlags}
+	var cmd = &cobra.Command{
+		Use:   "import FILE",
+		Short: "Imports the safety state from a file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rootNodeSafetyImport(flags, args[0])
+		},
+	}
+	flags.addRootNodeSafetyFlags(cmd)
+	return cmd
+}
+
+func (f *rootNodeSafetyFlags) addRootNodeSafetyFlags(cmd *cobra.Command) {
+	f.addKeyConfFlags(cmd, false)
+	cmd.Flags().StringVar(&f.RootStoreFile, "root-db", "",
+		fmt.Sprintf("path to the root database (default: %s)", filepath.Join("$UBFT_HOME", rootStoreFileName)))
+}
+
+func (f *rootNodeSafetyFlags) nodeID() (string, error) {
+	keyConf, err := f.loadKeyConf(f.baseFlags, false)
+	if err != nil {
+		return "", err
+	}
+	nodeID, err := keyConf.NodeID()
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate nodeID: %w", err)
+	}
+	return nodeID.String(), nil
+}
+
+func rootNodeSafetyExport(flags *rootNodeSafetyFlags, file string) (err error) {
+	nodeID, err := flags.nodeID()
+	if err != nil {
+		return err
+	}
+	rootStore, err := storage.NewBoltStorage(flags.PathWithDefault(flags.RootStoreFile, rootStoreFileName))
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, rootStore.Close()) }()
+
+	state, err := rootStore.ReadSafetyState()
+	if err != nil {
+		return fmt.Errorf("reading safety state: %w", err)
+	}
+	if err := util.WriteJsonFile(file, &safetyState{
+		Version:           safetyStateVersion,
+		NodeID:            nodeID,
+		HighestVotedRound: state.HighestVotedRound,
+		HighestQcRound:    state.HighestQcRound,
+	}); err != nil {
+		return fmt.Errorf("writing safety state: %w", err)
+	}
+	fmt.Printf("Exported safety state: highest voted round %d, highest QC round %d\n", state.HighestVotedRound, state.HighestQcRound)
+	return nil
+}
+
+func rootNodeSafetyImport(flags *rootNodeSafetyFlags, file string) (err error) {
+	nodeID, err := flags.nodeID()
+	if err != nil {
+		return err
+	}
+	imported, err := util.ReadJsonFile(file, &safetyState{})
+	if err != nil {
+		return fmt.Errorf("reading safety state: %w", err)
+	}
+	if imported.Version != safetyStateVersion {
+		return fmt.Errorf("unsupported safety state version %d", imported.Version)
+	}
+	// the state of another validator would not protect this validator
+	if imported.NodeID != nodeID {
+		return fmt.Errorf("safety state belongs to node %s, not to %s", imported.NodeID, nodeID)
+	}
+
+	rootStore, err := storage.NewBoltStorage(flags.PathWithDefault(flags.RootStoreFile, rootStoreFileName))
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, rootStore.Close()) }()
+
+	if err := rootStore.ImportSafetyState(imported.HighestVotedRound, imported.HighestQcRound); err != nil {
+		return fmt.Errorf("importing safety state: %w", err)
+	}
+	state, err := rootStore.ReadSafetyState()
+	if err != nil {
+		return fmt.Errorf("reading safety state: %w", err)
+	}
+	fmt.Printf("Imported safety state: highest voted round %d, highest QC round %d, watermark %d\n",
+		state.HighestVotedRound, state.HighestQcRound, state.Watermark)
+	return nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	testobserve "github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	"github.com/unicitynetwork/bft-core/rootchain/consensus/storage"
+	"github.com/unicitynetwork/bft-go-base/util"
+)
+
+func TestRootNodeSafety_ExportImport(t *testing.T) {
+	homeDir := t.TempDir()
+	flags := &keyConfFlags{KeyConfFile: filepath.Join(homeDir, keyConfFileName), PassphraseFD: -1}
+	_, err := flags.loadKeyConf(&baseFlags{}, true)
+	require.NoError(t, err)
+
+	oldDB := filepath.Join(homeDir, "old-rootchain.db")
+	rootStore, err := storage.NewBoltStorage(oldDB)
+	require.NoError(t, err)
+	require.NoError(t, rootStore.SetHighestQcRound(7, 8))
+	require.NoError(t, rootStore.Close())
+
+	stateFile := filepath.Join(homeDir, "safety.json")
+	cmd := New(testobserve.NewFactory(t))
+	cmd.baseCmd.SetArgs([]string{"root-node", "safety", "export", stateFile, "--home", homeDir, "--root-db", oldDB})
+	require.NoError(t, cmd.Execute(context.Background()))
+
+	exported, err := util.ReadJsonFile(stateFile, &safetyState{})
+	require.NoError(t, err)
+	require.EqualValues(t, 8, exported.HighestVotedRound)
+	require.EqualValues(t, 7, exported.HighestQcRound)
+
+	// import into the new database in the default location
+	cmd = New(testobserve.NewFactory(t))
+	cmd.baseCmd.SetArgs([]string{"root-node", "safety", "import", stateFile, "--home", homeDir})
+	require.NoError(t, cmd.Execute(context.Background()))
+
+	rootStore, err = storage.NewBoltStorage(filepath.Join(homeDir, rootStoreFileName))
+	require.NoError(t, err)
+	state, err := rootStore.ReadSafetyState()
+	require.NoError(t, err)
+	require.Equal(t, storage.SafetyState{HighestVotedRound: 8, HighestQcRound: 7, Watermark: 8}, state)
+	require.NoError(t, rootStore.Close())
+
+	// state of another node
+	exported.NodeID = "16Uiu2HAm6eQMr2sQVbcWZsPPbpc2Su7AnnMVGHpC23PUzGTAATnp"
+	require.NoError(t, util.WriteJsonFile(stateFile, exported))
+	cmd = New(testobserve.NewFactory(t))
+	cmd.baseCmd.SetArgs([]string{"root-node", "safety", "import", stateFile, "--home", homeDir})
+	require.ErrorContains(t, cmd.Execute(context.Background()),
+		"safety state belongs to node 16Uiu2HAm6eQMr2sQVbcWZsPPbpc2Su7AnnMVGHpC23PUzGTAATnp, not to ")
+}
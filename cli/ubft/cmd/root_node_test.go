@@ -19,7 +19,10 @@ import (
 	"github.com/unicitynetwork/bft-core/internal/testutils/net"
 	"github.com/unicitynetwork/bft-core/internal/testutils/observability"
 	testtime "github.com/unicitynetwork/bft-core/internal/testutils/time"
+	"github.com/unicitynetwork/bft-core/keyvaluedb/memorydb"
 	"github.com/unicitynetwork/bft-core/network/protocol/abdrc"
+	"github.com/unicitynetwork/bft-core/network/protocol/certification"
+	"github.com/unicitynetwork/bft-core/rootchain/consensus/storage"
 	rctypes "github.com/unicitynetwork/bft-core/rootchain/consensus/types"
 	"github.com/unicitynetwork/bft-go-base/types"
 )
@@ -290,6 +293,52 @@ func Test_roundInfoHandler(t *testing.T) {
 	})
 }
 
+func Test_certificateHandler(t *testing.T) {
+	db, err := memorydb.New()
+	require.NoError(t, err)
+	archive, err := storage.NewCertificateArchive(db, 0)
+	require.NoError(t, err)
+	require.NoError(t, archive.Add([]*certification.CertificationResponse{{
+		Partition: 1,
+		UC: types.UnicityCertificate{
+			InputRecord: &types.InputRecord{RoundNumber: 5},
+			UnicitySeal: &types.UnicitySeal{RootChainRoundNumber: 10},
+		},
+	}}))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/certificates/{partition}/{shard}", getCertificateHandler(archive, observability.Default(t)))
+
+	testCases := []struct {
+		path      string
+		status    int
+		rootRound uint64
+	}{
+		{path: "/api/v1/certificates/1/0x80?shardRound=5", status: http.StatusOK, rootRound: 10},
+		{path: "/api/v1/certificates/1/0x80?rootRound=12", status: http.StatusOK, rootRound: 10},
+		{path: "/api/v1/certificates/1/0x80?shardRound=6", status: http.StatusNotFound},
+		{path: "/api/v1/certificates/1/0x80?rootRound=9", status: http.StatusNotFound},
+		{path: "/api/v1/certificates/1/0x80", status: http.StatusBadRequest},
+		{path: "/api/v1/certificates/1/0x80?shardRound=5&rootRound=10", status: http.StatusBadRequest},
+		{path: "/api/v1/certificates/1/0x80?shardRound=x", status: http.StatusBadRequest},
+		{path: "/api/v1/certificates/x/0x80?shardRound=5", status: http.StatusBadRequest},
+		{path: "/api/v1/certificates/1/x?shardRound=5", status: http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			res, body := doRequest(t, mux.ServeHTTP, http.MethodGet, tc.path)
+			require.EqualValues(t, tc.status, res.StatusCode, string(body))
+			if tc.status != http.StatusOK {
+				return
+			}
+			var cr certification.CertificationResponse
+			require.NoError(t, json.Unmarshal(body, &cr))
+			require.EqualValues(t, 1, cr.Partition)
+			require.EqualValues(t, tc.rootRound, cr.UC.GetRootRoundNumber())
+		})
+	}
+}
+
 func doRequest(t *testing.T, hf http.HandlerFunc, method, path string) (*http.Response, []byte) {
 	req := httptest.NewRequest(method, path, nil)
 	rec := httptest.NewRecorder()
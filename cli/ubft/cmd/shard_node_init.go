@@ -24,8 +24,9 @@ const (
 
 type (
 	keyConfFlags struct {
-		KeyConfFile string
-		Generate    bool
+		KeyConfFile  string
+		Generate     bool
+		PassphraseFD int // file descriptor to read the passphrase of the encrypted key configuration from
 	}
 
 	shardNodeInitFlags struct {
@@ -91,6 +92,8 @@ func (c *keyConfFlags) addKeyConfFlags(cmd *cobra.Command, enableGenerate bool)
 	}
 	cmd.Flags().StringVarP(&c.KeyConfFile, "key-conf", "k", "",
 		fmt.Sprintf("path to the key configuration file (default: %s)", filepath.Join("$UBFT_HOME", keyConfFileName)))
+	cmd.Flags().IntVar(&c.PassphraseFD, "key-passphrase-fd", -1,
+		fmt.Sprintf("file descriptor to read the passphrase of the encrypted key configuration from (default: $%s or prompt)", envKey(envKeyPassphrase)))
 }
 
 func (c *keyConfFlags) loadKeyConf(baseFlags *baseFlags, generate bool) (ret *partition.KeyConf, err error) {
@@ -111,7 +114,22 @@ func (c *keyConfFlags) loadKeyConf(baseFlags *baseFlags, generate bool) (ret *pa
 		return keyConf, nil
 	}
 
-	return ret, baseFlags.loadConf(keyConfPath, keyConfFileName, &ret)
+	return readKeyConfFile(keyConfPath, func() ([]byte, error) {
+		return readPassphrase(c.PassphraseFD, envKeyPassphrase, "Key configuration passphrase: ", false)
+	})
+}
+
+// readKeyConfFile loads the plain or encrypted key configuration, passphrase is called only for the latter.
+func readKeyConfFile(path string, passphrase func() ([]byte, error)) (*partition.KeyConf, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %q: %w", path, err)
+	}
+	keyConf, err := partition.ParseKeyConf(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %q: %w", path, err)
+	}
+	return keyConf, nil
 }
 
 func generateKeys() (*partition.KeyConf, error) {
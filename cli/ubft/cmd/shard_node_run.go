@@ -17,6 +17,7 @@ import (
 	"github.com/unicitynetwork/bft-core/observability"
 	"github.com/unicitynetwork/bft-core/partition"
 	"github.com/unicitynetwork/bft-core/rpc"
+	"github.com/unicitynetwork/bft-core/state"
 	"github.com/unicitynetwork/bft-core/txsystem"
 	"github.com/unicitynetwork/bft-go-base/types"
 )
@@ -25,6 +26,10 @@ const (
 	shardStoreFileName = "shard.db"
 	blockStoreFileName = "blocks.db"
 	proofStoreFileName = "proof.db"
+	stateStoreFileName = "state.db"
+
+	stateBackendMemory = "memory"
+	stateBackendDB     = "db"
 )
 
 type ShardNodeRunFlags struct {
@@ -34,21 +39,34 @@ type ShardNodeRunFlags struct {
 	trustBaseFlags
 	p2pFlags
 	rpcFlags
+	remoteSignerFlags
 
 	StateFile      string
 	BlockStoreFile string
 	ProofStoreFile string
 	ShardStoreFile string
 
-	WithOwnerIndex bool
-	WithGetUnits   bool
+	WithOwnerIndex     bool
+	WithGetUnits       bool
+	StateArchiveRounds uint64
+	StateBackend       string
+	StateStoreFile     string
+	StateCacheSize     int
+	StateHashWorkers   uint32
+	TxWorkers          uint32
 
 	LedgerReplicationMaxBlocksFetch uint64
 	LedgerReplicationMaxBlocks      uint64
 	LedgerReplicationMaxTx          uint32
 	LedgerReplicationTimeoutMs      uint32
 	BlockSubscriptionTimeoutMs      uint32
+	BlockPushRenewIntervalMs        uint32
+	BlockPushValidators             uint32
+	BlockPushMaxFullNodes           uint32
 	T1TimeoutMs                     uint32
+	UCMaxTimestampDriftMs           uint32
+	ReadyMaxUCAgeMs                 uint32
+	RejectUCAnomalies               bool
 }
 
 func shardNodeRunCmd(baseFlags *baseFlags, shardNodeRunFn nodeRunnable) *cobra.Command {
@@ -70,6 +88,7 @@ func shardNodeRunCmd(baseFlags *baseFlags, shardNodeRunFn nodeRunnable) *cobra.C
 	flags.addShardConfFlags(cmd)
 	flags.addP2PFlags(cmd)
 	flags.addRPCFlags(cmd)
+	flags.addRemoteSignerFlags(cmd)
 
 	cmd.Flags().StringVarP(&flags.StateFile, "state", "", "",
 		fmt.Sprintf("path to the state file (default %s)", filepath.Join("$UBFT_HOME", StateFileName)))
@@ -82,6 +101,18 @@ func shardNodeRunCmd(baseFlags *baseFlags, shardNodeRunFn nodeRunnable) *cobra.C
 
 	cmd.Flags().BoolVar(&flags.WithOwnerIndex, "with-owner-index", true, "enable/disable owner indexer")
 	cmd.Flags().BoolVar(&flags.WithGetUnits, "with-get-units", false, "enable/disable state_getUnits RPC endpoint")
+	cmd.Flags().Uint64Var(&flags.StateArchiveRounds, "state-archive-rounds", 0,
+		"number of past rounds whose committed state is kept in memory for historical state queries (0 disables archive mode)")
+	cmd.Flags().StringVar(&flags.StateBackend, "state-backend", stateBackendMemory,
+		fmt.Sprintf("where the state tree is kept: %q or %q (only the recently used nodes are cached in memory)", stateBackendMemory, stateBackendDB))
+	cmd.Flags().StringVar(&flags.StateStoreFile, "state-db", "",
+		fmt.Sprintf("path to the state tree database of the %q state backend, the state is recovered from the state file only when the database is empty (default %s)", stateBackendDB, filepath.Join("$UBFT_HOME", stateStoreFileName)))
+	cmd.Flags().IntVar(&flags.StateCacheSize, "state-cache-size", 100000,
+		fmt.Sprintf("number of state tree nodes cached in memory by the %q state backend", stateBackendDB))
+	cmd.Flags().Uint32Var(&flags.StateHashWorkers, "state-hash-workers", 1,
+		"number of goroutines calculating the state root hash")
+	cmd.Flags().Uint32Var(&flags.TxWorkers, "tx-workers", 0,
+		"number of workers executing the non-conflicting transactions of a block in parallel (0 disables parallel execution)")
 
 	cmd.Flags().Uint64Var(&flags.LedgerReplicationMaxBlocksFetch, "ledger-replication-max-blocks-fetch", 1000,
 		"maximum number of blocks to query in a single replication request")
@@ -93,8 +124,22 @@ func shardNodeRunCmd(baseFlags *baseFlags, shardNodeRunFn nodeRunnable) *cobra.C
 		"time since last received replication response when to trigger another request (in ms)")
 	cmd.Flags().Uint32Var(&flags.BlockSubscriptionTimeoutMs, "block-subscription-timeout", 3000,
 		"time since last received block when when to trigger recovery (in ms) for non-validating nodes")
+	cmd.Flags().Uint32Var(&flags.BlockPushRenewIntervalMs, "block-push-renew-interval", uint32(partition.DefaultBlockPushRenewInterval.Milliseconds()),
+		"how often non-validating node renews its block push registration with validators (in ms)")
+	cmd.Flags().Uint32Var(&flags.BlockPushValidators, "block-push-validators", partition.DefaultBlockPushValidators,
+		"number of validators non-validating node registers with for the block push")
+	cmd.Flags().Uint32Var(&flags.BlockPushMaxFullNodes, "block-push-max-full-nodes", partition.DefaultBlockPushMaxFullNodes,
+		"maximum number of non-validating nodes validator pushes the blocks to")
 	cmd.Flags().Uint32Var(&flags.T1TimeoutMs, "t1-timeout", partition.DefaultT1Timeout, "T1 timeout (consensus parameter)")
 
+	cmd.Flags().Uint32Var(&flags.UCMaxTimestampDriftMs, "uc-max-timestamp-drift", uint32(partition.DefaultUCMaxTimestampDrift.Milliseconds()),
+		"max allowed difference between the unicity certificate timestamp and the local time (in ms)")
+	cmd.Flags().BoolVar(&flags.RejectUCAnomalies, "uc-reject-anomalies", false,
+		"do not process unicity certificates failing the timestamp and root round sanity checks")
+
+	cmd.Flags().Uint32Var(&flags.ReadyMaxUCAgeMs, "ready-max-uc-age", uint32(rpc.DefaultReadyMaxUCAge.Milliseconds()),
+		"readiness check fails when the latest unicity certificate is older (in ms)")
+
 	hideFlags(cmd, "t1-timeout")
 	return cmd
 }
@@ -121,6 +166,7 @@ func shardNodeRun(ctx context.Context, flags *ShardNodeRunFlags) error {
 		routers := []rpc.Registrar{
 			rpc.MetricsEndpoints(obs.PrometheusRegisterer()),
 			rpc.NodeEndpoints(node, obs),
+			rpc.HealthEndpoints(rpc.ShardNodeHealth(node), time.Duration(flags.ReadyMaxUCAgeMs)*time.Millisecond, log),
 		}
 		if flags.rpcFlags.Router != nil {
 			routers = append(routers, flags.rpcFlags.Router)
@@ -208,6 +254,10 @@ func createNode(ctx context.Context, flags *ShardNodeRunFlags) (*partition.Node,
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to calculate nodeID: %w", err)
 	}
+	signer, err := flags.signer(ctx, keyConf)
+	if err != nil {
+		return nil, nil, err
+	}
 	log := flags.observe.Logger().With(
 		logger.NodeID(nodeID),
 		logger.Shard(shardConf.PartitionID, shardConf.ShardID))
@@ -228,6 +278,7 @@ func createNode(ctx context.Context, flags *ShardNodeRunFlags) (*partition.Node,
 		shardConf,
 		trustBase,
 		obs,
+		partition.WithSigner(signer),
 		partition.WithAddress(flags.p2pFlags.Address),
 		partition.WithAnnounceAddresses(flags.AnnounceAddresses),
 		partition.WithBootstrapAddresses(flags.BootstrapAddresses),
@@ -242,7 +293,15 @@ func createNode(ctx context.Context, flags *ShardNodeRunFlags) (*partition.Node,
 		partition.WithProofIndex(proofStore, 20),
 		partition.WithOwnerIndex(ownerIndexer),
 		partition.WithBlockSubscriptionTimeout(time.Duration(flags.BlockSubscriptionTimeoutMs)*time.Millisecond),
+		partition.WithBlockPushParams(
+			time.Duration(flags.BlockPushRenewIntervalMs)*time.Millisecond,
+			// registration survives a couple of missed renewals
+			3*time.Duration(flags.BlockPushRenewIntervalMs)*time.Millisecond,
+			int(flags.BlockPushValidators),
+			int(flags.BlockPushMaxFullNodes)),
 		partition.WithT1Timeout(time.Duration(flags.T1TimeoutMs)*time.Millisecond),
+		partition.WithUnicityCertificateChecks(time.Duration(flags.UCMaxTimestampDriftMs)*time.Millisecond, flags.RejectUCAnomalies),
+		partition.WithTracePropagation(flags.TracePropagation),
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create node configuration: %w", err)
@@ -267,6 +326,38 @@ func createTxSystem(flags *ShardNodeRunFlags, nodeConf *partition.NodeConf) (txs
 	return partition.CreateTxSystem(flags, nodeConf)
 }
 
+/*
+loadState loads the state from the state file, the state tree is kept in the
+backend selected by the flags. The "db" backend keeps the committed state across
+restarts, the state file is only loaded when the database is empty.
+*/
+func (f *ShardNodeRunFlags) loadState(udc state.UnitDataConstructor) (*state.State, *state.Header, error) {
+	opts := []state.Option{state.WithArchive(f.StateArchiveRounds), state.WithHashWorkers(int(f.StateHashWorkers))}
+	switch f.StateBackend {
+	case stateBackendMemory:
+	case stateBackendDB:
+		db, err := f.initStore(f.StateStoreFile, stateStoreFileName)
+		if err != nil {
+			return nil, nil, err
+		}
+		store, err := state.NewNodeStore(db, udc, f.StateCacheSize)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create state node store: %w", err)
+		}
+		if !store.IsEmpty() {
+			s, header, err := state.NewStoredState(store, opts...)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to load state from %q: %w", f.PathWithDefault(f.StateStoreFile, stateStoreFileName), err)
+			}
+			return s, header, nil
+		}
+		opts = append(opts, state.WithNodeStore(store))
+	default:
+		return nil, nil, fmt.Errorf("unsupported state backend %q", f.StateBackend)
+	}
+	return loadStateFile(f.PathWithDefault(f.StateFile, StateFileName), udc, opts...)
+}
+
 func (f *ShardNodeRunFlags) loadShardConf() (ret *types.PartitionDescriptionRecord, err error) {
 	return ret, f.loadConf(f.ShardConfFile, shardConfFileName, &ret)
 }
@@ -110,6 +110,16 @@ func TestShardNodeRun_EnvAndFlags(t *testing.T) {
 				return f
 			}(),
 		},
+		{
+			args: "shard-node run --state-backend=db --state-db=/tmp/state.db --state-cache-size=10",
+			expectedConfig: func() *ShardNodeRunFlags {
+				f := defaultFlags()
+				f.StateBackend = stateBackendDB
+				f.StateStoreFile = "/tmp/state.db"
+				f.StateCacheSize = 10
+				return f
+			}(),
+		},
 		// Money tx system configuration from ENV
 		{
 			args: "shard-node run",
@@ -252,6 +262,7 @@ func defaultFlags() *ShardNodeRunFlags {
 		},
 	}
 	flags.KeyConfFile = ""
+	flags.PassphraseFD = -1
 	flags.ShardConfFile = ""
 	flags.TrustBaseFile = ""
 	flags.Address = "/ip4/127.0.0.1/tcp/26652"
@@ -260,8 +271,14 @@ func defaultFlags() *ShardNodeRunFlags {
 	flags.LedgerReplicationMaxTx = 10000
 	flags.LedgerReplicationTimeoutMs = 1500
 	flags.BlockSubscriptionTimeoutMs = 3000
+	flags.BlockPushRenewIntervalMs = uint32(partition.DefaultBlockPushRenewInterval.Milliseconds())
+	flags.BlockPushValidators = partition.DefaultBlockPushValidators
+	flags.BlockPushMaxFullNodes = partition.DefaultBlockPushMaxFullNodes
 	flags.WithOwnerIndex = true
 	flags.WithGetUnits = false
+	flags.StateBackend = stateBackendMemory
+	flags.StateCacheSize = 100000
+	flags.StateHashWorkers = 1
 	flags.rpcFlags.Address = ""
 	flags.MaxHeaderBytes = http.DefaultMaxHeaderBytes
 	flags.MaxBodyBytes = rpc.DefaultMaxBodyBytes
@@ -269,6 +286,8 @@ func defaultFlags() *ShardNodeRunFlags {
 	flags.BatchResponseSizeLimit = rpc.DefaultBatchResponseSizeLimit
 	flags.StateRpcRateLimit = 20
 	flags.T1TimeoutMs = partition.DefaultT1Timeout
+	flags.UCMaxTimestampDriftMs = uint32(partition.DefaultUCMaxTimestampDrift.Milliseconds())
+	flags.ReadyMaxUCAgeMs = uint32(rpc.DefaultReadyMaxUCAge.Milliseconds())
 	flags.StateRpcResponseItemLimit = 10000
 	flags.BootstrapConnectRetryCount = 10
 	flags.BootstrapConnectRetryDelay = 1
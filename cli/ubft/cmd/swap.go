@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unicitynetwork/bft-core/rpc/client"
+	"github.com/unicitynetwork/bft-core/swap"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+type (
+	swapFlags struct {
+		*baseFlags
+		trustBaseFlags
+
+		Legs         [2]swapLegFlags
+		PollInterval time.Duration
+	}
+
+	swapLegFlags struct {
+		RPCAddress string
+		LockTx     string // CBOR encoded state locking transaction
+		ExecuteTx  string // CBOR encoded transaction executing the lock
+		RollbackTx string // CBOR encoded transaction rolling back the lock
+	}
+)
+
+func newSwapCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &swapFlags{baseFlags: baseFlags}
+	var cmd = &cobra.Command{
+		Use:   "swap",
+		Short: "Coordinates atomic swap between two partitions",
+		Long: `Coordinates atomic swap of units on two partitions ("a" and "b"). Both
+parties lock their units with state locking transactions; the coordinator
+submits the locks (unless already in a block) and waits for their proofs. When
+both locks succeed the "execute" transactions are submitted, otherwise the
+successful locks are rolled back with the "rollback" transactions. When the
+rollback transaction is not given the coordinator waits for the lock to expire
+(the lock has expiry).
+
+The transaction proofs are verified using the trust base ("--trust-base") and the
+unlock transactions are submitted only when they unlock the units locked according
+to the verified lock proofs.
+
+The unlock transactions must be signed in advance with the state unlock proof
+(kind byte followed by the predicate argument, ie hash-lock secret and signature). Binary
+inputs are hex encoded (0x prefixed) or, when prefixed with "@", read from the
+file, ie "--lock-tx-a @lock.cbor".`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return swapRun(cmd.Context(), cmd.OutOrStdout(), flags)
+		},
+	}
+	for i, name := range []string{"a", "b"} {
+		leg := &flags.Legs[i]
+		cmd.Flags().StringVar(&leg.RPCAddress, "rpc-"+name, "", fmt.Sprintf("RPC URL of the shard node of the leg %q", name))
+		cmd.Flags().StringVar(&leg.LockTx, "lock-tx-"+name, "", "CBOR encoded state locking transaction")
+		cmd.Flags().StringVar(&leg.ExecuteTx, "execute-tx-"+name, "", "CBOR encoded transaction executing the locked transaction")
+		cmd.Flags().StringVar(&leg.RollbackTx, "rollback-tx-"+name, "", "CBOR encoded transaction rolling back the lock")
+		for _, flag := range []string{"rpc-", "lock-tx-", "execute-tx-"} {
+			if err := cmd.MarkFlagRequired(flag + name); err != nil {
+				panic(err)
+			}
+		}
+	}
+	cmd.Flags().DurationVar(&flags.PollInterval, "poll-interval", time.Second, "how often shards are polled for transaction proofs")
+	flags.addTrustBaseFlags(cmd)
+	return cmd
+}
+
+func swapRun(ctx context.Context, out io.Writer, flags *swapFlags) error {
+	names := []string{"a", "b"}
+	legs := make([]*swap.Leg, len(flags.Legs))
+	for i, lf := range flags.Legs {
+		c, err := client.New(ctx, lf.RPCAddress)
+		if err != nil {
+			return fmt.Errorf("leg %q: %w", names[i], err)
+		}
+		defer c.Close()
+		if legs[i], err = lf.leg(i, names[i], c); err != nil {
+			return fmt.Errorf("leg %q: %w", names[i], err)
+		}
+	}
+
+	// the unlock transactions are submitted based on the lock proofs, the proofs
+	// must be verified instead of trusting the RPC nodes
+	tb, err := flags.loadTrustBase(flags.baseFlags)
+	if err != nil {
+		return fmt.Errorf("loading trust base: %w", err)
+	}
+	opts := []swap.Option{
+		swap.WithPollInterval(flags.PollInterval),
+		// same as the shard node, the configured trust base is used for any epoch
+		// until epoch switching is implemented
+		swap.WithTrustBase(func(epoch uint64) (types.RootTrustBase, error) { return tb, nil }),
+	}
+	coordinator, err := swap.NewCoordinator(legs, flags.observe.Logger(), opts...)
+	if err != nil {
+		return fmt.Errorf("creating swap coordinator: %w", err)
+	}
+	res, runErr := coordinator.Run(ctx)
+	if res != nil {
+		fmt.Fprintf(out, "executed: %t\n", res.Executed)
+		for i, lr := range res.Legs {
+			for _, p := range []struct {
+				name  string
+				proof *types.TxRecordProof
+			}{{"lock", lr.LockProof}, {"unlock", lr.UnlockProof}} {
+				if p.proof == nil {
+					continue
+				}
+				b, err := types.Cbor.Marshal(p.proof)
+				if err != nil {
+					return fmt.Errorf("encoding %s proof: %w", p.name, err)
+				}
+				fmt.Fprintf(out, "%s %s proof (status %d): %s\n", names[i], p.name, p.proof.TxStatus(), hex.Encode(b))
+			}
+		}
+	}
+	if runErr != nil {
+		return fmt.Errorf("swap failed: %w", runErr)
+	}
+	return nil
+}
+
+func (f *swapLegFlags) leg(idx int, name string, c swap.Client) (*swap.Leg, error) {
+	lockTx, err := decodeTxOrder(f.LockTx)
+	if err != nil {
+		return nil, fmt.Errorf("reading lock transaction: %w", err)
+	}
+	executeTx, err := decodeTxOrder(f.ExecuteTx)
+	if err != nil {
+		return nil, fmt.Errorf("reading execute transaction: %w", err)
+	}
+	var rollbackTx *types.TransactionOrder
+	if f.RollbackTx != "" {
+		if rollbackTx, err = decodeTxOrder(f.RollbackTx); err != nil {
+			return nil, fmt.Errorf("reading rollback transaction: %w", err)
+		}
+	}
+	return &swap.Leg{
+		Name:   name,
+		Client: c,
+		LockTx: lockTx,
+		Unlock: swap.PresignedUnlock(idx, executeTx, rollbackTx),
+	}, nil
+}
+
+func decodeTxOrder(s string) (*types.TransactionOrder, error) {
+	data, err := decodeHexOrFile(s)
+	if err != nil {
+		return nil, err
+	}
+	txo := &types.TransactionOrder{}
+	if err := types.Cbor.Unmarshal(data, txo); err != nil {
+		return nil, fmt.Errorf("decoding transaction order: %w", err)
+	}
+	return txo, nil
+}
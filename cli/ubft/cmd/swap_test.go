@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	testblock "github.com/unicitynetwork/bft-core/internal/testutils/block"
+	testobserve "github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	testsig "github.com/unicitynetwork/bft-core/internal/testutils/sig"
+	"github.com/unicitynetwork/bft-core/internal/testutils/trustbase"
+	"github.com/unicitynetwork/bft-core/partition"
+	"github.com/unicitynetwork/bft-core/predicates/templates"
+	"github.com/unicitynetwork/bft-core/rpc"
+	"github.com/unicitynetwork/bft-core/txsystem"
+	testtransaction "github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
+	abcrypto "github.com/unicitynetwork/bft-go-base/crypto"
+	"github.com/unicitynetwork/bft-go-base/txsystem/nop"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+	"github.com/unicitynetwork/bft-go-base/util"
+)
+
+func TestSwap(t *testing.T) {
+	secret := []byte("secret")
+	owner, verifier := testsig.CreateSignerAndVerifier(t)
+	pubKey, err := verifier.MarshalPublicKey()
+	require.NoError(t, err)
+	ownerPKH := sha256.Sum256(pubKey)
+	tbSigner, tbVerifier := testsig.CreateSignerAndVerifier(t)
+	trustBaseFile := filepath.Join(t.TempDir(), trustBaseFileName)
+	require.NoError(t, util.WriteJsonFile(trustBaseFile, trustbase.NewTrustBase(t, tbVerifier)))
+	txHex := func(tx *types.TransactionOrder) string {
+		b, err := tx.MarshalCBOR()
+		require.NoError(t, err)
+		return string(hex.Encode(b))
+	}
+	newLeg := func(t *testing.T, name string) []string {
+		lockTx := testtransaction.NewTransactionOrder(t,
+			testtransaction.WithClientMetadata(&types.ClientMetadata{Timeout: 10}),
+			testtransaction.WithStateLock(&types.StateLock{
+				ExecutionPredicate: templates.NewHashLockBytesFromSecret(secret, ownerPKH[:]),
+				RollbackPredicate:  templates.NewTimeLockBytes(20),
+				Expiry:             20,
+			}),
+		)
+		executeTx := testtransaction.NewTransactionOrder(t,
+			testtransaction.WithUnitID(lockTx.UnitID),
+			testtransaction.WithTransactionType(nop.TransactionTypeNOP),
+			testtransaction.WithAttributes(&nop.Attributes{}),
+			testtransaction.WithClientMetadata(&types.ClientMetadata{Timeout: 10}),
+		)
+		proof, err := templates.NewHashLockProofBytes(secret, testsig.NewStateLockProofSignature(t, executeTx, owner))
+		require.NoError(t, err)
+		executeTx.StateUnlock = append([]byte{byte(txsystem.StateUnlockExecute)}, proof...)
+		srv := newSwapTestServer(t, tbSigner)
+		return []string{"--rpc-" + name, srv.URL, "--lock-tx-" + name, txHex(lockTx), "--execute-tx-" + name, txHex(executeTx)}
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		cmd := New(testobserve.NewFactory(t))
+		out := &bytes.Buffer{}
+		cmd.baseCmd.SetOut(out)
+		args := append([]string{"swap", "--home", t.TempDir(), "--poll-interval", "1ms", "--trust-base", trustBaseFile}, newLeg(t, "a")...)
+		cmd.baseCmd.SetArgs(append(args, newLeg(t, "b")...))
+		require.NoError(t, cmd.Execute(context.Background()))
+		require.Contains(t, out.String(), "executed: true\n")
+		require.Contains(t, out.String(), "a lock proof (status 1): 0x")
+		require.Contains(t, out.String(), "a unlock proof (status 1): 0x")
+		require.Contains(t, out.String(), "b lock proof (status 1): 0x")
+		require.Contains(t, out.String(), "b unlock proof (status 1): 0x")
+	})
+
+	t.Run("proof not signed by the trust base", func(t *testing.T) {
+		otherSigner, _ := testsig.CreateSignerAndVerifier(t)
+		srv := newSwapTestServer(t, otherSigner)
+		legB := newLeg(t, "b")
+		legB[1] = srv.URL
+		cmd := New(testobserve.NewFactory(t))
+		args := append([]string{"swap", "--home", t.TempDir(), "--poll-interval", "1ms", "--trust-base", trustBaseFile}, newLeg(t, "a")...)
+		cmd.baseCmd.SetArgs(append(args, legB...))
+		require.ErrorContains(t, cmd.Execute(context.Background()), `swap failed: locking b: invalid lock transaction proof:`)
+	})
+
+	t.Run("missing trust base", func(t *testing.T) {
+		cmd := New(testobserve.NewFactory(t))
+		args := append([]string{"swap", "--home", t.TempDir(), "--poll-interval", "1ms"}, newLeg(t, "a")...)
+		cmd.baseCmd.SetArgs(append(args, newLeg(t, "b")...))
+		require.ErrorContains(t, cmd.Execute(context.Background()), `loading trust base:`)
+	})
+
+	t.Run("missing flags", func(t *testing.T) {
+		cmd := New(testobserve.NewFactory(t))
+		cmd.baseCmd.SetArgs(append([]string{"swap", "--home", t.TempDir()}, newLeg(t, "a")...))
+		require.ErrorContains(t, cmd.Execute(context.Background()), `required flag(s) "execute-tx-b", "lock-tx-b", "rpc-b" not set`)
+	})
+
+	t.Run("invalid transaction", func(t *testing.T) {
+		cmd := New(testobserve.NewFactory(t))
+		args := append([]string{"swap", "--home", t.TempDir()}, newLeg(t, "a")...)
+		cmd.baseCmd.SetArgs(append(args, "--rpc-b", "http://localhost:1", "--lock-tx-b", "0x01", "--execute-tx-b", "0x01"))
+		require.ErrorContains(t, cmd.Execute(context.Background()), `leg "b": reading lock transaction: decoding transaction order:`)
+	})
+}
+
+/*
+swapTestAPI is "state" RPC API which includes submitted transactions into block
+immediately.
+*/
+type swapTestAPI struct {
+	t      *testing.T
+	signer abcrypto.Signer // signer of the UCs
+	mu     sync.Mutex
+	proofs map[string]*types.TxRecordProof
+}
+
+func newSwapTestServer(t *testing.T, signer abcrypto.Signer) *httptest.Server {
+	server := ethrpc.NewServer()
+	require.NoError(t, server.RegisterName("state", &swapTestAPI{t: t, signer: signer, proofs: map[string]*types.TxRecordProof{}}))
+	srv := httptest.NewServer(server)
+	t.Cleanup(func() {
+		srv.Close()
+		server.Stop()
+	})
+	return srv
+}
+
+func (api *swapTestAPI) GetRoundInfo(ctx context.Context) (*partition.RoundInfo, error) {
+	return &partition.RoundInfo{RoundNumber: 1}, nil
+}
+
+func (api *swapTestAPI) SendTransaction(ctx context.Context, txBytes hex.Bytes) (hex.Bytes, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	tx := &types.TransactionOrder{}
+	if err := types.Cbor.Unmarshal(txBytes, tx); err != nil {
+		return nil, err
+	}
+	txHash, err := tx.Hash(crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	txr := &types.TransactionRecord{
+		Version:          1,
+		TransactionOrder: types.TransactionOrderCBOR(txBytes),
+		ServerMetadata:   &types.ServerMetadata{TargetUnits: []types.UnitID{tx.UnitID}, SuccessIndicator: types.TxStatusSuccessful},
+	}
+	api.proofs[string(txHash)] = testblock.CreateTxRecordProof(api.t, txr, api.signer)
+	return txHash, nil
+}
+
+func (api *swapTestAPI) GetTransactionProof(ctx context.Context, txHash hex.Bytes) (*rpc.TransactionRecordAndProof, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	proof, ok := api.proofs[string(txHash)]
+	if !ok {
+		return nil, nil
+	}
+	b, err := types.Cbor.Marshal(proof)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.TransactionRecordAndProof{TxRecordProof: b}, nil
+}
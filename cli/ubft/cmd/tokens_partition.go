@@ -50,8 +50,7 @@ func (p *TokensPartition) NewGenesisState(pdr *types.PartitionDescriptionRecord)
 }
 
 func (p *TokensPartition) CreateTxSystem(flags *ShardNodeRunFlags, nodeConf *partition.NodeConf) (txsystem.TransactionSystem, error) {
-	stateFilePath := flags.PathWithDefault(flags.StateFile, StateFileName)
-	state, header, err := loadStateFile(stateFilePath, func(ui types.UnitID) (types.UnitData, error) {
+	s, header, err := flags.loadState(func(ui types.UnitID) (types.UnitData, error) {
 		return tokenssdk.NewUnitData(ui, nodeConf.ShardConf())
 	})
 	if err != nil {
@@ -63,6 +62,38 @@ func (p *TokensPartition) CreateTxSystem(flags *ShardNodeRunFlags, nodeConf *par
 		return nil, fmt.Errorf("failed to validate tokens partition params: %w", err)
 	}
 
+	// the parallel execution workers get their own predicate engines
+	newTxSystem := func(s *state.State, observe txsystem.Observability, opts ...tokens.Option) (*txsystem.GenericTxSystem, error) {
+		predEng, err := newTokensPredicateExecutor(nodeConf, observe)
+		if err != nil {
+			return nil, err
+		}
+		return tokens.NewTxSystem(
+			*nodeConf.ShardConf(),
+			observe,
+			append([]tokens.Option{
+				tokens.WithHashAlgorithm(nodeConf.HashAlgorithm()),
+				tokens.WithTrustBase(nodeConf.TrustBase()),
+				tokens.WithState(s),
+				tokens.WithPredicateExecutor(predEng),
+				tokens.WithAdminOwnerPredicate(params.AdminOwnerPredicate),
+				tokens.WithFeelessMode(params.FeelessMode),
+			}, opts...)...,
+		)
+	}
+	txs, err := newTxSystem(s, nodeConf.Observability(),
+		tokens.WithExecutedTransactions(header.ExecutedTransactions),
+		tokens.WithParallelExecution(int(flags.TxWorkers), func(s *state.State, observe txsystem.Observability) (*txsystem.GenericTxSystem, error) {
+			return newTxSystem(s, observe)
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tokens tx system: %w", err)
+	}
+	return txs, nil
+}
+
+func newTokensPredicateExecutor(nodeConf *partition.NodeConf, observe txsystem.Observability) (predicates.PredicateExecutor, error) {
 	// register all unit- and attribute types from token tx system
 	enc, err := encoder.New(nodeConf.PartitionID(), tokenc.RegisterTxAttributeEncoders, tokenc.RegisterUnitDataEncoders, tokenc.RegisterAuthProof)
 	if err != nil {
@@ -75,7 +106,7 @@ func (p *TokensPartition) CreateTxSystem(flags *ShardNodeRunFlags, nodeConf *par
 	// tx system engine.
 	// it's safe to share template engine as it doesn't have internal state and
 	// predicate executions happen in serialized manner anyway
-	templateEng, err := templates.New(nodeConf.Observability())
+	templateEng, err := templates.New(observe)
 	if err != nil {
 		return nil, fmt.Errorf("creating predicate templates executor: %w", err)
 	}
@@ -83,7 +114,7 @@ func (p *TokensPartition) CreateTxSystem(flags *ShardNodeRunFlags, nodeConf *par
 	if err != nil {
 		return nil, fmt.Errorf("creating predicate executor for WASM engine: %w", err)
 	}
-	wasmEng, err := wasm.New(enc, tpe.Execute, nodeConf.Orchestration(), nodeConf.Observability())
+	wasmEng, err := wasm.New(enc, tpe.Execute, nodeConf.Orchestration(), observe)
 	if err != nil {
 		return nil, fmt.Errorf("creating predicate WASM executor: %w", err)
 	}
@@ -91,20 +122,5 @@ func (p *TokensPartition) CreateTxSystem(flags *ShardNodeRunFlags, nodeConf *par
 	if err != nil {
 		return nil, fmt.Errorf("creating predicate executor: %w", err)
 	}
-
-	txs, err := tokens.NewTxSystem(
-		*nodeConf.ShardConf(),
-		nodeConf.Observability(),
-		tokens.WithHashAlgorithm(nodeConf.HashAlgorithm()),
-		tokens.WithTrustBase(nodeConf.TrustBase()),
-		tokens.WithState(state),
-		tokens.WithPredicateExecutor(predEng.Execute),
-		tokens.WithAdminOwnerPredicate(params.AdminOwnerPredicate),
-		tokens.WithFeelessMode(params.FeelessMode),
-		tokens.WithExecutedTransactions(header.ExecutedTransactions),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create tokens tx system: %w", err)
-	}
-	return txs, nil
+	return predEng.Execute, nil
 }
@@ -0,0 +1,754 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unicitynetwork/bft-core/rpc/client"
+	"github.com/unicitynetwork/bft-core/swap"
+	abcrypto "github.com/unicitynetwork/bft-go-base/crypto"
+	"github.com/unicitynetwork/bft-go-base/predicates/templates"
+	"github.com/unicitynetwork/bft-go-base/txsystem/fc"
+	"github.com/unicitynetwork/bft-go-base/txsystem/money"
+	"github.com/unicitynetwork/bft-go-base/txsystem/tokens"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+type (
+	txFlags struct {
+		*baseFlags
+		keyConfFlags
+		shardConfFlags
+		trustBaseFlags
+
+		RPCAddress        string
+		FeeCreditRecordID string
+		MaxFee            uint64
+		Timeout           uint64 // number of rounds the transaction is valid for
+		PollInterval      time.Duration
+	}
+
+	/*
+		txSession holds everything needed to build, sign and submit transactions
+		to a single shard.
+	*/
+	txSession struct {
+		client    *client.StateAPIClient
+		shardConf *types.PartitionDescriptionRecord
+		trustBase types.RootTrustBase
+		signer    abcrypto.Signer
+		pubKey    []byte
+		fcrID     types.UnitID // fee credit record paying for the transactions, nil in feeless mode
+		flags     *txFlags
+		out       io.Writer
+	}
+)
+
+func newTxCmd(baseFlags *baseFlags) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "tx",
+		Short: "Tools to create, sign and submit transactions",
+		Long: `Tools to create, sign and submit transactions. Transactions are signed with
+the signing key of the key configuration file ("--key-conf"), unit owner and
+fee credit record predicates must be either "always true" or P2PKH of the key.
+The command waits until the transaction is included in a block and verifies
+the transaction proof against the trust base.`,
+	}
+	cmd.AddCommand(txKeyCmd(baseFlags))
+	cmd.AddCommand(txTransferCmd(baseFlags))
+	cmd.AddCommand(txSplitCmd(baseFlags))
+	cmd.AddCommand(txAddFeeCreditCmd(baseFlags))
+	cmd.AddCommand(txDefineTokenCmd(baseFlags))
+	cmd.AddCommand(txMintTokenCmd(baseFlags))
+	cmd.AddCommand(txTransferTokenCmd(baseFlags))
+	return cmd
+}
+
+func txKeyCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &txFlags{baseFlags: baseFlags}
+	var cmd = &cobra.Command{
+		Use:   "key",
+		Short: "Prints the public key and the owner predicate of the key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keyConf, err := flags.loadKeyConf(flags.baseFlags, flags.Generate)
+			if err != nil {
+				return err
+			}
+			_, pubKey, err := txSigner(keyConf.SigKey.PrivateKey)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "public key: %s\nowner predicate: %s\n", hex.Encode(pubKey), hex.Encode(templates.NewP2pkh256BytesFromKey(pubKey)))
+			return nil
+		},
+	}
+	flags.addKeyConfFlags(cmd, true)
+	return cmd
+}
+
+func txTransferCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &txFlags{baseFlags: baseFlags}
+	var unitID, newOwner string
+	var cmd = &cobra.Command{
+		Use:   "transfer",
+		Short: "Transfers a bill to a new owner",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTx(cmd, flags, func(ctx context.Context, s *txSession) error {
+				return s.transferBill(ctx, unitID, newOwner)
+			})
+		},
+	}
+	flags.addTxFlags(cmd)
+	cmd.Flags().StringVar(&unitID, "unit-id", "", "ID of the bill to transfer")
+	cmd.Flags().StringVar(&newOwner, "new-owner", "", "owner predicate of the receiver (default: P2PKH of the key)")
+	mustMarkFlagsRequired(cmd, "unit-id")
+	return cmd
+}
+
+func txSplitCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &txFlags{baseFlags: baseFlags}
+	var unitID, newOwner string
+	var amount uint64
+	var cmd = &cobra.Command{
+		Use:   "split",
+		Short: "Splits a new bill off from the bill",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTx(cmd, flags, func(ctx context.Context, s *txSession) error {
+				return s.splitBill(ctx, unitID, amount, newOwner)
+			})
+		},
+	}
+	flags.addTxFlags(cmd)
+	cmd.Flags().StringVar(&unitID, "unit-id", "", "ID of the bill to split")
+	cmd.Flags().Uint64Var(&amount, "amount", 0, "value of the new bill")
+	cmd.Flags().StringVar(&newOwner, "new-owner", "", "owner predicate of the new bill (default: P2PKH of the key)")
+	mustMarkFlagsRequired(cmd, "unit-id", "amount")
+	return cmd
+}
+
+func txAddFeeCreditCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &txFlags{baseFlags: baseFlags}
+	var unitID, moneyRPCAddress, moneyShardConf string
+	var amount uint64
+	var cmd = &cobra.Command{
+		Use:   "add-fee-credit",
+		Short: "Adds fee credit to the partition",
+		Long: `Transfers fee credit from a money partition bill to the fee credit record of the
+target partition ("--rpc-address" and "--shard-conf"). When "--fee-credit-record-id"
+is given credit is added to the existing record, otherwise a new record owned by
+the key is created.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTx(cmd, flags, func(ctx context.Context, s *txSession) error {
+				ms, err := s.moneySession(ctx, moneyRPCAddress, moneyShardConf)
+				if err != nil {
+					return err
+				}
+				defer ms.client.Close()
+				return s.addFeeCredit(ctx, ms, unitID, amount)
+			})
+		},
+	}
+	flags.addTxFlags(cmd)
+	cmd.Flags().StringVar(&unitID, "unit-id", "", "ID of the money partition bill to transfer the fee credit from")
+	cmd.Flags().Uint64Var(&amount, "amount", 0, "amount of fee credit to transfer")
+	cmd.Flags().StringVar(&moneyRPCAddress, "money-rpc-address", "", "RPC URL of the money partition shard node (default: --rpc-address)")
+	cmd.Flags().StringVar(&moneyShardConf, "money-shard-conf", "", "path to money partition shard conf (default: --shard-conf)")
+	mustMarkFlagsRequired(cmd, "unit-id", "amount")
+	return cmd
+}
+
+func txDefineTokenCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &txFlags{baseFlags: baseFlags}
+	attr := &tokens.DefineFungibleTokenAttributes{}
+	var cmd = &cobra.Command{
+		Use:   "define-token",
+		Short: "Defines a new fungible token type",
+		Long: `Defines a new fungible token type. Minting tokens of the type and defining
+subtypes is allowed for the key, tokens of the type are not restricted by the
+type owner predicate.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTx(cmd, flags, func(ctx context.Context, s *txSession) error {
+				return s.defineToken(ctx, attr)
+			})
+		},
+	}
+	flags.addTxFlags(cmd)
+	cmd.Flags().StringVar(&attr.Symbol, "symbol", "", "symbol (short name) of the token type")
+	cmd.Flags().StringVar(&attr.Name, "name", "", "name of the token type")
+	cmd.Flags().Uint32Var(&attr.DecimalPlaces, "decimals", 8, "number of decimal places")
+	mustMarkFlagsRequired(cmd, "symbol")
+	return cmd
+}
+
+func txMintTokenCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &txFlags{baseFlags: baseFlags}
+	var typeID, owner string
+	var value uint64
+	var cmd = &cobra.Command{
+		Use:   "mint-token",
+		Short: "Mints a new fungible token",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTx(cmd, flags, func(ctx context.Context, s *txSession) error {
+				return s.mintToken(ctx, typeID, value, owner)
+			})
+		},
+	}
+	flags.addTxFlags(cmd)
+	cmd.Flags().StringVar(&typeID, "type-id", "", "ID of the token type")
+	cmd.Flags().Uint64Var(&value, "value", 0, "value of the token")
+	cmd.Flags().StringVar(&owner, "owner", "", "owner predicate of the token (default: P2PKH of the key)")
+	mustMarkFlagsRequired(cmd, "type-id", "value")
+	return cmd
+}
+
+func txTransferTokenCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &txFlags{baseFlags: baseFlags}
+	var unitID, newOwner string
+	var cmd = &cobra.Command{
+		Use:   "transfer-token",
+		Short: "Transfers a fungible token to a new owner",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTx(cmd, flags, func(ctx context.Context, s *txSession) error {
+				return s.transferToken(ctx, unitID, newOwner)
+			})
+		},
+	}
+	flags.addTxFlags(cmd)
+	cmd.Flags().StringVar(&unitID, "unit-id", "", "ID of the token to transfer")
+	cmd.Flags().StringVar(&newOwner, "new-owner", "", "owner predicate of the receiver (default: P2PKH of the key)")
+	mustMarkFlagsRequired(cmd, "unit-id")
+	return cmd
+}
+
+func (f *txFlags) addTxFlags(cmd *cobra.Command) {
+	f.addKeyConfFlags(cmd, true)
+	f.addShardConfFlags(cmd)
+	f.addTrustBaseFlags(cmd)
+	cmd.Flags().StringVar(&f.RPCAddress, "rpc-address", "", `RPC URL of the shard node, ie "http://localhost:26866/rpc"`)
+	cmd.Flags().StringVar(&f.FeeCreditRecordID, "fee-credit-record-id", "", "ID of the fee credit record paying for the transaction (not needed in feeless mode)")
+	cmd.Flags().Uint64Var(&f.MaxFee, "max-fee", 10, "maximum transaction fee")
+	cmd.Flags().Uint64Var(&f.Timeout, "timeout", 10, "number of rounds the transaction is valid for")
+	cmd.Flags().DurationVar(&f.PollInterval, "poll-interval", 500*time.Millisecond, "how often shard is polled for the transaction proof")
+	mustMarkFlagsRequired(cmd, "rpc-address")
+}
+
+func mustMarkFlagsRequired(cmd *cobra.Command, names ...string) {
+	for _, name := range names {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func runTx(cmd *cobra.Command, flags *txFlags, run func(ctx context.Context, s *txSession) error) error {
+	s, err := newTxSession(cmd.Context(), flags, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+	defer s.client.Close()
+	return run(cmd.Context(), s)
+}
+
+func newTxSession(ctx context.Context, flags *txFlags, out io.Writer) (*txSession, error) {
+	keyConf, err := flags.loadKeyConf(flags.baseFlags, flags.Generate)
+	if err != nil {
+		return nil, err
+	}
+	signer, pubKey, err := txSigner(keyConf.SigKey.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	shardConf, err := flags.loadShardConf(flags.baseFlags)
+	if err != nil {
+		return nil, err
+	}
+	trustBase, err := flags.loadTrustBase(flags.baseFlags)
+	if err != nil {
+		return nil, err
+	}
+	var fcrID types.UnitID
+	if flags.FeeCreditRecordID != "" {
+		if fcrID, err = parseUnitID(flags.FeeCreditRecordID); err != nil {
+			return nil, fmt.Errorf("fee credit record: %w", err)
+		}
+	}
+	c, err := client.New(ctx, flags.RPCAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &txSession{
+		client:    c,
+		shardConf: shardConf,
+		trustBase: trustBase,
+		signer:    signer,
+		pubKey:    pubKey,
+		fcrID:     fcrID,
+		flags:     flags,
+		out:       out,
+	}, nil
+}
+
+func txSigner(privateKey []byte) (abcrypto.Signer, []byte, error) {
+	signer, err := abcrypto.NewInMemorySecp256K1SignerFromKey(privateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+	verifier, err := signer.Verifier()
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating verifier: %w", err)
+	}
+	pubKey, err := verifier.MarshalPublicKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding public key: %w", err)
+	}
+	return signer, pubKey, nil
+}
+
+/*
+moneySession returns session for the money partition shard the fee credit is
+transferred from. Empty arguments default to the values of "s".
+*/
+func (s *txSession) moneySession(ctx context.Context, rpcAddress, shardConfFile string) (*txSession, error) {
+	ms := *s
+	// fee credit transactions must not refer to a fee credit record
+	ms.fcrID = nil
+	if shardConfFile != "" {
+		sc := shardConfFlags{ShardConfFile: shardConfFile}
+		shardConf, err := sc.loadShardConf(s.flags.baseFlags)
+		if err != nil {
+			return nil, err
+		}
+		ms.shardConf = shardConf
+	}
+	if ms.shardConf.PartitionTypeID != money.PartitionTypeID {
+		return nil, fmt.Errorf("expected money partition shard conf, got partition type %d", ms.shardConf.PartitionTypeID)
+	}
+	if rpcAddress == "" {
+		rpcAddress = s.flags.RPCAddress
+	}
+	c, err := client.New(ctx, rpcAddress)
+	if err != nil {
+		return nil, err
+	}
+	ms.client = c
+	return &ms, nil
+}
+
+func (s *txSession) transferBill(ctx context.Context, unitIDStr, newOwnerStr string) error {
+	unitID, err := parseUnitID(unitIDStr)
+	if err != nil {
+		return err
+	}
+	bill := &money.BillData{}
+	if err := s.getUnit(ctx, unitID, bill); err != nil {
+		return err
+	}
+	newOwner, err := s.ownerPredicateOrDefault(newOwnerStr)
+	if err != nil {
+		return err
+	}
+	tx, err := s.newTx(ctx, unitID, money.TransactionTypeTransfer, &money.TransferAttributes{
+		TargetValue:       bill.Value,
+		NewOwnerPredicate: newOwner,
+		Counter:           bill.Counter,
+	})
+	if err != nil {
+		return err
+	}
+	ownerProof, err := s.signAuthProof(tx, bill.OwnerPredicate)
+	if err != nil {
+		return err
+	}
+	if err := tx.SetAuthProof(&money.TransferAuthProof{OwnerProof: ownerProof}); err != nil {
+		return fmt.Errorf("setting auth proof: %w", err)
+	}
+	_, err = s.submit(ctx, tx)
+	return err
+}
+
+func (s *txSession) splitBill(ctx context.Context, unitIDStr string, amount uint64, newOwnerStr string) error {
+	unitID, err := parseUnitID(unitIDStr)
+	if err != nil {
+		return err
+	}
+	bill := &money.BillData{}
+	if err := s.getUnit(ctx, unitID, bill); err != nil {
+		return err
+	}
+	newOwner, err := s.ownerPredicateOrDefault(newOwnerStr)
+	if err != nil {
+		return err
+	}
+	tx, err := s.newTx(ctx, unitID, money.TransactionTypeSplit, &money.SplitAttributes{
+		TargetUnits: []*money.TargetUnit{{Amount: amount, OwnerPredicate: newOwner}},
+		Counter:     bill.Counter,
+	})
+	if err != nil {
+		return err
+	}
+	ownerProof, err := s.signAuthProof(tx, bill.OwnerPredicate)
+	if err != nil {
+		return err
+	}
+	if err := tx.SetAuthProof(&money.SplitAuthProof{OwnerProof: ownerProof}); err != nil {
+		return fmt.Errorf("setting auth proof: %w", err)
+	}
+	_, err = s.submit(ctx, tx)
+	return err
+}
+
+/*
+addFeeCredit transfers fee credit from the bill in the money partition ("ms")
+to the fee credit record of the partition of "s".
+*/
+func (s *txSession) addFeeCredit(ctx context.Context, ms *txSession, unitIDStr string, amount uint64) error {
+	unitID, err := parseUnitID(unitIDStr)
+	if err != nil {
+		return err
+	}
+	bill := &money.BillData{}
+	if err := ms.getUnit(ctx, unitID, bill); err != nil {
+		return err
+	}
+	ri, err := s.client.GetRoundInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("reading round info: %w", err)
+	}
+	ownerPredicate := templates.NewP2pkh256BytesFromKey(s.pubKey)
+	transferAttr := &fc.TransferFeeCreditAttributes{
+		Amount:             amount,
+		TargetPartitionID:  s.shardConf.PartitionID,
+		TargetRecordID:     s.fcrID,
+		LatestAdditionTime: ri.RoundNumber + s.flags.Timeout,
+		Counter:            bill.Counter,
+	}
+	if s.fcrID == nil {
+		fcrID, err := newFeeCreditRecordID(s.shardConf, ownerPredicate, transferAttr.LatestAdditionTime)
+		if err != nil {
+			return err
+		}
+		transferAttr.TargetRecordID = fcrID
+	} else {
+		fcr := &fc.FeeCreditRecord{}
+		if err := s.getUnit(ctx, s.fcrID, fcr); err != nil {
+			return err
+		}
+		ownerPredicate = fcr.OwnerPredicate
+		transferAttr.TargetUnitCounter = &fcr.Counter
+	}
+
+	transferTx, err := ms.newTx(ctx, unitID, fc.TransactionTypeTransferFeeCredit, transferAttr)
+	if err != nil {
+		return err
+	}
+	ownerProof, err := ms.signAuthProof(transferTx, bill.OwnerPredicate)
+	if err != nil {
+		return err
+	}
+	if err := transferTx.SetAuthProof(&fc.TransferFeeCreditAuthProof{OwnerProof: ownerProof}); err != nil {
+		return fmt.Errorf("setting auth proof: %w", err)
+	}
+	transferProof, err := ms.submit(ctx, transferTx)
+	if err != nil {
+		return fmt.Errorf("transfer fee credit: %w", err)
+	}
+
+	// fee credit transactions must not refer to a fee credit record
+	as := *s
+	as.fcrID = nil
+	addTx, err := as.newTx(ctx, transferAttr.TargetRecordID, fc.TransactionTypeAddFeeCredit, &fc.AddFeeCreditAttributes{
+		FeeCreditOwnerPredicate: ownerPredicate,
+		FeeCreditTransferProof:  transferProof,
+	})
+	if err != nil {
+		return err
+	}
+	if ownerProof, err = as.signAuthProof(addTx, ownerPredicate); err != nil {
+		return err
+	}
+	if err := addTx.SetAuthProof(&fc.AddFeeCreditAuthProof{OwnerProof: ownerProof}); err != nil {
+		return fmt.Errorf("setting auth proof: %w", err)
+	}
+	if _, err := as.submit(ctx, addTx); err != nil {
+		return fmt.Errorf("add fee credit: %w", err)
+	}
+	fmt.Fprintf(s.out, "fee credit record: %s\n", hex.Encode(transferAttr.TargetRecordID))
+	return nil
+}
+
+func (s *txSession) defineToken(ctx context.Context, attr *tokens.DefineFungibleTokenAttributes) error {
+	ownerPredicate := templates.NewP2pkh256BytesFromKey(s.pubKey)
+	attr.SubTypeCreationPredicate = ownerPredicate
+	attr.TokenMintingPredicate = ownerPredicate
+	attr.TokenTypeOwnerPredicate = templates.AlwaysTrueBytes()
+	tx, err := s.newTx(ctx, nil, tokens.TransactionTypeDefineFT, attr)
+	if err != nil {
+		return err
+	}
+	if err := tokens.GenerateUnitID(tx, s.shardConf); err != nil {
+		return fmt.Errorf("generating token type ID: %w", err)
+	}
+	if err := tx.SetAuthProof(&tokens.DefineFungibleTokenAuthProof{}); err != nil {
+		return fmt.Errorf("setting auth proof: %w", err)
+	}
+	_, err = s.submit(ctx, tx)
+	return err
+}
+
+func (s *txSession) mintToken(ctx context.Context, typeIDStr string, value uint64, ownerStr string) error {
+	typeID, err := parseUnitID(typeIDStr)
+	if err != nil {
+		return err
+	}
+	tokenType := &tokens.FungibleTokenTypeData{}
+	if err := s.getUnit(ctx, typeID, tokenType); err != nil {
+		return err
+	}
+	owner, err := s.ownerPredicateOrDefault(ownerStr)
+	if err != nil {
+		return err
+	}
+	tx, err := s.newTx(ctx, nil, tokens.TransactionTypeMintFT, &tokens.MintFungibleTokenAttributes{
+		TypeID:         typeID,
+		Value:          value,
+		OwnerPredicate: owner,
+	})
+	if err != nil {
+		return err
+	}
+	if err := tokens.GenerateUnitID(tx, s.shardConf); err != nil {
+		return fmt.Errorf("generating token ID: %w", err)
+	}
+	mintingProof, err := s.signAuthProof(tx, tokenType.TokenMintingPredicate)
+	if err != nil {
+		return err
+	}
+	if err := tx.SetAuthProof(&tokens.MintFungibleTokenAuthProof{TokenMintingProof: mintingProof}); err != nil {
+		return fmt.Errorf("setting auth proof: %w", err)
+	}
+	_, err = s.submit(ctx, tx)
+	return err
+}
+
+func (s *txSession) transferToken(ctx context.Context, unitIDStr, newOwnerStr string) error {
+	unitID, err := parseUnitID(unitIDStr)
+	if err != nil {
+		return err
+	}
+	token := &tokens.FungibleTokenData{}
+	if err := s.getUnit(ctx, unitID, token); err != nil {
+		return err
+	}
+	newOwner, err := s.ownerPredicateOrDefault(newOwnerStr)
+	if err != nil {
+		return err
+	}
+	tx, err := s.newTx(ctx, unitID, tokens.TransactionTypeTransferFT, &tokens.TransferFungibleTokenAttributes{
+		TypeID:            token.TypeID,
+		Value:             token.Value,
+		NewOwnerPredicate: newOwner,
+		Counter:           token.Counter,
+	})
+	if err != nil {
+		return err
+	}
+	authProof := &tokens.TransferFungibleTokenAuthProof{}
+	if authProof.OwnerProof, err = s.signAuthProof(tx, token.OwnerPredicate); err != nil {
+		return err
+	}
+	// inherited owner predicates of the type and its parent types
+	for typeID := token.TypeID; typeID != nil; {
+		tokenType := &tokens.FungibleTokenTypeData{}
+		if err := s.getUnit(ctx, typeID, tokenType); err != nil {
+			return err
+		}
+		proof, err := s.signAuthProof(tx, tokenType.TokenTypeOwnerPredicate)
+		if err != nil {
+			return fmt.Errorf("token type %s: %w", typeID, err)
+		}
+		authProof.TokenTypeOwnerProofs = append(authProof.TokenTypeOwnerProofs, proof)
+		typeID = tokenType.ParentTypeID
+	}
+	if err := tx.SetAuthProof(authProof); err != nil {
+		return fmt.Errorf("setting auth proof: %w", err)
+	}
+	_, err = s.submit(ctx, tx)
+	return err
+}
+
+/*
+newTx returns unsigned transaction order with client metadata filled in, the
+transaction timeout is relative to the current round of the shard.
+*/
+func (s *txSession) newTx(ctx context.Context, unitID types.UnitID, txType uint16, attr any) (*types.TransactionOrder, error) {
+	ri, err := s.client.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading round info: %w", err)
+	}
+	tx := &types.TransactionOrder{
+		Version: 1,
+		Payload: types.Payload{
+			NetworkID:   s.shardConf.NetworkID,
+			PartitionID: s.shardConf.PartitionID,
+			UnitID:      unitID,
+			Type:        txType,
+			ClientMetadata: &types.ClientMetadata{
+				Timeout:           ri.RoundNumber + s.flags.Timeout,
+				MaxTransactionFee: s.flags.MaxFee,
+				FeeCreditRecordID: s.fcrID,
+			},
+		},
+	}
+	if err := tx.SetAttributes(attr); err != nil {
+		return nil, fmt.Errorf("encoding transaction attributes: %w", err)
+	}
+	return tx, nil
+}
+
+/*
+signAuthProof returns the input for the "predicate" authorizing the transaction.
+Only "always true" and P2PKH of the key predicates are supported.
+*/
+func (s *txSession) signAuthProof(tx *types.TransactionOrder, predicate []byte) ([]byte, error) {
+	return s.signPredicate(predicate, tx.AuthProofSigBytes)
+}
+
+func (s *txSession) signPredicate(predicate []byte, sigBytes func() ([]byte, error)) ([]byte, error) {
+	switch {
+	case bytes.Equal(predicate, templates.AlwaysTrueBytes()):
+		return nil, nil
+	case bytes.Equal(predicate, templates.NewP2pkh256BytesFromKey(s.pubKey)):
+		data, err := sigBytes()
+		if err != nil {
+			return nil, err
+		}
+		sig, err := s.signer.SignBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("signing transaction: %w", err)
+		}
+		return templates.NewP2pkh256SignatureBytes(sig, s.pubKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported predicate %X, expected \"always true\" or P2PKH of the key", predicate)
+	}
+}
+
+/*
+submit adds the fee proof to the transaction, sends it to the shard and waits for
+the transaction proof. The proof is verified against the trust base and the
+transaction must have been executed successfully.
+*/
+func (s *txSession) submit(ctx context.Context, tx *types.TransactionOrder) (*types.TxRecordProof, error) {
+	if s.fcrID != nil {
+		fcr := &fc.FeeCreditRecord{}
+		if err := s.getUnit(ctx, s.fcrID, fcr); err != nil {
+			return nil, fmt.Errorf("reading fee credit record: %w", err)
+		}
+		feeProof, err := s.signPredicate(fcr.OwnerPredicate, tx.FeeProofSigBytes)
+		if err != nil {
+			return nil, fmt.Errorf("fee proof: %w", err)
+		}
+		tx.FeeProof = feeProof
+	}
+	txHash, err := tx.Hash(crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("hashing transaction: %w", err)
+	}
+	sentHash, err := s.client.SendTransaction(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("sending transaction: %w", err)
+	}
+	if !bytes.Equal(sentHash, txHash) {
+		return nil, fmt.Errorf("shard returned transaction hash %s, expected %s", hex.Encode(sentHash), hex.Encode(txHash))
+	}
+	fmt.Fprintf(s.out, "sent transaction %s\n", hex.Encode(txHash))
+
+	proof, err := swap.WaitProof(ctx, s.client, txHash, tx.Timeout(), s.flags.PollInterval)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyProofOfTx(proof, txHash, s.trustBase); err != nil {
+		return nil, fmt.Errorf("verifying transaction proof: %w", err)
+	}
+	proofBytes, err := types.Cbor.Marshal(proof)
+	if err != nil {
+		return nil, fmt.Errorf("encoding transaction proof: %w", err)
+	}
+	fmt.Fprintf(s.out, "proof: %s\n", hex.Encode(proofBytes))
+	if !proof.TxRecord.IsSuccessful() {
+		return nil, fmt.Errorf("transaction failed with status %d: %w", proof.TxStatus(), proof.TxRecord.ServerMetadata.ErrDetail())
+	}
+	fmt.Fprintf(s.out, "transaction executed, fee %d\n", proof.ActualFee())
+	for _, id := range proof.TxRecord.TargetUnits() {
+		fmt.Fprintf(s.out, "unit: %s\n", hex.Encode(id))
+	}
+	return proof, nil
+}
+
+// verifyProofOfTx verifies that the proof is valid and it is the proof of the transaction with hash "txHash".
+func verifyProofOfTx(proof *types.TxRecordProof, txHash []byte, tb types.RootTrustBase) error {
+	txo, err := proof.GetTransactionOrderV1()
+	if err != nil {
+		return fmt.Errorf("decoding transaction order: %w", err)
+	}
+	h, err := txo.Hash(crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("hashing transaction order: %w", err)
+	}
+	if !bytes.Equal(h, txHash) {
+		return fmt.Errorf("proof is for transaction %s, expected %s", hex.Encode(h), hex.Encode(txHash))
+	}
+	return types.VerifyTxInclusion(proof, tb, crypto.SHA256)
+}
+
+// getUnit reads the unit data into "data"
+func (s *txSession) getUnit(ctx context.Context, unitID types.UnitID, data any) error {
+	unit, err := s.client.GetUnit(ctx, unitID, false)
+	if err != nil {
+		return fmt.Errorf("reading unit %s: %w", unitID, err)
+	}
+	if unit == nil {
+		return fmt.Errorf("unit %s not found", unitID)
+	}
+	if err := json.Unmarshal(unit.Data, data); err != nil {
+		return fmt.Errorf("decoding unit %s data: %w", unitID, err)
+	}
+	return nil
+}
+
+func parseUnitID(s string) (types.UnitID, error) {
+	id, err := hex.Decode([]byte(s))
+	if err != nil {
+		return nil, fmt.Errorf("decoding unit ID %q: %w", s, err)
+	}
+	return id, nil
+}
+
+func (s *txSession) ownerPredicateOrDefault(predicate string) ([]byte, error) {
+	if predicate == "" {
+		return templates.NewP2pkh256BytesFromKey(s.pubKey), nil
+	}
+	b, err := decodeHexOrFile(predicate)
+	if err != nil {
+		return nil, fmt.Errorf("decoding owner predicate: %w", err)
+	}
+	return b, nil
+}
+
+func newFeeCreditRecordID(shardConf *types.PartitionDescriptionRecord, ownerPredicate []byte, latestAdditionTime uint64) (types.UnitID, error) {
+	switch shardConf.PartitionTypeID {
+	case money.PartitionTypeID:
+		return money.NewFeeCreditRecordIDFromOwnerPredicate(shardConf, shardConf.ShardID, ownerPredicate, latestAdditionTime)
+	case tokens.PartitionTypeID:
+		return tokens.NewFeeCreditRecordIDFromOwnerPredicate(shardConf, shardConf.ShardID, ownerPredicate, latestAdditionTime)
+	default:
+		return nil, fmt.Errorf("partition type %d doesn't support fee credit", shardConf.PartitionTypeID)
+	}
+}
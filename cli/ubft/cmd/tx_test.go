@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	testblock "github.com/unicitynetwork/bft-core/internal/testutils/block"
+	testobserve "github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	testsig "github.com/unicitynetwork/bft-core/internal/testutils/sig"
+	"github.com/unicitynetwork/bft-core/internal/testutils/trustbase"
+	testtransaction "github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+func TestTx(t *testing.T) {
+	dn := startTestDevnet(t, 1, 1)
+	keyHome := t.TempDir()
+	initialBillID := string(hex.Encode(moneyPartitionInitialBillID))
+
+	// executes "tx" subcommand against partition "idx" of the devnet
+	execTx := func(t *testing.T, home string, idx int, args ...string) (string, error) {
+		cmd := New(testobserve.NewFactory(t))
+		out := &bytes.Buffer{}
+		cmd.baseCmd.SetOut(out)
+		cmd.baseCmd.SetArgs(append([]string{"tx", args[0], "--home", home, "-g",
+			"--rpc-address", dn.rpcURL(idx),
+			"--shard-conf", filepath.Join(dn.dir, devnetPartitions[idx].shardConfFileName()),
+			"--trust-base", filepath.Join(dn.dir, trustBaseFileName),
+			"--poll-interval", "100ms",
+		}, args[1:]...))
+		err := cmd.Execute(context.Background())
+		return out.String(), err
+	}
+	runTx := func(t *testing.T, idx int, args ...string) string {
+		out, err := execTx(t, keyHome, idx, args...)
+		require.NoError(t, err, out)
+		return out
+	}
+	match := func(t *testing.T, out, pattern string) string {
+		m := regexp.MustCompile(pattern).FindStringSubmatch(out)
+		require.Len(t, m, 2, "pattern %q not found in output:\n%s", pattern, out)
+		return m[1]
+	}
+
+	// money partition: fee credit, split and transfer of the initial bill ("always true" owner)
+	out := runTx(t, 0, "add-fee-credit", "--unit-id", initialBillID, "--amount", "1000")
+	require.Contains(t, out, "transaction executed")
+	moneyFCR := match(t, out, `fee credit record: (0x[0-9a-f]+)`)
+
+	out = runTx(t, 0, "split", "--unit-id", initialBillID, "--amount", "500", "--fee-credit-record-id", moneyFCR)
+	require.Contains(t, out, "transaction executed")
+	out = runTx(t, 0, "transfer", "--unit-id", initialBillID, "--fee-credit-record-id", moneyFCR)
+	require.Contains(t, out, "transaction executed")
+
+	// tokens partition: fee credit is transferred from the bill now owned by the key
+	out = runTx(t, 1, "add-fee-credit", "--unit-id", initialBillID, "--amount", "1000",
+		"--money-rpc-address", dn.rpcURL(0),
+		"--money-shard-conf", filepath.Join(dn.dir, devnetPartitions[0].shardConfFileName()),
+	)
+	tokensFCR := match(t, out, `fee credit record: (0x[0-9a-f]+)`)
+
+	out = runTx(t, 1, "define-token", "--symbol", "TST", "--fee-credit-record-id", tokensFCR)
+	typeID := match(t, out, `unit: (0x[0-9a-f]+)`)
+	out = runTx(t, 1, "mint-token", "--type-id", typeID, "--value", "100", "--fee-credit-record-id", tokensFCR)
+	tokenID := match(t, out, `unit: (0x[0-9a-f]+)`)
+	out = runTx(t, 1, "transfer-token", "--unit-id", tokenID, "--fee-credit-record-id", tokensFCR)
+	require.Contains(t, out, "transaction executed")
+	require.Contains(t, out, "unit: "+tokenID)
+
+	// token is owned by the key of "keyHome"
+	_, err := execTx(t, t.TempDir(), 1, "transfer-token", "--unit-id", tokenID, "--fee-credit-record-id", tokensFCR)
+	require.ErrorContains(t, err, "unsupported predicate")
+}
+
+func TestTxKey(t *testing.T) {
+	cmd := New(testobserve.NewFactory(t))
+	out := &bytes.Buffer{}
+	cmd.baseCmd.SetOut(out)
+	cmd.baseCmd.SetArgs([]string{"tx", "key", "--home", t.TempDir(), "-g"})
+	require.NoError(t, cmd.Execute(context.Background()))
+	require.Regexp(t, `^public key: 0x[0-9a-f]{66}\nowner predicate: 0x[0-9a-f]+\n$`, out.String())
+}
+
+func Test_verifyProofOfTx(t *testing.T) {
+	signer, verifier := testsig.CreateSignerAndVerifier(t)
+	tb := trustbase.NewTrustBase(t, verifier)
+	txo := testtransaction.NewTransactionOrder(t)
+	txHash, err := txo.Hash(crypto.SHA256)
+	require.NoError(t, err)
+	proof := testblock.CreateTxRecordProof(t, &types.TransactionRecord{
+		Version:          1,
+		TransactionOrder: testtransaction.TxoToBytes(t, txo),
+		ServerMetadata:   &types.ServerMetadata{SuccessIndicator: types.TxStatusSuccessful},
+	}, signer)
+
+	require.NoError(t, verifyProofOfTx(proof, txHash, tb))
+
+	otherHash, err := testtransaction.NewTransactionOrder(t, testtransaction.WithUnitID(make([]byte, 33))).Hash(crypto.SHA256)
+	require.NoError(t, err)
+	require.ErrorContains(t, verifyProofOfTx(proof, otherHash, tb), "proof is for transaction 0x")
+
+	_, otherVerifier := testsig.CreateSignerAndVerifier(t)
+	require.ErrorContains(t, verifyProofOfTx(proof, txHash, trustbase.NewTrustBase(t, otherVerifier)), "invalid unicity certificate")
+}
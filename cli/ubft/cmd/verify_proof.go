@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unicitynetwork/bft-core/lightclient"
+	"github.com/unicitynetwork/bft-core/rpc"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+type verifyProofFlags struct {
+	*baseFlags
+	shardConfFlags
+	trustBaseFlags
+
+	NextTrustBaseFiles []string // trust bases of the epochs following the "--trust-base"
+	DeletionRound      uint64
+}
+
+func newVerifyProofCmd(baseFlags *baseFlags) *cobra.Command {
+	flags := &verifyProofFlags{baseFlags: baseFlags}
+	var cmd = &cobra.Command{
+		Use:   "verify-proof <proof>",
+		Short: "Verifies transaction proof or unit state proof against the trust base",
+		Long: `Verifies transaction proof or unit state proof against the trust base and
+explains which verification step failed. The proof is either the response of
+"state_getTransactionProof" or CBOR encoded transaction record proof, or the
+response of "state_getUnit" with "includeStateProof" set. Input is hex encoded
+(0x prefixed) or, when prefixed with "@", read from the file, ie
+"verify-proof @proof.json".
+
+The trust base of the first epoch ("--trust-base") is trusted, trust bases of
+the following epochs ("--next-trust-base") must be signed by the root nodes of
+the previous epoch. The unicity certificate is verified with the trust base of
+the epoch of its unicity seal.
+
+The certificate must be issued to the shard with the configuration given by
+"--shard-conf", proofs of other shards are rejected. Shard conf is also used to
+encode the unit data of the unit state proof.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return verifyProof(cmd.OutOrStdout(), flags, args[0])
+		},
+	}
+	flags.addTrustBaseFlags(cmd)
+	flags.addShardConfFlags(cmd)
+	cmd.Flags().StringArrayVar(&flags.NextTrustBaseFiles, "next-trust-base", nil,
+		"path to the trust base of the next epoch, repeat in epoch order for multiple epochs")
+	cmd.Flags().Uint64Var(&flags.DeletionRound, "deletion-round", 0, "deletion round of the unit, not returned by state_getUnit")
+	return cmd
+}
+
+func verifyProof(out io.Writer, flags *verifyProofFlags, input string) error {
+	data, err := decodeHexOrFile(input)
+	if err != nil {
+		return fmt.Errorf("reading proof: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+
+	shardConf, err := flags.loadShardConf(flags.baseFlags)
+	if err != nil {
+		return fmt.Errorf("loading shard conf: %w", err)
+	}
+	trustBases, err := flags.loadTrustBases()
+	if err != nil {
+		return err
+	}
+
+	var steps []lightclient.Step
+	verifier, err := lightclient.NewVerifier(trustBases, shardConf)
+	if err == nil {
+		steps, err = verifyProofData(verifier, shardConf, flags.DeletionRound, data)
+	}
+	if verr := (*lightclient.VerificationError)(nil); errors.As(err, &verr) {
+		fmt.Fprintf(out, "FAILED step %q: %s\n  %v\n", verr.Step, verr.Step.Description(), verr.Err)
+		return errors.New("proof is not valid")
+	}
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		fmt.Fprintf(out, "OK %s: %s\n", step, step.Description())
+	}
+	fmt.Fprintln(out, "proof is valid")
+	return nil
+}
+
+// loadTrustBases returns the trust bases of the consecutive epochs
+func (f *verifyProofFlags) loadTrustBases() ([]*types.RootTrustBaseV1, error) {
+	tb, err := f.loadTrustBase(f.baseFlags)
+	if err != nil {
+		return nil, fmt.Errorf("loading trust base: %w", err)
+	}
+	trustBases := []*types.RootTrustBaseV1{tb}
+	for _, file := range f.NextTrustBaseFiles {
+		var tb *types.RootTrustBaseV1
+		if err := f.loadConf(file, "", &tb); err != nil {
+			return nil, fmt.Errorf("loading trust base: %w", err)
+		}
+		trustBases = append(trustBases, tb)
+	}
+	return trustBases, nil
+}
+
+/*
+verifyProofData detects the type of the proof and verifies it, returns the
+verified steps.
+*/
+func verifyProofData(verifier *lightclient.Verifier, shardConf *types.PartitionDescriptionRecord, deletionRound uint64, data []byte) ([]lightclient.Step, error) {
+	// JSON responses of the RPC API
+	if bytes.HasPrefix(data, []byte("{")) {
+		var resp struct {
+			*rpc.TransactionRecordAndProof
+			*rpc.Unit[json.RawMessage]
+		}
+		resp.TransactionRecordAndProof = &rpc.TransactionRecordAndProof{}
+		resp.Unit = &rpc.Unit[json.RawMessage]{}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("decoding JSON proof: %w", err)
+		}
+		if resp.StateProof != nil {
+			unitState, err := unitStateFromRPC(shardConf, resp.Unit, deletionRound)
+			if err != nil {
+				return nil, err
+			}
+			return lightclient.UnitStateProofSteps, verifier.VerifyUnitStateProof(resp.StateProof, unitState)
+		}
+		if len(resp.TxRecordProof) == 0 {
+			return nil, errors.New("JSON input is neither transaction proof nor unit with state proof")
+		}
+		data = resp.TxRecordProof
+	}
+	if bytes.HasPrefix(data, []byte("0x")) {
+		var err error
+		if data, err = hex.Decode(data); err != nil {
+			return nil, fmt.Errorf("decoding hex proof: %w", err)
+		}
+	}
+	proof := &types.TxRecordProof{}
+	if err := types.Cbor.Unmarshal(data, proof); err != nil {
+		return nil, fmt.Errorf("decoding transaction record proof: %w", err)
+	}
+	return lightclient.TxProofSteps, verifier.VerifyTxProof(proof)
+}
+
+// unitStateFromRPC returns the unit state of the "state_getUnit" response, unit data is CBOR encoded as in the state
+func unitStateFromRPC(shardConf *types.PartitionDescriptionRecord, unit *rpc.Unit[json.RawMessage], deletionRound uint64) (*types.UnitState, error) {
+	udc, err := unitDataConstructor(shardConf)
+	if err != nil {
+		return nil, err
+	}
+	unitData, err := udc(unit.UnitID)
+	if err != nil {
+		return nil, fmt.Errorf("creating unit data for unit %s: %w", unit.UnitID, err)
+	}
+	if err := json.Unmarshal(unit.Data, unitData); err != nil {
+		return nil, fmt.Errorf("decoding data of unit %s: %w", unit.UnitID, err)
+	}
+	return types.NewUnitState(unitData, deletionRound, types.RawCBOR(unit.StateLockTx))
+}
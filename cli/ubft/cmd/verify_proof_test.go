@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	testobserve "github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	"github.com/unicitynetwork/bft-core/rpc"
+	"github.com/unicitynetwork/bft-core/rpc/client"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+	"github.com/unicitynetwork/bft-go-base/util"
+)
+
+func TestVerifyProof(t *testing.T) {
+	dn := startTestDevnet(t, 1)
+	shardConfFile := filepath.Join(dn.dir, devnetPartitions[0].shardConfFileName())
+	trustBaseFile := filepath.Join(dn.dir, trustBaseFileName)
+
+	verifyProof := func(t *testing.T, args ...string) (string, error) {
+		cmd := New(testobserve.NewFactory(t))
+		out := &bytes.Buffer{}
+		cmd.baseCmd.SetOut(out)
+		cmd.baseCmd.SetArgs(append([]string{"verify-proof", "--home", t.TempDir(), "--trust-base", trustBaseFile, "--shard-conf", shardConfFile}, args...))
+		err := cmd.Execute(context.Background())
+		return out.String(), err
+	}
+	writeFile := func(t *testing.T, data []byte) string {
+		file := filepath.Join(t.TempDir(), "proof")
+		require.NoError(t, os.WriteFile(file, data, 0600))
+		return "@" + file
+	}
+
+	// transaction proof of adding fee credit
+	cmd := New(testobserve.NewFactory(t))
+	out := &bytes.Buffer{}
+	cmd.baseCmd.SetOut(out)
+	cmd.baseCmd.SetArgs([]string{"tx", "add-fee-credit", "--home", t.TempDir(), "-g",
+		"--rpc-address", dn.rpcURL(0),
+		"--shard-conf", shardConfFile,
+		"--trust-base", trustBaseFile,
+		"--poll-interval", "100ms",
+		"--unit-id", string(hex.Encode(moneyPartitionInitialBillID)),
+		"--amount", "1000",
+	})
+	require.NoError(t, cmd.Execute(context.Background()), out.String())
+	m := regexp.MustCompile(`proof: (0x[0-9a-f]+)`).FindStringSubmatch(out.String())
+	require.Len(t, m, 2, out.String())
+	txProof := m[1]
+
+	t.Run("transaction proof", func(t *testing.T) {
+		out, err := verifyProof(t, txProof)
+		require.NoError(t, err, out)
+		require.Contains(t, out, "OK unicity seal: ")
+		require.Contains(t, out, "OK transaction inclusion: ")
+		require.Contains(t, out, "proof is valid")
+
+		// state_getTransactionProof response
+		resp, err := json.Marshal(&rpc.TransactionRecordAndProof{TxRecordProof: mustDecodeHex(t, txProof)})
+		require.NoError(t, err)
+		out, err = verifyProof(t, writeFile(t, resp))
+		require.NoError(t, err, out)
+		require.Contains(t, out, "proof is valid")
+	})
+
+	t.Run("modified transaction record", func(t *testing.T) {
+		proof := &types.TxRecordProof{}
+		require.NoError(t, types.Cbor.Unmarshal(mustDecodeHex(t, txProof), proof))
+		proof.TxRecord.ServerMetadata.ActualFee++
+		proofBytes, err := types.Cbor.Marshal(proof)
+		require.NoError(t, err)
+		out, err := verifyProof(t, string(hex.Encode(proofBytes)))
+		require.EqualError(t, err, "proof is not valid")
+		require.Contains(t, out, `FAILED step "transaction inclusion": transaction record is included in the certified block`)
+		require.Contains(t, out, "proof block hash does not match to block hash in unicity certificate")
+	})
+
+	t.Run("unit state proof", func(t *testing.T) {
+		c, err := client.New(context.Background(), dn.rpcURL(0))
+		require.NoError(t, err)
+		defer c.Close()
+		unit, err := c.GetUnit(context.Background(), moneyPartitionInitialBillID, true)
+		require.NoError(t, err)
+		require.NotNil(t, unit.StateProof)
+		resp, err := json.Marshal(unit)
+		require.NoError(t, err)
+		unitFile := writeFile(t, resp)
+
+		out, err := verifyProof(t, unitFile)
+		require.NoError(t, err, out)
+		require.Contains(t, out, "OK unit tree certificate: ")
+		require.Contains(t, out, "OK state tree certificate: ")
+
+		out, err = verifyProof(t, unitFile, "--deletion-round", "5")
+		require.EqualError(t, err, "proof is not valid")
+		require.Contains(t, out, `FAILED step "unit tree certificate"`)
+	})
+
+	t.Run("trust base of other network", func(t *testing.T) {
+		tb := &types.RootTrustBaseV1{}
+		_, err := util.ReadJsonFile(trustBaseFile, tb)
+		require.NoError(t, err)
+		tb.NetworkID++
+		file := filepath.Join(t.TempDir(), trustBaseFileName)
+		require.NoError(t, util.WriteJsonFile(file, tb))
+
+		out, err := verifyProof(t, txProof, "--trust-base", file)
+		require.EqualError(t, err, "proof is not valid")
+		require.Contains(t, out, `FAILED step "unicity seal"`)
+	})
+
+	t.Run("shard conf of other shard", func(t *testing.T) {
+		shardConf := &types.PartitionDescriptionRecord{}
+		_, err := util.ReadJsonFile(shardConfFile, shardConf)
+		require.NoError(t, err)
+		shardConf.T2Timeout++
+		file := filepath.Join(t.TempDir(), "shard-conf.json")
+		require.NoError(t, util.WriteJsonFile(file, shardConf))
+
+		out, err := verifyProof(t, txProof, "--shard-conf", file)
+		require.EqualError(t, err, "proof is not valid")
+		require.Contains(t, out, `FAILED step "unicity certificate"`)
+		require.Contains(t, out, "invalid shard configuration hash")
+
+		_, err = verifyProof(t, txProof, "--shard-conf", filepath.Join(t.TempDir(), "missing.json"))
+		require.ErrorContains(t, err, "loading shard conf")
+	})
+
+	t.Run("invalid trust base chain", func(t *testing.T) {
+		out, err := verifyProof(t, txProof, "--next-trust-base", trustBaseFile)
+		require.EqualError(t, err, "proof is not valid")
+		require.Contains(t, out, `FAILED step "trust base"`)
+		require.Contains(t, out, "trust base of epoch 1 follows trust base of epoch 1")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := verifyProof(t, "0x01")
+		require.ErrorContains(t, err, "decoding transaction record proof")
+		_, err = verifyProof(t, writeFile(t, []byte(`{"foo": 1}`)))
+		require.EqualError(t, err, "JSON input is neither transaction proof nor unit with state proof")
+	})
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.Decode([]byte(s))
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,341 @@
+package testnetwork
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/unicitynetwork/bft-core/network"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+/*
+Faults is a network conditions controller shared by all the fault injecting
+network wrappers of a test network. Faults are applied on the sending side,
+ie each message sent from node "from" to node "to" is checked against the
+current network partition and the fault rules. When every node of the test
+network is wrapped this is equivalent to faults applied by the "wire".
+
+Random decisions (drop rate, jitter) are made using PRNG seeded with the
+value given to NewFaults so a test can be replayed with the same seed.
+*/
+type Faults struct {
+	mu     sync.Mutex
+	rnd    *rand.Rand
+	rules  []*faultRule
+	groups map[peer.ID]int // peer ID -> index of the partition group
+	nextID int
+	stats  FaultStats
+	// pending delayed deliveries, canceled by Stop
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+/*
+FaultRule describes the fault applied to the messages matching the rule.
+When multiple rules match a message the first rule which drops the message
+wins, delays and duplicates of the matching rules are added up.
+*/
+type FaultRule struct {
+	// Match returns true when the rule applies to the message sent from
+	// peer "from" to peer "to". When nil the rule applies to all messages.
+	// For the messages published over gossip (PublishBlock) "to" is empty.
+	Match func(from, to peer.ID, msg any) bool
+	// DropRate is probability (0..1) of dropping the message, 1 drops
+	// all the matching messages.
+	DropRate float64
+	// Delay is fixed delay added to the message delivery.
+	Delay time.Duration
+	// Jitter is the upper bound of random delay added to the message
+	// delivery, causes messages to be reordered.
+	Jitter time.Duration
+	// Duplicate is the number of extra copies of the message to deliver.
+	Duplicate int
+}
+
+type faultRule struct {
+	FaultRule
+	id int
+}
+
+// FaultStats counts the decisions made by Faults.
+type FaultStats struct {
+	Delivered  int
+	Dropped    int
+	Delayed    int
+	Duplicated int
+}
+
+type (
+	rootNet interface {
+		Send(ctx context.Context, msg any, receivers ...peer.ID) error
+		ReceivedChannel() <-chan any
+	}
+
+	// validatorNet is the same as partition.ValidatorNetwork, it can't be
+	// imported here as partition tests use this package.
+	validatorNet interface {
+		rootNet
+
+		PublishBlock(ctx context.Context, block *types.Block) error
+		SubscribeToBlocks(ctx context.Context) error
+		UnsubscribeFromBlocks()
+		RegisterValidatorProtocols() error
+		UnregisterValidatorProtocols()
+		ReplicationPeers() peer.IDSlice
+
+		AddTransaction(ctx context.Context, tx *types.TransactionOrder) ([]byte, error)
+		ForwardTransactions(ctx context.Context, receiverFunc network.TxReceiver)
+		ProcessTransactions(ctx context.Context, txProcessor network.TxProcessor)
+	}
+)
+
+// NewFaults returns fault controller with no faults (all messages are delivered).
+func NewFaults(seed int64) *Faults {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Faults{
+		rnd:    rand.New(rand.NewSource(seed)), // #nosec G404 deterministic randomness is wanted in tests
+		groups: make(map[peer.ID]int),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+/*
+AddRule adds fault rule and returns function which removes the rule.
+*/
+func (f *Faults) AddRule(rule FaultRule) (remove func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	r := &faultRule{FaultRule: rule, id: f.nextID}
+	f.rules = append(f.rules, r)
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.rules = slices.DeleteFunc(f.rules, func(fr *faultRule) bool { return fr.id == r.id })
+	}
+}
+
+// ClearRules removes all fault rules, network partition is not affected.
+func (f *Faults) ClearRules() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = nil
+}
+
+/*
+Partition splits the network into given groups, messages between peers in
+different groups are dropped. Peers not listed in any group form an implicit
+group of their own. Calling Partition replaces the previous partition.
+*/
+func (f *Faults) Partition(groups ...[]peer.ID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clear(f.groups)
+	for i, g := range groups {
+		for _, id := range g {
+			f.groups[id] = i + 1
+		}
+	}
+}
+
+// Heal removes the network partition.
+func (f *Faults) Heal() {
+	f.Partition()
+}
+
+// Stats returns the counters of fault decisions made so far.
+func (f *Faults) Stats() FaultStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+/*
+Stop cancels delivery of the delayed messages and waits until pending
+delivery goroutines have exited.
+*/
+func (f *Faults) Stop() {
+	f.cancel()
+	f.wg.Wait()
+}
+
+/*
+decide returns list of delays, one item per copy of the message to deliver.
+Empty list means the message is dropped.
+*/
+func (f *Faults) decide(from, to peer.ID, msg any) []time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if to != "" && f.groups[from] != f.groups[to] {
+		f.stats.Dropped++
+		return nil
+	}
+
+	var delay time.Duration
+	copies := 1
+	for _, r := range f.rules {
+		if r.Match != nil && !r.Match(from, to, msg) {
+			continue
+		}
+		if r.DropRate > 0 && f.rnd.Float64() < r.DropRate {
+			f.stats.Dropped++
+			return nil
+		}
+		delay += r.Delay
+		if r.Jitter > 0 {
+			delay += time.Duration(f.rnd.Int63n(int64(r.Jitter)))
+		}
+		copies += r.Duplicate
+	}
+
+	delays := make([]time.Duration, copies)
+	for i := range delays {
+		delays[i] = delay
+	}
+	f.stats.Delivered++
+	f.stats.Duplicated += copies - 1
+	if delay > 0 {
+		f.stats.Delayed++
+	}
+	return delays
+}
+
+/*
+send sends the msg to the receivers using send callback after applying faults.
+Messages which are not delayed are sent synchronously with single call of the
+send callback, delayed messages are sent from separate goroutine and errors
+are ignored (as with lost messages on the network).
+*/
+func (f *Faults) send(ctx context.Context, from peer.ID, msg any, receivers []peer.ID, send func(ctx context.Context, msg any, receivers ...peer.ID) error) error {
+	var now []peer.ID
+	for _, to := range receivers {
+		for _, d := range f.decide(from, to, msg) {
+			if d == 0 {
+				now = append(now, to)
+				continue
+			}
+			f.sendLater(d, func(ctx context.Context) { _ = send(ctx, msg, to) })
+		}
+	}
+	if len(now) == 0 {
+		return nil
+	}
+	return send(ctx, msg, now...)
+}
+
+func (f *Faults) sendLater(delay time.Duration, send func(ctx context.Context)) {
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		select {
+		case <-f.ctx.Done():
+		case <-time.After(delay):
+			send(f.ctx)
+		}
+	}()
+}
+
+/*
+WrapRootNet returns network which applies faults to the messages "self" sends
+using "net". Returned value implements consensus.RootNet and rootchain.PartitionNet.
+*/
+func (f *Faults) WrapRootNet(self peer.ID, net rootNet) *FaultyRootNet {
+	return &FaultyRootNet{self: self, net: net, faults: f}
+}
+
+/*
+WrapValidatorNet returns network which applies faults to the messages "self"
+sends using "net". Returned value implements partition.ValidatorNetwork.
+*/
+func (f *Faults) WrapValidatorNet(self peer.ID, net validatorNet) *FaultyValidatorNet {
+	return &FaultyValidatorNet{validatorNet: net, self: self, faults: f}
+}
+
+// FaultyRootNet is a root network wrapper which injects faults configured by Faults.
+type FaultyRootNet struct {
+	self   peer.ID
+	net    rootNet
+	faults *Faults
+}
+
+func (n *FaultyRootNet) Send(ctx context.Context, msg any, receivers ...peer.ID) error {
+	return n.faults.send(ctx, n.self, msg, receivers, n.net.Send)
+}
+
+func (n *FaultyRootNet) ReceivedChannel() <-chan any {
+	return n.net.ReceivedChannel()
+}
+
+// FaultyValidatorNet is a validator network wrapper which injects faults configured by Faults.
+type FaultyValidatorNet struct {
+	validatorNet
+	self   peer.ID
+	faults *Faults
+}
+
+func (n *FaultyValidatorNet) Send(ctx context.Context, msg any, receivers ...peer.ID) error {
+	return n.faults.send(ctx, n.self, msg, receivers, n.validatorNet.Send)
+}
+
+/*
+PublishBlock applies fault rules to the block with empty receiver ID, network
+partition doesn't affect gossip as there is no single receiver.
+*/
+func (n *FaultyValidatorNet) PublishBlock(ctx context.Context, block *types.Block) error {
+	return n.faults.send(ctx, n.self, block, []peer.ID{""}, func(ctx context.Context, msg any, _ ...peer.ID) error {
+		return n.validatorNet.PublishBlock(ctx, msg.(*types.Block))
+	})
+}
+
+/*
+MatchType returns FaultRule.Match function which matches messages of type T
+(or *T), ie MatchType[abdrc.VoteMsg]() matches all root votes.
+*/
+func MatchType[T any]() func(from, to peer.ID, msg any) bool {
+	typ := reflect.TypeFor[T]()
+	return func(_, _ peer.ID, msg any) bool {
+		mt := reflect.TypeOf(msg)
+		return mt == typ || (mt != nil && mt.Kind() == reflect.Pointer && mt.Elem() == typ)
+	}
+}
+
+/*
+MatchFrom returns FaultRule.Match function which matches messages sent by any of the given peers.
+*/
+func MatchFrom(ids ...peer.ID) func(from, to peer.ID, msg any) bool {
+	return func(from, _ peer.ID, _ any) bool {
+		return slices.Contains(ids, from)
+	}
+}
+
+/*
+MatchTo returns FaultRule.Match function which matches messages sent to any of the given peers.
+*/
+func MatchTo(ids ...peer.ID) func(from, to peer.ID, msg any) bool {
+	return func(_, to peer.ID, _ any) bool {
+		return slices.Contains(ids, to)
+	}
+}
+
+/*
+MatchAll combines Match functions, the message must match all of them.
+*/
+func MatchAll(match ...func(from, to peer.ID, msg any) bool) func(from, to peer.ID, msg any) bool {
+	return func(from, to peer.ID, msg any) bool {
+		for _, m := range match {
+			if !m(from, to, msg) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,120 @@
+package testnetwork
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicitynetwork/bft-core/network"
+	"github.com/unicitynetwork/bft-core/network/protocol/abdrc"
+)
+
+func TestFaults_Partition(t *testing.T) {
+	faults := NewFaults(1)
+	t.Cleanup(faults.Stop)
+	mockNet := NewRootMockNetwork()
+	net := faults.WrapRootNet("A", mockNet)
+
+	faults.Partition([]peer.ID{"A", "B"}, []peer.ID{"C"})
+	require.NoError(t, net.Send(context.Background(), &abdrc.VoteMsg{}, "B", "C", "D"))
+	msgs := mockNet.SentMessages(network.ProtocolRootVote)
+	require.Len(t, msgs, 1)
+	require.EqualValues(t, "B", msgs[0].ID)
+	require.Equal(t, FaultStats{Delivered: 1, Dropped: 2}, faults.Stats())
+
+	mockNet.ResetSentMessages(network.ProtocolRootVote)
+	faults.Heal()
+	require.NoError(t, net.Send(context.Background(), &abdrc.VoteMsg{}, "B", "C", "D"))
+	require.Len(t, mockNet.SentMessages(network.ProtocolRootVote), 3)
+}
+
+func TestFaults_Rules(t *testing.T) {
+	t.Run("drop by message type", func(t *testing.T) {
+		faults := NewFaults(1)
+		t.Cleanup(faults.Stop)
+		mockNet := NewRootMockNetwork()
+		net := faults.WrapRootNet("A", mockNet)
+
+		remove := faults.AddRule(FaultRule{Match: MatchType[abdrc.VoteMsg](), DropRate: 1})
+		require.NoError(t, net.Send(context.Background(), &abdrc.VoteMsg{}, "B"))
+		require.NoError(t, net.Send(context.Background(), &abdrc.TimeoutMsg{}, "B"))
+		require.Empty(t, mockNet.SentMessages(network.ProtocolRootVote))
+		require.Len(t, mockNet.SentMessages(network.ProtocolRootTimeout), 1)
+
+		remove()
+		require.NoError(t, net.Send(context.Background(), &abdrc.VoteMsg{}, "B"))
+		require.Len(t, mockNet.SentMessages(network.ProtocolRootVote), 1)
+	})
+
+	t.Run("drop by sender and receiver", func(t *testing.T) {
+		faults := NewFaults(1)
+		t.Cleanup(faults.Stop)
+		mockNet := NewRootMockNetwork()
+		faults.AddRule(FaultRule{Match: MatchAll(MatchFrom("A"), MatchTo("C")), DropRate: 1})
+
+		require.NoError(t, faults.WrapRootNet("A", mockNet).Send(context.Background(), &abdrc.VoteMsg{}, "B", "C"))
+		require.NoError(t, faults.WrapRootNet("B", mockNet).Send(context.Background(), &abdrc.VoteMsg{}, "C"))
+		msgs := mockNet.SentMessages(network.ProtocolRootVote)
+		require.Len(t, msgs, 2)
+		require.EqualValues(t, "B", msgs[0].ID)
+		require.EqualValues(t, "C", msgs[1].ID)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		faults := NewFaults(1)
+		t.Cleanup(faults.Stop)
+		mockNet := NewRootMockNetwork()
+		net := faults.WrapRootNet("A", mockNet)
+
+		faults.AddRule(FaultRule{Duplicate: 2})
+		require.NoError(t, net.Send(context.Background(), &abdrc.VoteMsg{}, "B"))
+		require.Len(t, mockNet.SentMessages(network.ProtocolRootVote), 3)
+		require.Equal(t, FaultStats{Delivered: 1, Duplicated: 2}, faults.Stats())
+	})
+
+	t.Run("delay", func(t *testing.T) {
+		faults := NewFaults(1)
+		t.Cleanup(faults.Stop)
+		mockNet := NewRootMockNetwork()
+		net := faults.WrapRootNet("A", mockNet)
+
+		faults.AddRule(FaultRule{Delay: 100 * time.Millisecond})
+		require.NoError(t, net.Send(context.Background(), &abdrc.VoteMsg{}, "B"))
+		require.Empty(t, mockNet.SentMessages(network.ProtocolRootVote))
+		require.Eventually(t, func() bool {
+			return len(mockNet.SentMessages(network.ProtocolRootVote)) == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("stop cancels delayed messages", func(t *testing.T) {
+		faults := NewFaults(1)
+		mockNet := NewRootMockNetwork()
+		net := faults.WrapRootNet("A", mockNet)
+
+		faults.AddRule(FaultRule{Delay: time.Hour})
+		require.NoError(t, net.Send(context.Background(), &abdrc.VoteMsg{}, "B"))
+		faults.Stop()
+		require.Empty(t, mockNet.SentMessages(network.ProtocolRootVote))
+	})
+
+	t.Run("same seed, same decisions", func(t *testing.T) {
+		run := func(seed int64) []PeerMessage {
+			faults := NewFaults(seed)
+			t.Cleanup(faults.Stop)
+			mockNet := NewRootMockNetwork()
+			net := faults.WrapRootNet("A", mockNet)
+			faults.AddRule(FaultRule{DropRate: 0.5})
+			for range 100 {
+				require.NoError(t, net.Send(context.Background(), &abdrc.VoteMsg{}, "B", "C"))
+			}
+			return mockNet.SentMessages(network.ProtocolRootVote)
+		}
+		msgs := run(42)
+		require.NotEmpty(t, msgs)
+		require.Less(t, len(msgs), 200)
+		require.Equal(t, msgs, run(42))
+	})
+}
@@ -32,6 +32,7 @@ type MockNet struct {
 	txBuffer     *txbuffer.TxBuffer
 	sentMessages map[string][]PeerMessage
 	protocols    map[reflect.Type]string
+	replPeers    peer.IDSlice
 }
 
 type PeerMessage struct {
@@ -58,6 +59,8 @@ func NewMockNetwork(t *testing.T) *MockNet {
 		{protocolID: network.ProtocolLedgerReplicationResp, msgStruct: replication.LedgerReplicationResponse{}},
 		{protocolID: network.ProtocolHandshake, msgStruct: handshake.Handshake{}},
 		{protocolID: network.ProtocolUnicityCertificates, msgStruct: certification.CertificationResponse{}},
+		{protocolID: network.ProtocolBlockSubscription, msgStruct: replication.BlockSubscriptionRequest{}},
+		{protocolID: network.ProtocolBlockPush, msgStruct: types.Block{}},
 	})
 	if err != nil {
 		panic(fmt.Errorf("failed to register protocols: %w", err))
@@ -166,7 +169,7 @@ func (m *MockNet) AddTransaction(ctx context.Context, tx *types.TransactionOrder
 
 func (m *MockNet) ProcessTransactions(ctx context.Context, txProcessor network.TxProcessor) {
 	for {
-		tx, err := m.txBuffer.Remove(ctx)
+		tx, _, err := m.txBuffer.Remove(ctx)
 		if err != nil {
 			return
 		}
@@ -197,6 +200,19 @@ func (n *MockNet) RegisterValidatorProtocols() error {
 func (n *MockNet) UnregisterValidatorProtocols() {
 }
 
+func (m *MockNet) ReplicationPeers() peer.IDSlice {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.replPeers
+}
+
+// SetReplicationPeers sets the peers returned by ReplicationPeers
+func (m *MockNet) SetReplicationPeers(peers ...peer.ID) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.replPeers = peers
+}
+
 type msgProtocol struct {
 	msgStruct  any
 	protocolID string
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"runtime/pprof"
 	"slices"
+	"strconv"
 	"testing"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 
 	test "github.com/unicitynetwork/bft-core/internal/testutils"
 	testlogger "github.com/unicitynetwork/bft-core/internal/testutils/logger"
+	testnetwork "github.com/unicitynetwork/bft-core/internal/testutils/network"
 	testobserve "github.com/unicitynetwork/bft-core/internal/testutils/observability"
 	testevent "github.com/unicitynetwork/bft-core/internal/testutils/partition/event"
 	"github.com/unicitynetwork/bft-core/logger"
@@ -43,6 +45,9 @@ const speedFactor = 4
 type UnicityNetwork struct {
 	RootChain *RootChain
 	Shards    map[types.PartitionShardID]*Shard
+	// Faults controls the network conditions between all the nodes (root and shard)
+	// of the network, by default there are no faults.
+	Faults    *testnetwork.Faults
 	ctx       context.Context
 	ctxCancel context.CancelFunc
 }
@@ -50,6 +55,7 @@ type UnicityNetwork struct {
 type RootChain struct {
 	TrustBase types.RootTrustBase
 	nodes     []*rootNode
+	faults    *testnetwork.Faults
 }
 
 type Shard struct {
@@ -110,15 +116,33 @@ func NewUnicityNetwork(t *testing.T, rootNodeCount int) *UnicityNetwork {
 	trustBase, err := types.NewTrustBaseGenesis(networkID, nodeInfos)
 	require.NoError(t, err)
 
+	faults := testnetwork.NewFaults(faultsSeed(t))
 	return &UnicityNetwork{
 		RootChain: &RootChain{
 			TrustBase: trustBase,
 			nodes:     rootNodes,
+			faults:    faults,
 		},
 		Shards: make(map[types.PartitionShardID]*Shard),
+		Faults: faults,
 	}
 }
 
+/*
+faultsSeed returns the seed of the network faults PRNG, fixed by default so the
+test runs are reproducible. Env var UBFT_TEST_FAULTS_SEED overrides the seed.
+*/
+func faultsSeed(t *testing.T) int64 {
+	seed := int64(1)
+	if v := os.Getenv("UBFT_TEST_FAULTS_SEED"); v != "" {
+		var err error
+		seed, err = strconv.ParseInt(v, 10, 64)
+		require.NoError(t, err, "invalid UBFT_TEST_FAULTS_SEED")
+	}
+	t.Logf("network faults seed: %d", seed)
+	return seed
+}
+
 // Start AB network, no bootstrap all id's and addresses are injected to peer store at start
 func (a *UnicityNetwork) Start(t *testing.T) error {
 	a.ctx, a.ctxCancel = context.WithCancel(context.Background())
@@ -159,6 +183,7 @@ func (a *UnicityNetwork) AddShard(t *testing.T, shardConf *types.PartitionDescri
 		log := testlogger.New(t).With(logger.NodeID(node.PeerConf.ID))
 		obs := observability.WithLogger(testobserve.Default(t), log)
 
+		nodeID := node.PeerConf.ID
 		bootNode := a.RootChain.nodes[0]
 		bootstrapAddress := fmt.Sprintf("%s/p2p/%s", bootNode.addr[0], bootNode.peerConf.ID)
 
@@ -171,6 +196,9 @@ func (a *UnicityNetwork) AddShard(t *testing.T, shardConf *types.PartitionDescri
 			partition.WithBootstrapAddresses([]string{bootstrapAddress}),
 			partition.WithEventHandler(eventHandler.HandleEvent, 100),
 			partition.WithT1Timeout(partition.DefaultT1Timeout*time.Millisecond/speedFactor),
+			partition.WithValidatorNetworkWrapper(func(vn partition.ValidatorNetwork) partition.ValidatorNetwork {
+				return a.Faults.WrapValidatorNet(nodeID, vn)
+			}),
 		)
 		require.NoError(t, err)
 
@@ -214,6 +242,7 @@ func (a *UnicityNetwork) waitShardActivation(t *testing.T, partitionID types.Par
 
 func (a *UnicityNetwork) Close() (retErr error) {
 	a.ctxCancel()
+	a.Faults.Stop()
 	// wait and check validator exit
 	for _, shard := range a.Shards {
 		// stop all nodes
@@ -334,7 +363,7 @@ func (r *RootChain) start(t *testing.T, ctx context.Context) error {
 			rootPeer.ID(),
 			r.TrustBase,
 			orchestration,
-			rootConsensusNet,
+			r.faults.WrapRootNet(rootPeer.ID(), rootConsensusNet),
 			rn.RootSigner,
 			rcDB,
 			obs,
@@ -342,7 +371,7 @@ func (r *RootChain) start(t *testing.T, ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("consensus manager initialization failed, %w", err)
 		}
-		node, err := rootchain.New(rootPeer, rootNet, cm, obs)
+		node, err := rootchain.New(rootPeer, r.faults.WrapRootNet(rootPeer.ID(), rootNet), cm, obs)
 		if err != nil {
 			return fmt.Errorf("failed to create root node, %w", err)
 		}
@@ -1,9 +1,12 @@
 package testpartition
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/libp2p/go-libp2p/core/peer"
+
 	test "github.com/unicitynetwork/bft-core/internal/testutils"
 	testtxsystem "github.com/unicitynetwork/bft-core/internal/testutils/txsystem"
 	"github.com/unicitynetwork/bft-core/txsystem"
@@ -50,3 +53,69 @@ func TestNewNetwork_Ok(t *testing.T) {
 	require.NoError(t, cPart.BroadcastTx(tx))
 	test.TryTilCountIs(t, BlockchainContainsTx(t, cPart, tx), 40, test.WaitTick)
 }
+
+func TestNewNetwork_PartitionAndHeal(t *testing.T) {
+	shardConf := &types.PartitionDescriptionRecord{
+		Version:         1,
+		NetworkID:       networkID,
+		PartitionID:     0x01020401,
+		PartitionTypeID: 1,
+		ShardID:         types.ShardID{},
+		TypeIDLen:       8,
+		UnitIDLen:       256,
+		T2Timeout:       2500 * time.Millisecond,
+		Epoch:           0,
+		EpochStart:      0,
+	}
+
+	abNetwork := NewUnicityNetwork(t, 3)
+	require.NoError(t, abNetwork.Start(t))
+	t.Cleanup(func() { abNetwork.WaitClose(t) })
+
+	abNetwork.AddShard(t, shardConf, 3, func(tb types.RootTrustBase) txsystem.TransactionSystem {
+		return &testtxsystem.CounterTxSystem{FixedState: testtxsystem.MockState{}}
+	})
+	cPart, err := abNetwork.GetShard(types.PartitionShardID{PartitionID: shardConf.PartitionID, ShardID: shardConf.ShardID.Key()})
+	require.NoError(t, err)
+	require.Eventually(t, ShardInitReady(t, cPart), test.WaitDuration*3, test.WaitTick)
+
+	// isolate one shard validator from the rest of the network, the others must
+	// keep certifying rounds (also when the isolated validator is the leader)
+	isolated := cPart.Nodes[2]
+	abNetwork.Faults.Partition([]peer.ID{isolated.Peer().ID()})
+	startRound := currentRound(t, cPart.Nodes[0])
+	require.Eventually(t, func() bool {
+		return currentRound(t, cPart.Nodes[0]) >= startRound+3
+	}, test.WaitDuration*3, test.WaitTick)
+	require.Positive(t, abNetwork.Faults.Stats().Dropped)
+
+	// after healing the isolated node must catch up with the others...
+	abNetwork.Faults.Heal()
+	require.Eventually(t, func() bool {
+		return currentRound(t, isolated) >= currentRound(t, cPart.Nodes[0])
+	}, test.WaitDuration*3, test.WaitTick)
+
+	// ...and take part in the consensus again. Tx is not submitted while the network is
+	// partitioned as it is lost when forwarded to the isolated leader.
+	tx := testtransaction.NewTransactionOrder(t, testtransaction.WithPartitionID(shardConf.PartitionID))
+	require.NoError(t, cPart.SubmitTx(tx))
+	test.TryTilCountIs(t, BlockchainContainsTx(t, cPart, tx), 40, test.WaitTick)
+	require.Eventually(t, func() bool {
+		want, err := cPart.Nodes[0].LatestBlockNumber()
+		if err != nil {
+			return false
+		}
+		got, err := isolated.LatestBlockNumber()
+		return err == nil && got >= want
+	}, test.WaitDuration*3, test.WaitTick)
+}
+
+// currentRound returns the current round of the node, zero when the node is not ready.
+func currentRound(t *testing.T, node *shardNode) uint64 {
+	t.Helper()
+	ri, err := node.CurrentRoundInfo(context.Background())
+	if err != nil {
+		return 0
+	}
+	return ri.RoundNumber
+}
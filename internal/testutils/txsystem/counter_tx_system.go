@@ -237,3 +237,7 @@ func (m MockState) Serialize(writer io.Writer, committed bool, executedTransacti
 func (m MockState) GetUnits(unitTypeID *uint32, pdr *types.PartitionDescriptionRecord) ([]types.UnitID, error) {
 	return nil, nil
 }
+
+func (m MockState) AtRound(round uint64) (*state.State, error) {
+	return nil, state.ErrRoundNotArchived
+}
@@ -0,0 +1,302 @@
+/*
+Package lightclient implements verification of transaction and unit state
+proofs for clients which do not follow the blockchain, they only need to trust
+the root chain's trust base.
+
+Verification is done in steps (see Step), when a step fails the returned error
+is of type *VerificationError which records the failed step so that the reason
+can be explained to the user.
+*/
+package lightclient
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/util"
+)
+
+// Step is a step of the proof verification.
+type Step string
+
+const (
+	StepTrustBase          Step = "trust base"
+	StepUnicityCertificate Step = "unicity certificate"
+	StepShardTree          Step = "shard tree certificate"
+	StepUnicityTree        Step = "unicity tree certificate"
+	StepUnicitySeal        Step = "unicity seal"
+	StepTxInclusion        Step = "transaction inclusion"
+	StepTxStatus           Step = "transaction status"
+	StepUnitTree           Step = "unit tree certificate"
+	StepStateTree          Step = "state tree certificate"
+)
+
+// steps of the transaction proof and unit state proof verification in the order they are verified
+var (
+	TxProofSteps        = []Step{StepTrustBase, StepUnicityCertificate, StepShardTree, StepUnicityTree, StepUnicitySeal, StepTxInclusion, StepTxStatus}
+	UnitStateProofSteps = []Step{StepTrustBase, StepUnicityCertificate, StepShardTree, StepUnicityTree, StepUnicitySeal, StepUnitTree, StepStateTree}
+)
+
+var stepDescriptions = map[Step]string{
+	StepTrustBase:          "chain of the root epochs is valid and has the trust base of the certificate's epoch",
+	StepUnicityCertificate: "unicity certificate is well-formed and issued to the shard",
+	StepShardTree:          "input record of the shard is certified by the shard tree",
+	StepUnicityTree:        "root of the shard tree is certified by the unicity tree",
+	StepUnicitySeal:        "root of the unicity tree is signed by the quorum of the root nodes",
+	StepTxInclusion:        "transaction record is included in the certified block",
+	StepTxStatus:           "transaction was executed successfully",
+	StepUnitTree:           "unit state is certified by the unit tree",
+	StepStateTree:          "unit tree is certified by the state tree of the certified state",
+}
+
+type (
+	/*
+		Verifier verifies proofs of the shard against the chain of root trust
+		bases. The first trust base is trusted, each following trust base must
+		be signed by the quorum of the root nodes of the previous epoch.
+	*/
+	Verifier struct {
+		trustBases    []*types.RootTrustBaseV1
+		hashAlgorithm crypto.Hash
+		shardConf     *types.PartitionDescriptionRecord
+	}
+
+	Option func(*Verifier)
+
+	// VerificationError is returned when a proof fails verification.
+	VerificationError struct {
+		Step Step
+		Err  error
+	}
+)
+
+// WithHashAlgorithm sets the hash algorithm used by the partition and the root chain (default SHA256).
+func WithHashAlgorithm(algorithm crypto.Hash) Option {
+	return func(v *Verifier) {
+		v.hashAlgorithm = algorithm
+	}
+}
+
+/*
+NewVerifier returns verifier of the proofs of the shard with given
+configuration certified by the root chain with given trust bases. Trust bases
+must be ordered by epoch and without gaps, the first trust base is trusted.
+Unicity certificates must be issued to the shard configuration, proofs without
+the shard conf hash are rejected.
+*/
+func NewVerifier(trustBases []*types.RootTrustBaseV1, shardConf *types.PartitionDescriptionRecord, opts ...Option) (*Verifier, error) {
+	v := &Verifier{
+		trustBases:    trustBases,
+		hashAlgorithm: crypto.SHA256,
+		shardConf:     shardConf,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if shardConf == nil {
+		return nil, &VerificationError{Step: StepUnicityCertificate, Err: errors.New("shard conf is missing")}
+	}
+	if err := v.verifyTrustBases(); err != nil {
+		return nil, &VerificationError{Step: StepTrustBase, Err: err}
+	}
+	return v, nil
+}
+
+func (v *Verifier) verifyTrustBases() error {
+	if len(v.trustBases) == 0 {
+		return errors.New("trust base is missing")
+	}
+	for i := 1; i < len(v.trustBases); i++ {
+		prev, tb := v.trustBases[i-1], v.trustBases[i]
+		if tb.Epoch != prev.Epoch+1 {
+			return fmt.Errorf("trust base of epoch %d follows trust base of epoch %d", tb.Epoch, prev.Epoch)
+		}
+		if tb.EpochStartRound <= prev.EpochStartRound {
+			return fmt.Errorf("epoch %d starts at round %d, not after the previous epoch (round %d)", tb.Epoch, tb.EpochStartRound, prev.EpochStartRound)
+		}
+		prevHash, err := prev.Hash(v.hashAlgorithm)
+		if err != nil {
+			return fmt.Errorf("hashing trust base of epoch %d: %w", prev.Epoch, err)
+		}
+		if !bytes.Equal(tb.PreviousEntryHash, prevHash) {
+			return fmt.Errorf("trust base of epoch %d: previous entry hash %X does not match hash of the epoch %d trust base %X", tb.Epoch, tb.PreviousEntryHash, prev.Epoch, prevHash)
+		}
+		sigBytes, err := tb.SigBytes()
+		if err != nil {
+			return fmt.Errorf("trust base of epoch %d: %w", tb.Epoch, err)
+		}
+		if err := prev.VerifyQuorumSignatures(sigBytes, tb.Signatures); err != nil {
+			return fmt.Errorf("trust base of epoch %d is not signed by the root nodes of epoch %d: %w", tb.Epoch, prev.Epoch, err)
+		}
+	}
+	return nil
+}
+
+/*
+VerifyTxProof verifies that the transaction record is included in the block
+certified by the root chain and that the transaction was executed successfully.
+*/
+func (v *Verifier) VerifyTxProof(proof *types.TxRecordProof) error {
+	if err := v.VerifyTxInclusion(proof); err != nil {
+		return err
+	}
+	if !proof.TxRecord.IsSuccessful() {
+		return &VerificationError{Step: StepTxStatus, Err: fmt.Errorf("transaction failed with status %d", proof.TxRecord.ServerMetadata.SuccessIndicator)}
+	}
+	return nil
+}
+
+/*
+VerifyTxInclusion verifies that the transaction record is included in the block
+certified by the root chain, the transaction may have failed.
+*/
+func (v *Verifier) VerifyTxInclusion(proof *types.TxRecordProof) error {
+	if err := proof.IsValid(); err != nil {
+		return &VerificationError{Step: StepTxInclusion, Err: err}
+	}
+	uc, err := proof.TxProof.GetUC()
+	if err != nil {
+		return &VerificationError{Step: StepUnicityCertificate, Err: err}
+	}
+	txo, err := proof.TxRecord.GetTransactionOrderV1()
+	if err != nil {
+		return &VerificationError{Step: StepTxInclusion, Err: fmt.Errorf("decoding transaction order: %w", err)}
+	}
+	tb, err := v.verifyUnicityCertificate(uc, txo.PartitionID)
+	if err != nil {
+		return err
+	}
+	// the certificate is verified above with the shard conf hash, the SDK verifies the inclusion
+	if err := types.VerifyTxInclusion(proof, tb, v.hashAlgorithm); err != nil {
+		return &VerificationError{Step: StepTxInclusion, Err: err}
+	}
+	return nil
+}
+
+/*
+VerifyUnitStateProof verifies that the unit has the given state in the state
+certified by the root chain.
+*/
+func (v *Verifier) VerifyUnitStateProof(proof *types.UnitStateProof, unitState *types.UnitState) error {
+	if err := proof.IsValid(); err != nil {
+		return &VerificationError{Step: StepUnitTree, Err: err}
+	}
+	if unitState == nil {
+		return &VerificationError{Step: StepUnitTree, Err: errors.New("unit state is nil")}
+	}
+	uc := &types.UnicityCertificate{}
+	if err := types.Cbor.Unmarshal(proof.UnicityCertificate, uc); err != nil {
+		return &VerificationError{Step: StepUnicityCertificate, Err: fmt.Errorf("decoding unicity certificate: %w", err)}
+	}
+	if err := v.VerifyUnicityCertificate(uc, uc.GetPartitionID()); err != nil {
+		return err
+	}
+
+	unitStateHash, err := unitState.Hash(v.hashAlgorithm)
+	if err != nil {
+		return &VerificationError{Step: StepUnitTree, Err: fmt.Errorf("hashing unit state: %w", err)}
+	}
+	if !bytes.Equal(proof.UnitTreeCert.UnitStateHash, unitStateHash) {
+		return &VerificationError{Step: StepUnitTree, Err: fmt.Errorf("unit state hash %X does not match unit state hash %X in the unit tree certificate", unitStateHash, proof.UnitTreeCert.UnitStateHash)}
+	}
+
+	stateRootHash, summary, err := proof.CalculateStateTreeOutput(v.hashAlgorithm)
+	if err != nil {
+		return &VerificationError{Step: StepStateTree, Err: err}
+	}
+	ir := uc.InputRecord
+	if !bytes.Equal(util.Uint64ToBytes(summary), ir.SummaryValue) {
+		return &VerificationError{Step: StepStateTree, Err: fmt.Errorf("summary value %X does not match summary value %X in the unicity certificate", util.Uint64ToBytes(summary), ir.SummaryValue)}
+	}
+	if !bytes.Equal(stateRootHash, ir.Hash) {
+		return &VerificationError{Step: StepStateTree, Err: fmt.Errorf("state root hash %X does not match state hash %X in the unicity certificate", stateRootHash, ir.Hash)}
+	}
+	return nil
+}
+
+/*
+VerifyUnicityCertificate verifies the unicity certificate issued to the
+partition: the shard conf hash, the shard tree and unicity tree certificates and
+the signatures of the unicity seal.
+*/
+func (v *Verifier) VerifyUnicityCertificate(uc *types.UnicityCertificate, partitionID types.PartitionID) error {
+	_, err := v.verifyUnicityCertificate(uc, partitionID)
+	return err
+}
+
+// verifyUnicityCertificate verifies the unicity certificate and returns the trust base it was verified with
+func (v *Verifier) verifyUnicityCertificate(uc *types.UnicityCertificate, partitionID types.PartitionID) (*types.RootTrustBaseV1, error) {
+	if v.shardConf.PartitionID != partitionID {
+		return nil, &VerificationError{Step: StepUnicityCertificate, Err: fmt.Errorf("proof is for partition %s, shard conf is for partition %s", partitionID, v.shardConf.PartitionID)}
+	}
+	shardConfHash, err := v.shardConf.Hash(v.hashAlgorithm)
+	if err != nil {
+		return nil, &VerificationError{Step: StepUnicityCertificate, Err: fmt.Errorf("hashing shard conf: %w", err)}
+	}
+	if err := uc.IsValid(partitionID, shardConfHash); err != nil {
+		return nil, &VerificationError{Step: StepUnicityCertificate, Err: err}
+	}
+
+	shardTreeRoot, err := uc.ShardTreeCertificate.ComputeCertificateHash(uc.InputRecord, uc.TRHash, uc.ShardConfHash, v.hashAlgorithm)
+	if err != nil {
+		return nil, &VerificationError{Step: StepShardTree, Err: err}
+	}
+	unicityTreeRoot, err := uc.UnicityTreeCertificate.EvalAuthPath(shardTreeRoot, v.hashAlgorithm)
+	if err != nil {
+		return nil, &VerificationError{Step: StepUnicityTree, Err: err}
+	}
+	if !bytes.Equal(unicityTreeRoot, uc.UnicitySeal.Hash) {
+		return nil, &VerificationError{Step: StepUnicityTree, Err: fmt.Errorf("root hash of the unicity tree %X does not match unicity seal hash %X", unicityTreeRoot, uc.UnicitySeal.Hash)}
+	}
+
+	tb, err := v.trustBase(uc.UnicitySeal)
+	if err != nil {
+		return nil, &VerificationError{Step: StepTrustBase, Err: err}
+	}
+	if uc.UnicitySeal.NetworkID != tb.NetworkID {
+		return nil, &VerificationError{Step: StepUnicitySeal, Err: fmt.Errorf("unicity seal is for network %d, trust base is for network %d", uc.UnicitySeal.NetworkID, tb.NetworkID)}
+	}
+	if err := uc.UnicitySeal.Verify(tb); err != nil {
+		return nil, &VerificationError{Step: StepUnicitySeal, Err: fmt.Errorf("epoch %d trust base: %w", tb.Epoch, err)}
+	}
+	return tb, nil
+}
+
+/*
+trustBase returns the trust base of the epoch of the unicity seal. Root chain
+counts epochs from 0 (genesis root epoch) while trust bases are numbered from 1,
+so the seal of the root epoch N is verified with the trust base of the epoch
+N+1. The root round of the seal must be in the range of the epoch.
+*/
+func (v *Verifier) trustBase(seal *types.UnicitySeal) (*types.RootTrustBaseV1, error) {
+	epoch := seal.Epoch + 1
+	for i, tb := range v.trustBases {
+		if tb.Epoch != epoch {
+			continue
+		}
+		if seal.RootChainRoundNumber < tb.EpochStartRound {
+			return nil, fmt.Errorf("root round %d is before the start round %d of the epoch %d", seal.RootChainRoundNumber, tb.EpochStartRound, tb.Epoch)
+		}
+		if i+1 < len(v.trustBases) && seal.RootChainRoundNumber >= v.trustBases[i+1].EpochStartRound {
+			return nil, fmt.Errorf("root round %d is after the end of the epoch %d", seal.RootChainRoundNumber, tb.Epoch)
+		}
+		return tb, nil
+	}
+	return nil, fmt.Errorf("no trust base for epoch %d, known epochs are %d..%d", epoch, v.trustBases[0].Epoch, v.trustBases[len(v.trustBases)-1].Epoch)
+}
+
+// Description explains what is verified in the step.
+func (s Step) Description() string {
+	return stepDescriptions[s]
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("%s verification failed: %v", e.Step, e.Err)
+}
+
+func (e *VerificationError) Unwrap() error {
+	return e.Err
+}
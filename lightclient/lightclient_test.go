@@ -0,0 +1,310 @@
+package lightclient
+
+import (
+	"crypto"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	test "github.com/unicitynetwork/bft-core/internal/testutils"
+	testcertificates "github.com/unicitynetwork/bft-core/internal/testutils/certificates"
+	testsig "github.com/unicitynetwork/bft-core/internal/testutils/sig"
+	testtb "github.com/unicitynetwork/bft-core/internal/testutils/trustbase"
+	"github.com/unicitynetwork/bft-core/state"
+	testtransaction "github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
+	abcrypto "github.com/unicitynetwork/bft-go-base/crypto"
+	"github.com/unicitynetwork/bft-go-base/predicates/templates"
+	"github.com/unicitynetwork/bft-go-base/txsystem/money"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+	"github.com/unicitynetwork/bft-go-base/util"
+)
+
+var testShardConf = &types.PartitionDescriptionRecord{
+	Version:         1,
+	NetworkID:       5,
+	PartitionID:     money.DefaultPartitionID,
+	PartitionTypeID: money.PartitionTypeID,
+	TypeIDLen:       8,
+	UnitIDLen:       256,
+	T2Timeout:       2500000000,
+}
+
+func TestNewVerifier_TrustBaseChain(t *testing.T) {
+	signer1, verifier1 := testsig.CreateSignerAndVerifier(t)
+	tb1 := newTrustBase(t, verifier1)
+	signer2, verifier2 := testsig.CreateSignerAndVerifier(t)
+
+	// epoch 2 trust base signed by the root nodes of epoch 1
+	nextEpoch := func(t *testing.T) *types.RootTrustBaseV1 {
+		tb2 := newTrustBase(t, verifier2)
+		tb2.Epoch = 2
+		tb2.EpochStartRound = 100
+		tb2.PreviousEntryHash = test.DoHash(t, tb1)
+		require.NoError(t, tb2.Sign(tb1.RootNodes[0].NodeID, signer1))
+		return tb2
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		tb2 := nextEpoch(t)
+		v, err := NewVerifier([]*types.RootTrustBaseV1{tb1, tb2}, testShardConf)
+		require.NoError(t, err)
+
+		// UC of the epoch 1
+		uc := newUC(t, signer1, tb1, newIR(t), 99)
+		require.NoError(t, v.VerifyUnicityCertificate(uc, testShardConf.PartitionID))
+
+		// UC of the epoch 2
+		uc = newUC(t, signer2, tb2, newIR(t), 100)
+		require.NoError(t, v.VerifyUnicityCertificate(uc, testShardConf.PartitionID))
+
+		// UC of the epoch 2 signed by epoch 1 root nodes
+		uc = newUC(t, signer1, tb2, newIR(t), 100)
+		requireStep(t, v.VerifyUnicityCertificate(uc, testShardConf.PartitionID), StepUnicitySeal, "epoch 2 trust base: verifying signatures: quorum not reached")
+
+		// trust base is selected by the epoch of the seal, not by the root round
+		uc = newUC(t, signer1, tb2, newIR(t), 99)
+		requireStep(t, v.VerifyUnicityCertificate(uc, testShardConf.PartitionID), StepTrustBase, "root round 99 is before the start round 100 of the epoch 2")
+		uc = newUC(t, signer1, tb1, newIR(t), 100)
+		requireStep(t, v.VerifyUnicityCertificate(uc, testShardConf.PartitionID), StepTrustBase, "root round 100 is after the end of the epoch 1")
+	})
+
+	t.Run("no trust base", func(t *testing.T) {
+		_, err := NewVerifier(nil, testShardConf)
+		requireStep(t, err, StepTrustBase, "trust base is missing")
+	})
+
+	t.Run("no shard conf", func(t *testing.T) {
+		_, err := NewVerifier([]*types.RootTrustBaseV1{tb1}, nil)
+		requireStep(t, err, StepUnicityCertificate, "shard conf is missing")
+	})
+
+	t.Run("epoch gap", func(t *testing.T) {
+		tb2 := nextEpoch(t)
+		tb2.Epoch = 3
+		_, err := NewVerifier([]*types.RootTrustBaseV1{tb1, tb2}, testShardConf)
+		requireStep(t, err, StepTrustBase, "trust base of epoch 3 follows trust base of epoch 1")
+	})
+
+	t.Run("invalid previous entry hash", func(t *testing.T) {
+		tb2 := nextEpoch(t)
+		tb2.PreviousEntryHash = []byte{1}
+		_, err := NewVerifier([]*types.RootTrustBaseV1{tb1, tb2}, testShardConf)
+		requireStep(t, err, StepTrustBase, "trust base of epoch 2: previous entry hash 01 does not match hash of the epoch 1 trust base")
+	})
+
+	t.Run("not signed by previous epoch", func(t *testing.T) {
+		tb2 := nextEpoch(t)
+		tb2.Signatures = map[string]hex.Bytes{}
+		require.NoError(t, tb2.Sign(tb2.RootNodes[0].NodeID, signer2))
+		_, err := NewVerifier([]*types.RootTrustBaseV1{tb1, tb2}, testShardConf)
+		requireStep(t, err, StepTrustBase, "trust base of epoch 2 is not signed by the root nodes of epoch 1: quorum not reached")
+	})
+
+	t.Run("UC of unknown epoch", func(t *testing.T) {
+		v, err := NewVerifier([]*types.RootTrustBaseV1{nextEpoch(t)}, testShardConf)
+		require.NoError(t, err)
+		uc := newUC(t, signer1, tb1, newIR(t), 10)
+		requireStep(t, v.VerifyUnicityCertificate(uc, testShardConf.PartitionID), StepTrustBase, "no trust base for epoch 1, known epochs are 2..2")
+	})
+}
+
+func TestVerifier_TxProof(t *testing.T) {
+	signer, verifier := testsig.CreateSignerAndVerifier(t)
+	tb := newTrustBase(t, verifier)
+	v, err := NewVerifier([]*types.RootTrustBaseV1{tb}, testShardConf)
+	require.NoError(t, err)
+
+	newProof := func(t *testing.T, status types.TxStatus) *types.TxRecordProof {
+		txo := testtransaction.NewTransactionOrder(t, testtransaction.WithPartitionID(testShardConf.PartitionID))
+		txRecord := &types.TransactionRecord{
+			Version:          1,
+			TransactionOrder: testtransaction.TxoToBytes(t, txo),
+			ServerMetadata:   &types.ServerMetadata{ActualFee: 1, SuccessIndicator: status},
+		}
+		block := &types.Block{
+			Header:       &types.Header{Version: 1, PartitionID: testShardConf.PartitionID, ProposerID: "test", PreviousBlockHash: make([]byte, 32)},
+			Transactions: []*types.TransactionRecord{txRecord, txRecord},
+		}
+		var err error
+		block.UnicityCertificate, err = types.Cbor.Marshal(&types.UnicityCertificate{Version: 1, InputRecord: newIR(t)})
+		require.NoError(t, err)
+		ir, err := block.CalculateBlockHash(crypto.SHA256)
+		require.NoError(t, err)
+		block.UnicityCertificate, err = types.Cbor.Marshal(newUC(t, signer, tb, ir, 10))
+		require.NoError(t, err)
+		proof, err := types.NewTxRecordProof(block, 1, crypto.SHA256)
+		require.NoError(t, err)
+		return proof
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		require.NoError(t, v.VerifyTxProof(newProof(t, types.TxStatusSuccessful)))
+	})
+
+	t.Run("transaction failed", func(t *testing.T) {
+		proof := newProof(t, types.TxStatusFailed)
+		require.NoError(t, v.VerifyTxInclusion(proof))
+		requireStep(t, v.VerifyTxProof(proof), StepTxStatus, "transaction failed with status 0")
+	})
+
+	t.Run("invalid merkle path", func(t *testing.T) {
+		proof := newProof(t, types.TxStatusSuccessful)
+		proof.TxProof.Chain[0].Hash = make([]byte, 32)
+		requireStep(t, v.VerifyTxProof(proof), StepTxInclusion, "proof block hash does not match to block hash in unicity certificate")
+	})
+
+	t.Run("invalid block header hash", func(t *testing.T) {
+		proof := newProof(t, types.TxStatusSuccessful)
+		proof.TxProof.BlockHeaderHash = make([]byte, 32)
+		requireStep(t, v.VerifyTxProof(proof), StepTxInclusion, "proof block hash does not match to block hash in unicity certificate")
+	})
+
+	t.Run("missing UC", func(t *testing.T) {
+		proof := newProof(t, types.TxStatusSuccessful)
+		proof.TxProof.UnicityCertificate = nil
+		requireStep(t, v.VerifyTxProof(proof), StepUnicityCertificate, "unicity certificate is nil")
+	})
+
+	t.Run("modified input record", func(t *testing.T) {
+		proof := newProof(t, types.TxStatusSuccessful)
+		uc, err := proof.TxProof.GetUC()
+		require.NoError(t, err)
+		uc.InputRecord.SumOfEarnedFees++
+		proof.TxProof.UnicityCertificate, err = types.Cbor.Marshal(uc)
+		require.NoError(t, err)
+		requireStep(t, v.VerifyTxProof(proof), StepUnicityTree, "does not match unicity seal hash")
+	})
+
+	t.Run("signed by unknown root node", func(t *testing.T) {
+		otherSigner, _ := testsig.CreateSignerAndVerifier(t)
+		proof := newProof(t, types.TxStatusSuccessful)
+		uc, err := proof.TxProof.GetUC()
+		require.NoError(t, err)
+		uc.UnicitySeal.Signatures = nil
+		require.NoError(t, uc.UnicitySeal.Sign(tb.RootNodes[0].NodeID, otherSigner))
+		proof.TxProof.UnicityCertificate, err = types.Cbor.Marshal(uc)
+		require.NoError(t, err)
+		requireStep(t, v.VerifyTxProof(proof), StepUnicitySeal, "epoch 1 trust base: verifying signatures: quorum not reached")
+	})
+
+	t.Run("missing shard conf hash", func(t *testing.T) {
+		proof := newProof(t, types.TxStatusSuccessful)
+		uc, err := proof.TxProof.GetUC()
+		require.NoError(t, err)
+		uc.ShardConfHash = nil
+		proof.TxProof.UnicityCertificate, err = types.Cbor.Marshal(uc)
+		require.NoError(t, err)
+		requireStep(t, v.VerifyTxProof(proof), StepUnicityCertificate, "invalid shard configuration hash")
+	})
+
+	t.Run("different shard conf", func(t *testing.T) {
+		shardConf := *testShardConf
+		shardConf.T2Timeout++
+		v, err := NewVerifier([]*types.RootTrustBaseV1{tb}, &shardConf)
+		require.NoError(t, err)
+		requireStep(t, v.VerifyTxProof(newProof(t, types.TxStatusSuccessful)), StepUnicityCertificate, "invalid shard configuration hash")
+	})
+}
+
+func TestVerifier_UnitStateProof(t *testing.T) {
+	signer, verifier := testsig.CreateSignerAndVerifier(t)
+	tb := newTrustBase(t, verifier)
+	v, err := NewVerifier([]*types.RootTrustBaseV1{tb}, testShardConf)
+	require.NoError(t, err)
+
+	// state with a few bills committed with UC signed by the root node
+	s := state.NewEmptyState()
+	var unitIDs []types.UnitID
+	for i := range byte(5) {
+		unitID := append(make(types.UnitID, 31), i, money.BillUnitType)
+		unitIDs = append(unitIDs, unitID)
+		require.NoError(t, s.Apply(state.AddUnit(unitID, &money.BillData{Version: 1, Value: uint64(i) * 10, OwnerPredicate: templates.AlwaysTrueBytes()})))
+		require.NoError(t, s.AddUnitLog(unitID, test.RandomBytes(32)))
+	}
+	summaryValue, summaryHash, err := s.CalculateRoot()
+	require.NoError(t, err)
+	ir := newIR(t)
+	ir.Hash = summaryHash
+	ir.SummaryValue = util.Uint64ToBytes(summaryValue)
+	require.NoError(t, s.Commit(newUC(t, signer, tb, ir, 10)))
+
+	unitID := unitIDs[3]
+	newProof := func(t *testing.T) (*types.UnitStateProof, *types.UnitState) {
+		proof, err := s.CreateUnitStateProof(unitID, 0)
+		require.NoError(t, err)
+		unit, err := s.GetUnit(unitID, true)
+		require.NoError(t, err)
+		unitState, err := types.NewUnitState(unit.Data(), 0, nil)
+		require.NoError(t, err)
+		return proof, unitState
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		require.NoError(t, v.VerifyUnitStateProof(newProof(t)))
+	})
+
+	t.Run("different unit state", func(t *testing.T) {
+		proof, _ := newProof(t)
+		unitState, err := types.NewUnitState(&money.BillData{Version: 1, Value: 1000}, 0, nil)
+		require.NoError(t, err)
+		requireStep(t, v.VerifyUnitStateProof(proof, unitState), StepUnitTree, "does not match unit state hash")
+	})
+
+	t.Run("invalid state tree certificate", func(t *testing.T) {
+		proof, unitState := newProof(t)
+		proof.UnitValue++
+		requireStep(t, v.VerifyUnitStateProof(proof, unitState), StepStateTree, "does not match summary value")
+
+		proof, unitState = newProof(t)
+		proof.StateTreeCert.LeftSummaryHash = make([]byte, 32)
+		requireStep(t, v.VerifyUnitStateProof(proof, unitState), StepStateTree, "does not match state hash")
+	})
+
+	t.Run("invalid UC", func(t *testing.T) {
+		proof, unitState := newProof(t)
+		proof.UnicityCertificate = []byte{1, 2, 3}
+		requireStep(t, v.VerifyUnitStateProof(proof, unitState), StepUnicityCertificate, "decoding unicity certificate")
+	})
+}
+
+func requireStep(t *testing.T, err error, step Step, msg string) {
+	t.Helper()
+	var verr *VerificationError
+	require.True(t, errors.As(err, &verr), "expected VerificationError, got %v", err)
+	require.Equal(t, step, verr.Step, err.Error())
+	require.ErrorContains(t, err, msg)
+}
+
+func newTrustBase(t *testing.T, verifier abcrypto.Verifier) *types.RootTrustBaseV1 {
+	tb, ok := testtb.NewTrustBase(t, verifier).(*types.RootTrustBaseV1)
+	require.True(t, ok)
+	return tb
+}
+
+func newIR(t *testing.T) *types.InputRecord {
+	return &types.InputRecord{
+		Version:      1,
+		RoundNumber:  5,
+		PreviousHash: test.RandomBytes(32),
+		Hash:         test.RandomBytes(32),
+		SummaryValue: []byte{0},
+		Timestamp:    types.NewTimestamp(),
+		BlockHash:    test.RandomBytes(32),
+		ETHash:       test.RandomBytes(32),
+	}
+}
+
+// newUC returns UC certifying the input record for testShardConf, signed by the "signer" in the network and epoch of "tb"
+func newUC(t *testing.T, signer abcrypto.Signer, tb *types.RootTrustBaseV1, ir *types.InputRecord, rootRound uint64) *types.UnicityCertificate {
+	uc := testcertificates.CreateUnicityCertificate(t, signer, ir, testShardConf, rootRound, nil, make([]byte, 32))
+	verifier, err := signer.Verifier()
+	require.NoError(t, err)
+	nodeID := testtb.NewTrustBase(t, verifier).GetRootNodes()[0].NodeID
+	uc.UnicitySeal.NetworkID = tb.NetworkID
+	uc.UnicitySeal.Epoch = tb.Epoch - 1
+	uc.UnicitySeal.Signatures = nil
+	require.NoError(t, uc.UnicitySeal.Sign(nodeID, signer))
+	return uc
+}
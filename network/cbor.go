@@ -2,39 +2,202 @@ package network
 
 import (
 	"bufio"
+	"bytes"
+	"compress/flate"
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/unicitynetwork/bft-go-base/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+/*
+traceEnvelopeTag is the CBOR tag of the envelope which carries the trace context
+(as injected by the globally configured propagator) along with the message.
+*/
+const traceEnvelopeTag types.CborTag = 1100
+
+// CBOR encoding of the head of the traceEnvelopeTag
+var traceEnvelopeHead = []byte{0xd9, 0x04, 0x4c}
+
+var ErrMsgTooLarge = errors.New("message exceeds the size limit")
+
+/*
+msgFormat describes the messages of the protocol on the stream. Each message is
+prefixed with its (uvarint) length, when the protocol is compressed the message
+data is flate compressed and the length is the length of the compressed data.
+*/
+type msgFormat struct {
+	maxSize    uint64 // limit of the (decompressed) message data length
+	compressed bool
+}
+
+type traceEnvelope struct {
+	_       struct{} `cbor:",toarray"`
+	Carrier propagation.MapCarrier
+	Msg     types.RawCBOR
+}
+
 func serializeMsg(msg any) ([]byte, error) {
 	data, err := types.Cbor.Marshal(msg)
 	if err != nil {
 		return nil, fmt.Errorf("marshaling %T as CBOR: %w", msg, err)
 	}
+	return prependLength(data), nil
+}
+
+/*
+serializeTracedMsg serializes the message wrapped into trace envelope when ctx
+contains valid span context, otherwise the bare message is serialized.
+*/
+func serializeTracedMsg(ctx context.Context, msg any) ([]byte, error) {
+	data, err := encodeTracedMsg(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	return prependLength(data), nil
+}
+
+/*
+encodeTracedMsg returns CBOR encoding of the message wrapped into trace envelope
+when ctx contains valid span context, otherwise CBOR encoding of the bare message.
+*/
+func encodeTracedMsg(ctx context.Context, msg any) ([]byte, error) {
+	data, err := types.Cbor.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling %T as CBOR: %w", msg, err)
+	}
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return data, nil
+	}
+	env := traceEnvelope{Carrier: propagation.MapCarrier{}, Msg: data}
+	otel.GetTextMapPropagator().Inject(ctx, env.Carrier)
+	if data, err = types.Cbor.MarshalTaggedValue(traceEnvelopeTag, env); err != nil {
+		return nil, fmt.Errorf("marshaling trace envelope: %w", err)
+	}
+	return data, nil
+}
+
+// frameMsg returns the message data prefixed with its length, compressed when requested.
+func frameMsg(data []byte, compressed bool) ([]byte, error) {
+	if compressed {
+		return compressMsg(data)
+	}
+	return prependLength(data), nil
+}
+
+// compressMsg returns flate compressed message data prefixed with the length of the compressed data.
+func compressMsg(data []byte) ([]byte, error) {
+	buf := bytes.Buffer{}
+	zw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, fmt.Errorf("creating compressor: %w", err)
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("compressing message: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compressing message: %w", err)
+	}
+	return prependLength(buf.Bytes()), nil
+}
+
+func prependLength(data []byte) []byte {
 	length := uint64(len(data))
 	lengthBytes := make([]byte, 8, 8+length)
 	bytesWritten := binary.PutUvarint(lengthBytes, length)
-	return append(lengthBytes[:bytesWritten], data...), nil
+	return append(lengthBytes[:bytesWritten], data...)
 }
 
-func deserializeMsg(r io.Reader, msg any) error {
+func deserializeMsg(r io.Reader, msg any, maxSize uint64) error {
+	_, err := deserializeTracedMsg(r, msg, maxSize)
+	return err
+}
+
+/*
+deserializeTracedMsg decodes message (of at most maxSize bytes) which might be wrapped
+into trace envelope. Returns the trace context carrier of the envelope, nil when bare
+message was received.
+*/
+func deserializeTracedMsg(r io.Reader, msg any, maxSize uint64) (propagation.MapCarrier, error) {
+	return decodeMsg(r, msg, msgFormat{maxSize: maxSize})
+}
+
+/*
+decodeMsg decodes message of the given format. The length of the message is
+checked against the size limit before the message is decoded, ErrMsgTooLarge
+is returned when the message exceeds the limit.
+*/
+func decodeMsg(r io.Reader, msg any, format msgFormat) (propagation.MapCarrier, error) {
 	src := bufio.NewReader(r)
 	// read data length
 	length64, err := binary.ReadUvarint(src)
 	if err != nil {
-		return fmt.Errorf("reading data length: %w", err)
+		return nil, fmt.Errorf("reading data length: %w", err)
 	}
 	if length64 == 0 {
-		return fmt.Errorf("unexpected data length zero")
+		return nil, fmt.Errorf("unexpected data length zero")
+	}
+	if length64 > format.maxSize {
+		return nil, fmt.Errorf("%w: data length %d, limit %d", ErrMsgTooLarge, length64, format.maxSize)
 	}
 
-	lengthInt64 := int64(length64) /* #nosec G115 its unlikely that value of length64 exceeds int64 max value */
-	if err := types.Cbor.Decode(io.LimitReader(src, lengthInt64), msg); err != nil {
-		return fmt.Errorf("decoding message data: %w", err)
+	lengthInt64 := int64(length64) /* #nosec G115 length is limited by the max message size */
+	if format.compressed {
+		if src, lengthInt64, err = decompressMsg(src, lengthInt64, format.maxSize); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	if head, err := src.Peek(len(traceEnvelopeHead)); err != nil || !bytes.Equal(head, traceEnvelopeHead) {
+		if err := types.Cbor.Decode(io.LimitReader(src, lengthInt64), msg); err != nil {
+			return nil, fmt.Errorf("decoding message data: %w", err)
+		}
+		return nil, nil
+	}
+
+	var raw cbor.RawTag
+	if err := types.Cbor.Decode(io.LimitReader(src, lengthInt64), &raw); err != nil {
+		return nil, fmt.Errorf("decoding trace envelope: %w", err)
+	}
+	var env traceEnvelope
+	if err := types.Cbor.Unmarshal(raw.Content, &env); err != nil {
+		return nil, fmt.Errorf("decoding trace envelope: %w", err)
+	}
+	if err := types.Cbor.Unmarshal(env.Msg, msg); err != nil {
+		return nil, fmt.Errorf("decoding message data: %w", err)
+	}
+	return env.Carrier, nil
+}
+
+/*
+decompressMsg reads the compressed message data (length bytes) from src and returns
+reader of the decompressed data and its length. The decompressed data may not exceed
+the maxSize bytes.
+*/
+func decompressMsg(src io.Reader, length int64, maxSize uint64) (*bufio.Reader, int64, error) {
+	// the whole message is read so that the stream is positioned at the next message
+	data := make([]byte, length)
+	if _, err := io.ReadFull(src, data); err != nil {
+		return nil, 0, fmt.Errorf("reading compressed message data: %w", err)
+	}
+	zr := flate.NewReader(bytes.NewReader(data))
+	defer zr.Close()
+	data, err := io.ReadAll(io.LimitReader(zr, int64(maxSize)+1)) /* #nosec G115 max message size is far below int64 max value */
+	if err != nil {
+		return nil, 0, fmt.Errorf("decompressing message data: %w", err)
+	}
+	if uint64(len(data)) > maxSize {
+		return nil, 0, fmt.Errorf("%w: decompressed data exceeds limit %d", ErrMsgTooLarge, maxSize)
+	}
+	if len(data) == 0 {
+		return nil, 0, fmt.Errorf("unexpected decompressed data length zero")
+	}
+	return bufio.NewReader(bytes.NewReader(data)), int64(len(data)), nil
 }
@@ -1,12 +1,18 @@
 package network
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type noCBOR struct {
@@ -17,6 +23,9 @@ type noCBOR struct {
 func (*noCBOR) MarshalCBOR() ([]byte, error) { return nil, errors.New("no CBOR for this type") }
 func (*noCBOR) UnmarshalCBOR([]byte) error   { return errors.New("no CBOR for this type") }
 
+// size limit of the messages of the test protocols
+const testMaxMsgSize = 4 << 20
+
 func Test_serializeMsg(t *testing.T) {
 	type testMsg struct {
 		_     struct{} `cbor:",toarray"`
@@ -37,12 +46,65 @@ func Test_serializeMsg(t *testing.T) {
 		require.NotNil(t, b)
 
 		var dest testMsg
-		err = deserializeMsg(bytes.NewReader(b), &dest)
+		err = deserializeMsg(bytes.NewReader(b), &dest, testMaxMsgSize)
 		require.NoError(t, err)
 		require.Equal(t, msg, dest)
 	})
 }
 
+func Test_serializeTracedMsg(t *testing.T) {
+	type testMsg struct {
+		_     struct{} `cbor:",toarray"`
+		Name  string
+		Value int
+	}
+	observability.Default(t) // sets the global propagator
+	msg := testMsg{Name: "foo", Value: 12}
+
+	t.Run("no trace context", func(t *testing.T) {
+		b, err := serializeTracedMsg(context.Background(), msg)
+		require.NoError(t, err)
+		bare, err := serializeMsg(msg)
+		require.NoError(t, err)
+		require.Equal(t, bare, b)
+
+		var dest testMsg
+		carrier, err := deserializeTracedMsg(bytes.NewReader(b), &dest, testMaxMsgSize)
+		require.NoError(t, err)
+		require.Empty(t, carrier)
+		require.Equal(t, msg, dest)
+	})
+
+	t.Run("with trace context", func(t *testing.T) {
+		sc := testSpanContext()
+		b, err := serializeTracedMsg(trace.ContextWithSpanContext(context.Background(), sc), msg)
+		require.NoError(t, err)
+
+		var dest testMsg
+		carrier, err := deserializeTracedMsg(bytes.NewReader(b), &dest, testMaxMsgSize)
+		require.NoError(t, err)
+		require.Equal(t, msg, dest)
+		require.Contains(t, carrier.Get("traceparent"), sc.TraceID().String())
+
+		// receiver which doesn't care about trace context
+		dest = testMsg{}
+		require.NoError(t, deserializeMsg(bytes.NewReader(b), &dest, testMaxMsgSize))
+		require.Equal(t, msg, dest)
+	})
+
+	t.Run("tagged message without trace context", func(t *testing.T) {
+		// messages which are tagged CBOR themselves must not be mistaken for the envelope
+		block := &types.Block{Header: &types.Header{Version: 1, PartitionID: 1}}
+		b, err := serializeMsg(block)
+		require.NoError(t, err)
+		dest := &types.Block{}
+		carrier, err := deserializeTracedMsg(bytes.NewReader(b), dest, testMaxMsgSize)
+		require.NoError(t, err)
+		require.Empty(t, carrier)
+		require.Equal(t, block.Header, dest.Header)
+	})
+}
+
 func Test_deserializeMsg(t *testing.T) {
 	type testMsg struct {
 		_     struct{} `cbor:",toarray"`
@@ -58,32 +120,32 @@ func Test_deserializeMsg(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		var dest testMsg
-		require.NoError(t, deserializeMsg(bytes.NewReader(cborData), &dest))
+		require.NoError(t, deserializeMsg(bytes.NewReader(cborData), &dest, testMaxMsgSize))
 		require.Equal(t, msg, dest)
 	})
 
 	t.Run("destination type doesn't support CBOR", func(t *testing.T) {
 		var dest noCBOR
-		err := deserializeMsg(bytes.NewReader(cborData), &dest)
+		err := deserializeMsg(bytes.NewReader(cborData), &dest, testMaxMsgSize)
 		require.EqualError(t, err, `decoding message data: no CBOR for this type`)
 	})
 
 	t.Run("empty input", func(t *testing.T) {
 		var dest testMsg
-		err := deserializeMsg(bytes.NewReader(nil), &dest)
+		err := deserializeMsg(bytes.NewReader(nil), &dest, testMaxMsgSize)
 		require.EqualError(t, err, `reading data length: EOF`)
 	})
 
 	t.Run("data stream is shorter than expected", func(t *testing.T) {
 		var dest testMsg
-		err := deserializeMsg(bytes.NewReader(cborData[:len(cborData)-1]), &dest)
+		err := deserializeMsg(bytes.NewReader(cborData[:len(cborData)-1]), &dest, testMaxMsgSize)
 		require.EqualError(t, err, `decoding message data: unexpected EOF`)
 	})
 
 	t.Run("extra data in the data stream", func(t *testing.T) {
 		// data stream has a "length" in it so the extra data should be ignored
 		var dest testMsg
-		err := deserializeMsg(bytes.NewReader(append(slices.Clone(cborData), 2, 3, 4)), &dest)
+		err := deserializeMsg(bytes.NewReader(append(slices.Clone(cborData), 2, 3, 4)), &dest, testMaxMsgSize)
 		require.NoError(t, err)
 	})
 
@@ -91,7 +153,7 @@ func Test_deserializeMsg(t *testing.T) {
 		data := slices.Clone(cborData)
 		data[0]--
 		var dest testMsg
-		err := deserializeMsg(bytes.NewReader(data), &dest)
+		err := deserializeMsg(bytes.NewReader(data), &dest, testMaxMsgSize)
 		require.EqualError(t, err, `decoding message data: unexpected EOF`)
 	})
 
@@ -103,7 +165,7 @@ func Test_deserializeMsg(t *testing.T) {
 		data := append(slices.Clone(cborData), 42)
 		data[0]++
 		var dest testMsg
-		err := deserializeMsg(bytes.NewReader(data), &dest)
+		err := deserializeMsg(bytes.NewReader(data), &dest, testMaxMsgSize)
 		require.NoError(t, err)
 		require.Equal(t, msg, dest)
 	})
@@ -112,7 +174,93 @@ func Test_deserializeMsg(t *testing.T) {
 		data := slices.Clone(cborData)
 		data[0] = 0
 		var dest testMsg
-		err := deserializeMsg(bytes.NewReader(data), &dest)
+		err := deserializeMsg(bytes.NewReader(data), &dest, testMaxMsgSize)
 		require.EqualError(t, err, `unexpected data length zero`)
 	})
 }
+
+func Test_decodeMsg(t *testing.T) {
+	type testMsg struct {
+		_     struct{} `cbor:",toarray"`
+		Name  string
+		Value int
+	}
+	observability.Default(t) // sets the global propagator
+	msg := testMsg{Name: "foo", Value: 12}
+	data, err := encodeTracedMsg(context.Background(), msg)
+	require.NoError(t, err)
+
+	t.Run("message exceeds size limit", func(t *testing.T) {
+		var dest testMsg
+		_, err := decodeMsg(bytes.NewReader(prependLength(data)), &dest, msgFormat{maxSize: uint64(len(data) - 1)})
+		require.ErrorIs(t, err, ErrMsgTooLarge)
+		require.Empty(t, dest)
+
+		// the data is not read, only the length
+		_, err = decodeMsg(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0x7f}), &dest, msgFormat{maxSize: testMaxMsgSize})
+		require.ErrorIs(t, err, ErrMsgTooLarge)
+	})
+
+	t.Run("compressed", func(t *testing.T) {
+		b, err := frameMsg(data, true)
+		require.NoError(t, err)
+		var dest testMsg
+		carrier, err := decodeMsg(bytes.NewReader(b), &dest, msgFormat{maxSize: testMaxMsgSize, compressed: true})
+		require.NoError(t, err)
+		require.Empty(t, carrier)
+		require.Equal(t, msg, dest)
+	})
+
+	t.Run("compressed, with trace context", func(t *testing.T) {
+		sc := testSpanContext()
+		data, err := encodeTracedMsg(trace.ContextWithSpanContext(context.Background(), sc), msg)
+		require.NoError(t, err)
+		b, err := frameMsg(data, true)
+		require.NoError(t, err)
+		var dest testMsg
+		carrier, err := decodeMsg(bytes.NewReader(b), &dest, msgFormat{maxSize: testMaxMsgSize, compressed: true})
+		require.NoError(t, err)
+		require.Equal(t, msg, dest)
+		require.Contains(t, carrier.Get("traceparent"), sc.TraceID().String())
+	})
+
+	t.Run("compressed messages in the stream", func(t *testing.T) {
+		msg2 := testMsg{Name: "bar", Value: 42}
+		data2, err := encodeTracedMsg(context.Background(), msg2)
+		require.NoError(t, err)
+		b1, err := frameMsg(data, true)
+		require.NoError(t, err)
+		b2, err := frameMsg(data2, true)
+		require.NoError(t, err)
+
+		r := bufio.NewReader(bytes.NewReader(append(b1, b2...)))
+		var dest testMsg
+		_, err = decodeMsg(r, &dest, msgFormat{maxSize: testMaxMsgSize, compressed: true})
+		require.NoError(t, err)
+		require.Equal(t, msg, dest)
+		_, err = decodeMsg(r, &dest, msgFormat{maxSize: testMaxMsgSize, compressed: true})
+		require.NoError(t, err)
+		require.Equal(t, msg2, dest)
+	})
+
+	t.Run("decompressed message exceeds size limit", func(t *testing.T) {
+		// compresses well, ie compressed data is within the limit
+		big := testMsg{Name: strings.Repeat("a", 10000)}
+		data, err := encodeTracedMsg(context.Background(), big)
+		require.NoError(t, err)
+		b, err := frameMsg(data, true)
+		require.NoError(t, err)
+		require.Less(t, len(b), 1000)
+
+		var dest testMsg
+		_, err = decodeMsg(bytes.NewReader(b), &dest, msgFormat{maxSize: 1000, compressed: true})
+		require.ErrorIs(t, err, ErrMsgTooLarge)
+		require.Empty(t, dest)
+	})
+
+	t.Run("invalid compressed data", func(t *testing.T) {
+		var dest testMsg
+		_, err := decodeMsg(bytes.NewReader(prependLength(data)), &dest, msgFormat{maxSize: testMaxMsgSize, compressed: true})
+		require.ErrorContains(t, err, "decompressing message data")
+	})
+}
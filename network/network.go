@@ -9,14 +9,19 @@ import (
 	"log/slog"
 	"reflect"
 	"slices"
+	"sync"
 	"time"
 
 	libp2pNetwork "github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/unicitynetwork/bft-core/logger"
+	"github.com/unicitynetwork/bft-core/network/protocol/replication"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -30,23 +35,54 @@ type (
 
 	SendProtocolDescription struct {
 		ProtocolID string
-		MsgType    any           // value of the message type of the protocol
-		Timeout    time.Duration // timeout per receiver
+		// optional version of the protocol which sends flate compressed messages,
+		// it is preferred when the receiver supports it
+		CompressedProtocolID string
+		MsgType              any           // value of the message type of the protocol
+		Timeout              time.Duration // timeout per receiver
 	}
 
 	ReceiveProtocolDescription struct {
 		ProtocolID string
+		// optional version of the protocol which receives flate compressed messages
+		CompressedProtocolID string
 		// constructor which returns pointer to a data struct into which
 		// received message can be stored
 		TypeFn  func() any
 		Handler libp2pNetwork.StreamHandler
+		// size limit of the (decompressed) message, must be set unless custom Handler
+		// is used. Peer which sends message exceeding the limit is disconnected.
+		MaxMsgSize uint64
 	}
 
 	sendProtocolData struct {
-		protocolID string
-		timeout    time.Duration // per receiver timeout, ie when sending batch this is for each msg!
+		protocolID           string
+		compressedProtocolID string
+		timeout              time.Duration // per receiver timeout, ie when sending batch this is for each msg!
 	}
 
+	/*
+		encodedMsg is the message serialized for sending with either the plain or the
+		compressed version of the protocol (compressed data is created on demand).
+	*/
+	encodedMsg struct {
+		plain      []byte
+		compressed func() ([]byte, error)
+	}
+
+	/*
+		TracedMsg is delivered by the ReceivedChannel instead of the bare message when
+		the sender propagated trace context along with the message. Use UnwrapMsg to
+		get the message and the context in which to continue the trace.
+	*/
+	TracedMsg struct {
+		Msg     any
+		carrier propagation.MapCarrier
+	}
+
+	// NetworkOption configures optional features of the LibP2PNetwork.
+	NetworkOption func(*LibP2PNetwork)
+
 	Observability interface {
 		Tracer(name string, options ...trace.TracerOption) trace.Tracer
 		Meter(name string, opts ...metric.MeterOption) metric.Meter
@@ -60,11 +96,24 @@ LibP2PNetwork implements "unicity network" using libp2p.
 Zero value is not useable, use one of the constructors to create network!
 */
 type LibP2PNetwork struct {
-	self          *Peer
-	sendProtocols map[reflect.Type]*sendProtocolData
-	receivedMsgs  chan any // messages from LibP2PNetwork sent to this peer
-	tracer        trace.Tracer
-	log           *slog.Logger
+	self           *Peer
+	sendProtocols  map[reflect.Type]*sendProtocolData
+	receivedMsgs   chan any // messages from LibP2PNetwork sent to this peer
+	propagateTrace bool     // send messages wrapped into trace envelope
+	tracer         trace.Tracer
+	log            *slog.Logger
+}
+
+/*
+WithTracePropagation enables sending the trace context along with the messages
+(in the trace envelope) so that the receiver continues the sender's trace. Off
+by default as nodes which do not support the trace envelope can't decode such
+messages, messages in the envelope are always accepted by the receiver.
+*/
+func WithTracePropagation(enabled bool) NetworkOption {
+	return func(n *LibP2PNetwork) {
+		n.propagateTrace = enabled
+	}
 }
 
 /*
@@ -75,7 +124,7 @@ In case of slow consumer up to "capacity" messages are buffered, after that mess
 
 Logger (log) is assumed to already have node_id attribute added, won't be added by NW component!
 */
-func NewLibP2PNetwork(self *Peer, capacity uint, obs Observability) (*LibP2PNetwork, error) {
+func NewLibP2PNetwork(self *Peer, capacity uint, obs Observability, opts ...NetworkOption) (*LibP2PNetwork, error) {
 	if self == nil {
 		return nil, errors.New("peer is nil")
 	}
@@ -87,6 +136,9 @@ func NewLibP2PNetwork(self *Peer, capacity uint, obs Observability) (*LibP2PNetw
 		tracer:        obs.Tracer("LibP2PNetwork"),
 		log:           obs.Logger(),
 	}
+	for _, opt := range opts {
+		opt(n)
+	}
 	return n, nil
 }
 
@@ -94,6 +146,20 @@ func (n *LibP2PNetwork) ReceivedChannel() <-chan any {
 	return n.receivedMsgs
 }
 
+/*
+UnwrapMsg returns the message received from the ReceivedChannel and the context
+for handling it. When the message was sent with trace context the returned context
+continues the sender's trace, otherwise the span of the handler will be a new root.
+*/
+func UnwrapMsg(ctx context.Context, msg any) (context.Context, any) {
+	// the handler of the message must not continue the trace of the receive loop
+	ctx = trace.ContextWithSpanContext(ctx, trace.SpanContext{})
+	if tm, ok := msg.(*TracedMsg); ok {
+		return otel.GetTextMapPropagator().Extract(ctx, tm.carrier), tm.Msg
+	}
+	return ctx, msg
+}
+
 // Send - send a single message to one or more peers asynchronously
 func (n *LibP2PNetwork) Send(ctx context.Context, msg any, receivers ...peer.ID) error {
 	if len(receivers) == 0 {
@@ -120,6 +186,7 @@ func (n *LibP2PNetwork) SendMsgs(ctx context.Context, messages MsgQueue, receive
 	ctx, span := n.tracer.Start(ctx, "network.SenMsgs", trace.WithAttributes(attribute.Stringer("receiver", receiver)))
 	defer span.End()
 	var stream libp2pNetwork.Stream
+	var compressed bool
 	var err error
 	for messages.Len() > 0 {
 		msg := messages.PopFront()
@@ -129,13 +196,17 @@ func (n *LibP2PNetwork) SendMsgs(ctx context.Context, messages MsgQueue, receive
 			if !f {
 				return fmt.Errorf("no protocol registered for messages of type %T", msg)
 			}
-			stream, err = n.self.CreateStream(ctx, receiver, p.protocolID)
+			stream, err = n.self.CreateStream(ctx, receiver, p.protocolIDs()...)
 			if err != nil {
 				return fmt.Errorf("opening p2p stream %w", err)
 			}
+			compressed = p.isCompressed(stream)
 		}
 		var data []byte
-		data, err = serializeMsg(msg)
+		data, err = n.encodeMsg(ctx, msg)
+		if err == nil {
+			data, err = frameMsg(data, compressed)
+		}
 		if err != nil {
 			// if serialization fails, then still try to send the rest
 			resErr = errors.Join(resErr, fmt.Errorf("serializing message: %w", err))
@@ -155,7 +226,7 @@ func (n *LibP2PNetwork) sendAsync(ctx context.Context, protocol *sendProtocolDat
 	ctx, span := n.tracer.Start(ctx, "LibP2PNetwork.sendAsync")
 	defer span.End()
 
-	data, err := serializeMsg(msg)
+	data, err := n.newEncodedMsg(ctx, msg)
 	if err != nil {
 		return fmt.Errorf("serializing message: %w", err)
 	}
@@ -164,7 +235,9 @@ func (n *LibP2PNetwork) sendAsync(ctx context.Context, protocol *sendProtocolDat
 		// loop-back for self-messages as libp2p would otherwise error:
 		// open stream error: failed to dial: dial to self attempted
 		if receiver == n.self.ID() {
-			if err = n.receivedMsg(n.self.ID(), protocol.protocolID, msg); err != nil {
+			carrier := propagation.MapCarrier{}
+			otel.GetTextMapPropagator().Inject(ctx, carrier)
+			if err = n.receivedMsg(n.self.ID(), protocol.protocolID, msg, carrier); err != nil {
 				// todo: this must be improved loop-back should not fail
 				n.log.WarnContext(ctx, "message loop-back failed", logger.Error(err))
 			}
@@ -176,7 +249,7 @@ func (n *LibP2PNetwork) sendAsync(ctx context.Context, protocol *sendProtocolDat
 			// network nodes
 			sendCtx, cancel := context.WithTimeout(ctx, protocol.timeout)
 			defer cancel()
-			if err := sendMsg(sendCtx, host, protocol.protocolID, data, receiverID); err != nil {
+			if err := sendMsg(sendCtx, host, protocol, data, receiverID); err != nil {
 				n.log.WarnContext(sendCtx, fmt.Sprintf("sending %s to %v", protocol.protocolID, receiverID), logger.Error(err))
 			}
 		}(n.self, receiver)
@@ -184,8 +257,8 @@ func (n *LibP2PNetwork) sendAsync(ctx context.Context, protocol *sendProtocolDat
 	return nil
 }
 
-func sendMsg(ctx context.Context, host *Peer, protocolID string, data []byte, receiverID peer.ID) (err error) {
-	s, err := host.CreateStream(ctx, receiverID, protocolID)
+func sendMsg(ctx context.Context, host *Peer, protocol *sendProtocolData, msg *encodedMsg, receiverID peer.ID) (err error) {
+	s, err := host.CreateStream(ctx, receiverID, protocol.protocolIDs()...)
 	if err != nil {
 		return fmt.Errorf("open p2p stream: %w", err)
 	}
@@ -199,6 +272,10 @@ func sendMsg(ctx context.Context, host *Peer, protocolID string, data []byte, re
 			return fmt.Errorf("error setting write deadline: %w", err)
 		}
 	}
+	data, err := msg.frame(protocol.isCompressed(s))
+	if err != nil {
+		return fmt.Errorf("serializing message: %w", err)
+	}
 	if _, err = s.Write(data); err != nil {
 		return fmt.Errorf("writing data to p2p stream: %w", err)
 	}
@@ -206,12 +283,67 @@ func sendMsg(ctx context.Context, host *Peer, protocolID string, data []byte, re
 	return nil
 }
 
+func (n *LibP2PNetwork) newEncodedMsg(ctx context.Context, msg any) (*encodedMsg, error) {
+	data, err := n.encodeMsg(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &encodedMsg{
+		plain:      prependLength(data),
+		compressed: sync.OnceValues(func() ([]byte, error) { return compressMsg(data) }),
+	}, nil
+}
+
+/*
+encodeMsg returns CBOR encoding of the message, wrapped into trace envelope when
+trace propagation is enabled.
+*/
+func (n *LibP2PNetwork) encodeMsg(ctx context.Context, msg any) ([]byte, error) {
+	if n.propagateTrace {
+		return encodeTracedMsg(ctx, msg)
+	}
+	data, err := types.Cbor.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling %T as CBOR: %w", msg, err)
+	}
+	return data, nil
+}
+
+// frame returns the message data to be written to the stream of the (compressed) protocol.
+func (m *encodedMsg) frame(compressed bool) ([]byte, error) {
+	if compressed {
+		return m.compressed()
+	}
+	return m.plain, nil
+}
+
+// protocolIDs returns the IDs of the protocol in the order of preference.
+func (p *sendProtocolData) protocolIDs() []string {
+	if p.compressedProtocolID == "" {
+		return []string{p.protocolID}
+	}
+	return []string{p.compressedProtocolID, p.protocolID}
+}
+
+// isCompressed returns true when the compressed version of the protocol was negotiated for the stream.
+func (p *sendProtocolData) isCompressed(s libp2pNetwork.Stream) bool {
+	return p.compressedProtocolID != "" && string(s.Protocol()) == p.compressedProtocolID
+}
+
+// penalizePeer disconnects the peer which violated the protocol.
+func (n *LibP2PNetwork) penalizePeer(id peer.ID, protocolID string, reason error) {
+	n.log.Warn(fmt.Sprintf("disconnecting peer %s which sent invalid %q message", id, protocolID), logger.Error(reason))
+	if err := n.self.host.Network().ClosePeer(id); err != nil {
+		n.log.Warn(fmt.Sprintf("closing connection to peer %s", id), logger.Error(err))
+	}
+}
+
 /*
 streamHandlerForProtocol returns libp2p stream handler for given protocolID.
 The "ctor" is constructor which returns pointer to a data struct into which
 incoming message can be stored.
 */
-func (n *LibP2PNetwork) streamHandlerForProtocol(protocolID string, ctor func() any) libp2pNetwork.StreamHandler {
+func (n *LibP2PNetwork) streamHandlerForProtocol(protocolID string, ctor func() any, format msgFormat) libp2pNetwork.StreamHandler {
 	return func(s libp2pNetwork.Stream) {
 		success := false
 		defer func() {
@@ -235,14 +367,19 @@ func (n *LibP2PNetwork) streamHandlerForProtocol(protocolID string, ctor func()
 		reader := bufio.NewReader(s)
 		for {
 			msg := ctor()
-			if err = deserializeMsg(reader, msg); err != nil {
+			carrier, err := decodeMsg(reader, msg, format)
+			if err != nil {
 				if errors.Is(err, io.EOF) {
 					break
 				}
+				if errors.Is(err, ErrMsgTooLarge) {
+					n.penalizePeer(s.Conn().RemotePeer(), protocolID, err)
+					return
+				}
 				n.log.Warn(fmt.Sprintf("reading %q message", protocolID), logger.Error(err))
 				return
 			}
-			if err = n.receivedMsg(s.Conn().RemotePeer(), protocolID, msg); err != nil {
+			if err = n.receivedMsg(s.Conn().RemotePeer(), protocolID, msg, carrier); err != nil {
 				// log error, but also reset the stream to signal that node is not able to consume more messages
 				n.log.Warn(fmt.Sprintf("failed to process message: %v", err))
 				return
@@ -252,7 +389,14 @@ func (n *LibP2PNetwork) streamHandlerForProtocol(protocolID string, ctor func()
 	}
 }
 
-func (n *LibP2PNetwork) receivedMsg(from peer.ID, protocolID string, msg any) error {
+func (n *LibP2PNetwork) receivedMsg(from peer.ID, protocolID string, msg any, carrier propagation.MapCarrier) error {
+	if req, ok := msg.(*replication.BlockSubscriptionRequest); ok {
+		// subscribe the authenticated sender, not the node claimed in the request
+		req.NodeID = from.String()
+	}
+	if len(carrier) != 0 {
+		msg = &TracedMsg{Msg: msg, carrier: carrier}
+	}
 	select {
 	case n.receivedMsgs <- msg:
 	default:
@@ -282,8 +426,14 @@ func (n *LibP2PNetwork) registerReceiveProtocol(protoc ReceiveProtocolDescriptio
 	if slices.Contains(n.self.host.Mux().Protocols(), protocol.ID(protoc.ProtocolID)) {
 		return fmt.Errorf("protocol %q is already registered", protoc.ProtocolID)
 	}
+	if protoc.CompressedProtocolID != "" && slices.Contains(n.self.host.Mux().Protocols(), protocol.ID(protoc.CompressedProtocolID)) {
+		return fmt.Errorf("protocol %q is already registered", protoc.CompressedProtocolID)
+	}
 
 	if protoc.Handler != nil {
+		if protoc.CompressedProtocolID != "" {
+			return errors.New("compressed protocol is not supported with custom handler")
+		}
 		n.self.RegisterProtocolHandler(protoc.ProtocolID, protoc.Handler)
 		return nil
 	}
@@ -307,7 +457,15 @@ func (n *LibP2PNetwork) registerReceiveProtocol(protoc ReceiveProtocolDescriptio
 		return fmt.Errorf("data struct constructor must return pointer to struct but returns %s", typ)
 	}
 
-	n.self.RegisterProtocolHandler(protoc.ProtocolID, n.streamHandlerForProtocol(protoc.ProtocolID, protoc.TypeFn))
+	if protoc.MaxMsgSize == 0 {
+		return errors.New("message size limit must be assigned")
+	}
+	format := msgFormat{maxSize: protoc.MaxMsgSize}
+	n.self.RegisterProtocolHandler(protoc.ProtocolID, n.streamHandlerForProtocol(protoc.ProtocolID, protoc.TypeFn, format))
+	if protoc.CompressedProtocolID != "" {
+		format.compressed = true
+		n.self.RegisterProtocolHandler(protoc.CompressedProtocolID, n.streamHandlerForProtocol(protoc.CompressedProtocolID, protoc.TypeFn, format))
+	}
 	return nil
 }
 
@@ -349,7 +507,7 @@ func (n *LibP2PNetwork) registerSendProtocol(protocol SendProtocolDescription) e
 		return fmt.Errorf("data type %s has been already registered for protocol %s", typ, spd.protocolID)
 	}
 
-	spx := &sendProtocolData{protocolID: protocol.ProtocolID, timeout: protocol.Timeout}
+	spx := &sendProtocolData{protocolID: protocol.ProtocolID, compressedProtocolID: protocol.CompressedProtocolID, timeout: protocol.Timeout}
 	n.sendProtocols[typ] = spx
 	n.sendProtocols[reflect.PointerTo(typ)] = spx
 	return nil
@@ -3,13 +3,19 @@ package network
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	libp2pNetwork "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/peerstore"
 	"github.com/stretchr/testify/require"
 	test "github.com/unicitynetwork/bft-core/internal/testutils"
 	"github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type testStrMsg struct {
@@ -38,6 +44,22 @@ func (t *testMsgContainer) Len() int {
 	return len(t.msgs)
 }
 
+func testSpanContext() trace.SpanContext {
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3, 4},
+		SpanID:     trace.SpanID{5, 6, 7, 8},
+		TraceFlags: trace.FlagsSampled,
+	})
+}
+
+func Test_UnwrapMsg(t *testing.T) {
+	// bare message, handler must not continue the trace of the receive loop
+	ctx := trace.ContextWithSpanContext(context.Background(), testSpanContext())
+	msgCtx, msg := UnwrapMsg(ctx, &testStrMsg{Info: "foo"})
+	require.Equal(t, &testStrMsg{Info: "foo"}, msg)
+	require.False(t, trace.SpanContextFromContext(msgCtx).IsValid())
+}
+
 func TestNewRootNodeLibP2PNetwork_Ok(t *testing.T) {
 	peer := createPeer(t)
 	defer func() { require.NoError(t, peer.Close()) }()
@@ -155,7 +177,7 @@ func Test_LibP2PNetwork_Send(t *testing.T) {
 		peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
 
 		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testMsg{}, Timeout: 100 * time.Millisecond}))
-		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testMsg{} }}))
+		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testMsg{} }, MaxMsgSize: testMaxMsgSize}))
 
 		msg := &testMsg{Name: "test message", Value: 127}
 		require.NoError(t, nw1.Send(context.Background(), msg, peer2.ID()))
@@ -168,6 +190,156 @@ func Test_LibP2PNetwork_Send(t *testing.T) {
 		}
 	})
 
+	t.Run("success, compressed protocol", func(t *testing.T) {
+		testCases := []struct {
+			name          string
+			receiverProto string // compressed protocol ID supported by the receiver
+			compressed    bool
+		}{
+			{name: "receiver supports compression", receiverProto: "test/p/z", compressed: true},
+			{name: "receiver doesn't support compression", compressed: false},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				obs := observability.Default(t)
+				peer1 := createPeer(t)
+				defer func() { require.NoError(t, peer1.Close()) }()
+				nw1, err := NewLibP2PNetwork(peer1, 1, obs)
+				require.NoError(t, err)
+
+				peer2 := createPeer(t)
+				defer func() { require.NoError(t, peer2.Close()) }()
+				nw2, err := NewLibP2PNetwork(peer2, 1, obs)
+				require.NoError(t, err)
+				peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
+
+				require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", CompressedProtocolID: "test/p/z", MsgType: testMsg{}, Timeout: 100 * time.Millisecond}))
+				require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", CompressedProtocolID: tc.receiverProto, TypeFn: func() any { return &testMsg{} }, MaxMsgSize: testMaxMsgSize}))
+
+				stream, err := peer1.CreateStream(context.Background(), peer2.ID(), nw1.sendProtocols[reflect.TypeOf(testMsg{})].protocolIDs()...)
+				require.NoError(t, err)
+				require.Equal(t, tc.compressed, nw1.sendProtocols[reflect.TypeOf(testMsg{})].isCompressed(stream))
+				require.NoError(t, stream.Close())
+
+				msg := &testMsg{Name: "test message", Value: 127}
+				require.NoError(t, nw1.Send(context.Background(), msg, peer2.ID()))
+
+				select {
+				case rm := <-nw2.ReceivedChannel():
+					require.Equal(t, msg, rm)
+				case <-time.After(time.Second):
+					t.Error("haven't got message before timeout")
+				}
+			})
+		}
+	})
+
+	t.Run("message exceeds size limit", func(t *testing.T) {
+		obs := observability.Default(t)
+		peer1 := createPeer(t)
+		defer func() { require.NoError(t, peer1.Close()) }()
+		nw1, err := NewLibP2PNetwork(peer1, 1, obs)
+		require.NoError(t, err)
+
+		peer2 := createPeer(t)
+		defer func() { require.NoError(t, peer2.Close()) }()
+		nw2, err := NewLibP2PNetwork(peer2, 1, obs)
+		require.NoError(t, err)
+		peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
+
+		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testMsg{}, Timeout: 100 * time.Millisecond}))
+		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testMsg{} }, MaxMsgSize: 100}))
+
+		// connect before sending so that the connection carrying the message is known
+		// (the peers might reconnect later, ie because of the routing table refresh)
+		require.NoError(t, peer1.host.Connect(context.Background(), peer.AddrInfo{ID: peer2.ID(), Addrs: peer2.MultiAddresses()}))
+		conns := peer2.Network().ConnsToPeer(peer1.ID())
+		require.NotEmpty(t, conns)
+
+		msg := &testMsg{Name: strings.Repeat("a", 100), Value: 127}
+		require.NoError(t, nw1.Send(context.Background(), msg, peer2.ID()))
+
+		// the receiver drops the message and disconnects the sender
+		require.Eventually(t, func() bool {
+			return !slices.ContainsFunc(conns, func(c libp2pNetwork.Conn) bool { return !c.IsClosed() })
+		}, time.Second, 10*time.Millisecond)
+		require.Empty(t, nw2.ReceivedChannel())
+	})
+
+	t.Run("success, trace context is not propagated by default", func(t *testing.T) {
+		obs := observability.Default(t)
+		peer1 := createPeer(t)
+		defer func() { require.NoError(t, peer1.Close()) }()
+		nw1, err := NewLibP2PNetwork(peer1, 1, obs)
+		require.NoError(t, err)
+
+		peer2 := createPeer(t)
+		defer func() { require.NoError(t, peer2.Close()) }()
+		nw2, err := NewLibP2PNetwork(peer2, 1, obs)
+		require.NoError(t, err)
+		peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
+
+		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testMsg{}, Timeout: 100 * time.Millisecond}))
+		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testMsg{} }, MaxMsgSize: testMaxMsgSize}))
+
+		ctx := trace.ContextWithSpanContext(context.Background(), testSpanContext())
+		msg := &testMsg{Name: "test message", Value: 127}
+		require.NoError(t, nw1.Send(ctx, msg, peer2.ID()))
+
+		select {
+		case rm := <-nw2.ReceivedChannel():
+			require.Equal(t, msg, rm)
+		case <-time.After(time.Second):
+			t.Error("haven't got message before timeout")
+		}
+	})
+
+	t.Run("success, trace context is propagated", func(t *testing.T) {
+		obs := observability.Default(t)
+		peer1 := createPeer(t)
+		defer func() { require.NoError(t, peer1.Close()) }()
+		nw1, err := NewLibP2PNetwork(peer1, 1, obs, WithTracePropagation(true))
+		require.NoError(t, err)
+
+		peer2 := createPeer(t)
+		defer func() { require.NoError(t, peer2.Close()) }()
+		nw2, err := NewLibP2PNetwork(peer2, 1, obs)
+		require.NoError(t, err)
+		peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
+
+		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testMsg{}, Timeout: 100 * time.Millisecond}))
+		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testMsg{} }, MaxMsgSize: testMaxMsgSize}))
+
+		sc := testSpanContext()
+		ctx := trace.ContextWithSpanContext(context.Background(), sc)
+		msg := &testMsg{Name: "test message", Value: 127}
+		require.NoError(t, nw1.Send(ctx, msg, peer2.ID()))
+
+		select {
+		case rm := <-nw2.ReceivedChannel():
+			require.IsType(t, &TracedMsg{}, rm)
+			msgCtx, rmsg := UnwrapMsg(context.Background(), rm)
+			require.Equal(t, msg, rmsg)
+			rsc := trace.SpanContextFromContext(msgCtx)
+			require.True(t, rsc.IsRemote())
+			require.Equal(t, sc.TraceID(), rsc.TraceID())
+		case <-time.After(time.Second):
+			t.Error("haven't got message before timeout")
+		}
+
+		// message to self
+		require.NoError(t, nw2.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testMsg{}, Timeout: 100 * time.Millisecond}))
+		require.NoError(t, nw2.Send(ctx, msg, peer2.ID()))
+		select {
+		case rm := <-nw2.ReceivedChannel():
+			msgCtx, rmsg := UnwrapMsg(context.Background(), rm)
+			require.Equal(t, msg, rmsg)
+			require.Equal(t, sc.TraceID(), trace.SpanContextFromContext(msgCtx).TraceID())
+		case <-time.After(time.Second):
+			t.Error("haven't got message before timeout")
+		}
+	})
+
 	t.Run("success, message to two peers", func(t *testing.T) {
 		obs := observability.Default(t)
 		// create peer for sender and two receivers
@@ -191,7 +363,7 @@ func Test_LibP2PNetwork_Send(t *testing.T) {
 		peer1.Network().Peerstore().AddAddrs(peer3.ID(), peer3.MultiAddresses(), peerstore.PermanentAddrTTL)
 
 		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testMsg{}, Timeout: 100 * time.Millisecond}))
-		rpd := ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testMsg{} }}
+		rpd := ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testMsg{} }, MaxMsgSize: testMaxMsgSize}
 		require.NoError(t, nw2.registerReceiveProtocol(rpd))
 		require.NoError(t, nw3.registerReceiveProtocol(rpd))
 
@@ -230,7 +402,7 @@ func Test_LibP2PNetwork_SendMsgs(t *testing.T) {
 		peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
 
 		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testStrMsg{}, Timeout: 100 * time.Millisecond}))
-		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testStrMsg{} }}))
+		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testStrMsg{} }, MaxMsgSize: testMaxMsgSize}))
 		msgQueue := &testMsgContainer{}
 		msgQueue.PushBack(&testStrMsg{Info: "test message1"})
 		msgQueue.PushBack(&testStrMsg{Info: "test message2"})
@@ -257,7 +429,7 @@ func Test_LibP2PNetwork_SendMsgs(t *testing.T) {
 		peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
 
 		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testStrMsg{}, Timeout: 100 * time.Millisecond}))
-		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testStrMsg{} }}))
+		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testStrMsg{} }, MaxMsgSize: testMaxMsgSize}))
 		msgQueue := &testMsgContainer{}
 		for i := 1; i <= 4; i++ {
 			msgQueue.PushBack(&testStrMsg{Info: fmt.Sprintf("make a test message that is a bit longer to simulate real messages: test message %v", i)})
@@ -285,7 +457,7 @@ func Test_LibP2PNetwork_SendMsgs(t *testing.T) {
 		peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
 
 		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testStrMsg{}, Timeout: 100 * time.Millisecond}))
-		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testStrMsg{} }}))
+		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testStrMsg{} }, MaxMsgSize: testMaxMsgSize}))
 		msgQueue := &testMsgContainer{}
 		for i := 1; i <= 10000; i++ {
 			msgQueue.PushBack(&testStrMsg{Info: fmt.Sprintf("make a test message that is a bit longer to simulate real messages: test message %v", i)})
@@ -307,7 +479,7 @@ func Test_LibP2PNetwork_SendMsgs(t *testing.T) {
 		peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
 		type fooMsg struct{}
 		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: fooMsg{}, Timeout: 100 * time.Millisecond}))
-		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testStrMsg{} }}))
+		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testStrMsg{} }, MaxMsgSize: testMaxMsgSize}))
 		msgQueue := &testMsgContainer{}
 		for i := 1; i <= 4; i++ {
 			msgQueue.PushBack(&testStrMsg{Info: fmt.Sprintf("make a test message that is a bit longer to simulate real messages: test message %v", i)})
@@ -326,7 +498,7 @@ func Test_LibP2PNetwork_SendMsgs(t *testing.T) {
 		nw2, err := NewLibP2PNetwork(peer2, 1, obs)
 		require.NoError(t, err)
 		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testStrMsg{}, Timeout: 100 * time.Millisecond}))
-		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testStrMsg{} }}))
+		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testStrMsg{} }, MaxMsgSize: testMaxMsgSize}))
 		msgQueue := &testMsgContainer{}
 		for i := 1; i <= 4; i++ {
 			msgQueue.PushBack(&testStrMsg{Info: fmt.Sprintf("make a test message that is a bit longer to simulate real messages: test message %v", i)})
@@ -350,10 +522,10 @@ func Test_LibP2PNetwork_sendMsg(t *testing.T) {
 		peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
 
 		// networks have no protocols registered so sending data must fail
-		msg := []byte{3, 2, 1}
+		msg := &encodedMsg{plain: []byte{3, 2, 1}}
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 		defer cancel()
-		err = sendMsg(ctx, nw1.self, "test/p", msg, peer2.ID())
+		err = sendMsg(ctx, nw1.self, &sendProtocolData{protocolID: "test/p"}, msg, peer2.ID())
 		require.EqualError(t, err, `open p2p stream: failed to negotiate protocol: protocols not supported: [test/p]`)
 	})
 
@@ -378,10 +550,10 @@ func Test_LibP2PNetwork_sendMsg(t *testing.T) {
 		//peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
 		//peer2.Network().Peerstore().AddAddrs(peer1.ID(), peer1.MultiAddresses(), peerstore.PermanentAddrTTL)
 
-		msg := []byte{3, 2, 1}
+		msg := &encodedMsg{plain: []byte{3, 2, 1}}
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 		defer cancel()
-		err = sendMsg(ctx, nw1.self, "test/p", msg, peer2.ID())
+		err = sendMsg(ctx, nw1.self, &sendProtocolData{protocolID: "test/p"}, msg, peer2.ID())
 		require.EqualError(t, err, "open p2p stream: failed to find any peer in table")
 	})
 
@@ -401,10 +573,10 @@ func Test_LibP2PNetwork_sendMsg(t *testing.T) {
 		// ...but close peer2 network connection
 		require.NoError(t, peer2.Close())
 
-		msg := []byte{3, 2, 1}
+		msg := &encodedMsg{plain: []byte{3, 2, 1}}
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 		defer cancel()
-		err = sendMsg(ctx, nw1.self, "test/p", msg, peer2.ID())
+		err = sendMsg(ctx, nw1.self, &sendProtocolData{protocolID: "test/p"}, msg, peer2.ID())
 		require.ErrorContains(t, err, fmt.Sprintf("open p2p stream: failed to dial: failed to dial %s: all dials failed", peer2.ID()))
 		require.ErrorContains(t, err, `connection refused`)
 	})
@@ -425,14 +597,14 @@ func Test_LibP2PNetwork_sendMsg(t *testing.T) {
 		peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
 
 		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testMsg{}, Timeout: 100 * time.Millisecond}))
-		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testMsg{} }}))
+		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testMsg{} }, MaxMsgSize: testMaxMsgSize}))
 
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
-		msg := []byte{3, 2, 1}
+		msg := &encodedMsg{plain: []byte{3, 2, 1}}
 		ctx, cancel = context.WithTimeout(ctx, 100*time.Millisecond)
 		defer cancel()
-		err = sendMsg(ctx, nw1.self, "test/p", msg, peer2.ID())
+		err = sendMsg(ctx, nw1.self, &sendProtocolData{protocolID: "test/p"}, msg, peer2.ID())
 		require.EqualError(t, err, `open p2p stream: failed to dial: context canceled`)
 	})
 
@@ -452,14 +624,14 @@ func Test_LibP2PNetwork_sendMsg(t *testing.T) {
 		peer1.Network().Peerstore().AddAddrs(peer2.ID(), peer2.MultiAddresses(), peerstore.PermanentAddrTTL)
 
 		require.NoError(t, nw1.registerSendProtocol(SendProtocolDescription{ProtocolID: "test/p", MsgType: testMsg{}, Timeout: 100 * time.Millisecond}))
-		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testMsg{} }}))
+		require.NoError(t, nw2.registerReceiveProtocol(ReceiveProtocolDescription{ProtocolID: "test/p", TypeFn: func() any { return &testMsg{} }, MaxMsgSize: testMaxMsgSize}))
 
 		msg := &testMsg{Name: "oh my!", Value: 555}
-		data, err := serializeMsg(msg)
+		data, err := nw1.newEncodedMsg(context.Background(), msg)
 		require.NoError(t, err)
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 		defer cancel()
-		require.NoError(t, sendMsg(ctx, nw1.self, "test/p", data, peer2.ID()))
+		require.NoError(t, sendMsg(ctx, nw1.self, &sendProtocolData{protocolID: "test/p"}, data, peer2.ID()))
 
 		select {
 		case rm := <-nw2.ReceivedChannel():
@@ -615,6 +787,7 @@ func Test_LibP2PNetwork_registerReceiveProtocols(t *testing.T) {
 		return ReceiveProtocolDescription{
 			ProtocolID: "foo/bar",
 			TypeFn:     func() any { return &testMsg{} },
+			MaxMsgSize: testMaxMsgSize,
 		}
 	}
 
@@ -659,6 +832,7 @@ func Test_LibP2PNetwork_registerReceiveProtocol(t *testing.T) {
 		return ReceiveProtocolDescription{
 			ProtocolID: "foo/bar",
 			TypeFn:     func() any { return &testMsg{} },
+			MaxMsgSize: testMaxMsgSize,
 		}
 	}
 	obs := observability.NOPObservability()
@@ -700,6 +874,18 @@ func Test_LibP2PNetwork_registerReceiveProtocol(t *testing.T) {
 		require.EqualError(t, err, `data struct constructor or handler must be assigned`)
 	})
 
+	t.Run("message size limit unassigned", func(t *testing.T) {
+		peer := createPeer(t)
+		defer func() { require.NoError(t, peer.Close()) }()
+		nw, err := NewLibP2PNetwork(peer, 1, obs)
+		require.NoError(t, err)
+
+		data := validReceiveProtocolDescription()
+		data.MaxMsgSize = 0
+		err = nw.registerReceiveProtocol(data)
+		require.EqualError(t, err, `message size limit must be assigned`)
+	})
+
 	t.Run("constructor returns invalid type", func(t *testing.T) {
 		peer := createPeer(t)
 		defer func() { require.NoError(t, peer.Close()) }()
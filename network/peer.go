@@ -264,9 +264,16 @@ func (p *Peer) RemoveProtocolHandler(protocolID string) {
 	p.host.RemoveStreamHandler(libp2pprotocol.ID(protocolID))
 }
 
-// CreateStream opens a new stream to given peer p, and writes a libp2p protocol header with given ProtocolID.
-func (p *Peer) CreateStream(ctx context.Context, peerID peer.ID, protocolID string) (network.Stream, error) {
-	return p.host.NewStream(ctx, peerID, libp2pprotocol.ID(protocolID))
+/*
+CreateStream opens a new stream to given peer p, and writes a libp2p protocol header with
+the first of the given protocol IDs the peer supports (protocol IDs are in the order of preference).
+*/
+func (p *Peer) CreateStream(ctx context.Context, peerID peer.ID, protocolIDs ...string) (network.Stream, error) {
+	ids := make([]libp2pprotocol.ID, len(protocolIDs))
+	for i, id := range protocolIDs {
+		ids[i] = libp2pprotocol.ID(id)
+	}
+	return p.host.NewStream(ctx, peerID, ids...)
 }
 
 // Configuration returns peer configuration
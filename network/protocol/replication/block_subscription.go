@@ -0,0 +1,35 @@
+package replication
+
+import (
+	"errors"
+
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+var ErrBlockSubscriptionReqIsNil = errors.New("block subscription request is nil")
+
+/*
+BlockSubscriptionRequest is sent by the non-validator (full) node to validators
+to register for certified block push. Registration expires unless renewed, so
+full nodes must send the request periodically. The receiver overwrites the
+NodeID with the authenticated peer ID of the sender.
+*/
+type BlockSubscriptionRequest struct {
+	_           struct{} `cbor:",toarray"`
+	PartitionID types.PartitionID
+	ShardID     types.ShardID
+	NodeID      string
+}
+
+func (r *BlockSubscriptionRequest) IsValid() error {
+	if r == nil {
+		return ErrBlockSubscriptionReqIsNil
+	}
+	if r.PartitionID == 0 {
+		return ErrInvalidPartitionID
+	}
+	if r.NodeID == "" {
+		return ErrNodeIDIsMissing
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package replication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockSubscriptionRequestValidation(t *testing.T) {
+	var nilReq *BlockSubscriptionRequest
+	require.ErrorIs(t, nilReq.IsValid(), ErrBlockSubscriptionReqIsNil)
+
+	req := &BlockSubscriptionRequest{NodeID: "1"}
+	require.ErrorIs(t, req.IsValid(), ErrInvalidPartitionID)
+
+	req = &BlockSubscriptionRequest{PartitionID: 1}
+	require.ErrorIs(t, req.IsValid(), ErrNodeIDIsMissing)
+
+	req = &BlockSubscriptionRequest{PartitionID: 1, NodeID: "1"}
+	require.NoError(t, req.IsValid())
+}
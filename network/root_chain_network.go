@@ -14,11 +14,19 @@ const (
 	ProtocolUnicityCertificates = "/ab/certificates/0.0.1"
 )
 
+// size limits of the (decompressed) messages of the protocols
+const (
+	// input record and the signature of the validator
+	maxCertificationRequestMsgSize = 64 << 10
+	// request which carries identifiers only
+	maxHandshakeMsgSize = 4 << 10
+)
+
 /*
 Logger (log) is assumed to already have node_id attribute added, won't be added by NW component!
 */
-func NewLibP2PRootChainNetwork(self *Peer, capacity uint, sendCertificateTimeout time.Duration, obs Observability) (*LibP2PNetwork, error) {
-	n, err := NewLibP2PNetwork(self, capacity, obs)
+func NewLibP2PRootChainNetwork(self *Peer, capacity uint, sendCertificateTimeout time.Duration, obs Observability, opts ...NetworkOption) (*LibP2PNetwork, error) {
+	n, err := NewLibP2PNetwork(self, capacity, obs, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -34,10 +42,12 @@ func NewLibP2PRootChainNetwork(self *Peer, capacity uint, sendCertificateTimeout
 		{
 			ProtocolID: ProtocolBlockCertification,
 			TypeFn:     func() any { return &certification.BlockCertificationRequest{} },
+			MaxMsgSize: maxCertificationRequestMsgSize,
 		},
 		{
 			ProtocolID: ProtocolHandshake,
 			TypeFn:     func() any { return &handshake.Handshake{} },
+			MaxMsgSize: maxHandshakeMsgSize,
 		},
 	}
 	if err = n.RegisterReceiveProtocols(receiveProtocolDescriptions); err != nil {
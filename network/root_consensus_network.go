@@ -13,10 +13,28 @@ const (
 	ProtocolRootTimeout     = "/ab/root-timeout/0.0.1"
 	ProtocolRootStateReq    = "/ab/root-state-req/0.0.1"
 	ProtocolRootStateResp   = "/ab/root-state-resp/0.0.1"
+
+	// version of the state response protocol with flate compressed messages
+	ProtocolRootStateRespCompressed = "/ab/root-state-resp/0.1.0"
+)
+
+// size limits of the (decompressed) messages of the protocols
+const (
+	// pending blocks (with the IR change requests of all the shards) and certificates of the root chain
+	maxRootStateMsgSize = 32 << 20
+	// IR change requests of all the changed shards
+	maxRootProposalMsgSize = 32 << 20
+	// certification requests of the validators of the shard
+	maxRootIrChangeReqMsgSize = 8 << 20
+	// QC (and TC) signed by the root validators
+	maxRootVoteMsgSize    = 1 << 20
+	maxRootTimeoutMsgSize = 1 << 20
+	// request which carries identifiers only
+	maxRootStateReqMsgSize = 4 << 10
 )
 
-func NewLibP2RootConsensusNetwork(self *Peer, capacity uint, sendTimeout time.Duration, obs Observability) (*LibP2PNetwork, error) {
-	n, err := NewLibP2PNetwork(self, capacity, obs)
+func NewLibP2RootConsensusNetwork(self *Peer, capacity uint, sendTimeout time.Duration, obs Observability, opts ...NetworkOption) (*LibP2PNetwork, error) {
+	n, err := NewLibP2PNetwork(self, capacity, obs, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +44,7 @@ func NewLibP2RootConsensusNetwork(self *Peer, capacity uint, sendTimeout time.Du
 		{ProtocolID: ProtocolRootVote, Timeout: sendTimeout, MsgType: abdrc.VoteMsg{}},
 		{ProtocolID: ProtocolRootTimeout, Timeout: sendTimeout, MsgType: abdrc.TimeoutMsg{}},
 		{ProtocolID: ProtocolRootStateReq, Timeout: sendTimeout, MsgType: abdrc.StateRequestMsg{}},
-		{ProtocolID: ProtocolRootStateResp, Timeout: sendTimeout, MsgType: abdrc.StateMsg{}},
+		{ProtocolID: ProtocolRootStateResp, CompressedProtocolID: ProtocolRootStateRespCompressed, Timeout: sendTimeout, MsgType: abdrc.StateMsg{}},
 	}
 	if err = n.RegisterSendProtocols(sendProtocolDescriptions); err != nil {
 		return nil, err
@@ -35,26 +53,33 @@ func NewLibP2RootConsensusNetwork(self *Peer, capacity uint, sendTimeout time.Du
 		{
 			ProtocolID: ProtocolRootIrChangeReq,
 			TypeFn:     func() any { return &abdrc.IrChangeReqMsg{} },
+			MaxMsgSize: maxRootIrChangeReqMsgSize,
 		},
 		{
 			ProtocolID: ProtocolRootProposal,
 			TypeFn:     func() any { return &abdrc.ProposalMsg{} },
+			MaxMsgSize: maxRootProposalMsgSize,
 		},
 		{
 			ProtocolID: ProtocolRootVote,
 			TypeFn:     func() any { return &abdrc.VoteMsg{} },
+			MaxMsgSize: maxRootVoteMsgSize,
 		},
 		{
 			ProtocolID: ProtocolRootTimeout,
 			TypeFn:     func() any { return &abdrc.TimeoutMsg{} },
+			MaxMsgSize: maxRootTimeoutMsgSize,
 		},
 		{
 			ProtocolID: ProtocolRootStateReq,
 			TypeFn:     func() any { return &abdrc.StateRequestMsg{} },
+			MaxMsgSize: maxRootStateReqMsgSize,
 		},
 		{
-			ProtocolID: ProtocolRootStateResp,
-			TypeFn:     func() any { return &abdrc.StateMsg{} },
+			ProtocolID:           ProtocolRootStateResp,
+			CompressedProtocolID: ProtocolRootStateRespCompressed,
+			TypeFn:               func() any { return &abdrc.StateMsg{} },
+			MaxMsgSize:           maxRootStateMsgSize,
 		},
 	}
 	if err = n.RegisterReceiveProtocols(receiveProtocolDescriptions); err != nil {
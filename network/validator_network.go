@@ -13,6 +13,7 @@ import (
 	pubsub_pb "github.com/libp2p/go-libp2p-pubsub/pb"
 	libp2pNetwork "github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/unicitynetwork/bft-core/logger"
 	"github.com/unicitynetwork/bft-core/network/protocol/blockproposal"
 	"github.com/unicitynetwork/bft-core/network/protocol/certification"
@@ -21,6 +22,7 @@ import (
 	"github.com/unicitynetwork/bft-core/observability"
 	"github.com/unicitynetwork/bft-core/txbuffer"
 	"github.com/unicitynetwork/bft-go-base/types"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
@@ -31,7 +33,27 @@ const (
 	ProtocolBlockProposal         = "/ab/block-proposal/0.0.1"
 	ProtocolLedgerReplicationReq  = "/ab/replication-req/0.0.1"
 	ProtocolLedgerReplicationResp = "/ab/replication-resp/0.0.1"
+	ProtocolBlockSubscription     = "/ab/block-subscription/0.0.1"
+	ProtocolBlockPush             = "/ab/block-push/0.0.1"
 	TopicPrefixBlock              = "/ab/block/0.0.1/"
+
+	// versions of the protocols with flate compressed messages
+	ProtocolBlockProposalCompressed         = "/ab/block-proposal/0.1.0"
+	ProtocolLedgerReplicationRespCompressed = "/ab/replication-resp/0.1.0"
+	ProtocolBlockPushCompressed             = "/ab/block-push/0.1.0"
+)
+
+// size limits of the (decompressed) messages of the protocols
+const (
+	maxBlockMsgSize             = 32 << 20
+	maxLedgerReplicationMsgSize = 128 << 20
+	// attributes (ie token data up to 64KB), predicates (up to 64KB each) and proofs of the transaction
+	maxTxMsgSize = 1 << 20
+	// UC with the unicity tree certificate and the seal signed by the root validators, technical record
+	maxCertificationResponseMsgSize = 256 << 10
+	// requests which carry identifiers only
+	maxLedgerReplicationReqMsgSize = 4 << 10
+	maxBlockSubscriptionMsgSize    = 4 << 10
 )
 
 var DefaultValidatorNetworkOptions = ValidatorNetworkOptions{
@@ -43,6 +65,8 @@ var DefaultValidatorNetworkOptions = ValidatorNetworkOptions{
 	LedgerReplicationRequestTimeout:  300 * time.Millisecond,
 	LedgerReplicationResponseTimeout: 300 * time.Millisecond,
 	HandshakeTimeout:                 300 * time.Millisecond,
+	BlockSubscriptionTimeout:         300 * time.Millisecond,
+	BlockPushTimeout:                 300 * time.Millisecond,
 }
 
 type (
@@ -64,6 +88,11 @@ type (
 		LedgerReplicationRequestTimeout  time.Duration
 		LedgerReplicationResponseTimeout time.Duration
 		HandshakeTimeout                 time.Duration
+		BlockSubscriptionTimeout         time.Duration
+		BlockPushTimeout                 time.Duration
+
+		// send the trace context along with the messages, see WithTracePropagation
+		TracePropagation bool
 	}
 
 	TxProcessor func(ctx context.Context, tx *types.TransactionOrder) error
@@ -96,7 +125,7 @@ NewLibP2PValidatorNetwork creates a new LibP2PNetwork based validator network.
 Logger (log) is assumed to already have node_id attribute added, won't be added by NW component!
 */
 func NewLibP2PValidatorNetwork(ctx context.Context, node node, opts ValidatorNetworkOptions, obs Observability) (*validatorNetwork, error) {
-	base, err := NewLibP2PNetwork(node.Peer(), opts.ReceivedChannelCapacity, obs)
+	base, err := NewLibP2PNetwork(node.Peer(), opts.ReceivedChannelCapacity, obs, WithTracePropagation(opts.TracePropagation))
 	if err != nil {
 		return nil, err
 	}
@@ -122,14 +151,16 @@ func NewLibP2PValidatorNetwork(ctx context.Context, node node, opts ValidatorNet
 			Timeout:    opts.LedgerReplicationRequestTimeout,
 			MsgType:    replication.LedgerReplicationRequest{}},
 		{
-			ProtocolID: ProtocolLedgerReplicationResp,
-			Timeout:    opts.LedgerReplicationResponseTimeout,
-			MsgType:    replication.LedgerReplicationResponse{},
+			ProtocolID:           ProtocolLedgerReplicationResp,
+			CompressedProtocolID: ProtocolLedgerReplicationRespCompressed,
+			Timeout:              opts.LedgerReplicationResponseTimeout,
+			MsgType:              replication.LedgerReplicationResponse{},
 		},
 		{
-			ProtocolID: ProtocolBlockProposal,
-			Timeout:    opts.BlockProposalTimeout,
-			MsgType:    blockproposal.BlockProposal{},
+			ProtocolID:           ProtocolBlockProposal,
+			CompressedProtocolID: ProtocolBlockProposalCompressed,
+			Timeout:              opts.BlockProposalTimeout,
+			MsgType:              blockproposal.BlockProposal{},
 		},
 		{
 			ProtocolID: ProtocolBlockCertification,
@@ -141,6 +172,17 @@ func NewLibP2PValidatorNetwork(ctx context.Context, node node, opts ValidatorNet
 			Timeout:    opts.HandshakeTimeout,
 			MsgType:    handshake.Handshake{},
 		},
+		{
+			ProtocolID: ProtocolBlockSubscription,
+			Timeout:    opts.BlockSubscriptionTimeout,
+			MsgType:    replication.BlockSubscriptionRequest{},
+		},
+		{
+			ProtocolID:           ProtocolBlockPush,
+			CompressedProtocolID: ProtocolBlockPushCompressed,
+			Timeout:              opts.BlockPushTimeout,
+			MsgType:              types.Block{},
+		},
 	}
 	if err = n.RegisterSendProtocols(sendProtocolDescriptions); err != nil {
 		return nil, fmt.Errorf("registering send protocols: %w", err)
@@ -150,10 +192,13 @@ func NewLibP2PValidatorNetwork(ctx context.Context, node node, opts ValidatorNet
 		{
 			ProtocolID: ProtocolLedgerReplicationReq,
 			TypeFn:     func() any { return &replication.LedgerReplicationRequest{} },
+			MaxMsgSize: maxLedgerReplicationReqMsgSize,
 		},
 		{
-			ProtocolID: ProtocolLedgerReplicationResp,
-			TypeFn:     func() any { return &replication.LedgerReplicationResponse{} },
+			ProtocolID:           ProtocolLedgerReplicationResp,
+			CompressedProtocolID: ProtocolLedgerReplicationRespCompressed,
+			TypeFn:               func() any { return &replication.LedgerReplicationResponse{} },
+			MaxMsgSize:           maxLedgerReplicationMsgSize,
 		},
 	}
 	if err = n.RegisterReceiveProtocols(receiveProtocolDescriptions); err != nil {
@@ -230,9 +275,17 @@ func (n *validatorNetwork) SubscribeToBlocks(ctx context.Context) error {
 	n.gsSubscriptionBlock = sub
 
 	ctx, n.gsCancelHandleBlocks = context.WithCancel(ctx)
-	go n.handleBlocks(ctx)
+	go n.handleBlocks(ctx, sub)
 
-	return nil
+	// blocks pushed by the validators the node has subscribed to
+	return n.RegisterReceiveProtocols([]ReceiveProtocolDescription{
+		{
+			ProtocolID:           ProtocolBlockPush,
+			CompressedProtocolID: ProtocolBlockPushCompressed,
+			TypeFn:               func() any { return &types.Block{} },
+			MaxMsgSize:           maxBlockMsgSize,
+		},
+	})
 }
 
 func (n *validatorNetwork) UnsubscribeFromBlocks() {
@@ -244,13 +297,17 @@ func (n *validatorNetwork) UnsubscribeFromBlocks() {
 	n.gsSubscriptionBlock.Cancel()
 	n.gsSubscriptionBlock = nil
 	n.gsCancelHandleBlocks()
+	n.self.RemoveProtocolHandler(ProtocolBlockPush)
+	n.self.RemoveProtocolHandler(ProtocolBlockPushCompressed)
 }
 
 func (n *validatorNetwork) RegisterValidatorProtocols() error {
 	receiveProtocols := []ReceiveProtocolDescription{
 		{
-			ProtocolID: ProtocolBlockProposal,
-			TypeFn:     func() any { return &blockproposal.BlockProposal{} },
+			ProtocolID:           ProtocolBlockProposal,
+			CompressedProtocolID: ProtocolBlockProposalCompressed,
+			TypeFn:               func() any { return &blockproposal.BlockProposal{} },
+			MaxMsgSize:           maxBlockMsgSize,
 		},
 		{
 			ProtocolID: ProtocolInputForward,
@@ -259,6 +316,12 @@ func (n *validatorNetwork) RegisterValidatorProtocols() error {
 		{
 			ProtocolID: ProtocolUnicityCertificates,
 			TypeFn:     func() any { return &certification.CertificationResponse{} },
+			MaxMsgSize: maxCertificationResponseMsgSize,
+		},
+		{
+			ProtocolID: ProtocolBlockSubscription,
+			TypeFn:     func() any { return &replication.BlockSubscriptionRequest{} },
+			MaxMsgSize: maxBlockSubscriptionMsgSize,
 		},
 	}
 	return n.RegisterReceiveProtocols(receiveProtocols)
@@ -266,8 +329,28 @@ func (n *validatorNetwork) RegisterValidatorProtocols() error {
 
 func (n *validatorNetwork) UnregisterValidatorProtocols() {
 	n.self.RemoveProtocolHandler(ProtocolBlockProposal)
+	n.self.RemoveProtocolHandler(ProtocolBlockProposalCompressed)
 	n.self.RemoveProtocolHandler(ProtocolInputForward)
 	n.self.RemoveProtocolHandler(ProtocolUnicityCertificates)
+	n.self.RemoveProtocolHandler(ProtocolBlockSubscription)
+}
+
+/*
+ReplicationPeers returns the peers known to support the ledger replication
+protocol, ie validators and full nodes the node has exchanged identities with.
+*/
+func (n *validatorNetwork) ReplicationPeers() peer.IDSlice {
+	var peers peer.IDSlice
+	ps := n.self.host.Peerstore()
+	for _, id := range ps.Peers() {
+		if id == n.self.ID() {
+			continue
+		}
+		if supported, err := ps.SupportsProtocols(id, protocol.ID(ProtocolLedgerReplicationReq)); err == nil && len(supported) > 0 {
+			peers = append(peers, id)
+		}
+	}
+	return peers
 }
 
 func (n *validatorNetwork) PublishBlock(ctx context.Context, block *types.Block) error {
@@ -283,14 +366,27 @@ func (n *validatorNetwork) ProcessTransactions(ctx context.Context, txProcessor
 	ctx, span := n.tracer.Start(ctx, "validatorNetwork.ProcessTransactions")
 	defer span.End()
 	for {
-		tx, err := n.txBuffer.Remove(ctx)
+		tx, sc, err := n.txBuffer.Remove(ctx)
 		if err != nil {
 			// context cancelled, no need to log
 			return
 		}
-		if err := txProcessor(ctx, tx); err != nil {
-			n.log.WarnContext(ctx, "processing transaction", logger.Error(err), logger.UnitID(tx.UnitID))
-		}
+		n.processTransaction(ctx, txProcessor, tx, sc)
+	}
+}
+
+/*
+processTransaction executes the transaction continuing the trace of the
+transaction (ie trace started when the transaction was added to the buffer).
+*/
+func (n *validatorNetwork) processTransaction(ctx context.Context, txProcessor TxProcessor, tx *types.TransactionOrder, sc trace.SpanContext) {
+	if sc.IsValid() {
+		var span trace.Span
+		ctx, span = n.tracer.Start(trace.ContextWithSpanContext(ctx, sc), "validatorNetwork.processTransaction", trace.WithLinks(trace.LinkFromContext(ctx)))
+		defer span.End()
+	}
+	if err := txProcessor(ctx, tx); err != nil {
+		n.log.WarnContext(ctx, "processing transaction", logger.Error(err), logger.UnitID(tx.UnitID))
 	}
 }
 
@@ -314,7 +410,7 @@ func (n *validatorNetwork) ForwardTransactions(ctx context.Context, receiverFunc
 
 	var stream libp2pNetwork.Stream
 	for {
-		tx, err := n.txBuffer.Remove(ctx)
+		tx, sc, err := n.txBuffer.Remove(ctx)
 		if err != nil {
 			// context cancelled, no need to log
 			return
@@ -356,7 +452,13 @@ func (n *validatorNetwork) ForwardTransactions(ctx context.Context, receiverFunc
 			fmt.Sprintf("forward tx %X to %v", txHash, receiver),
 			logger.UnitID(tx.UnitID))
 
-		data, err := serializeMsg(tx)
+		// the receiver continues the trace of the transaction when propagation is enabled
+		var data []byte
+		if n.propagateTrace {
+			data, err = serializeTracedMsg(trace.ContextWithSpanContext(ctx, sc), tx)
+		} else {
+			data, err = serializeMsg(tx)
+		}
 		if err != nil {
 			n.log.WarnContext(ctx, "serializing tx", logger.Error(err), logger.UnitID(tx.UnitID))
 			addToMetric("err.serialize")
@@ -384,14 +486,24 @@ func (n *validatorNetwork) handleTransactions(stream libp2pNetwork.Stream) {
 
 	for {
 		tx := &types.TransactionOrder{Version: 1}
-		if err := deserializeMsg(stream, tx); err != nil {
-			if !errors.Is(err, io.EOF) {
+		carrier, err := deserializeTracedMsg(stream, tx, maxTxMsgSize)
+		if err != nil {
+			switch {
+			case errors.Is(err, io.EOF):
+			case errors.Is(err, ErrMsgTooLarge):
+				n.penalizePeer(stream.Conn().RemotePeer(), string(stream.Protocol()), err)
+			default:
 				n.log.WarnContext(ctx, fmt.Sprintf("reading %q message", stream.Protocol()), logger.Error(err))
 			}
 			return
 		}
 
-		_, err := n.txBuffer.Add(ctx, tx)
+		// continue the trace of the forwarded transaction when sender propagated it
+		txCtx := ctx
+		if len(carrier) != 0 {
+			txCtx = otel.GetTextMapPropagator().Extract(trace.ContextWithSpanContext(ctx, trace.SpanContext{}), carrier)
+		}
+		_, err = n.txBuffer.Add(txCtx, tx)
 		if err != nil {
 			n.log.WarnContext(ctx, "adding tx to buffer", logger.Error(err))
 			span.AddEvent(err.Error())
@@ -405,9 +517,9 @@ func (n *validatorNetwork) handleTransactions(stream libp2pNetwork.Stream) {
 	}
 }
 
-func (n *validatorNetwork) handleBlocks(ctx context.Context) {
+func (n *validatorNetwork) handleBlocks(ctx context.Context, sub *pubsub.Subscription) {
 	for {
-		msg, err := n.gsSubscriptionBlock.Next(ctx)
+		msg, err := sub.Next(ctx)
 		if err != nil {
 			if cErr := ctx.Err(); cErr == nil {
 				n.log.DebugContext(ctx, "failed to get next block", logger.Error(err))
@@ -422,7 +534,7 @@ func (n *validatorNetwork) handleBlocks(ctx context.Context) {
 			continue
 		}
 
-		if err = n.receivedMsg(msg.ReceivedFrom, msg.GetTopic(), block); err != nil {
+		if err = n.receivedMsg(msg.ReceivedFrom, msg.GetTopic(), block, nil); err != nil {
 			n.log.WarnContext(ctx, "failed to receive block", logger.Error(err))
 		}
 	}
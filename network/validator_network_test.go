@@ -13,11 +13,14 @@ import (
 	"github.com/libp2p/go-libp2p/config"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/stretchr/testify/require"
 	test "github.com/unicitynetwork/bft-core/internal/testutils"
 	"github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	"github.com/unicitynetwork/bft-core/network/protocol/replication"
 	"github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
 	"github.com/unicitynetwork/bft-go-base/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestNewLibP2PValidatorNetwork(t *testing.T) {
@@ -59,7 +62,7 @@ func TestNewValidatorLibP2PNetwork_Ok(t *testing.T) {
 	// we register protocol for each message for both value and pointer type thus
 	// there must be twice the amount of items in the sendProtocols map than the
 	// actual supported message types is
-	require.Equal(t, 10, len(net.sendProtocols))
+	require.Equal(t, 14, len(net.sendProtocols))
 }
 
 func TestForwardTransactions_ChangingReceiver(t *testing.T) {
@@ -192,6 +195,94 @@ func TestForwardTransactions_ChangingReceiver(t *testing.T) {
 	require.NoError(t, peer3.Close())
 }
 
+func TestForwardTransactions_TraceContext(t *testing.T) {
+	obs := observability.Default(t)
+	peer1 := createPeer(t)
+	defer func() { require.NoError(t, peer1.Close()) }()
+	peer2 := createBootstrappedPeer(t, []peer.AddrInfo{{ID: peer1.ID(), Addrs: peer1.host.Addrs()}})
+	defer func() { require.NoError(t, peer2.Close()) }()
+	validators := []peer.ID{peer1.ID(), peer2.ID()}
+
+	network1, err := NewLibP2PValidatorNetwork(context.Background(), &mockNode{1, peer1, validators}, DefaultValidatorNetworkOptions, obs)
+	require.NoError(t, err)
+	require.NoError(t, network1.RegisterValidatorProtocols())
+	opts := DefaultValidatorNetworkOptions
+	opts.TracePropagation = true
+	network2, err := NewLibP2PValidatorNetwork(context.Background(), &mockNode{1, peer2, validators}, opts, obs)
+	require.NoError(t, err)
+	require.NoError(t, network2.RegisterValidatorProtocols())
+	require.NoError(t, peer2.BootstrapConnect(context.Background(), obs.Logger()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go network2.ForwardTransactions(ctx, func() peer.ID { return peer1.ID() })
+
+	traceIDs := make(chan trace.TraceID, 1)
+	go network1.ProcessTransactions(ctx, func(ctx context.Context, tx *types.TransactionOrder) error {
+		traceIDs <- trace.SpanContextFromContext(ctx).TraceID()
+		return nil
+	})
+
+	// the leader continues the trace in which the transaction was submitted to the forwarding node
+	sc := testSpanContext()
+	_, err = network2.AddTransaction(trace.ContextWithSpanContext(ctx, sc), transaction.NewTransactionOrder(t))
+	require.NoError(t, err)
+	select {
+	case id := <-traceIDs:
+		require.Equal(t, sc.TraceID(), id)
+	case <-time.After(test.WaitDuration):
+		t.Fatal("transaction was not processed before timeout")
+	}
+}
+
+func TestBlockPush(t *testing.T) {
+	obs := observability.Default(t)
+	validator := createPeer(t)
+	defer func() { require.NoError(t, validator.Close()) }()
+	fullNode := createBootstrappedPeer(t, []peer.AddrInfo{{ID: validator.ID(), Addrs: validator.host.Addrs()}})
+	defer func() { require.NoError(t, fullNode.Close()) }()
+
+	validatorNet, err := NewLibP2PValidatorNetwork(context.Background(), &mockNode{1, validator, []peer.ID{validator.ID()}}, DefaultValidatorNetworkOptions, obs)
+	require.NoError(t, err)
+	require.NoError(t, validatorNet.RegisterValidatorProtocols())
+	fullNodeNet, err := NewLibP2PValidatorNetwork(context.Background(), &mockNode{1, fullNode, []peer.ID{validator.ID()}}, DefaultValidatorNetworkOptions, obs)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, fullNodeNet.SubscribeToBlocks(ctx))
+	require.NoError(t, fullNode.BootstrapConnect(ctx, obs.Logger()))
+
+	// both nodes serve ledger replication
+	require.Eventually(t, func() bool {
+		return slices.Contains(validatorNet.ReplicationPeers(), fullNode.ID()) &&
+			slices.Contains(fullNodeNet.ReplicationPeers(), validator.ID())
+	}, test.WaitDuration, test.WaitTick)
+
+	// full node subscribes, validator pushes the block; the subscriber is
+	// the authenticated sender, not the node claimed in the request
+	req := &replication.BlockSubscriptionRequest{PartitionID: 1, NodeID: validator.ID().String()}
+	require.NoError(t, fullNodeNet.Send(ctx, req, validator.ID()))
+	select {
+	case msg := <-validatorNet.ReceivedChannel():
+		require.Equal(t, &replication.BlockSubscriptionRequest{PartitionID: 1, NodeID: fullNode.ID().String()}, msg)
+	case <-time.After(test.WaitDuration):
+		t.Fatal("block subscription request not received")
+	}
+
+	block := &types.Block{Header: &types.Header{Version: 1, PartitionID: 1}}
+	require.NoError(t, validatorNet.Send(ctx, block, fullNode.ID()))
+	select {
+	case msg := <-fullNodeNet.ReceivedChannel():
+		require.Equal(t, block.Header, msg.(*types.Block).Header)
+	case <-time.After(test.WaitDuration):
+		t.Fatal("pushed block not received")
+	}
+
+	// no block push when not subscribed
+	fullNodeNet.UnsubscribeFromBlocks()
+	require.NotContains(t, fullNode.host.Mux().Protocols(), protocol.ID(ProtocolBlockPush))
+}
+
 type mockNode struct {
 	partitionID    types.PartitionID
 	peer           *Peer
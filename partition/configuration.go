@@ -26,6 +26,11 @@ const (
 	DefaultReplicationMaxTx         uint32 = 10000
 	DefaultBlockSubscriptionTimeout        = 3000 * time.Millisecond
 	DefaultLedgerReplicationTimeout        = 1500 * time.Millisecond
+	DefaultUCMaxTimestampDrift             = 60 * time.Second
+	DefaultBlockPushRenewInterval          = 10 * time.Second
+	DefaultBlockPushRegistrationTTL        = 30 * time.Second
+	DefaultBlockPushValidators             = 2
+	DefaultBlockPushMaxFullNodes           = 100
 )
 
 var (
@@ -46,6 +51,7 @@ type (
 		bootstrapAddresses    []string
 		bootstrapConnectRetry *network.BootstrapConnectRetry
 		validatorNetwork      ValidatorNetwork
+		wrapValidatorNetwork  func(ValidatorNetwork) ValidatorNetwork
 
 		signer           abcrypto.Signer
 		hashAlgorithm    crypto.Hash // make hash algorithm configurable in the future. currently it is using SHA-256.
@@ -61,7 +67,11 @@ type (
 		eventHandler             event.Handler
 		eventChCapacity          int
 		replicationConfig        ledgerReplicationConfig
+		blockPushConfig          blockPushConfig
 		blockSubscriptionTimeout time.Duration // time since last block when to start recovery on non-validating node
+		ucMaxTimestampDrift      time.Duration // max allowed difference between the UC timestamp and the local time
+		rejectUCAnomalies        bool          // do not process UCs which fail the sanity checks
+		tracePropagation         bool          // send trace context along with the p2p messages
 	}
 
 	NodeOption func(c *NodeConf)
@@ -92,6 +102,18 @@ type (
 		maxTx           uint32
 		timeout         time.Duration
 	}
+
+	// blockPushConfig configures pushing certified blocks from validators to full (non-validator) nodes
+	// renewInterval - how often full node renews its registration with validators;
+	// registrationTTL - how long validator keeps the registration of a full node;
+	// validators - number of validators the full node registers with;
+	// maxFullNodes - max number of full nodes registered with a validator.
+	blockPushConfig struct {
+		renewInterval   time.Duration
+		registrationTTL time.Duration
+		validators      int
+		maxFullNodes    int
+	}
 )
 
 func NewNodeConf(
@@ -110,16 +132,11 @@ func NewNodeConf(
 	if trustBase == nil {
 		return nil, ErrTrustBaseIsNil
 	}
-	signer, err := keyConf.Signer()
-	if err != nil {
-		return nil, err
-	}
 
 	c := &NodeConf{
 		keyConf:       keyConf,
 		shardConf:     shardConf,
 		trustBase:     trustBase,
-		signer:        signer,
 		hashAlgorithm: crypto.SHA256,
 		proofIndexConfig: proofIndexConfig{
 			historyLen: 20,
@@ -129,6 +146,13 @@ func NewNodeConf(
 	for _, option := range nodeOptions {
 		option(c)
 	}
+	if c.signer == nil {
+		signer, err := keyConf.Signer()
+		if err != nil {
+			return nil, err
+		}
+		c.signer = signer
+	}
 	// init default for those not specified by the user
 	if err := c.initMissingDefaults(); err != nil {
 		return nil, fmt.Errorf("initializing missing configuration to default values: %w", err)
@@ -170,6 +194,17 @@ func WithValidatorNetwork(validatorNetwork ValidatorNetwork) NodeOption {
 	}
 }
 
+/*
+WithValidatorNetworkWrapper sets function which is called with the validator
+network created by the node, the network returned by the function is then
+used by the node. Mostly useful for tests which need to intercept the messages.
+*/
+func WithValidatorNetworkWrapper(wrap func(ValidatorNetwork) ValidatorNetwork) NodeOption {
+	return func(c *NodeConf) {
+		c.wrapValidatorNetwork = wrap
+	}
+}
+
 func WithReplicationParams(maxFetchBlocks, maxReturnBlocks uint64, maxTx uint32, timeout time.Duration) NodeOption {
 	return func(c *NodeConf) {
 		c.replicationConfig.maxFetchBlocks = maxFetchBlocks
@@ -179,6 +214,16 @@ func WithReplicationParams(maxFetchBlocks, maxReturnBlocks uint64, maxTx uint32,
 	}
 }
 
+/*
+WithSigner sets the signer used to sign the block proposals and the certification
+requests instead of the signing key of the key configuration, ie remote signer.
+*/
+func WithSigner(signer abcrypto.Signer) NodeOption {
+	return func(c *NodeConf) {
+		c.signer = signer
+	}
+}
+
 func WithUnicityCertificateValidator(unicityCertificateValidator UnicityCertificateValidator) NodeOption {
 	return func(c *NodeConf) {
 		c.ucValidator = unicityCertificateValidator
@@ -241,6 +286,45 @@ func WithBlockSubscriptionTimeout(t time.Duration) NodeOption {
 	}
 }
 
+/*
+WithBlockPushParams configures the certified block push to full nodes. Full
+node registers with "validators" random validators every renewInterval,
+validator pushes blocks to at most maxFullNodes full nodes whose registration
+has not expired (registrationTTL). Zero values mean defaults.
+*/
+func WithBlockPushParams(renewInterval, registrationTTL time.Duration, validators, maxFullNodes int) NodeOption {
+	return func(c *NodeConf) {
+		c.blockPushConfig.renewInterval = renewInterval
+		c.blockPushConfig.registrationTTL = registrationTTL
+		c.blockPushConfig.validators = validators
+		c.blockPushConfig.maxFullNodes = maxFullNodes
+	}
+}
+
+/*
+WithUnicityCertificateChecks configures the sanity checks of the UCs received
+from the root chain. Unicity seal timestamp may differ from the local time by
+at most maxTimestampDrift (0 means default). When reject is true UCs failing
+the checks are not processed, otherwise the anomaly is only reported.
+*/
+func WithUnicityCertificateChecks(maxTimestampDrift time.Duration, reject bool) NodeOption {
+	return func(c *NodeConf) {
+		c.ucMaxTimestampDrift = maxTimestampDrift
+		c.rejectUCAnomalies = reject
+	}
+}
+
+/*
+WithTracePropagation enables sending the trace context along with the p2p messages
+so that the receiving nodes continue the trace. All the nodes of the shard must
+support the trace envelope of the messages.
+*/
+func WithTracePropagation(enabled bool) NodeOption {
+	return func(c *NodeConf) {
+		c.tracePropagation = enabled
+	}
+}
+
 // initMissingDefaults loads missing default configuration.
 func (c *NodeConf) initMissingDefaults() error {
 	if c.t1Timeout == 0 {
@@ -301,6 +385,21 @@ func (c *NodeConf) initMissingDefaults() error {
 	if c.blockSubscriptionTimeout == 0 {
 		c.blockSubscriptionTimeout = DefaultBlockSubscriptionTimeout
 	}
+	if c.blockPushConfig.renewInterval == 0 {
+		c.blockPushConfig.renewInterval = DefaultBlockPushRenewInterval
+	}
+	if c.blockPushConfig.registrationTTL == 0 {
+		c.blockPushConfig.registrationTTL = DefaultBlockPushRegistrationTTL
+	}
+	if c.blockPushConfig.validators == 0 {
+		c.blockPushConfig.validators = DefaultBlockPushValidators
+	}
+	if c.blockPushConfig.maxFullNodes == 0 {
+		c.blockPushConfig.maxFullNodes = DefaultBlockPushMaxFullNodes
+	}
+	if c.ucMaxTimestampDrift == 0 {
+		c.ucMaxTimestampDrift = DefaultUCMaxTimestampDrift
+	}
 	return nil
 }
 
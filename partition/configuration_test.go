@@ -115,3 +115,29 @@ func TestNewNodeConf_WithDefaults(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, rootNodes, 1)
 }
+
+func TestNewNodeConf_WithSigner(t *testing.T) {
+	keyConf, nodeInfo := createKeyConf(t)
+	shardConf := &types.PartitionDescriptionRecord{
+		Version:         1,
+		NetworkID:       5,
+		PartitionID:     0x01010101,
+		PartitionTypeID: 999,
+		TypeIDLen:       8,
+		UnitIDLen:       256,
+		T2Timeout:       2500 * time.Millisecond,
+		Validators:      []*types.NodeInfo{nodeInfo},
+	}
+	_, verifier := testsig.CreateSignerAndVerifier(t)
+	trustBase := trustbase.NewTrustBase(t, verifier)
+	signer, _ := testsig.CreateSignerAndVerifier(t)
+
+	// signing key is not needed in the key configuration when the signer is given
+	keyConf.SigKey = Key{}
+	_, err := NewNodeConf(keyConf, shardConf, trustBase, testobserve.Default(t))
+	require.ErrorContains(t, err, "invalid signing key")
+
+	conf, err := NewNodeConf(keyConf, shardConf, trustBase, testobserve.Default(t), WithSigner(signer))
+	require.NoError(t, err)
+	require.Equal(t, signer, conf.signer)
+}
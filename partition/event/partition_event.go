@@ -11,6 +11,7 @@ const (
 	StateReverted
 	ReplicationResponseSent
 	LatestUnicityCertificateUpdated
+	UnicityCertificateAnomaly
 )
 
 type (
@@ -0,0 +1,92 @@
+package partition
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+var errFullNodeRegistryFull = errors.New("max number of full nodes registered")
+
+/*
+fullNodeRegistry keeps track of the full (non-validator) nodes which have
+subscribed to the certified blocks of the validator. Registration expires
+after the TTL unless renewed by the full node. Node may renew its registration
+at most once per minInterval.
+*/
+type fullNodeRegistry struct {
+	mu          sync.Mutex
+	ttl         time.Duration
+	minInterval time.Duration
+	maxNodes    int
+	expires     map[peer.ID]time.Time
+}
+
+func newFullNodeRegistry(ttl, minInterval time.Duration, maxNodes int) *fullNodeRegistry {
+	return &fullNodeRegistry{
+		ttl:         ttl,
+		minInterval: minInterval,
+		maxNodes:    maxNodes,
+		expires:     make(map[peer.ID]time.Time),
+	}
+}
+
+/*
+register adds or renews the registration of the full node. Returns error when
+the registry is full and the node is not already registered or when the node
+renews its registration too often.
+*/
+func (r *fullNodeRegistry) register(id peer.ID, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.removeExpired(now)
+	exp, ok := r.expires[id]
+	if !ok && len(r.expires) >= r.maxNodes {
+		return errFullNodeRegistryFull
+	}
+	if ok {
+		if lastReg := exp.Add(-r.ttl); now.Sub(lastReg) < r.minInterval {
+			return fmt.Errorf("registration renewed too often, last renewal %s ago", now.Sub(lastReg))
+		}
+	}
+	r.expires[id] = now.Add(r.ttl)
+	return nil
+}
+
+// nodes returns the full nodes whose registration has not expired
+func (r *fullNodeRegistry) nodes(now time.Time) peer.IDSlice {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.removeExpired(now)
+	ids := make(peer.IDSlice, 0, len(r.expires))
+	for id := range r.expires {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (r *fullNodeRegistry) isRegistered(id peer.ID, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exp, ok := r.expires[id]
+	return ok && now.Before(exp)
+}
+
+func (r *fullNodeRegistry) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.expires)
+}
+
+func (r *fullNodeRegistry) removeExpired(now time.Time) {
+	for id, exp := range r.expires {
+		if !now.Before(exp) {
+			delete(r.expires, id)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package partition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullNodeRegistry(t *testing.T) {
+	const ttl = time.Minute
+	r := newFullNodeRegistry(ttl, time.Second, 2)
+	now := time.Now()
+	id1, id2, id3 := peer.ID("1"), peer.ID("2"), peer.ID("3")
+
+	require.Empty(t, r.nodes(now))
+	require.NoError(t, r.register(id1, now))
+	require.NoError(t, r.register(id2, now.Add(time.Second)))
+	require.ElementsMatch(t, peer.IDSlice{id1, id2}, r.nodes(now))
+
+	// registry is full, renewing the registration is allowed
+	require.ErrorIs(t, r.register(id3, now), errFullNodeRegistryFull)
+	require.NoError(t, r.register(id1, now.Add(2*time.Second)))
+	// but not too often
+	require.ErrorContains(t, r.register(id1, now.Add(2500*time.Millisecond)), "registration renewed too often")
+
+	// registration of id2 expires
+	later := now.Add(ttl + time.Second)
+	require.False(t, r.isRegistered(id2, later))
+	require.True(t, r.isRegistered(id1, later))
+	require.Equal(t, peer.IDSlice{id1}, r.nodes(later))
+	require.NoError(t, r.register(id3, later))
+	require.ElementsMatch(t, peer.IDSlice{id1, id3}, r.nodes(later))
+
+	r.clear()
+	require.Empty(t, r.nodes(later))
+}
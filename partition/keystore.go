@@ -0,0 +1,193 @@
+package partition
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+const (
+	keystoreVersion = 1
+
+	KeystoreKDFScrypt       = "scrypt"
+	KeystoreCipherAES256GCM = "aes-256-gcm"
+
+	keystoreKeyLen  = 32 // AES-256
+	keystoreSaltLen = 32
+)
+
+var (
+	ErrKeyConfEncrypted  = errors.New("key configuration is encrypted")
+	ErrInvalidPassphrase = errors.New("invalid passphrase or corrupted key configuration")
+
+	// scrypt cost parameters used for the new key files
+	keystoreScryptN = 1 << 17
+	keystoreScryptR = 8
+	keystoreScryptP = 1
+)
+
+type (
+	/*
+		EncryptedKeyConf is the passphrase protected KeyConf. The encryption key
+		is derived from the passphrase with scrypt and the JSON encoding of the
+		KeyConf is encrypted with AES-256-GCM.
+	*/
+	EncryptedKeyConf struct {
+		Version int            `json:"version"`
+		Crypto  KeystoreCrypto `json:"crypto"`
+	}
+
+	KeystoreCrypto struct {
+		KDF        string       `json:"kdf"`
+		KDFParams  ScryptParams `json:"kdfParams"`
+		Cipher     string       `json:"cipher"`
+		Nonce      hex.Bytes    `json:"nonce"`
+		CipherText hex.Bytes    `json:"cipherText"`
+	}
+
+	ScryptParams struct {
+		N    int       `json:"n"`
+		R    int       `json:"r"`
+		P    int       `json:"p"`
+		Salt hex.Bytes `json:"salt"`
+	}
+)
+
+// EncryptKeyConf encrypts the key configuration with the key derived from the passphrase.
+func EncryptKeyConf(keyConf *KeyConf, passphrase []byte) (*EncryptedKeyConf, error) {
+	if keyConf == nil {
+		return nil, ErrKeyConfIsNil
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase is empty")
+	}
+	plaintext, err := json.Marshal(keyConf)
+	if err != nil {
+		return nil, fmt.Errorf("encoding key configuration: %w", err)
+	}
+
+	ekc := &EncryptedKeyConf{
+		Version: keystoreVersion,
+		Crypto: KeystoreCrypto{
+			KDF: KeystoreKDFScrypt,
+			KDFParams: ScryptParams{
+				N:    keystoreScryptN,
+				R:    keystoreScryptR,
+				P:    keystoreScryptP,
+				Salt: make([]byte, keystoreSaltLen),
+			},
+			Cipher: KeystoreCipherAES256GCM,
+		},
+	}
+	if _, err := rand.Read(ekc.Crypto.KDFParams.Salt); err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+	aead, err := ekc.Crypto.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	ekc.Crypto.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(ekc.Crypto.Nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	ekc.Crypto.CipherText = aead.Seal(nil, ekc.Crypto.Nonce, plaintext, nil)
+	return ekc, nil
+}
+
+// Decrypt returns the key configuration decrypted with the key derived from the passphrase.
+func (ekc *EncryptedKeyConf) Decrypt(passphrase []byte) (*KeyConf, error) {
+	if ekc.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported key configuration version %d", ekc.Version)
+	}
+	aead, err := ekc.Crypto.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(ekc.Crypto.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length %d", len(ekc.Crypto.Nonce))
+	}
+	plaintext, err := aead.Open(nil, ekc.Crypto.Nonce, ekc.Crypto.CipherText, nil)
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+	keyConf := &KeyConf{}
+	if err := json.Unmarshal(plaintext, keyConf); err != nil {
+		return nil, fmt.Errorf("decoding key configuration: %w", err)
+	}
+	return keyConf, nil
+}
+
+func (c *KeystoreCrypto) aead(passphrase []byte) (cipher.AEAD, error) {
+	if c.KDF != KeystoreKDFScrypt {
+		return nil, fmt.Errorf("unsupported key derivation function %q", c.KDF)
+	}
+	if c.Cipher != KeystoreCipherAES256GCM {
+		return nil, fmt.Errorf("unsupported cipher %q", c.Cipher)
+	}
+	key, err := scrypt.Key(passphrase, c.KDFParams.Salt, c.KDFParams.N, c.KDFParams.R, c.KDFParams.P, keystoreKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("deriving encryption key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+/*
+ParseKeyConf decodes the JSON encoded key configuration which is either plain
+KeyConf or EncryptedKeyConf. The passphrase func is called only when the key
+configuration is encrypted, when it is nil ErrKeyConfEncrypted is returned for
+the encrypted key configuration.
+*/
+func ParseKeyConf(data []byte, passphrase func() ([]byte, error)) (*KeyConf, error) {
+	ekc, err := ParseEncryptedKeyConf(data)
+	if err != nil {
+		return nil, err
+	}
+	if ekc == nil {
+		keyConf := &KeyConf{}
+		if err := json.Unmarshal(data, keyConf); err != nil {
+			return nil, fmt.Errorf("decoding key configuration: %w", err)
+		}
+		return keyConf, nil
+	}
+
+	if passphrase == nil {
+		return nil, ErrKeyConfEncrypted
+	}
+	pass, err := passphrase()
+	if err != nil {
+		return nil, fmt.Errorf("reading passphrase: %w", err)
+	}
+	return ekc.Decrypt(pass)
+}
+
+/*
+ParseEncryptedKeyConf decodes the JSON encoded encrypted key configuration,
+returns nil when the data is plain (unencrypted) key configuration.
+*/
+func ParseEncryptedKeyConf(data []byte) (*EncryptedKeyConf, error) {
+	var head struct {
+		Crypto json.RawMessage `json:"crypto"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("decoding key configuration: %w", err)
+	}
+	if head.Crypto == nil {
+		return nil, nil
+	}
+	ekc := &EncryptedKeyConf{}
+	if err := json.Unmarshal(data, ekc); err != nil {
+		return nil, fmt.Errorf("decoding encrypted key configuration: %w", err)
+	}
+	return ekc, nil
+}
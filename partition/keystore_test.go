@@ -0,0 +1,128 @@
+package partition
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lowers the scrypt cost for the duration of the test
+func setTestScryptCost(t *testing.T) {
+	n := keystoreScryptN
+	keystoreScryptN = 1 << 10
+	t.Cleanup(func() { keystoreScryptN = n })
+}
+
+func TestEncryptKeyConf(t *testing.T) {
+	setTestScryptCost(t)
+	keyConf, _ := createKeyConf(t)
+	passphrase := []byte("secret")
+
+	ekc, err := EncryptKeyConf(keyConf, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, KeystoreKDFScrypt, ekc.Crypto.KDF)
+	require.Equal(t, KeystoreCipherAES256GCM, ekc.Crypto.Cipher)
+	require.NotContains(t, string(ekc.Crypto.CipherText), string(keyConf.SigKey.PrivateKey))
+
+	t.Run("decrypt", func(t *testing.T) {
+		decrypted, err := ekc.Decrypt(passphrase)
+		require.NoError(t, err)
+		require.Equal(t, keyConf, decrypted)
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		decrypted, err := ekc.Decrypt([]byte("wrong"))
+		require.ErrorIs(t, err, ErrInvalidPassphrase)
+		require.Nil(t, decrypted)
+	})
+
+	t.Run("salt and nonce are not reused", func(t *testing.T) {
+		ekc2, err := EncryptKeyConf(keyConf, passphrase)
+		require.NoError(t, err)
+		require.NotEqual(t, ekc.Crypto.KDFParams.Salt, ekc2.Crypto.KDFParams.Salt)
+		require.NotEqual(t, ekc.Crypto.Nonce, ekc2.Crypto.Nonce)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := EncryptKeyConf(nil, passphrase)
+		require.ErrorIs(t, err, ErrKeyConfIsNil)
+		_, err = EncryptKeyConf(keyConf, nil)
+		require.EqualError(t, err, "passphrase is empty")
+	})
+
+	t.Run("tampered cipher text", func(t *testing.T) {
+		tampered := *ekc
+		tampered.Crypto.CipherText = append([]byte{}, ekc.Crypto.CipherText...)
+		tampered.Crypto.CipherText[0] ^= 1
+		_, err := tampered.Decrypt(passphrase)
+		require.ErrorIs(t, err, ErrInvalidPassphrase)
+	})
+
+	t.Run("unsupported parameters", func(t *testing.T) {
+		unsupported := *ekc
+		unsupported.Version = 2
+		_, err := unsupported.Decrypt(passphrase)
+		require.EqualError(t, err, "unsupported key configuration version 2")
+
+		unsupported = *ekc
+		unsupported.Crypto.KDF = "pbkdf2"
+		_, err = unsupported.Decrypt(passphrase)
+		require.EqualError(t, err, `unsupported key derivation function "pbkdf2"`)
+
+		unsupported = *ekc
+		unsupported.Crypto.Cipher = "aes-128-ctr"
+		_, err = unsupported.Decrypt(passphrase)
+		require.EqualError(t, err, `unsupported cipher "aes-128-ctr"`)
+	})
+}
+
+func TestParseKeyConf(t *testing.T) {
+	setTestScryptCost(t)
+	keyConf, _ := createKeyConf(t)
+	passphrase := []byte("secret")
+	plain, err := json.Marshal(keyConf)
+	require.NoError(t, err)
+	ekc, err := EncryptKeyConf(keyConf, passphrase)
+	require.NoError(t, err)
+	encrypted, err := json.Marshal(ekc)
+	require.NoError(t, err)
+
+	t.Run("plain", func(t *testing.T) {
+		parsed, err := ParseKeyConf(plain, func() ([]byte, error) {
+			t.Error("passphrase is not needed for plain key configuration")
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, keyConf, parsed)
+
+		parsed, err = ParseKeyConf(plain, nil)
+		require.NoError(t, err)
+		require.Equal(t, keyConf, parsed)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		parsed, err := ParseKeyConf(encrypted, func() ([]byte, error) { return passphrase, nil })
+		require.NoError(t, err)
+		require.Equal(t, keyConf, parsed)
+
+		parsed, err = ParseKeyConf(encrypted, func() ([]byte, error) { return []byte("wrong"), nil })
+		require.ErrorIs(t, err, ErrInvalidPassphrase)
+		require.Nil(t, parsed)
+	})
+
+	t.Run("encrypted, no passphrase", func(t *testing.T) {
+		_, err := ParseKeyConf(encrypted, nil)
+		require.ErrorIs(t, err, ErrKeyConfEncrypted)
+
+		expErr := errors.New("no terminal")
+		_, err = ParseKeyConf(encrypted, func() ([]byte, error) { return nil, expErr })
+		require.ErrorIs(t, err, expErr)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := ParseKeyConf([]byte("not json"), nil)
+		require.ErrorContains(t, err, "decoding key configuration")
+	})
+}
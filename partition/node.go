@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -32,6 +33,7 @@ import (
 	"github.com/unicitynetwork/bft-core/network/protocol/replication"
 	"github.com/unicitynetwork/bft-core/observability"
 	"github.com/unicitynetwork/bft-core/partition/event"
+	"github.com/unicitynetwork/bft-core/remotesigner"
 	"github.com/unicitynetwork/bft-core/txsystem"
 )
 
@@ -71,6 +73,7 @@ type (
 		UnsubscribeFromBlocks()
 		RegisterValidatorProtocols() error
 		UnregisterValidatorProtocols()
+		ReplicationPeers() peer.IDSlice
 
 		AddTransaction(ctx context.Context, tx *types.TransactionOrder) ([]byte, error)
 		ForwardTransactions(ctx context.Context, receiverFunc network.TxReceiver)
@@ -102,6 +105,7 @@ type (
 		// Can be nil if latest UC was received with a block (recovery or block propagation protocols).
 		ltr                  atomic.Pointer[certification.TechnicalRecord]
 		proposedTransactions []*types.TransactionRecord
+		proposedTxLinks      []trace.Link // links to the traces of the proposed transactions
 		sumOfEarnedFees      uint64
 		pendingBlockProposal *types.Block
 		leader               Leader
@@ -118,6 +122,8 @@ type (
 		network           ValidatorNetwork
 		eventCh           chan event.Event
 		lastLedgerReqTime time.Time
+		fullNodes         *fullNodeRegistry // full nodes subscribed to the blocks of the validator
+		lastBlockSubReq   time.Time         // when the full node last registered with validators
 		eventHandler      event.Handler
 		recoveryLastProp  *blockproposal.BlockProposal
 		log               *slog.Logger
@@ -131,6 +137,7 @@ type (
 		execMsgDur  metric.Float64Histogram
 		execT1Dur   metric.Float64Histogram
 		recoveryReq metric.Int64Counter
+		ucAnomalies metric.Int64Counter
 		fixedAttr   metric.MeasurementOption // partition & shard
 	}
 
@@ -139,6 +146,18 @@ type (
 		EpochNumber uint64 `json:"epochNumber"`
 	}
 
+	// HealthInfo is the snapshot of the node's sync status and role
+	HealthInfo struct {
+		Status         string    // initializing, normal or recovering
+		IsValidator    bool      // node is validator in the current epoch
+		IsLeader       bool      // node is the leader of the current round
+		CommittedRound uint64    // round of the latest committed block
+		LatestRound    uint64    // round of the latest UC seen, recovery target while recovering
+		LatestUCTime   time.Time // unicity seal timestamp of the latest UC seen
+		PeerCount      int       // number of connected peers
+		ValidatorCount int       // number of validators in the current epoch
+	}
+
 	status int
 )
 
@@ -193,6 +212,7 @@ func NewNode(ctx context.Context, txSystem txsystem.TransactionSystem, conf *Nod
 		shardStore:        shardStore,
 		network:           conf.validatorNetwork,
 		lastLedgerReqTime: time.Time{},
+		fullNodes:         newFullNodeRegistry(conf.blockPushConfig.registrationTTL, conf.blockPushConfig.renewInterval/2, conf.blockPushConfig.maxFullNodes),
 		tracer:            tracer,
 	}
 	n.log = conf.observability.RoundLogger(n.currentRoundNumber)
@@ -286,6 +306,11 @@ func (n *Node) initMetrics(observe Observability) (err error) {
 		return fmt.Errorf("creating counter for recovery attempts: %w", err)
 	}
 
+	n.ucAnomalies, err = m.Int64Counter("uc.anomaly", metric.WithDescription("Number of UCs received from the root chain which failed the sanity checks"))
+	if err != nil {
+		return fmt.Errorf("creating counter for UC anomalies: %w", err)
+	}
+
 	n.fixedAttr = observability.Shard(n.PartitionID(), n.ShardID())
 
 	return nil
@@ -371,11 +396,15 @@ func (n *Node) initNetwork(ctx context.Context, peerConf *network.PeerConfigurat
 
 	opts := network.DefaultValidatorNetworkOptions
 	opts.TxBufferHashAlgorithm = n.conf.hashAlgorithm
+	opts.TracePropagation = n.conf.tracePropagation
 
 	n.network, err = network.NewLibP2PValidatorNetwork(ctx, n, opts, observe)
 	if err != nil {
 		return err
 	}
+	if n.conf.wrapValidatorNetwork != nil {
+		n.network = n.conf.wrapValidatorNetwork(n.network)
+	}
 
 	// Open a connection to the bootstrap nodes.
 	// This is the only way to discover other peers, so let's do this as soon as possible.
@@ -457,17 +486,21 @@ func (n *Node) applyBlockTransactions(ctx context.Context, round uint64, txs []*
 	if err := n.transactionSystem.BeginBlock(round); err != nil {
 		return nil, 0, err
 	}
-	for _, txr := range txs {
+	txos := make([]*types.TransactionOrder, len(txs))
+	for i, txr := range txs {
 		txo, err := txr.GetTransactionOrderV1()
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to get transaction order: %w", err)
 		}
-		tr, err := n.validateAndExecuteTx(ctx, txo, round)
-		if err != nil {
+		txos[i] = txo
+	}
+	trs, errs := n.validateAndExecuteTxs(ctx, txos, round)
+	for i, txo := range txos {
+		if err := errs[i]; err != nil {
 			n.log.WarnContext(ctx, "processing transaction", logger.Error(err), logger.UnitID(txo.UnitID))
 			return nil, 0, fmt.Errorf("processing transaction '%v': %w", txo.UnitID, err)
 		}
-		sumOfEarnedFees += tr.GetActualFee()
+		sumOfEarnedFees += trs[i].GetActualFee()
 	}
 	state, err := n.transactionSystem.EndBlock()
 	if err != nil {
@@ -562,9 +595,10 @@ func (n *Node) loop(ctx context.Context) error {
 			if !ok {
 				return errors.New("network received channel is closed")
 			}
+			msgCtx, m := network.UnwrapMsg(ctx, m)
 			n.log.Log(ctx, logger.LevelTrace, fmt.Sprintf("received %T", m), logger.Data(m))
 
-			if err := n.handleMessage(ctx, m); err != nil {
+			if err := n.handleMessage(msgCtx, m); err != nil {
 				n.log.WarnContext(ctx, fmt.Sprintf("handling %T", m), logger.Error(err))
 			} else if _, ok := m.(*certification.CertificationResponse); ok {
 				lastUCReceived = time.Now()
@@ -586,7 +620,7 @@ handleMessage processes message received from AB network (ie from other shard va
 */
 func (n *Node) handleMessage(ctx context.Context, msg any) (rErr error) {
 	msgAttr := attribute.String("msg", fmt.Sprintf("%T", msg))
-	ctx, span := n.tracer.Start(ctx, "node.handleMessage", trace.WithNewRoot(), trace.WithAttributes(msgAttr, n.attrRound()), trace.WithSpanKind(trace.SpanKindServer))
+	ctx, span := n.tracer.Start(ctx, "node.handleMessage", trace.WithAttributes(msgAttr, n.attrRound()), trace.WithSpanKind(trace.SpanKindServer))
 	defer func(start time.Time) {
 		if rErr != nil {
 			span.RecordError(rErr)
@@ -607,6 +641,8 @@ func (n *Node) handleMessage(ctx context.Context, msg any) (rErr error) {
 		return n.handleLedgerReplicationRequest(ctx, mt)
 	case *replication.LedgerReplicationResponse:
 		return n.handleLedgerReplicationResponse(ctx, mt)
+	case *replication.BlockSubscriptionRequest:
+		return n.handleBlockSubscriptionRequest(ctx, mt)
 	case *types.Block:
 		return n.handleBlock(ctx, mt)
 	default:
@@ -650,6 +686,11 @@ func statusCodeOfTxError(err error) string {
 
 func (n *Node) process(ctx context.Context, tx *types.TransactionOrder) error {
 	trx, err := n.validateAndExecuteTx(ctx, tx, n.currentRoundNumber())
+	return n.processed(ctx, tx, trx, err)
+}
+
+// processed adds the executed transaction to the proposal, err is the error of the validation or execution of the transaction.
+func (n *Node) processed(ctx context.Context, tx *types.TransactionOrder, trx *types.TransactionRecord, err error) error {
 	if err != nil || (n.IsFeelessMode() && trx.TxStatus() != types.TxStatusSuccessful) {
 		n.sendEvent(event.TransactionFailed, tx)
 		if err == nil {
@@ -662,6 +703,9 @@ func (n *Node) process(ctx context.Context, tx *types.TransactionOrder) error {
 		return fmt.Errorf("executing transaction %X: %w", txHash, err)
 	}
 	n.proposedTransactions = append(n.proposedTransactions, trx)
+	if link := trace.LinkFromContext(ctx); link.SpanContext.IsValid() {
+		n.proposedTxLinks = append(n.proposedTxLinks, link)
+	}
 	n.sumOfEarnedFees += trx.GetActualFee()
 	n.sendEvent(event.TransactionProcessed, tx)
 	n.log.DebugContext(ctx, fmt.Sprintf("transaction processed, proposal size: %d", len(n.proposedTransactions)), logger.UnitID(tx.UnitID))
@@ -685,6 +729,56 @@ func (n *Node) validateAndExecuteTx(ctx context.Context, tx *types.TransactionOr
 	return txr, nil
 }
 
+/*
+validateAndExecuteTxs validates and executes the transactions in the given order,
+the results are the same as if validateAndExecuteTx was called for each of them.
+When the transaction system supports it the transactions are executed as a batch,
+ie the non-conflicting transactions may be executed in parallel.
+*/
+func (n *Node) validateAndExecuteTxs(ctx context.Context, txs []*types.TransactionOrder, round uint64) ([]*types.TransactionRecord, []error) {
+	batchExec, ok := n.transactionSystem.(txsystem.BatchExecutor)
+	if !ok {
+		trs := make([]*types.TransactionRecord, len(txs))
+		errs := make([]error, len(txs))
+		for i, tx := range txs {
+			trs[i], errs[i] = n.validateAndExecuteTx(ctx, tx, round)
+		}
+		return trs, errs
+	}
+
+	errs := make([]error, len(txs))
+	// validation time of the transactions, the execution time is measured by the executor
+	durs := make([]time.Duration, len(txs))
+	valid := make([]*types.TransactionOrder, 0, len(txs))
+	for i, tx := range txs {
+		start := time.Now()
+		err := n.conf.txValidator.Validate(tx, round)
+		durs[i] = time.Since(start)
+		if err != nil {
+			errs[i] = fmt.Errorf("invalid transaction: %w", err)
+			continue
+		}
+		valid = append(valid, tx)
+	}
+	validTrs, validDurs, validErrs := batchExec.ExecuteBatch(valid)
+
+	trs := make([]*types.TransactionRecord, len(txs))
+	for i, j := 0, 0; i < len(txs); i++ {
+		if errs[i] == nil {
+			trs[i] = validTrs[j]
+			durs[i] += validDurs[j]
+			if validErrs[j] != nil {
+				errs[i] = fmt.Errorf("executing transaction in transaction system: %w", validErrs[j])
+			}
+			j++
+		}
+		txTypeAttr := attribute.Int("tx", int(txs[i].Type))
+		n.execTxCnt.Add(ctx, 1, metric.WithAttributeSet(attribute.NewSet(txTypeAttr, attribute.String("status", statusCodeOfTxError(errs[i])))), n.fixedAttr)
+		n.execTxDur.Record(ctx, durs[i].Seconds(), metric.WithAttributeSet(attribute.NewSet(txTypeAttr)), n.fixedAttr)
+	}
+	return trs, errs
+}
+
 // handleBlockProposal processes a block proposals. Performs the following steps:
 //  1. Block proposal as a whole is validated:
 //     * It must have valid signature, correct transaction partition ID, valid UC;
@@ -757,12 +851,15 @@ func (n *Node) handleBlockProposal(ctx context.Context, prop *blockproposal.Bloc
 	if err := n.transactionSystem.BeginBlock(n.currentRoundNumber()); err != nil {
 		return fmt.Errorf("transaction system BeginBlock error, %w", err)
 	}
-	for _, tx := range prop.Transactions {
-		txo, err := tx.GetTransactionOrderV1()
-		if err != nil {
+	txos := make([]*types.TransactionOrder, len(prop.Transactions))
+	for i, tx := range prop.Transactions {
+		if txos[i], err = tx.GetTransactionOrderV1(); err != nil {
 			return fmt.Errorf("failed to get transaction order: %w", err)
 		}
-		if err = n.process(ctx, txo); err != nil {
+	}
+	trs, errs := n.validateAndExecuteTxs(ctx, txos, n.currentRoundNumber())
+	for i, txo := range txos {
+		if err = n.processed(ctx, txo, trs[i], errs[i]); err != nil {
 			txHash, err2 := txo.Hash(n.conf.hashAlgorithm)
 			if err2 != nil {
 				return fmt.Errorf("hashing transaction during processing: %w", err2)
@@ -810,6 +907,14 @@ func (n *Node) updateLUC(ctx context.Context, uc *types.UnicityCertificate, tr *
 		n.log.DebugContext(ctx, fmt.Sprintf("LUC:\n%s\n\nReceived UC:\n%s", printUC(luc), printUC(uc)))
 	}
 
+	// sanity check the supposedly current UC only after it has been validated
+	// cryptographically, so that forged UCs can't raise anomalies
+	if tr != nil {
+		if err := n.checkUnicitySeal(ctx, uc); err != nil && n.conf.rejectUCAnomalies {
+			return fmt.Errorf("rejected UC of root round %d: %w", uc.GetRootRoundNumber(), err)
+		}
+	}
+
 	// check for equivocation
 	// Skip this check if LUC is missing. LUC can only miss if node was started with an uncertified state (likely genesis).
 	if luc != nil {
@@ -935,10 +1040,48 @@ func (n *Node) handleCertificationResponse(ctx context.Context, cr *certificatio
 	return n.handleUnicityCertificate(ctx, &cr.UC, &cr.Technical)
 }
 
+/*
+checkUnicitySeal performs sanity checks of the UC received from the root chain:
+root round and timestamp of the unicity seal must not go backwards compared to
+the LUC and the timestamp must be within the allowed drift from the local time.
+Failed checks are reported as UnicityCertificateAnomaly event and counted by
+the "uc.anomaly" metric, returns the failed checks as an error.
+*/
+func (n *Node) checkUnicitySeal(ctx context.Context, uc *types.UnicityCertificate) error {
+	seal := uc.UnicitySeal
+	var errs []error
+	anomaly := func(check string, err error) {
+		n.ucAnomalies.Add(ctx, 1, n.fixedAttr, metric.WithAttributes(attribute.String("check", check)))
+		errs = append(errs, err)
+	}
+
+	if luc := n.luc.Load(); luc != nil {
+		lucSeal := luc.UnicitySeal
+		if seal.RootChainRoundNumber < lucSeal.RootChainRoundNumber {
+			anomaly("root_round", fmt.Errorf("root round %d is older than root round %d of the latest UC", seal.RootChainRoundNumber, lucSeal.RootChainRoundNumber))
+		} else if seal.RootChainRoundNumber > lucSeal.RootChainRoundNumber && seal.Timestamp < lucSeal.Timestamp {
+			anomaly("timestamp", fmt.Errorf("timestamp %d of root round %d is before timestamp %d of root round %d of the latest UC",
+				seal.Timestamp, seal.RootChainRoundNumber, lucSeal.Timestamp, lucSeal.RootChainRoundNumber))
+		}
+	}
+	// #nosec G115 its unlikely that the timestamp exceeds int64 max value
+	if drift := time.Since(time.Unix(int64(seal.Timestamp), 0)); drift > n.conf.ucMaxTimestampDrift || -drift > n.conf.ucMaxTimestampDrift {
+		anomaly("drift", fmt.Errorf("timestamp %d of root round %d differs from the local time by %s", seal.Timestamp, seal.RootChainRoundNumber, drift.Truncate(time.Second)))
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		n.log.WarnContext(ctx, "UC sanity check failed", logger.Error(err))
+		n.sendEvent(event.UnicityCertificateAnomaly, err)
+	}
+	return err
+}
+
 // handleUnicityCertificate processes the Unicity Certificate and finalizes a block. Performs the following steps:
 //  1. Given UC is validated cryptographically -> checked before this method is called by unicityCertificateValidator
 //  2. Given UC has correct partition identifier -> checked before this method is called by unicityCertificateValidator
-//  3. TODO: sanity check timestamp
+//  3. Timestamp and root round of the UC received from the root chain are sanity checked -> checked before this method
+//     is called by checkUnicitySeal
 //  4. Given UC is checked for equivocation (for more details see certificates.CheckNonEquivocatingCertificates)
 //  5. On unexpected case where there is no pending block proposal, recovery is initiated, unless the state is already
 //     up-to-date with the given UC.
@@ -1066,6 +1209,7 @@ func (n *Node) handleUnicityCertificate(ctx context.Context, uc *types.UnicityCe
 		n.log.WarnContext(ctx, fmt.Sprintf("failed to publish block %d: %v",
 			uc.GetRoundNumber(), err))
 	}
+	n.pushBlock(ctx, n.pendingBlockProposal, uc.GetRoundNumber())
 
 	return n.startNewRound(ctx)
 }
@@ -1195,6 +1339,64 @@ func (n *Node) handleMonitoring(ctx context.Context, lastUCReceived, lastBlockRe
 		n.log.WarnContext(ctx, "Block subscription timeout, starting recovery")
 		n.startRecovery(ctx)
 	}
+	// full node renews its registration with validators for the block push
+	if !n.IsValidator() && time.Since(n.lastBlockSubReq) > n.conf.blockPushConfig.renewInterval {
+		n.sendBlockSubscriptionRequest(ctx)
+	}
+}
+
+/*
+sendBlockSubscriptionRequest registers the full node with random validators
+so that they push certified blocks to the node.
+*/
+func (n *Node) sendBlockSubscriptionRequest(ctx context.Context) {
+	n.lastBlockSubReq = time.Now()
+	req := &replication.BlockSubscriptionRequest{
+		PartitionID: n.PartitionID(),
+		ShardID:     n.ShardID(),
+		NodeID:      n.peer.ID().String(),
+	}
+	validators := util.ShuffleSliceCopy(n.Validators())
+	validators = validators[:min(len(validators), n.conf.blockPushConfig.validators)]
+	if len(validators) == 0 {
+		return
+	}
+	n.log.DebugContext(ctx, fmt.Sprintf("Sending block subscription request to %v", validators))
+	if err := n.network.Send(ctx, req, validators...); err != nil {
+		n.log.WarnContext(ctx, "sending block subscription request", logger.Error(err))
+	}
+}
+
+func (n *Node) handleBlockSubscriptionRequest(ctx context.Context, req *replication.BlockSubscriptionRequest) error {
+	if err := req.IsValid(); err != nil {
+		return fmt.Errorf("invalid block subscription request: %w", err)
+	}
+	if req.PartitionID != n.PartitionID() || !req.ShardID.Equal(n.ShardID()) {
+		return fmt.Errorf("block subscription request for wrong shard %s-%s", req.PartitionID, req.ShardID)
+	}
+	if !n.IsValidator() {
+		return errors.New("block subscription request received by non-validator")
+	}
+	// NodeID is set by the network to the authenticated sender of the request
+	id, err := peer.Decode(req.NodeID)
+	if err != nil {
+		return fmt.Errorf("decoding peer id %q: %w", req.NodeID, err)
+	}
+	if err := n.fullNodes.register(id, time.Now()); err != nil {
+		return fmt.Errorf("block subscription of %s rejected: %w", id, err)
+	}
+	return nil
+}
+
+// pushBlock sends the certified block to the full nodes registered with the validator
+func (n *Node) pushBlock(ctx context.Context, b *types.Block, round uint64) {
+	fullNodes := n.fullNodes.nodes(time.Now())
+	if len(fullNodes) == 0 {
+		return
+	}
+	if err := n.network.Send(ctx, b, fullNodes...); err != nil {
+		n.log.WarnContext(ctx, fmt.Sprintf("failed to push block %d to full nodes", round), logger.Error(err))
+	}
 }
 
 func (n *Node) sendLedgerReplicationResponse(ctx context.Context, msg *replication.LedgerReplicationResponse, toId string) error {
@@ -1443,15 +1645,14 @@ func (n *Node) sendLedgerReplicationRequest(ctx context.Context) {
 	}
 	n.log.Log(ctx, logger.LevelTrace, "sending ledger replication request", logger.Data(req))
 
-	// TODO: should send to non-validators also
-	peers := n.Validators()
+	peers := n.replicationPeers()
 	if len(peers) == 0 {
 		n.log.WarnContext(ctx, "Error sending ledger replication request, no peers")
 		return
 	}
 
-	// send Ledger Replication request to a first alive randomly chosen node
-	for _, p := range util.ShuffleSliceCopy(peers) {
+	// send Ledger Replication request to a first alive node
+	for _, p := range peers {
 		if n.peer.ID() == p {
 			continue
 		}
@@ -1470,8 +1671,30 @@ func (n *Node) sendLedgerReplicationRequest(ctx context.Context) {
 	n.log.WarnContext(ctx, "failed to send ledger replication request (no peers, all peers down?)")
 }
 
+/*
+replicationPeers returns the peers to request the missing blocks from, in the
+order of preference. Full nodes prefer other full nodes to keep the load off
+the validators, validators prefer other validators. Within the group the peers
+are shuffled.
+*/
+func (n *Node) replicationPeers() peer.IDSlice {
+	validators := n.Validators()
+	var fullNodes peer.IDSlice
+	for _, p := range n.network.ReplicationPeers() {
+		if !slices.Contains(validators, p) {
+			fullNodes = append(fullNodes, p)
+		}
+	}
+	validators = util.ShuffleSliceCopy(validators)
+	fullNodes = util.ShuffleSliceCopy(fullNodes)
+	if n.IsValidator() {
+		return append(validators, fullNodes...)
+	}
+	return append(fullNodes, validators...)
+}
+
 func (n *Node) sendBlockProposal(ctx context.Context) error {
-	ctx, span := n.tracer.Start(ctx, "node.sendBlockProposal")
+	ctx, span := n.tracer.Start(ctx, "node.sendBlockProposal", trace.WithLinks(n.proposedTxLinks...))
 	defer span.End()
 
 	ltr := n.ltr.Load()
@@ -1490,7 +1713,10 @@ func (n *Node) sendBlockProposal(ctx context.Context) error {
 		Transactions:       n.proposedTransactions,
 	}
 	n.log.Log(ctx, logger.LevelTrace, "created BlockProposal", logger.Data(prop))
-	if err := prop.Sign(n.conf.hashAlgorithm, n.conf.signer); err != nil {
+	// the remote signer keys the proposal by the partition round, the root round of the UC
+	// orders the proposals of the round which repeats when the root chain times out the round
+	signer := remotesigner.ForMessage(n.conf.signer, remotesigner.KindBlockProposal, prop.UnicityCertificate.GetRootRoundNumber(), prop)
+	if err := prop.Sign(n.conf.hashAlgorithm, signer); err != nil {
 		return fmt.Errorf("block proposal sign failed, %w", err)
 	}
 	n.blockSize.Record(ctx, int64(len(prop.Transactions)), n.fixedAttr)
@@ -1554,6 +1780,7 @@ func (n *Node) sendCertificationRequest(ctx context.Context, blockAuthor string)
 	}
 	n.pendingBlockProposal = pendingProposal
 	n.proposedTransactions = []*types.TransactionRecord{}
+	n.proposedTxLinks = nil
 	n.sumOfEarnedFees = 0
 
 	// send new input record for certification
@@ -1570,7 +1797,8 @@ func (n *Node) sendCertificationRequest(ctx context.Context, blockAuthor string)
 	if req.StateSize, err = n.transactionSystem.StateSize(); err != nil {
 		return fmt.Errorf("calculating state size: %w", err)
 	}
-	if err = req.Sign(n.conf.signer); err != nil {
+	signer := remotesigner.ForMessage(n.conf.signer, remotesigner.KindCertificationRequest, luc.GetRootRoundNumber(), req)
+	if err = req.Sign(signer); err != nil {
 		return fmt.Errorf("failed to sign certification request: %w", err)
 	}
 	n.log.InfoContext(ctx, fmt.Sprintf("Round %v sending block certification request to root chain, IR hash %X, Block Hash %X, ET hash %X, fee sum %d",
@@ -1585,6 +1813,7 @@ func (n *Node) sendCertificationRequest(ctx context.Context, blockAuthor string)
 
 func (n *Node) resetProposal() {
 	n.proposedTransactions = []*types.TransactionRecord{}
+	n.proposedTxLinks = nil
 	n.pendingBlockProposal = nil
 }
 
@@ -1709,6 +1938,29 @@ func (n *Node) CurrentRoundInfo(ctx context.Context) (*RoundInfo, error) {
 	}, nil
 }
 
+/*
+Health returns the sync status and role of the node. It's called concurrently
+with the node's main loop so the fields are read only through atomics (status,
+latest UC) or under the lock of their owner (leader, shard store, state).
+*/
+func (n *Node) Health() *HealthInfo {
+	luc := n.luc.Load()
+	isValidator, validatorCount := n.shardStore.ValidatorStatus(n.peer.ID())
+	info := &HealthInfo{
+		Status:         n.status.Load().(status).String(),
+		IsValidator:    isValidator,
+		IsLeader:       n.leader.IsLeader(n.peer.ID()),
+		CommittedRound: n.committedUC().GetRoundNumber(),
+		LatestRound:    luc.GetRoundNumber(),
+		PeerCount:      len(n.peer.Network().Peers()),
+		ValidatorCount: validatorCount,
+	}
+	if luc != nil && luc.UnicitySeal != nil {
+		info.LatestUCTime = time.Unix(int64(luc.UnicitySeal.Timestamp), 0)
+	}
+	return info
+}
+
 func (n *Node) GetTrustBase(epochNumber uint64) (types.RootTrustBase, error) {
 	// TODO verify epoch number after epoch switching is implemented
 	// fast-track solution is to restart all partition nodes with new config on epoch change
@@ -1811,6 +2063,7 @@ func (n *Node) startValidating(ctx context.Context) {
 	n.log.InfoContext(ctx, "Entering validator mode")
 
 	n.network.UnsubscribeFromBlocks()
+	n.fullNodes.clear()
 	if err := n.network.RegisterValidatorProtocols(); err != nil {
 		n.log.ErrorContext(ctx, "Failed to register validator protocols", logger.Error(err))
 	}
@@ -1826,6 +2079,9 @@ func (n *Node) stopValidating(ctx context.Context) {
 		n.log.ErrorContext(ctx, "Failed to subscribe to blocks", logger.Error(err))
 	}
 	n.network.UnregisterValidatorProtocols()
+	n.fullNodes.clear()
+	// register with validators for the block push right away
+	n.lastBlockSubReq = time.Time{}
 	n.startProcessingTransactions(ctx)
 }
 
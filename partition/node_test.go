@@ -4,18 +4,22 @@ import (
 	"context"
 	gocrypto "crypto"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/require"
 	test "github.com/unicitynetwork/bft-core/internal/testutils"
 	testevent "github.com/unicitynetwork/bft-core/internal/testutils/partition/event"
+	testsig "github.com/unicitynetwork/bft-core/internal/testutils/sig"
 	"github.com/unicitynetwork/bft-core/internal/testutils/trustbase"
 	testtxsystem "github.com/unicitynetwork/bft-core/internal/testutils/txsystem"
 	"github.com/unicitynetwork/bft-core/keyvaluedb/memorydb"
 	"github.com/unicitynetwork/bft-core/network"
 	"github.com/unicitynetwork/bft-core/network/protocol/blockproposal"
 	"github.com/unicitynetwork/bft-core/network/protocol/certification"
+	"github.com/unicitynetwork/bft-core/network/protocol/replication"
 	"github.com/unicitynetwork/bft-core/partition/event"
 	testtransaction "github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
 	"github.com/unicitynetwork/bft-go-base/types"
@@ -257,6 +261,86 @@ func TestNode_HandleStaleCertificationResponse(t *testing.T) {
 	ContainsError(t, tp, "new certificate is from older root round 1 than previous certificate 2")
 }
 
+func TestNode_CertificationResponseSanityChecks(t *testing.T) {
+	// returns CertificationResponse with repeat UC of the committed IR, signed with the seal timestamp
+	certResponse := func(t *testing.T, tp *SingleNodePartition, timestamp uint64) *certification.CertificationResponse {
+		luc := tp.node.luc.Load()
+		uc, tr, err := tp.CreateUnicityCertificateTR(t, luc.InputRecord, luc.GetRootRoundNumber()+1, luc.InputRecord.Epoch)
+		require.NoError(t, err)
+		uc.UnicitySeal.Timestamp = timestamp
+		uc.UnicitySeal.Signatures = nil
+		require.NoError(t, uc.UnicitySeal.Sign(tp.rootNodeID, tp.rootSigner))
+		return &certification.CertificationResponse{Partition: tp.nodeConf.PartitionID(), Shard: tp.nodeConf.ShardID(), Technical: tr, UC: *uc}
+	}
+	oldTimestamp := types.NewTimestamp() - 3600
+
+	t.Run("anomaly is reported", func(t *testing.T) {
+		tp := runSingleValidatorNodePartition(t, &testtxsystem.CounterTxSystem{})
+		tp.WaitHandshake(t)
+		cr := certResponse(t, tp, oldTimestamp)
+		tp.mockNet.Receive(cr)
+		ContainsEventType(t, tp, event.UnicityCertificateAnomaly)
+		// UC is processed
+		require.Eventually(t, func() bool {
+			return tp.node.luc.Load().GetRootRoundNumber() == cr.UC.GetRootRoundNumber()
+		}, test.WaitDuration, test.WaitTick)
+	})
+
+	t.Run("UC is rejected", func(t *testing.T) {
+		tp := runSingleValidatorNodePartition(t, &testtxsystem.CounterTxSystem{}, WithUnicityCertificateChecks(time.Minute, true))
+		tp.WaitHandshake(t)
+		rootRound := tp.node.luc.Load().GetRootRoundNumber()
+		tp.mockNet.Receive(certResponse(t, tp, oldTimestamp))
+		ContainsError(t, tp, fmt.Sprintf("rejected UC of root round %d: timestamp %d of root round %d is before timestamp", rootRound+1, oldTimestamp, rootRound+1))
+		ContainsError(t, tp, fmt.Sprintf("timestamp %d of root round %d differs from the local time by 1h", oldTimestamp, rootRound+1))
+		require.Equal(t, rootRound, tp.node.luc.Load().GetRootRoundNumber())
+
+		// UC with the current timestamp is accepted
+		cr := certResponse(t, tp, types.NewTimestamp())
+		tp.mockNet.Receive(cr)
+		require.Eventually(t, func() bool {
+			return tp.node.luc.Load().GetRootRoundNumber() == cr.UC.GetRootRoundNumber()
+		}, test.WaitDuration, test.WaitTick)
+	})
+
+	t.Run("UC with invalid signature is not checked", func(t *testing.T) {
+		tp := runSingleValidatorNodePartition(t, &testtxsystem.CounterTxSystem{})
+		tp.WaitHandshake(t)
+		cr := certResponse(t, tp, oldTimestamp)
+		signer, _ := testsig.CreateSignerAndVerifier(t)
+		cr.UC.UnicitySeal.Signatures = nil
+		require.NoError(t, cr.UC.UnicitySeal.Sign(tp.rootNodeID, signer))
+		tp.mockNet.Receive(cr)
+		ContainsError(t, tp, "certificate invalid")
+		for _, e := range tp.eh.GetEvents() {
+			require.NotEqual(t, event.UnicityCertificateAnomaly, e.EventType)
+		}
+	})
+
+	t.Run("checks", func(t *testing.T) {
+		tp := runSingleValidatorNodePartition(t, &testtxsystem.CounterTxSystem{})
+		now := types.NewTimestamp()
+		luc := &types.UnicityCertificate{UnicitySeal: &types.UnicitySeal{RootChainRoundNumber: 10, Timestamp: now}}
+		tp.node.luc.Store(luc)
+
+		uc := &types.UnicityCertificate{UnicitySeal: &types.UnicitySeal{RootChainRoundNumber: 11, Timestamp: now}}
+		require.NoError(t, tp.node.checkUnicitySeal(context.Background(), uc))
+		uc.UnicitySeal.RootChainRoundNumber = 10
+		require.NoError(t, tp.node.checkUnicitySeal(context.Background(), uc))
+
+		uc.UnicitySeal.RootChainRoundNumber = 9
+		require.EqualError(t, tp.node.checkUnicitySeal(context.Background(), uc), "root round 9 is older than root round 10 of the latest UC")
+
+		uc.UnicitySeal.RootChainRoundNumber = 11
+		uc.UnicitySeal.Timestamp = now - 1
+		require.EqualError(t, tp.node.checkUnicitySeal(context.Background(), uc),
+			fmt.Sprintf("timestamp %d of root round 11 is before timestamp %d of root round 10 of the latest UC", now-1, now))
+
+		uc.UnicitySeal.Timestamp = now + 3600
+		require.ErrorContains(t, tp.node.checkUnicitySeal(context.Background(), uc), "differs from the local time by -")
+	})
+}
+
 func TestNode_StartNodeBehindRootchain_OK(t *testing.T) {
 	tp := runSingleValidatorNodePartition(t, &testtxsystem.CounterTxSystem{})
 	luc, found := tp.certs[tp.nodeConf.PartitionID()]
@@ -737,3 +821,128 @@ func TestNode_GetTransactionRecord_NotFound(t *testing.T) {
 	require.ErrorIs(t, err, ErrIndexNotFound)
 	require.Nil(t, proof)
 }
+
+func TestNode_BlockPushToFullNodes(t *testing.T) {
+	tp := runSingleValidatorNodePartition(t, &testtxsystem.CounterTxSystem{})
+	tp.WaitHandshake(t)
+
+	keyConf, _ := createKeyConf(t)
+	fullNodeID, err := keyConf.NodeID()
+	require.NoError(t, err)
+
+	t.Run("invalid subscription", func(t *testing.T) {
+		tp.mockNet.Receive(&replication.BlockSubscriptionRequest{
+			PartitionID: tp.nodeConf.PartitionID() + 1,
+			NodeID:      fullNodeID.String(),
+		})
+		ContainsError(t, tp, "block subscription request for wrong shard")
+
+		tp.mockNet.Receive(&replication.BlockSubscriptionRequest{
+			PartitionID: tp.nodeConf.PartitionID(),
+			NodeID:      "foo",
+		})
+		ContainsError(t, tp, `decoding peer id "foo"`)
+	})
+
+	t.Run("certified block is pushed", func(t *testing.T) {
+		tp.mockNet.Receive(&replication.BlockSubscriptionRequest{
+			PartitionID: tp.nodeConf.PartitionID(),
+			ShardID:     tp.nodeConf.ShardID(),
+			NodeID:      fullNodeID.String(),
+		})
+		require.Eventually(t, func() bool {
+			return tp.node.fullNodes.isRegistered(fullNodeID, time.Now())
+		}, test.WaitDuration, test.WaitTick)
+
+		uc := tp.GetCommittedUC(t)
+		tp.CreateBlock(t)
+		require.Eventually(t, NextBlockReceived(t, tp, uc), test.WaitDuration, test.WaitTick)
+
+		msg := WaitNodeRequestReceived(t, tp, network.ProtocolBlockPush)
+		require.Equal(t, fullNodeID, msg.ID)
+		block, ok := msg.Message.(*types.Block)
+		require.True(t, ok)
+		blockUC, err := getUCv1(block)
+		require.NoError(t, err)
+		require.Equal(t, uc.GetRoundNumber()+1, blockUC.GetRoundNumber())
+	})
+}
+
+func TestNode_FullNodeSubscribesToBlocks(t *testing.T) {
+	tp := runSingleNonValidatorNodePartition(t, &testtxsystem.CounterTxSystem{},
+		WithBlockPushParams(100*time.Millisecond, time.Second, 1, 1))
+
+	// renews the registration with the only validator
+	for range 2 {
+		msg := WaitNodeRequestReceived(t, tp, network.ProtocolBlockSubscription)
+		require.Equal(t, tp.node.Validators()[0], msg.ID)
+		req, ok := msg.Message.(*replication.BlockSubscriptionRequest)
+		require.True(t, ok)
+		require.NoError(t, req.IsValid())
+		require.Equal(t, tp.nodeID(t).String(), req.NodeID)
+	}
+
+	// full node does not serve block subscriptions
+	tp.mockNet.Receive(&replication.BlockSubscriptionRequest{
+		PartitionID: tp.nodeConf.PartitionID(),
+		NodeID:      tp.nodeID(t).String(),
+	})
+	ContainsError(t, tp, "block subscription request received by non-validator")
+}
+
+func TestNode_ReplicationPeers(t *testing.T) {
+	keyConf, _ := createKeyConf(t)
+	fullNodeID, err := keyConf.NodeID()
+	require.NoError(t, err)
+
+	t.Run("full node prefers full nodes", func(t *testing.T) {
+		tp := runSingleNonValidatorNodePartition(t, &testtxsystem.CounterTxSystem{})
+		validator := tp.node.Validators()[0]
+		tp.mockNet.SetReplicationPeers(validator, fullNodeID)
+		require.Equal(t, peer.IDSlice{fullNodeID, validator}, tp.node.replicationPeers())
+	})
+
+	t.Run("validator prefers validators", func(t *testing.T) {
+		tp := runSingleValidatorNodePartition(t, &testtxsystem.CounterTxSystem{})
+		tp.mockNet.SetReplicationPeers(fullNodeID)
+		peers := tp.node.replicationPeers()
+		require.Len(t, peers, 3)
+		require.ElementsMatch(t, tp.node.Validators(), peers[:2])
+		require.Equal(t, fullNodeID, peers[2])
+	})
+}
+
+func TestNode_Health(t *testing.T) {
+	tp := runSingleValidatorNodePartition(t, &testtxsystem.CounterTxSystem{})
+	tp.WaitHandshake(t)
+	uc := tp.GetCommittedUC(t)
+	tp.CreateBlock(t)
+	require.Eventually(t, NextBlockReceived(t, tp, uc), test.WaitDuration, test.WaitTick)
+
+	info := tp.node.Health()
+	require.Equal(t, "normal", info.Status)
+	require.True(t, info.IsValidator)
+	require.Equal(t, uc.GetRoundNumber()+1, info.CommittedRound)
+	require.Equal(t, info.CommittedRound, info.LatestRound)
+	require.WithinDuration(t, time.Now(), info.LatestUCTime, 2*time.Second)
+	require.Equal(t, 2, info.ValidatorCount)
+
+	t.Run("concurrent with the node", func(t *testing.T) {
+		// run with -race: Health is called by the HTTP server while the node changes its state
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for ctx.Err() == nil {
+				require.NotNil(t, tp.node.Health())
+			}
+		}()
+		for range 3 {
+			uc := tp.GetCommittedUC(t)
+			tp.CreateBlock(t)
+			require.Eventually(t, NextBlockReceived(t, tp, uc), test.WaitDuration, test.WaitTick)
+		}
+		cancel()
+		<-done
+	})
+}
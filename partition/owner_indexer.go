@@ -32,6 +32,7 @@ type (
 
 	IndexReader interface {
 		GetOwnerUnits(ownerID []byte, sinceUnitID *types.UnitID, limit int) ([]types.UnitID, error)
+		GetOwnerUnitsInState(s txsystem.StateReader, ownerID []byte, sinceUnitID *types.UnitID, limit int) ([]types.UnitID, error)
 	}
 
 	StateProvider interface {
@@ -62,6 +63,32 @@ func (o *OwnerIndexer) GetOwnerUnits(ownerID []byte, sinceUnitID *types.UnitID,
 	return slices.Clone(units[startIndex:endIndex]), nil
 }
 
+/*
+GetOwnerUnitsInState returns unit ids of the owner in the given state (ie state of
+some earlier round). The index is not used, the whole state tree is traversed.
+*/
+func (o *OwnerIndexer) GetOwnerUnitsInState(s txsystem.StateReader, ownerID []byte, sinceUnitID *types.UnitID, limit int) ([]types.UnitID, error) {
+	owner := string(ownerID)
+	index, err := s.CreateIndex(func(unit state.Unit) (string, error) {
+		if unit.Data() == nil {
+			return "", nil // dummy unit
+		}
+		if id, err := o.extractOwnerID(unit); err != nil || id != owner {
+			return "", err
+		}
+		return owner, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ownerID index: %w", err)
+	}
+	units := index[owner]
+	startIndex := startIndex(sinceUnitID, units)
+	if startIndex >= len(units) {
+		return []types.UnitID{}, nil
+	}
+	return units[startIndex:endIndex(startIndex, limit, units)], nil
+}
+
 func startIndex(sinceUnitID *types.UnitID, ownerUnitIDs []types.UnitID) int {
 	if sinceUnitID == nil {
 		return 0
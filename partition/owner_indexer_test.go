@@ -155,6 +155,49 @@ func TestOwnerIndexer(t *testing.T) {
 		ownerUnitIDs := ownerIndexer.ownerUnits[string(ownerPredicate)]
 		require.Len(t, ownerUnitIDs, 0)
 	})
+	t.Run("owner units in the state of earlier round", func(t *testing.T) {
+		ownerIndexer := NewOwnerIndexer(testlogger.New(t))
+		unitID1, unitID2, dummyID := types.UnitID{1}, types.UnitID{2}, types.UnitID{3}
+		owner1, owner2 := []byte{1}, []byte{2}
+
+		s := state.NewEmptyState(state.WithArchive(1))
+		require.NoError(t, s.Apply(
+			state.AddUnit(unitID1, &mockUnitData{ownerPredicate: templates.NewP2pkh256BytesFromKeyHash(owner1)}),
+			state.AddUnit(unitID2, &mockUnitData{ownerPredicate: templates.NewP2pkh256BytesFromKeyHash(owner1)}),
+			state.AddDummyUnit(dummyID),
+		))
+		require.NoError(t, s.AddUnitLog(unitID1, test.RandomBytes(4)))
+		require.NoError(t, s.AddUnitLog(unitID2, test.RandomBytes(4)))
+		require.NoError(t, s.AddUnitLog(dummyID, test.RandomBytes(4)))
+		commitState(t, s)
+		// unit2 is transferred to owner2 in round 2
+		require.NoError(t, s.Apply(state.UpdateUnitData(unitID2, func(data types.UnitData) (types.UnitData, error) {
+			return &mockUnitData{ownerPredicate: templates.NewP2pkh256BytesFromKeyHash(owner2)}, nil
+		})))
+		require.NoError(t, s.AddUnitLog(unitID2, test.RandomBytes(4)))
+		commitState(t, s)
+
+		round1, err := s.AtRound(1)
+		require.NoError(t, err)
+		ownerUnitIDs, err := ownerIndexer.GetOwnerUnitsInState(round1, owner1, nil, 0)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{unitID1, unitID2}, ownerUnitIDs)
+		ownerUnitIDs, err = ownerIndexer.GetOwnerUnitsInState(round1, owner1, &unitID1, 0)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{unitID2}, ownerUnitIDs)
+		ownerUnitIDs, err = ownerIndexer.GetOwnerUnitsInState(round1, owner2, nil, 0)
+		require.NoError(t, err)
+		require.Empty(t, ownerUnitIDs)
+
+		round2, err := s.AtRound(2)
+		require.NoError(t, err)
+		ownerUnitIDs, err = ownerIndexer.GetOwnerUnitsInState(round2, owner1, nil, 1)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{unitID1}, ownerUnitIDs)
+		ownerUnitIDs, err = ownerIndexer.GetOwnerUnitsInState(round2, owner2, nil, 0)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{unitID2}, ownerUnitIDs)
+	})
 	t.Run("non-p2pkh predicate is not indexed", func(t *testing.T) {
 		ownerIndexer := NewOwnerIndexer(testlogger.New(t))
 		unitID := types.UnitID{1}
@@ -216,6 +259,49 @@ func TestOwnerIndexer(t *testing.T) {
 		ownerUnitIDs := ownerIndexer.ownerUnits[string(ownerPredicate)]
 		require.Len(t, ownerUnitIDs, 0)
 	})
+	t.Run("owner units in the state of earlier round", func(t *testing.T) {
+		ownerIndexer := NewOwnerIndexer(testlogger.New(t))
+		unitID1, unitID2, dummyID := types.UnitID{1}, types.UnitID{2}, types.UnitID{3}
+		owner1, owner2 := []byte{1}, []byte{2}
+
+		s := state.NewEmptyState(state.WithArchive(1))
+		require.NoError(t, s.Apply(
+			state.AddUnit(unitID1, &mockUnitData{ownerPredicate: templates.NewP2pkh256BytesFromKeyHash(owner1)}),
+			state.AddUnit(unitID2, &mockUnitData{ownerPredicate: templates.NewP2pkh256BytesFromKeyHash(owner1)}),
+			state.AddDummyUnit(dummyID),
+		))
+		require.NoError(t, s.AddUnitLog(unitID1, test.RandomBytes(4)))
+		require.NoError(t, s.AddUnitLog(unitID2, test.RandomBytes(4)))
+		require.NoError(t, s.AddUnitLog(dummyID, test.RandomBytes(4)))
+		commitState(t, s)
+		// unit2 is transferred to owner2 in round 2
+		require.NoError(t, s.Apply(state.UpdateUnitData(unitID2, func(data types.UnitData) (types.UnitData, error) {
+			return &mockUnitData{ownerPredicate: templates.NewP2pkh256BytesFromKeyHash(owner2)}, nil
+		})))
+		require.NoError(t, s.AddUnitLog(unitID2, test.RandomBytes(4)))
+		commitState(t, s)
+
+		round1, err := s.AtRound(1)
+		require.NoError(t, err)
+		ownerUnitIDs, err := ownerIndexer.GetOwnerUnitsInState(round1, owner1, nil, 0)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{unitID1, unitID2}, ownerUnitIDs)
+		ownerUnitIDs, err = ownerIndexer.GetOwnerUnitsInState(round1, owner1, &unitID1, 0)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{unitID2}, ownerUnitIDs)
+		ownerUnitIDs, err = ownerIndexer.GetOwnerUnitsInState(round1, owner2, nil, 0)
+		require.NoError(t, err)
+		require.Empty(t, ownerUnitIDs)
+
+		round2, err := s.AtRound(2)
+		require.NoError(t, err)
+		ownerUnitIDs, err = ownerIndexer.GetOwnerUnitsInState(round2, owner1, nil, 1)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{unitID1}, ownerUnitIDs)
+		ownerUnitIDs, err = ownerIndexer.GetOwnerUnitsInState(round2, owner2, nil, 0)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{unitID2}, ownerUnitIDs)
+	})
 
 }
 
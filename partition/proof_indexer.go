@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"log/slog"
 
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/unicitynetwork/bft-core/keyvaluedb"
 	"github.com/unicitynetwork/bft-core/logger"
+	"github.com/unicitynetwork/bft-core/observability"
 	"github.com/unicitynetwork/bft-core/state"
 	"github.com/unicitynetwork/bft-go-base/types"
 	"github.com/unicitynetwork/bft-go-base/util"
@@ -52,11 +54,12 @@ type (
 		blockCh       chan *BlockAndState
 		log           *slog.Logger
 		tracer        trace.Tracer
+		invalidProofs metric.Int64Counter // nil when the counter could not be created
 	}
 )
 
 func NewProofIndexer(algo crypto.Hash, db keyvaluedb.KeyValueDB, historySize uint64, obs Observability) *ProofIndexer {
-	return &ProofIndexer{
+	p := &ProofIndexer{
 		hashAlgorithm: algo,
 		storage:       db,
 		historySize:   historySize,
@@ -64,6 +67,17 @@ func NewProofIndexer(algo crypto.Hash, db keyvaluedb.KeyValueDB, historySize uin
 		log:           obs.Logger(),
 		tracer:        obs.Tracer("proof-indexer"),
 	}
+	var err error
+	p.invalidProofs, err = obs.Meter("partition.proof_indexer").Int64Counter(
+		"unit_proof.invalid",
+		metric.WithDescription("Number of generated unit state proofs which failed verification"),
+		metric.WithUnit("{proof}"),
+	)
+	if err != nil {
+		p.log.Error("creating invalid unit proof counter", logger.Error(err))
+		p.invalidProofs = nil
+	}
+	return p
 }
 
 func (p *ProofIndexer) IndexBlock(ctx context.Context, block *types.Block, roundNumber uint64, state UnitAndProof) error {
@@ -183,13 +197,18 @@ func (p *ProofIndexer) create(ctx context.Context, block *types.Block, roundNumb
 					continue
 				}
 				unitStateProof, e := stateReader.CreateUnitStateProof(unitID, j)
+				// the proof of the unit is not indexed but the rest of the block is
 				if e != nil {
-					err = errors.Join(err, fmt.Errorf("unit %X proof creation failed: %w", unitID, e))
+					if proofErr := (*state.UnitStateProofError)(nil); errors.As(e, &proofErr) {
+						p.invalidUnitProof(ctx, block, proofErr)
+					} else {
+						p.log.WarnContext(ctx, "unit proof creation failed", logger.Error(e), logger.UnitID(unitID))
+					}
 					continue
 				}
 				unitState, e := unitLog.UnitState()
 				if e != nil {
-					err = errors.Join(err, fmt.Errorf("unit %X data encode failed: %w", unitID, e))
+					p.log.WarnContext(ctx, "unit data encode failed", logger.Error(e), logger.UnitID(unitID))
 					continue
 				}
 				key := bytes.Join([][]byte{unitID, txoHash}, nil)
@@ -217,6 +236,17 @@ func (p *ProofIndexer) create(ctx context.Context, block *types.Block, roundNumb
 	return nil
 }
 
+/*
+invalidUnitProof records unit state proof which failed self-verification, it
+means that the state of the node is corrupted so it's logged as error.
+*/
+func (p *ProofIndexer) invalidUnitProof(ctx context.Context, block *types.Block, proofErr *state.UnitStateProofError) {
+	p.log.ErrorContext(ctx, "generated unit state proof is invalid", logger.Error(proofErr), logger.UnitID(proofErr.UnitID))
+	if p.invalidProofs != nil {
+		p.invalidProofs.Add(ctx, 1, observability.Shard(block.Header.PartitionID, block.Header.ShardID))
+	}
+}
+
 func (p *ProofIndexer) latestIndexedBlockNumber() uint64 {
 	var blockNr uint64
 	if found, err := p.storage.Read(keyLatestRoundNumber, &blockNr); !found || err != nil {
@@ -17,6 +17,8 @@ import (
 	testtxsystem "github.com/unicitynetwork/bft-core/internal/testutils/txsystem"
 	"github.com/unicitynetwork/bft-core/keyvaluedb/boltdb"
 	"github.com/unicitynetwork/bft-core/keyvaluedb/memorydb"
+	"github.com/unicitynetwork/bft-core/state"
+	"github.com/unicitynetwork/bft-go-base/txsystem/money"
 	"github.com/unicitynetwork/bft-go-base/types"
 	"github.com/unicitynetwork/bft-go-base/util"
 )
@@ -160,6 +162,34 @@ func TestNewProofIndexer_SimulateWriteError(t *testing.T) {
 	require.Error(t, indexer.IndexBlock(ctx, blockRound2.Block, 2, blockRound2.State))
 }
 
+func TestNewProofIndexer_InvalidUnitProof(t *testing.T) {
+	proofDB, err := memorydb.New()
+	require.NoError(t, err)
+	indexer := NewProofIndexer(crypto.SHA256, proofDB, 2, observability.Default(t))
+	unitID := make([]byte, 32)
+	input := simulateInput(1, unitID)
+	txrHash, err := input.Block.Transactions[0].Hash(crypto.SHA256)
+	require.NoError(t, err)
+	unit := state.NewUnit(&money.BillData{Version: 1, Value: 10})
+	require.NoError(t, unit.AddUnitLog(crypto.SHA256, txrHash))
+	proofErr := &state.UnitStateProofError{UnitID: unitID, Err: errors.New("corrupted state")}
+
+	// invalid proof is logged and counted, the rest of the block is indexed
+	require.NoError(t, indexer.IndexBlock(context.Background(), input.Block, 1, &invalidProofState{unit: unit, err: proofErr}))
+	require.EqualValues(t, 1, indexer.latestIndexedBlockNumber())
+	txo, err := input.Block.Transactions[0].GetTransactionOrderV1()
+	require.NoError(t, err)
+	txoHash, err := txo.Hash(crypto.SHA256)
+	require.NoError(t, err)
+	idx, err := ReadTransactionIndex(proofDB, txoHash)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, idx.RoundNumber)
+	var unitProof *types.UnitStateWithProof
+	found, err := proofDB.Read(bytes.Join([][]byte{unitID, txoHash}, nil), &unitProof)
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
 func TestNewProofIndexer_RunLoop(t *testing.T) {
 	t.Run("run loop - no history clean-up", func(t *testing.T) {
 		proofDB, err := memorydb.New()
@@ -287,6 +317,20 @@ func TestProofIndexer_BoltDBTx(t *testing.T) {
 	require.False(t, dbIt.Valid())
 }
 
+// invalidProofState returns the unit and fails to create its state proof
+type invalidProofState struct {
+	unit state.Unit
+	err  error
+}
+
+func (s *invalidProofState) GetUnit(types.UnitID, bool) (state.Unit, error) {
+	return s.unit, nil
+}
+
+func (s *invalidProofState) CreateUnitStateProof(types.UnitID, int) (*types.UnitStateProof, error) {
+	return nil, s.err
+}
+
 func simulateInput(round uint64, unitID []byte) *BlockAndState {
 	uc, _ := (&types.UnicityCertificate{
 		Version:     1,
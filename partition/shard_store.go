@@ -87,6 +87,8 @@ func (s *shardStore) LoadEpoch(epoch uint64) error {
 }
 
 func (s *shardStore) LoadedEpoch() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.epoch
 }
 
@@ -111,6 +113,13 @@ func (s *shardStore) IsValidator(peerID peer.ID) bool {
 	return s.epochValidators[peerID] != nil
 }
 
+// ValidatorStatus returns whether the peer is a validator and the number of validators of the loaded epoch
+func (s *shardStore) ValidatorStatus(peerID peer.ID) (isValidator bool, validatorCount int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.epochValidators[peerID] != nil, len(s.epochValidators)
+}
+
 func (s *shardStore) Verifier(validator peer.ID) crypto.Verifier {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
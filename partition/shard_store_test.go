@@ -51,6 +51,9 @@ func TestVerifier_Ok(t *testing.T) {
 	require.EqualValues(t, 1, ss.LoadedEpoch())
 	require.Equal(t, 2, len(ss.Validators()))
 	require.True(t, ss.IsValidator(nodeID))
+	isValidator, validatorCount := ss.ValidatorStatus(nodeID)
+	require.True(t, isValidator)
+	require.Equal(t, 2, validatorCount)
 	require.True(t, slices.Contains(ss.Validators(), ss.RandomValidator()))
 
 	shardConf2 := createShardConfWithRemovedNode(t, shardConf1, 0)
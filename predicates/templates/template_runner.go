@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -22,6 +23,13 @@ const (
 	P2PKHGasCost       = 1000
 	AlwaysTrueGasCost  = 100
 	AlwaysFalseGasCost = 100
+	// HashLockGasCost is the cost of checking the hash-lock preimage, when it
+	// matches P2PKHGasCost is charged for verifying the signature.
+	HashLockGasCost = 200
+	TimeLockGasCost = 100
+	// MultiSigGasCost is the base cost of the multisig predicate, in addition
+	// to it P2PKHGasCost is charged for every signature verified.
+	MultiSigGasCost = 100
 )
 
 var cborNull = []byte{0xf6}
@@ -68,6 +76,12 @@ func (tr TemplateRunner) Execute(ctx context.Context, p *sdkpredicates.Predicate
 		return executeAlwaysTrue(p.Params, args, env)
 	case templates.AlwaysFalseID:
 		return executeAlwaysFalse(p.Params, args, env)
+	case templates.HashLockID:
+		return executeHashLock(p.Params, args, env)
+	case templates.TimeLockID:
+		return executeTimeLock(p.Params, args, env)
+	case templates.MultiSigID:
+		return executeMultiSig(p.Params, args, env)
 	default:
 		return false, fmt.Errorf("unknown predicate template with id %d", p.Code[0])
 	}
@@ -112,21 +126,29 @@ func executeP2PKH256(pubKeyHash, args []byte, env predicates.TxContext) (bool, e
 	if len(pubKeyHash) != 32 {
 		return false, fmt.Errorf("invalid pubkey hash size: expected 32, got %d (%X)", len(pubKeyHash), pubKeyHash)
 	}
-	if len(p2pkh256Signature.Sig) != 65 {
-		return false, fmt.Errorf("invalid signature size: expected 65, got %d (%X)", len(p2pkh256Signature.Sig), p2pkh256Signature.Sig)
+	return verifyP2PKH256Signature(pubKeyHash, &p2pkh256Signature, sigBytes)
+}
+
+/*
+verifyP2PKH256Signature returns "true" when the sig has been created over sigBytes
+by the owner of the pubKeyHash.
+*/
+func verifyP2PKH256Signature(pubKeyHash []byte, sig *templates.P2pkh256Signature, sigBytes []byte) (bool, error) {
+	if len(sig.Sig) != 65 {
+		return false, fmt.Errorf("invalid signature size: expected 65, got %d (%X)", len(sig.Sig), sig.Sig)
 	}
-	if len(p2pkh256Signature.PubKey) != 33 {
-		return false, fmt.Errorf("invalid pubkey size: expected 33, got %d (%X)", len(p2pkh256Signature.PubKey), p2pkh256Signature.PubKey)
+	if len(sig.PubKey) != 33 {
+		return false, fmt.Errorf("invalid pubkey size: expected 33, got %d (%X)", len(sig.PubKey), sig.PubKey)
 	}
-	if pkh := sha256.Sum256(p2pkh256Signature.PubKey); !bytes.Equal(pubKeyHash, pkh[:]) {
+	if pkh := sha256.Sum256(sig.PubKey); !bytes.Equal(pubKeyHash, pkh[:]) {
 		return false, nil
 	}
 
-	verifier, err := crypto.NewVerifierSecp256k1(p2pkh256Signature.PubKey)
+	verifier, err := crypto.NewVerifierSecp256k1(sig.PubKey)
 	if err != nil {
 		return false, fmt.Errorf("failed to create verifier: %w", err)
 	}
-	if err = verifier.VerifyBytes(p2pkh256Signature.Sig, sigBytes); err != nil {
+	if err = verifier.VerifyBytes(sig.Sig, sigBytes); err != nil {
 		if errors.Is(err, crypto.ErrVerificationFailed) {
 			return false, nil
 		}
@@ -134,3 +156,107 @@ func executeP2PKH256(pubKeyHash, args []byte, env predicates.TxContext) (bool, e
 	}
 	return true, nil
 }
+
+func executeHashLock(params, args []byte, env predicates.TxContext) (bool, error) {
+	if err := env.SpendGas(HashLockGasCost); err != nil {
+		return false, err
+	}
+	hashLock := HashLock{}
+	if err := types.Cbor.Unmarshal(params, &hashLock); err != nil {
+		return false, fmt.Errorf("failed to decode hash-lock parameters: %w", err)
+	}
+	if len(hashLock.Hash) != sha256.Size {
+		return false, fmt.Errorf("invalid hash size: expected %d, got %d (%X)", sha256.Size, len(hashLock.Hash), hashLock.Hash)
+	}
+	if len(hashLock.PubKeyHash) != sha256.Size {
+		return false, fmt.Errorf("invalid pubkey hash size: expected %d, got %d (%X)", sha256.Size, len(hashLock.PubKeyHash), hashLock.PubKeyHash)
+	}
+	proof := HashLockProof{}
+	if err := types.Cbor.Unmarshal(args, &proof); err != nil {
+		return false, fmt.Errorf("failed to decode hash-lock proof: %w", err)
+	}
+	if proof.Signature == nil {
+		return false, errors.New("hash-lock proof signature is nil")
+	}
+	if h := sha256.Sum256(proof.Secret); !bytes.Equal(h[:], hashLock.Hash) {
+		return false, nil
+	}
+
+	// the preimage alone is not enough, it is public once the tx is in the mempool
+	if err := env.SpendGas(P2PKHGasCost); err != nil {
+		return false, err
+	}
+	sigBytes, err := env.ExtraArgument()
+	if err != nil {
+		return false, fmt.Errorf("reading tx signature bytes: %w", err)
+	}
+	return verifyP2PKH256Signature(hashLock.PubKeyHash, proof.Signature, sigBytes)
+}
+
+func executeTimeLock(params, args []byte, env predicates.TxContext) (bool, error) {
+	if err := env.SpendGas(TimeLockGasCost); err != nil {
+		return false, err
+	}
+	timeLock := TimeLock{}
+	if err := types.Cbor.Unmarshal(params, &timeLock); err != nil {
+		return false, fmt.Errorf("failed to decode time-lock parameters: %w", err)
+	}
+	// do not allow to piggyback any additional data on "time-lock" predicate
+	if len(args) != 0 && !bytes.Equal(args, cborNull) {
+		return false, fmt.Errorf(`"time-lock" predicate arguments must be empty`)
+	}
+	return env.CurrentRound() >= timeLock.Round, nil
+}
+
+func executeMultiSig(params, args []byte, env predicates.TxContext) (bool, error) {
+	if err := env.SpendGas(MultiSigGasCost); err != nil {
+		return false, err
+	}
+	multiSig := MultiSig{}
+	if err := types.Cbor.Unmarshal(params, &multiSig); err != nil {
+		return false, fmt.Errorf("failed to decode multisig parameters: %w", err)
+	}
+	if err := multiSig.IsValid(); err != nil {
+		return false, fmt.Errorf("invalid multisig parameters: %w", err)
+	}
+	proof := MultiSigProof{}
+	if err := types.Cbor.Unmarshal(args, &proof); err != nil {
+		return false, fmt.Errorf("failed to decode multisig proof: %w", err)
+	}
+	if len(proof.Signatures) > len(multiSig.PubKeyHashes) {
+		return false, fmt.Errorf("multisig proof contains %d signatures, predicate has %d keys", len(proof.Signatures), len(multiSig.PubKeyHashes))
+	}
+	sigBytes, err := env.ExtraArgument()
+	if err != nil {
+		return false, fmt.Errorf("reading tx signature bytes: %w", err)
+	}
+
+	signed := make([]bool, len(multiSig.PubKeyHashes))
+	var count uint64
+	for i, sig := range proof.Signatures {
+		if sig == nil {
+			return false, fmt.Errorf("multisig proof signature %d is nil", i)
+		}
+		if len(sig.PubKey) != 33 {
+			return false, fmt.Errorf("invalid pubkey size: expected 33, got %d (%X)", len(sig.PubKey), sig.PubKey)
+		}
+		pkh := sha256.Sum256(sig.PubKey)
+		idx := slices.IndexFunc(multiSig.PubKeyHashes, func(h []byte) bool { return bytes.Equal(h, pkh[:]) })
+		if idx == -1 || signed[idx] {
+			// signer is not one of the owners or has signed already
+			continue
+		}
+		if err := env.SpendGas(P2PKHGasCost); err != nil {
+			return false, err
+		}
+		ok, err := verifyP2PKH256Signature(multiSig.PubKeyHashes[idx], sig, sigBytes)
+		if err != nil {
+			return false, fmt.Errorf("verifying signature %d: %w", i, err)
+		}
+		if ok {
+			signed[idx] = true
+			count++
+		}
+	}
+	return count >= multiSig.Threshold, nil
+}
@@ -340,6 +340,283 @@ func TestP2pkh256_Execute(t *testing.T) {
 	})
 }
 
+func TestHashLock_Execute(t *testing.T) {
+	t.Parallel()
+
+	runner, err := New(observability.Default(t))
+	require.NoError(t, err)
+
+	sigBytes := []byte("tx signature bytes")
+	signer, err := crypto.NewInMemorySecp256K1Signer()
+	require.NoError(t, err)
+	verifier, err := signer.Verifier()
+	require.NoError(t, err)
+	pubKey, err := verifier.MarshalPublicKey()
+	require.NoError(t, err)
+	pkh := sha256.Sum256(pubKey)
+
+	secret := []byte("secret")
+	secretHash := sha256.Sum256(secret)
+	predicate := NewHashLock(secretHash[:], pkh[:])
+	proof := func(t *testing.T, secret []byte, signer crypto.Signer) []byte {
+		b, err := NewHashLockProofBytes(secret, testsig.NewP2pkhSignature(t, signer, sigBytes))
+		require.NoError(t, err)
+		return b
+	}
+	var gasSpent uint64
+	env := &mockTxContext{
+		spendGas:   func(gas uint64) error { gasSpent += gas; return nil },
+		exArgument: func() ([]byte, error) { return sigBytes, nil },
+	}
+
+	t.Run("success", func(t *testing.T) {
+		gasSpent = 0
+		res, err := runner.Execute(context.Background(), &predicate, proof(t, secret, signer), nil, env)
+		require.NoError(t, err)
+		require.True(t, res)
+		require.EqualValues(t, HashLockGasCost+P2PKHGasCost, gasSpent)
+	})
+
+	t.Run("wrong preimage", func(t *testing.T) {
+		gasSpent = 0
+		res, err := runner.Execute(context.Background(), &predicate, proof(t, []byte("other secret"), signer), nil, env)
+		require.NoError(t, err)
+		require.False(t, res)
+		require.EqualValues(t, HashLockGasCost, gasSpent)
+	})
+
+	t.Run("preimage without owner's signature", func(t *testing.T) {
+		// revealed preimage can't be used by anyone else
+		other, err := crypto.NewInMemorySecp256K1Signer()
+		require.NoError(t, err)
+		res, err := runner.Execute(context.Background(), &predicate, proof(t, secret, other), nil, env)
+		require.NoError(t, err)
+		require.False(t, res)
+
+		// signature must be over the tx
+		env := &mockTxContext{
+			spendGas:   func(gas uint64) error { return nil },
+			exArgument: func() ([]byte, error) { return []byte("other tx"), nil },
+		}
+		res, err = runner.Execute(context.Background(), &predicate, proof(t, secret, signer), nil, env)
+		require.NoError(t, err)
+		require.False(t, res)
+
+		b, err := types.Cbor.Marshal(HashLockProof{Secret: secret})
+		require.NoError(t, err)
+		res, err = runner.Execute(context.Background(), &predicate, b, nil, env)
+		require.EqualError(t, err, `hash-lock proof signature is nil`)
+		require.False(t, res)
+	})
+
+	t.Run("invalid proof encoding", func(t *testing.T) {
+		res, err := runner.Execute(context.Background(), &predicate, nil, nil, env)
+		require.EqualError(t, err, `failed to decode hash-lock proof: EOF`)
+		require.False(t, res)
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		p := NewHashLock([]byte{1, 2, 3}, pkh[:])
+		res, err := runner.Execute(context.Background(), &p, proof(t, secret, signer), nil, env)
+		require.EqualError(t, err, `invalid hash size: expected 32, got 3 (010203)`)
+		require.False(t, res)
+
+		p = NewHashLock(secretHash[:], nil)
+		res, err = runner.Execute(context.Background(), &p, proof(t, secret, signer), nil, env)
+		require.EqualError(t, err, `invalid pubkey hash size: expected 32, got 0 ()`)
+		require.False(t, res)
+	})
+
+	t.Run("out of gas", func(t *testing.T) {
+		env := &mockTxContext{spendGas: func(gas uint64) error { return types.ErrOutOfGas }}
+		res, err := runner.Execute(context.Background(), &predicate, proof(t, secret, signer), nil, env)
+		require.ErrorIs(t, err, types.ErrOutOfGas)
+		require.False(t, res)
+
+		// enough gas for the preimage check only
+		env = &mockTxContext{spendGas: func(gas uint64) error {
+			if gas > HashLockGasCost {
+				return types.ErrOutOfGas
+			}
+			return nil
+		}}
+		res, err = runner.Execute(context.Background(), &predicate, proof(t, secret, signer), nil, env)
+		require.ErrorIs(t, err, types.ErrOutOfGas)
+		require.False(t, res)
+	})
+}
+
+func TestTimeLock_Execute(t *testing.T) {
+	t.Parallel()
+
+	runner, err := New(observability.Default(t))
+	require.NoError(t, err)
+
+	predicate := NewTimeLock(10)
+	env := &mockTxContext{
+		spendGas: func(gas uint64) error { return nil },
+	}
+
+	for round, expected := range map[uint64]bool{0: false, 9: false, 10: true, 11: true} {
+		env := *env
+		env.currentRound = round
+		res, err := runner.Execute(context.Background(), &predicate, nil, nil, &env)
+		require.NoError(t, err)
+		require.Equal(t, expected, res, "round %d", round)
+	}
+
+	t.Run("arguments must be empty", func(t *testing.T) {
+		env := *env
+		env.currentRound = 10
+		res, err := runner.Execute(context.Background(), &predicate, []byte{1}, nil, &env)
+		require.EqualError(t, err, `"time-lock" predicate arguments must be empty`)
+		require.False(t, res)
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		p := sdkpredicates.Predicate{Tag: templates.TemplateStartByte, Code: []byte{templates.TimeLockID}}
+		res, err := runner.Execute(context.Background(), &p, nil, nil, env)
+		require.EqualError(t, err, `failed to decode time-lock parameters: EOF`)
+		require.False(t, res)
+	})
+}
+
+func TestMultiSig_Execute(t *testing.T) {
+	t.Parallel()
+
+	runner, err := New(observability.Default(t))
+	require.NoError(t, err)
+
+	txo := &types.TransactionOrder{
+		Version: 1,
+		Payload: types.Payload{
+			PartitionID: 1,
+			Type:        22,
+			UnitID:      []byte{0, 0, 1, 1, 2, 2},
+		},
+	}
+	require.NoError(t, txo.SetAttributes("not really attributes"))
+
+	var signers []crypto.Signer
+	var pubKeyHashes [][]byte
+	for range 3 {
+		signer, err := crypto.NewInMemorySecp256K1Signer()
+		require.NoError(t, err)
+		verifier, err := signer.Verifier()
+		require.NoError(t, err)
+		pubKey, err := verifier.MarshalPublicKey()
+		require.NoError(t, err)
+		h := sha256.Sum256(pubKey)
+		signers = append(signers, signer)
+		pubKeyHashes = append(pubKeyHashes, h[:])
+	}
+	proof := func(t *testing.T, signers ...crypto.Signer) []byte {
+		sigs := make([][]byte, len(signers))
+		for i, s := range signers {
+			sigs[i] = testsig.NewAuthProofSignature(t, txo, s)
+		}
+		b, err := NewMultiSigProofBytes(sigs...)
+		require.NoError(t, err)
+		return b
+	}
+
+	predicate, err := NewMultiSig(2, pubKeyHashes...)
+	require.NoError(t, err)
+	var gasSpent uint64
+	env := &mockTxContext{
+		spendGas:   func(gas uint64) error { gasSpent += gas; return nil },
+		exArgument: txo.AuthProofSigBytes,
+	}
+
+	t.Run("threshold reached", func(t *testing.T) {
+		gasSpent = 0
+		res, err := runner.Execute(context.Background(), &predicate, proof(t, signers[0], signers[2]), txo, env)
+		require.NoError(t, err)
+		require.True(t, res)
+		require.EqualValues(t, MultiSigGasCost+2*P2PKHGasCost, gasSpent)
+
+		res, err = runner.Execute(context.Background(), &predicate, proof(t, signers...), txo, env)
+		require.NoError(t, err)
+		require.True(t, res)
+	})
+
+	t.Run("threshold not reached", func(t *testing.T) {
+		res, err := runner.Execute(context.Background(), &predicate, proof(t, signers[1]), txo, env)
+		require.NoError(t, err)
+		require.False(t, res)
+
+		// the same key signing twice counts once
+		res, err = runner.Execute(context.Background(), &predicate, proof(t, signers[1], signers[1]), txo, env)
+		require.NoError(t, err)
+		require.False(t, res)
+
+		// signature of a key which is not one of the owners
+		other, err := crypto.NewInMemorySecp256K1Signer()
+		require.NoError(t, err)
+		res, err = runner.Execute(context.Background(), &predicate, proof(t, signers[1], other), txo, env)
+		require.NoError(t, err)
+		require.False(t, res)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		p := proof(t, signers[0], signers[1])
+		env := &mockTxContext{
+			spendGas:   func(gas uint64) error { return nil },
+			exArgument: func() ([]byte, error) { return []byte("other data"), nil },
+		}
+		res, err := runner.Execute(context.Background(), &predicate, p, txo, env)
+		require.NoError(t, err)
+		require.False(t, res)
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		for _, threshold := range []uint64{0, 4} {
+			_, err := NewMultiSig(threshold, pubKeyHashes...)
+			require.EqualError(t, err, fmt.Sprintf("invalid multisig threshold %d for 3 keys", threshold))
+
+			params, err := types.Cbor.Marshal(MultiSig{Threshold: threshold, PubKeyHashes: pubKeyHashes})
+			require.NoError(t, err)
+			p := sdkpredicates.Predicate{Tag: templates.TemplateStartByte, Code: []byte{templates.MultiSigID}, Params: params}
+			res, err := runner.Execute(context.Background(), &p, proof(t, signers...), txo, env)
+			require.EqualError(t, err, fmt.Sprintf("invalid multisig parameters: invalid multisig threshold %d for 3 keys", threshold))
+			require.False(t, res)
+		}
+
+		// duplicate key would make the predicate impossible to satisfy
+		_, err := NewMultiSigBytes(2, pubKeyHashes[0], pubKeyHashes[1], pubKeyHashes[0])
+		require.EqualError(t, err, fmt.Sprintf("duplicate pubkey hash %X", pubKeyHashes[0]))
+		params, err := types.Cbor.Marshal(MultiSig{Threshold: 3, PubKeyHashes: [][]byte{pubKeyHashes[0], pubKeyHashes[1], pubKeyHashes[0]}})
+		require.NoError(t, err)
+		p := sdkpredicates.Predicate{Tag: templates.TemplateStartByte, Code: []byte{templates.MultiSigID}, Params: params}
+		res, err := runner.Execute(context.Background(), &p, proof(t, signers...), txo, env)
+		require.EqualError(t, err, fmt.Sprintf("invalid multisig parameters: duplicate pubkey hash %X", pubKeyHashes[0]))
+		require.False(t, res)
+
+		_, err = NewMultiSig(1, []byte{1, 2, 3})
+		require.EqualError(t, err, `invalid pubkey hash size: expected 32, got 3 (010203)`)
+	})
+
+	t.Run("invalid proof", func(t *testing.T) {
+		res, err := runner.Execute(context.Background(), &predicate, nil, txo, env)
+		require.EqualError(t, err, `failed to decode multisig proof: EOF`)
+		require.False(t, res)
+
+		res, err = runner.Execute(context.Background(), &predicate, proof(t, signers[0], signers[1], signers[2], signers[0]), txo, env)
+		require.EqualError(t, err, `multisig proof contains 4 signatures, predicate has 3 keys`)
+		require.False(t, res)
+	})
+
+	t.Run("out of gas", func(t *testing.T) {
+		env := &mockTxContext{
+			spendGas:   func(gas uint64) error { return types.ErrOutOfGas },
+			exArgument: txo.AuthProofSigBytes,
+		}
+		res, err := runner.Execute(context.Background(), &predicate, proof(t, signers...), txo, env)
+		require.ErrorIs(t, err, types.ErrOutOfGas)
+		require.False(t, res)
+	})
+}
+
 func Benchmark_templateExecute(b *testing.B) {
 	b.Run("p2pkh", func(b *testing.B) {
 		// random 42 bytes
@@ -407,6 +684,7 @@ type mockTxContext struct {
 	committedUC  func() *types.UnicityCertificate
 	spendGas     func(gas uint64) error
 	exArgument   func() ([]byte, error)
+	currentRound uint64
 }
 
 func (env *mockTxContext) GasAvailable() uint64 {
@@ -423,7 +701,7 @@ func (env *mockTxContext) GetUnit(id types.UnitID, committed bool) (state.Unit,
 
 func (env *mockTxContext) CommittedUC() *types.UnicityCertificate { return env.committedUC() }
 
-func (env *mockTxContext) CurrentRound() uint64 { return 0 }
+func (env *mockTxContext) CurrentRound() uint64 { return env.currentRound }
 
 func (env *mockTxContext) TrustBase(epoch uint64) (types.RootTrustBase, error) {
 	return nil, fmt.Errorf("mockTxContext.TrustBase is not implemented")
@@ -0,0 +1,156 @@
+package templates
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+
+	sdkpredicates "github.com/unicitynetwork/bft-go-base/predicates"
+	"github.com/unicitynetwork/bft-go-base/predicates/templates"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+type (
+	/*
+		HashLock is the parameter of the hash-lock predicate (HTLC style), the
+		predicate evaluates to "true" when argument contains SHA256 preimage of
+		the Hash and the transaction is signed by the owner of the PubKeyHash.
+		Signature is required as revealing the preimage (ie in the mempool)
+		would otherwise allow anyone to unlock.
+	*/
+	HashLock struct {
+		_          struct{} `cbor:",toarray"`
+		Hash       []byte
+		PubKeyHash []byte
+	}
+
+	// HashLockProof is the argument of the hash-lock predicate.
+	HashLockProof struct {
+		_         struct{} `cbor:",toarray"`
+		Secret    []byte
+		Signature *templates.P2pkh256Signature
+	}
+
+	/*
+		TimeLock is the parameter of the time-lock predicate, the predicate
+		evaluates to "true" starting from the round Round.
+	*/
+	TimeLock struct {
+		_     struct{} `cbor:",toarray"`
+		Round uint64
+	}
+
+	/*
+		MultiSig is the parameter of the m-of-n multisig predicate, the predicate
+		evaluates to "true" when at least Threshold of the owners of the
+		PubKeyHashes have signed the transaction.
+	*/
+	MultiSig struct {
+		_            struct{} `cbor:",toarray"`
+		Threshold    uint64
+		PubKeyHashes [][]byte
+	}
+
+	// MultiSigProof is the argument of the multisig predicate, ie owner proof.
+	MultiSigProof struct {
+		_          struct{} `cbor:",toarray"`
+		Signatures []*templates.P2pkh256Signature
+	}
+)
+
+func NewHashLock(hash, pubKeyHash []byte) sdkpredicates.Predicate {
+	params, _ := types.Cbor.Marshal(HashLock{Hash: hash, PubKeyHash: pubKeyHash})
+	return sdkpredicates.Predicate{Tag: templates.TemplateStartByte, Code: []byte{templates.HashLockID}, Params: params}
+}
+
+// NewHashLockBytes returns hash-lock predicate for the SHA256 hash of the secret and the owner's public key hash.
+func NewHashLockBytes(hash, pubKeyHash []byte) types.PredicateBytes {
+	pb, _ := types.Cbor.Marshal(NewHashLock(hash, pubKeyHash))
+	return pb
+}
+
+// NewHashLockBytesFromSecret returns hash-lock predicate which can be unlocked by the owner of the pubKeyHash with the secret.
+func NewHashLockBytesFromSecret(secret, pubKeyHash []byte) types.PredicateBytes {
+	h := sha256.Sum256(secret)
+	return NewHashLockBytes(h[:], pubKeyHash)
+}
+
+/*
+NewHashLockProofBytes returns the argument which unlocks the hash-lock predicate,
+"signature" is P2PKH256 signature (ie created by templates.NewP2pkh256SignatureBytes).
+*/
+func NewHashLockProofBytes(secret, signature []byte) ([]byte, error) {
+	sig := &templates.P2pkh256Signature{}
+	if err := types.Cbor.Unmarshal(signature, sig); err != nil {
+		return nil, err
+	}
+	return types.Cbor.Marshal(HashLockProof{Secret: secret, Signature: sig})
+}
+
+func NewTimeLock(round uint64) sdkpredicates.Predicate {
+	params, _ := types.Cbor.Marshal(TimeLock{Round: round})
+	return sdkpredicates.Predicate{Tag: templates.TemplateStartByte, Code: []byte{templates.TimeLockID}, Params: params}
+}
+
+// NewTimeLockBytes returns time-lock predicate which evaluates to "true" starting from the round.
+func NewTimeLockBytes(round uint64) types.PredicateBytes {
+	pb, _ := types.Cbor.Marshal(NewTimeLock(round))
+	return pb
+}
+
+func NewMultiSig(threshold uint64, pubKeyHashes ...[]byte) (sdkpredicates.Predicate, error) {
+	multiSig := MultiSig{Threshold: threshold, PubKeyHashes: pubKeyHashes}
+	if err := multiSig.IsValid(); err != nil {
+		return sdkpredicates.Predicate{}, err
+	}
+	params, err := types.Cbor.Marshal(multiSig)
+	if err != nil {
+		return sdkpredicates.Predicate{}, err
+	}
+	return sdkpredicates.Predicate{Tag: templates.TemplateStartByte, Code: []byte{templates.MultiSigID}, Params: params}, nil
+}
+
+// NewMultiSigBytes returns m-of-n multisig predicate of the P2PKH256 public key hashes.
+func NewMultiSigBytes(threshold uint64, pubKeyHashes ...[]byte) (types.PredicateBytes, error) {
+	p, err := NewMultiSig(threshold, pubKeyHashes...)
+	if err != nil {
+		return nil, err
+	}
+	return types.Cbor.Marshal(p)
+}
+
+/*
+IsValid checks the threshold and that the public key hashes are unique,
+with duplicate keys the predicate could not be satisfied.
+*/
+func (m *MultiSig) IsValid() error {
+	if m.Threshold == 0 || m.Threshold > uint64(len(m.PubKeyHashes)) {
+		return fmt.Errorf("invalid multisig threshold %d for %d keys", m.Threshold, len(m.PubKeyHashes))
+	}
+	for i, pkh := range m.PubKeyHashes {
+		if len(pkh) != sha256.Size {
+			return fmt.Errorf("invalid pubkey hash size: expected %d, got %d (%X)", sha256.Size, len(pkh), pkh)
+		}
+		if slices.ContainsFunc(m.PubKeyHashes[:i], func(h []byte) bool { return bytes.Equal(h, pkh) }) {
+			return fmt.Errorf("duplicate pubkey hash %X", pkh)
+		}
+	}
+	return nil
+}
+
+/*
+NewMultiSigProofBytes returns multisig predicate argument, "signatures" are
+P2PKH256 signatures (ie created by templates.NewP2pkh256SignatureBytes).
+*/
+func NewMultiSigProofBytes(signatures ...[]byte) ([]byte, error) {
+	proof := MultiSigProof{Signatures: make([]*templates.P2pkh256Signature, len(signatures))}
+	for i, sb := range signatures {
+		sig := &templates.P2pkh256Signature{}
+		if err := types.Cbor.Unmarshal(sb, sig); err != nil {
+			return nil, err
+		}
+		proof.Signatures[i] = sig
+	}
+	return types.Cbor.Marshal(proof)
+}
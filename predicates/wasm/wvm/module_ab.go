@@ -3,6 +3,7 @@ package wvm
 import (
 	"bytes"
 	"context"
+	"crypto"
 	"crypto/sha256"
 	"errors"
 	"fmt"
@@ -11,11 +12,26 @@ import (
 	"github.com/tetratelabs/wazero/api"
 
 	"github.com/unicitynetwork/bft-core/logger"
+	abcrypto "github.com/unicitynetwork/bft-go-base/crypto"
 	"github.com/unicitynetwork/bft-go-base/predicates/templates"
 	"github.com/unicitynetwork/bft-go-base/txsystem/money"
 	"github.com/unicitynetwork/bft-go-base/types"
 )
 
+// gas costs of the host APIs doing cryptographic verification
+const (
+	gasVerifySecp256k1 = 1000
+	gasVerifyTxProof   = 5000
+	gasVerifyUnitProof = 5000
+)
+
+// return codes of the verify_* host APIs
+const (
+	verifyResultOK      = 0 // verification succeeded
+	verifyResultInvalid = 1 // verification failed
+	verifyResultBadArgs = 2 // input is not valid (ie failed to decode)
+)
+
 /*
 functions to verify objects etc
 */
@@ -24,6 +40,9 @@ func addModule(ctx context.Context, rt wazero.Runtime, _ Observability) error {
 		NewFunctionBuilder().WithGoModuleFunction(hostAPI(digestSHA256), []api.ValueType{api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).Export("digest_sha256").
 		NewFunctionBuilder().WithGoModuleFunction(hostAPI(amountTransferred), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI64}, []api.ValueType{api.ValueTypeI64}).Export("amount_transferred").
 		NewFunctionBuilder().WithGoModuleFunction(api.GoModuleFunc(txSignedByPKH), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("tx_signed_by_pkh").
+		NewFunctionBuilder().WithGoModuleFunction(hostAPI(verifySecp256k1), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("verify_secp256k1").
+		NewFunctionBuilder().WithGoModuleFunction(hostAPI(verifyTxProof), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("verify_tx_proof").
+		NewFunctionBuilder().WithGoModuleFunction(hostAPI(verifyUnitProof), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("verify_unit_proof").
 		Instantiate(ctx)
 	return err
 }
@@ -229,6 +248,237 @@ func transferredSum(txRecordProof *types.TxRecordProof, receiverPKH, refNo []byt
 	return sum, nil
 }
 
+/*
+verifySecp256k1 verifies secp256k1 signature over arbitrary message (the
+message is hashed with SHA256 before verification).
+
+Arguments (stack):
+  - 0: handle of the compressed public key;
+  - 1: handle of the signature;
+  - 2: handle of the message;
+
+Returns:
+  - 0: signature is valid;
+  - 1: signature is not valid;
+  - 2: public key or signature is not valid argument;
+*/
+func verifySecp256k1(vec *vmContext, mod api.Module, stack []uint64) error {
+	if err := vec.curPrg.env.SpendGas(gasVerifySecp256k1); err != nil {
+		return fmt.Errorf("spending gas: %w", err)
+	}
+
+	pubKey, err := vec.getBytesVariable(api.DecodeU32(stack[0]))
+	if err != nil {
+		return fmt.Errorf("reading public key: %w", err)
+	}
+	sig, err := vec.getBytesVariable(api.DecodeU32(stack[1]))
+	if err != nil {
+		return fmt.Errorf("reading signature: %w", err)
+	}
+	msg, err := vec.getBytesVariable(api.DecodeU32(stack[2]))
+	if err != nil {
+		return fmt.Errorf("reading message: %w", err)
+	}
+
+	verifier, err := abcrypto.NewVerifierSecp256k1(pubKey)
+	if err != nil {
+		vec.log.Debug("creating secp256k1 verifier", logger.Error(err))
+		stack[0] = verifyResultBadArgs
+		return nil
+	}
+	switch err := verifier.VerifyBytes(sig, msg); {
+	case err == nil:
+		stack[0] = verifyResultOK
+	case errors.Is(err, abcrypto.ErrVerificationFailed):
+		stack[0] = verifyResultInvalid
+	default:
+		vec.log.Debug("verifying secp256k1 signature", logger.Error(err))
+		stack[0] = verifyResultBadArgs
+	}
+	return nil
+}
+
+/*
+verifyTxProof verifies CBOR encoded TxRecordProof (possibly of a transaction
+in another partition), ie that the proof is for the expected transaction of the
+expected partition, the transaction was executed successfully in the block certified
+by the UC in the proof and the UC is valid according to the trust base of it's
+epoch and the expected shard configuration.
+
+Arguments (stack):
+  - 0: handle of the CBOR encoded TxRecordProof;
+  - 1: handle of the expected transaction order hash;
+  - 2: expected partition ID;
+  - 3: handle of the expected shard configuration hash;
+
+Returns:
+  - 0: proof is valid;
+  - 1: proof is not valid;
+  - 2: argument is not valid CBOR encoded TxRecordProof or shard configuration hash is empty;
+*/
+func verifyTxProof(vec *vmContext, mod api.Module, stack []uint64) error {
+	if err := vec.curPrg.env.SpendGas(gasVerifyTxProof); err != nil {
+		return fmt.Errorf("spending gas: %w", err)
+	}
+
+	data, err := vec.getBytesVariable(api.DecodeU32(stack[0]))
+	if err != nil {
+		return fmt.Errorf("reading tx proof: %w", err)
+	}
+	txHash, err := vec.getBytesVariable(api.DecodeU32(stack[1]))
+	if err != nil {
+		return fmt.Errorf("reading tx hash: %w", err)
+	}
+	partitionID := types.PartitionID(api.DecodeU32(stack[2]))
+	shardConfHash, err := vec.getBytesVariable(api.DecodeU32(stack[3]))
+	if err != nil {
+		return fmt.Errorf("reading shard configuration hash: %w", err)
+	}
+	if len(shardConfHash) == 0 {
+		stack[0] = verifyResultBadArgs
+		return nil
+	}
+
+	proof := &types.TxRecordProof{}
+	if err := types.Cbor.Unmarshal(data, proof); err != nil {
+		vec.log.Debug("decoding tx record proof", logger.Error(err))
+		stack[0] = verifyResultBadArgs
+		return nil
+	}
+	if err := proof.IsValid(); err != nil {
+		vec.log.Debug("invalid tx record proof", logger.Error(err))
+		stack[0] = verifyResultBadArgs
+		return nil
+	}
+	txo, err := proof.GetTransactionOrderV1()
+	if err != nil {
+		vec.log.Debug("decoding transaction order of the tx record proof", logger.Error(err))
+		stack[0] = verifyResultBadArgs
+		return nil
+	}
+	uc, err := proof.TxProof.GetUC()
+	if err != nil {
+		vec.log.Debug("decoding UC of the tx record proof", logger.Error(err))
+		stack[0] = verifyResultBadArgs
+		return nil
+	}
+
+	if h, err := txo.Hash(crypto.SHA256); err != nil || !bytes.Equal(h, txHash) {
+		vec.log.Debug(fmt.Sprintf("tx record proof is for transaction %X, expected %X", h, txHash), logger.Error(err))
+		stack[0] = verifyResultInvalid
+		return nil
+	}
+	if txo.PartitionID != partitionID {
+		vec.log.Debug(fmt.Sprintf("tx record proof is for partition %s, expected %s", txo.PartitionID, partitionID))
+		stack[0] = verifyResultInvalid
+		return nil
+	}
+	ucv := trustBaseUCValidator{getTrustBase: vec.orc.TrustBase, partitionID: partitionID}
+	if err := ucv.Validate(uc, shardConfHash); err != nil {
+		vec.log.Debug("verifying UC of the tx record proof", logger.Error(err))
+		stack[0] = verifyResultInvalid
+		return nil
+	}
+	// the UC is verified against the expected shard configuration above, the
+	// proof's own verification binds the transaction to the block of the UC
+	if err := proof.Verify(vec.orc.TrustBase); err != nil {
+		vec.log.Debug("verifying tx record proof", logger.Error(err))
+		stack[0] = verifyResultInvalid
+		return nil
+	}
+	stack[0] = verifyResultOK
+	return nil
+}
+
+/*
+verifyUnitProof verifies CBOR encoded UnitStateWithProof (possibly of a unit
+in another partition), ie that the proof is for the expected unit of the expected
+partition, the unit state is certified by the UC in the proof and the UC is valid
+according to the trust base of it's epoch and the expected shard configuration.
+
+Arguments (stack):
+  - 0: handle of the CBOR encoded UnitStateWithProof;
+  - 1: handle of the expected unit ID;
+  - 2: expected partition ID;
+  - 3: handle of the expected shard configuration hash;
+
+Returns:
+  - 0: proof is valid;
+  - 1: proof is not valid;
+  - 2: argument is not valid CBOR encoded UnitStateWithProof or shard configuration hash is empty;
+*/
+func verifyUnitProof(vec *vmContext, mod api.Module, stack []uint64) error {
+	if err := vec.curPrg.env.SpendGas(gasVerifyUnitProof); err != nil {
+		return fmt.Errorf("spending gas: %w", err)
+	}
+
+	data, err := vec.getBytesVariable(api.DecodeU32(stack[0]))
+	if err != nil {
+		return fmt.Errorf("reading unit proof: %w", err)
+	}
+	unitID, err := vec.getBytesVariable(api.DecodeU32(stack[1]))
+	if err != nil {
+		return fmt.Errorf("reading unit ID: %w", err)
+	}
+	partitionID := types.PartitionID(api.DecodeU32(stack[2]))
+	shardConfHash, err := vec.getBytesVariable(api.DecodeU32(stack[3]))
+	if err != nil {
+		return fmt.Errorf("reading shard configuration hash: %w", err)
+	}
+	if len(shardConfHash) == 0 {
+		stack[0] = verifyResultBadArgs
+		return nil
+	}
+
+	usp := &types.UnitStateWithProof{}
+	if err := types.Cbor.Unmarshal(data, usp); err != nil {
+		vec.log.Debug("decoding unit state proof", logger.Error(err))
+		stack[0] = verifyResultBadArgs
+		return nil
+	}
+	if usp.State == nil || usp.Proof == nil {
+		stack[0] = verifyResultBadArgs
+		return nil
+	}
+
+	if !bytes.Equal(usp.Proof.UnitID, unitID) {
+		vec.log.Debug(fmt.Sprintf("unit state proof is for unit %s, expected %s", usp.Proof.UnitID, types.UnitID(unitID)))
+		stack[0] = verifyResultInvalid
+		return nil
+	}
+	ucv := trustBaseUCValidator{getTrustBase: vec.orc.TrustBase, partitionID: partitionID}
+	if err := usp.Proof.Verify(crypto.SHA256, usp.State, ucv, shardConfHash); err != nil {
+		vec.log.Debug("verifying unit state proof", logger.Error(err))
+		stack[0] = verifyResultInvalid
+		return nil
+	}
+	stack[0] = verifyResultOK
+	return nil
+}
+
+/*
+trustBaseUCValidator verifies UC of the partition against the trust base of
+the UC's epoch.
+*/
+type trustBaseUCValidator struct {
+	getTrustBase func(epoch uint64) (types.RootTrustBase, error)
+	partitionID  types.PartitionID
+}
+
+func (v trustBaseUCValidator) Validate(uc *types.UnicityCertificate, shardConfHash []byte) error {
+	if uc == nil || uc.UnicitySeal == nil || uc.UnicityTreeCertificate == nil {
+		return errors.New("invalid UC: missing unicity seal or unicity tree certificate")
+	}
+	if len(shardConfHash) == 0 {
+		return errors.New("shard configuration hash is required")
+	}
+	tb, err := v.getTrustBase(uc.UnicitySeal.Epoch)
+	if err != nil {
+		return fmt.Errorf("acquiring trust base: %w", err)
+	}
+	return uc.Verify(tb, crypto.SHA256, v.partitionID, shardConfHash)
+}
+
 type txoEvalCtx struct {
 	EvalEnvironment
 	exArgument func() ([]byte, error)
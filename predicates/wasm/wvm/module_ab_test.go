@@ -2,18 +2,23 @@ package wvm
 
 import (
 	"context"
+	"crypto"
 	"errors"
+	"fmt"
 	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tetratelabs/wazero/api"
 
+	test "github.com/unicitynetwork/bft-core/internal/testutils"
 	testblock "github.com/unicitynetwork/bft-core/internal/testutils/block"
+	testcertificates "github.com/unicitynetwork/bft-core/internal/testutils/certificates"
 	"github.com/unicitynetwork/bft-core/internal/testutils/observability"
 	"github.com/unicitynetwork/bft-core/predicates"
 	"github.com/unicitynetwork/bft-core/predicates/wasm/wvm/bumpallocator"
 	"github.com/unicitynetwork/bft-core/predicates/wasm/wvm/encoder"
+	"github.com/unicitynetwork/bft-core/state"
 	testtransaction "github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
 	abcrypto "github.com/unicitynetwork/bft-go-base/crypto"
 	"github.com/unicitynetwork/bft-go-base/predicates/templates"
@@ -21,6 +26,7 @@ import (
 	"github.com/unicitynetwork/bft-go-base/txsystem/tokens"
 	"github.com/unicitynetwork/bft-go-base/types"
 	"github.com/unicitynetwork/bft-go-base/types/hex"
+	"github.com/unicitynetwork/bft-go-base/util"
 )
 
 func Test_txSignedByPKH(t *testing.T) {
@@ -519,3 +525,300 @@ func Test_transferredSum(t *testing.T) {
 		require.Zero(t, sum)
 	})
 }
+
+func Test_verifySecp256k1(t *testing.T) {
+	signer, err := abcrypto.NewInMemorySecp256K1Signer()
+	require.NoError(t, err)
+	verifier, err := signer.Verifier()
+	require.NoError(t, err)
+	pubKey, err := verifier.MarshalPublicKey()
+	require.NoError(t, err)
+	msg := []byte("message to sign")
+	sig, err := signer.SignBytes(msg)
+	require.NoError(t, err)
+
+	newVMContext := func(gas uint64) *vmContext {
+		return &vmContext{
+			curPrg: &evalContext{
+				vars:   map[uint32]any{},
+				varIdx: handle_max_reserved,
+				env:    &mockTxContext{GasRemaining: gas},
+			},
+			log: observability.Default(t).Logger(),
+		}
+	}
+	call := func(vec *vmContext, pubKey, sig, msg any) (uint64, error) {
+		stack := []uint64{uint64(vec.curPrg.addVar(pubKey)), uint64(vec.curPrg.addVar(sig)), uint64(vec.curPrg.addVar(msg))}
+		err := verifySecp256k1(vec, &mockApiMod{}, stack)
+		return stack[0], err
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		vec := newVMContext(gasVerifySecp256k1 + 1)
+		res, err := call(vec, pubKey, sig, msg)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultOK, res)
+		require.EqualValues(t, 1, vec.curPrg.env.GasAvailable())
+	})
+
+	t.Run("signature of different message", func(t *testing.T) {
+		res, err := call(newVMContext(gasVerifySecp256k1), pubKey, sig, []byte("other message"))
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultInvalid, res)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		res, err := call(newVMContext(gasVerifySecp256k1), pubKey[1:], sig, msg)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultBadArgs, res)
+
+		res, err = call(newVMContext(gasVerifySecp256k1), pubKey, sig[:10], msg)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultBadArgs, res)
+
+		_, err = call(newVMContext(gasVerifySecp256k1), pubKey, 42, msg)
+		require.EqualError(t, err, `reading signature: can't handle var of type int`)
+	})
+
+	t.Run("out of gas", func(t *testing.T) {
+		_, err := call(newVMContext(gasVerifySecp256k1-1), pubKey, sig, msg)
+		require.EqualError(t, err, `spending gas: out of gas`)
+	})
+}
+
+func Test_verifyTxProof(t *testing.T) {
+	trustBaseOK := &mockRootTrustBase{
+		verifyQuorumSignatures: func(data []byte, signatures map[string]hex.Bytes) error { return nil },
+	}
+	tbSigner, err := abcrypto.NewInMemorySecp256K1Signer()
+	require.NoError(t, err)
+	txo := testtransaction.NewTransactionOrder(t, testtransaction.WithPartitionID(money.DefaultPartitionID))
+	txBytes, err := txo.MarshalCBOR()
+	require.NoError(t, err)
+	txHash, err := txo.Hash(crypto.SHA256)
+	require.NoError(t, err)
+	txRec := &types.TransactionRecord{Version: 1, TransactionOrder: txBytes, ServerMetadata: &types.ServerMetadata{SuccessIndicator: types.TxStatusSuccessful}}
+	txProof := testblock.CreateTxRecordProof(t, txRec, tbSigner, testblock.WithPartitionID(money.DefaultPartitionID))
+	uc, err := txProof.TxProof.GetUC()
+	require.NoError(t, err)
+	shardConfHash := uc.ShardConfHash
+	proof, err := types.Cbor.Marshal(txProof)
+	require.NoError(t, err)
+
+	call := func(t *testing.T, proof, txHash any, partitionID types.PartitionID, shardConfHash []byte, gas uint64, tbErr error) (uint64, error) {
+		vec := &vmContext{
+			curPrg: &evalContext{
+				vars:   map[uint32]any{},
+				varIdx: handle_max_reserved,
+				env:    &mockTxContext{GasRemaining: gas},
+			},
+			orc: mockOrchestration{
+				trustBase: func(epoch uint64) (types.RootTrustBase, error) { return trustBaseOK, tbErr },
+			},
+			log: observability.Default(t).Logger(),
+		}
+		stack := []uint64{
+			uint64(vec.curPrg.addVar(proof)),
+			uint64(vec.curPrg.addVar(txHash)),
+			api.EncodeU32(uint32(partitionID)),
+			uint64(vec.curPrg.addVar(shardConfHash)),
+		}
+		err := verifyTxProof(vec, &mockApiMod{}, stack)
+		return stack[0], err
+	}
+
+	t.Run("valid proof", func(t *testing.T) {
+		res, err := call(t, proof, txHash, money.DefaultPartitionID, shardConfHash, gasVerifyTxProof, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultOK, res)
+	})
+
+	t.Run("proof of another transaction", func(t *testing.T) {
+		res, err := call(t, proof, test.RandomBytes(32), money.DefaultPartitionID, shardConfHash, gasVerifyTxProof, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultInvalid, res)
+	})
+
+	t.Run("proof of another partition", func(t *testing.T) {
+		res, err := call(t, proof, txHash, money.DefaultPartitionID+1, shardConfHash, gasVerifyTxProof, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultInvalid, res)
+	})
+
+	t.Run("proof of another shard configuration", func(t *testing.T) {
+		res, err := call(t, proof, txHash, money.DefaultPartitionID, test.RandomBytes(32), gasVerifyTxProof, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultInvalid, res)
+
+		res, err = call(t, proof, txHash, money.DefaultPartitionID, []byte{}, gasVerifyTxProof, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultBadArgs, res)
+	})
+
+	t.Run("unknown epoch", func(t *testing.T) {
+		res, err := call(t, proof, txHash, money.DefaultPartitionID, shardConfHash, gasVerifyTxProof, errors.New("unknown epoch"))
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultInvalid, res)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		res, err := call(t, []byte{0xA0}, txHash, money.DefaultPartitionID, shardConfHash, gasVerifyTxProof, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultBadArgs, res)
+
+		_, err = call(t, proof, 42, money.DefaultPartitionID, shardConfHash, gasVerifyTxProof, nil)
+		require.EqualError(t, err, `reading tx hash: can't handle var of type int`)
+	})
+
+	t.Run("out of gas", func(t *testing.T) {
+		_, err := call(t, proof, txHash, money.DefaultPartitionID, shardConfHash, gasVerifyTxProof-1, nil)
+		require.EqualError(t, err, `spending gas: out of gas`)
+	})
+}
+
+func Test_verifyUnitProof(t *testing.T) {
+	trustBaseOK := &mockRootTrustBase{
+		verifyQuorumSignatures: func(data []byte, signatures map[string]hex.Bytes) error { return nil },
+	}
+	shardConf := &types.PartitionDescriptionRecord{
+		Version:         1,
+		NetworkID:       5,
+		PartitionID:     money.DefaultPartitionID,
+		PartitionTypeID: money.PartitionTypeID,
+		TypeIDLen:       8,
+		UnitIDLen:       256,
+		T2Timeout:       2500000000,
+	}
+	shardConfHash := test.DoHash(t, shardConf)
+
+	// state with a few bills committed with UC of the shard
+	tbSigner, err := abcrypto.NewInMemorySecp256K1Signer()
+	require.NoError(t, err)
+	s := state.NewEmptyState()
+	var unitIDs []types.UnitID
+	for i := range byte(3) {
+		unitID := append(make(types.UnitID, 31), i, money.BillUnitType)
+		unitIDs = append(unitIDs, unitID)
+		require.NoError(t, s.Apply(state.AddUnit(unitID, &money.BillData{Version: 1, Value: uint64(i) * 10, OwnerPredicate: templates.AlwaysTrueBytes()})))
+		require.NoError(t, s.AddUnitLog(unitID, test.RandomBytes(32)))
+	}
+	summaryValue, summaryHash, err := s.CalculateRoot()
+	require.NoError(t, err)
+	ir := &types.InputRecord{
+		Version:      1,
+		RoundNumber:  5,
+		PreviousHash: test.RandomBytes(32),
+		Hash:         summaryHash,
+		SummaryValue: util.Uint64ToBytes(summaryValue),
+		Timestamp:    types.NewTimestamp(),
+		BlockHash:    test.RandomBytes(32),
+		ETHash:       test.RandomBytes(32),
+	}
+	require.NoError(t, s.Commit(testcertificates.CreateUnicityCertificate(t, tbSigner, ir, shardConf, 10, nil, make([]byte, 32))))
+
+	unitID := unitIDs[1]
+	proof, err := s.CreateUnitStateProof(unitID, 0)
+	require.NoError(t, err)
+	unit, err := s.GetUnit(unitID, true)
+	require.NoError(t, err)
+	unitState, err := types.NewUnitState(unit.Data(), 0, nil)
+	require.NoError(t, err)
+	validProof, err := types.Cbor.Marshal(&types.UnitStateWithProof{State: unitState, Proof: proof})
+	require.NoError(t, err)
+
+	call := func(t *testing.T, proof, unitID any, partitionID types.PartitionID, shardConfHash []byte, gas uint64) (uint64, error) {
+		vec := &vmContext{
+			curPrg: &evalContext{
+				vars:   map[uint32]any{},
+				varIdx: handle_max_reserved,
+				env:    &mockTxContext{GasRemaining: gas},
+			},
+			orc: mockOrchestration{
+				trustBase: func(epoch uint64) (types.RootTrustBase, error) { return trustBaseOK, nil },
+			},
+			log: observability.Default(t).Logger(),
+		}
+		if id, ok := unitID.(types.UnitID); ok {
+			unitID = []byte(id)
+		}
+		stack := []uint64{
+			uint64(vec.curPrg.addVar(proof)),
+			uint64(vec.curPrg.addVar(unitID)),
+			api.EncodeU32(uint32(partitionID)),
+			uint64(vec.curPrg.addVar(shardConfHash)),
+		}
+		err := verifyUnitProof(vec, &mockApiMod{}, stack)
+		return stack[0], err
+	}
+
+	t.Run("valid proof", func(t *testing.T) {
+		res, err := call(t, validProof, unitID, shardConf.PartitionID, shardConfHash, gasVerifyUnitProof)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultOK, res)
+	})
+
+	t.Run("proof of another unit", func(t *testing.T) {
+		res, err := call(t, validProof, unitIDs[2], shardConf.PartitionID, shardConfHash, gasVerifyUnitProof)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultInvalid, res)
+	})
+
+	t.Run("proof of another partition", func(t *testing.T) {
+		res, err := call(t, validProof, unitID, shardConf.PartitionID+1, shardConfHash, gasVerifyUnitProof)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultInvalid, res)
+	})
+
+	t.Run("proof of another shard configuration", func(t *testing.T) {
+		res, err := call(t, validProof, unitID, shardConf.PartitionID, test.RandomBytes(32), gasVerifyUnitProof)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultInvalid, res)
+
+		res, err = call(t, validProof, unitID, shardConf.PartitionID, []byte{}, gasVerifyUnitProof)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultBadArgs, res)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		res, err := call(t, []byte{0xA0}, unitID, shardConf.PartitionID, shardConfHash, gasVerifyUnitProof)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultBadArgs, res)
+
+		// state is missing
+		data, err := types.Cbor.Marshal(&types.UnitStateWithProof{Proof: &types.UnitStateProof{}})
+		require.NoError(t, err)
+		res, err = call(t, data, unitID, shardConf.PartitionID, shardConfHash, gasVerifyUnitProof)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultBadArgs, res)
+
+		// proof is not valid
+		data, err = types.Cbor.Marshal(&types.UnitStateWithProof{State: &types.UnitState{}, Proof: &types.UnitStateProof{Version: 1, UnitID: unitID}})
+		require.NoError(t, err)
+		res, err = call(t, data, unitID, shardConf.PartitionID, shardConfHash, gasVerifyUnitProof)
+		require.NoError(t, err)
+		require.EqualValues(t, verifyResultInvalid, res)
+
+		_, err = call(t, validProof, 42, shardConf.PartitionID, shardConfHash, gasVerifyUnitProof)
+		require.EqualError(t, err, `reading unit ID: can't handle var of type int`)
+	})
+
+	t.Run("out of gas", func(t *testing.T) {
+		_, err := call(t, validProof, unitID, shardConf.PartitionID, shardConfHash, gasVerifyUnitProof-1)
+		require.EqualError(t, err, `spending gas: out of gas`)
+	})
+}
+
+func Test_trustBaseUCValidator(t *testing.T) {
+	validator := trustBaseUCValidator{
+		getTrustBase: func(epoch uint64) (types.RootTrustBase, error) {
+			return nil, fmt.Errorf("no trust base for epoch %d", epoch)
+		},
+		partitionID: money.DefaultPartitionID,
+	}
+	require.EqualError(t, validator.Validate(nil, nil), `invalid UC: missing unicity seal or unicity tree certificate`)
+	require.EqualError(t, validator.Validate(&types.UnicityCertificate{UnicitySeal: &types.UnicitySeal{Epoch: 3}}, nil), `invalid UC: missing unicity seal or unicity tree certificate`)
+
+	uc := &types.UnicityCertificate{UnicitySeal: &types.UnicitySeal{Epoch: 3}, UnicityTreeCertificate: &types.UnicityTreeCertificate{}}
+	require.EqualError(t, validator.Validate(uc, nil), `shard configuration hash is required`)
+	require.EqualError(t, validator.Validate(uc, []byte{1}), `acquiring trust base: no trust base for epoch 3`)
+}
@@ -11,6 +11,9 @@ import (
 	"github.com/unicitynetwork/bft-go-base/types"
 )
 
+// gas cost of reading data of the committed UC
+const gasCommittedUC = 10
+
 /*
 addContextModule adds "context" module to the "rt".
 This module provides access to "current predicate evaluation context",
@@ -20,6 +23,8 @@ func addContextModule(ctx context.Context, rt wazero.Runtime, _ Observability) e
 	_, err := rt.NewHostModuleBuilder("context").
 		NewFunctionBuilder().WithGoModuleFunction(hostAPI(expCurrentTime), nil, []api.ValueType{api.ValueTypeI64}).Export("now").
 		NewFunctionBuilder().WithGoModuleFunction(hostAPI(expCurrentRound), nil, []api.ValueType{api.ValueTypeI64}).Export("current_round").
+		NewFunctionBuilder().WithGoModuleFunction(hostAPI(expCommittedRound), nil, []api.ValueType{api.ValueTypeI64}).Export("committed_round").
+		NewFunctionBuilder().WithGoModuleFunction(hostAPI(expCommittedRootRound), nil, []api.ValueType{api.ValueTypeI64}).Export("committed_root_round").
 		NewFunctionBuilder().WithGoModuleFunction(hostAPI(createObjH), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("create_obj_h").
 		NewFunctionBuilder().WithGoModuleFunction(hostAPI(addVar), []api.ValueType{api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).Export("add_var").
 		NewFunctionBuilder().WithGoModuleFunction(hostAPI(expSerialize), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI64}).Export("serialize_obj").
@@ -133,3 +138,44 @@ func expCurrentTime(vec *vmContext, mod api.Module, stack []uint64) error {
 	stack[0] = uc.UnicitySeal.Timestamp
 	return nil
 }
+
+/*
+expCommittedRound returns the partition round number of the latest committed UC.
+*/
+func expCommittedRound(vec *vmContext, mod api.Module, stack []uint64) error {
+	uc, err := committedUC(vec)
+	if err != nil {
+		return err
+	}
+	if uc.InputRecord == nil {
+		return errors.New("committed UC has no input record")
+	}
+	stack[0] = uc.InputRecord.RoundNumber
+	return nil
+}
+
+/*
+expCommittedRootRound returns the root chain round number of the latest committed UC.
+*/
+func expCommittedRootRound(vec *vmContext, mod api.Module, stack []uint64) error {
+	uc, err := committedUC(vec)
+	if err != nil {
+		return err
+	}
+	if uc.UnicitySeal == nil {
+		return errors.New("committed UC has no unicity seal")
+	}
+	stack[0] = uc.UnicitySeal.RootChainRoundNumber
+	return nil
+}
+
+func committedUC(vec *vmContext) (*types.UnicityCertificate, error) {
+	if err := vec.curPrg.env.SpendGas(gasCommittedUC); err != nil {
+		return nil, fmt.Errorf("spending gas: %w", err)
+	}
+	uc := vec.curPrg.env.CommittedUC()
+	if uc == nil {
+		return nil, errors.New("no committed UC available")
+	}
+	return uc, nil
+}
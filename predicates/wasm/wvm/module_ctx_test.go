@@ -60,3 +60,43 @@ func Test_expCurrentRound(t *testing.T) {
 	require.NoError(t, expCurrentRound(vec, &mockApiMod{}, stack))
 	require.EqualValues(t, 567, stack[0])
 }
+
+func Test_expCommittedRound(t *testing.T) {
+	uc := &types.UnicityCertificate{
+		InputRecord: &types.InputRecord{RoundNumber: 42},
+		UnicitySeal: &types.UnicitySeal{RootChainRoundNumber: 84},
+	}
+	newVMContext := func(uc *types.UnicityCertificate, gas uint64) *vmContext {
+		return &vmContext{
+			curPrg: &evalContext{
+				env: &mockTxContext{
+					committedUC:  func() *types.UnicityCertificate { return uc },
+					GasRemaining: gas,
+				},
+			},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		vec := newVMContext(uc, 2*gasCommittedUC)
+		stack := []uint64{0}
+		require.NoError(t, expCommittedRound(vec, &mockApiMod{}, stack))
+		require.EqualValues(t, 42, stack[0])
+		require.NoError(t, expCommittedRootRound(vec, &mockApiMod{}, stack))
+		require.EqualValues(t, 84, stack[0])
+		require.Zero(t, vec.curPrg.env.GasAvailable())
+	})
+
+	t.Run("no UC", func(t *testing.T) {
+		stack := []uint64{0}
+		require.EqualError(t, expCommittedRound(newVMContext(nil, gasCommittedUC), &mockApiMod{}, stack), `no committed UC available`)
+		require.EqualError(t, expCommittedRootRound(newVMContext(nil, gasCommittedUC), &mockApiMod{}, stack), `no committed UC available`)
+	})
+
+	t.Run("out of gas", func(t *testing.T) {
+		stack := []uint64{0}
+		require.EqualError(t, expCommittedRound(newVMContext(uc, 0), &mockApiMod{}, stack), `spending gas: out of gas`)
+		require.EqualError(t, expCommittedRootRound(newVMContext(uc, 0), &mockApiMod{}, stack), `spending gas: out of gas`)
+		require.Zero(t, stack[0])
+	})
+}
@@ -2,11 +2,13 @@ package wvm
 
 import (
 	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/experimental"
 )
 
 type (
 	Options struct {
-		cfg wazero.RuntimeConfig
+		cfg      wazero.RuntimeConfig
+		listener experimental.FunctionListenerFactory
 	}
 
 	Option func(*Options)
@@ -23,3 +25,14 @@ func WithRuntimeConfig(cfg wazero.RuntimeConfig) Option {
 		c.cfg = cfg
 	}
 }
+
+/*
+WithFunctionListener registers listener factory which will be notified about
+host API and predicate function calls. Meant for debugging and profiling tools,
+listeners slow down the predicate execution.
+*/
+func WithFunctionListener(factory experimental.FunctionListenerFactory) Option {
+	return func(c *Options) {
+		c.listener = factory
+	}
+}
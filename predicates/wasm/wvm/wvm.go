@@ -10,6 +10,7 @@ import (
 
 	"github.com/tetratelabs/wazero"
 	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/experimental"
 	"go.opentelemetry.io/otel/metric"
 
 	"github.com/unicitynetwork/bft-core/logger"
@@ -56,8 +57,9 @@ type (
 	}
 
 	WasmVM struct {
-		runtime wazero.Runtime
-		ctx     *vmContext
+		runtime  wazero.Runtime
+		ctx      *vmContext
+		listener experimental.FunctionListenerFactory
 	}
 
 	// "evaluation context" of current program
@@ -180,6 +182,10 @@ func New(ctx context.Context, enc Encoder, engines predicates.PredicateExecutor,
 	}
 
 	rt := wazero.NewRuntimeWithConfig(ctx, options.cfg)
+	if options.listener != nil {
+		// listeners are attached to the functions of the module when it's compiled
+		ctx = experimental.WithFunctionListenerFactory(ctx, options.listener)
+	}
 	// WASM shared memory env
 	if _, err := rt.Instantiate(ctx, envWasm); err != nil {
 		return nil, errors.Join(fmt.Errorf("instantiate env module: %w", err), rt.Close(ctx))
@@ -200,7 +206,8 @@ func New(ctx context.Context, enc Encoder, engines predicates.PredicateExecutor,
 	}
 
 	return &WasmVM{
-		runtime: rt,
+		runtime:  rt,
+		listener: options.listener,
 		ctx: &vmContext{
 			curPrg: &evalContext{
 				vars: map[uint32]any{},
@@ -231,7 +238,11 @@ func (vm *WasmVM) Exec(ctx context.Context, predicate, args []byte, conf wasm.Pr
 	if err != nil {
 		return 0, fmt.Errorf("instrumenting predicate error: %w", err)
 	}
-	m, err := vm.runtime.Instantiate(ctx, instrPredicate)
+	instCtx := ctx
+	if vm.listener != nil {
+		instCtx = experimental.WithFunctionListenerFactory(ctx, vm.listener)
+	}
+	m, err := vm.runtime.Instantiate(instCtx, instrPredicate)
 	if err != nil {
 		return 0, fmt.Errorf("failed to instantiate predicate code: %w", err)
 	}
@@ -0,0 +1,189 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/unicitynetwork/bft-go-base/crypto"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+const (
+	// timeout of the signing request, the node must not stall when the signer hangs
+	requestTimeout = 2 * time.Second
+	// max size of the signer response
+	maxResponseSize = 64 << 10
+)
+
+/*
+Client is the MessageSigner which delegates signing to the remote signer (see Server).
+The Client itself can't sign, signing without the message fails with ErrMessageRequired,
+use ForMessage to get the Signer for the message.
+*/
+type Client struct {
+	client   *http.Client
+	signURL  string
+	pubKey   []byte
+	verifier crypto.Verifier
+}
+
+/*
+NewClient returns the Client signing with the key (compressed secp256k1 public key)
+of the remote signer at the address (see Listen for the address format). When the
+key is nil the remote signer must serve exactly one key which is then used.
+*/
+func NewClient(ctx context.Context, address string, pubKey []byte) (*Client, error) {
+	path, err := socketPath(address)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	}
+	// the host of the URL is not used, requests are sent to the socket
+	baseURL := "http://remote-signer"
+	c := &Client{client: &http.Client{Transport: transport, Timeout: requestTimeout}}
+
+	keys := &KeysResponse{}
+	if err := c.do(ctx, http.MethodGet, baseURL+"/api/v1/keys", nil, keys); err != nil {
+		return nil, fmt.Errorf("loading remote signer keys: %w", err)
+	}
+	for _, key := range keys.Keys {
+		if (pubKey == nil && len(keys.Keys) == 1) || bytes.Equal(key, pubKey) {
+			c.pubKey = key
+			break
+		}
+	}
+	switch {
+	case c.pubKey != nil:
+	case pubKey == nil:
+		return nil, fmt.Errorf("remote signer serves %d keys, signing key must be specified", len(keys.Keys))
+	default:
+		return nil, fmt.Errorf("remote signer does not serve the key %X", pubKey)
+	}
+
+	if c.verifier, err = crypto.NewVerifierSecp256k1(c.pubKey); err != nil {
+		return nil, fmt.Errorf("invalid remote signer key: %w", err)
+	}
+	c.signURL = fmt.Sprintf("%s/api/v1/keys/%s/sign", baseURL, keyID(c.pubKey))
+	return c, nil
+}
+
+func (c *Client) ForMessage(kind Kind, rootRound uint64, msg any) crypto.Signer {
+	return &messageSigner{client: c, kind: kind, rootRound: rootRound, msg: msg}
+}
+
+func (c *Client) SignBytes([]byte) ([]byte, error) {
+	return nil, ErrMessageRequired
+}
+
+func (c *Client) SignHash([]byte) ([]byte, error) {
+	return nil, ErrMessageRequired
+}
+
+func (c *Client) MarshalPrivateKey() ([]byte, error) {
+	return nil, ErrPrivateKeyNotAvailable
+}
+
+func (c *Client) Verifier() (crypto.Verifier, error) {
+	return c.verifier, nil
+}
+
+/*
+sign sends the message to the remote signer and verifies the returned signature
+against the data (the hash of the data when isHash is set) the caller expects to
+be signed, ie the signer must have derived the same data from the message.
+*/
+func (c *Client) sign(req *SignRequest, data []byte, isHash bool) ([]byte, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encoding sign request: %w", err)
+	}
+	resp := &SignResponse{}
+	if err := c.do(context.Background(), http.MethodPost, c.signURL, body, resp); err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+	// do not trust the signer blindly, misconfigured signer must not break the consensus
+	if isHash {
+		err = c.verifier.VerifyHash(resp.Signature, data)
+	} else {
+		err = c.verifier.VerifyBytes(resp.Signature, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from remote signer: %w", err)
+	}
+	return resp.Signature, nil
+}
+
+func (c *Client) do(ctx context.Context, method, url string, body []byte, response any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := json.Unmarshal(data, response); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+		return nil
+	case http.StatusConflict:
+		return fmt.Errorf("%w: %s", ErrDoubleSign, strings.TrimPrefix(string(data), ErrDoubleSign.Error()+": "))
+	default:
+		return errors.New(resp.Status + ": " + string(data))
+	}
+}
+
+// messageSigner signs the message of the kind in the root round with the remote signer.
+type messageSigner struct {
+	client    *Client
+	kind      Kind
+	rootRound uint64
+	msg       any
+}
+
+func (s *messageSigner) SignBytes(data []byte) ([]byte, error) {
+	return s.sign(data, false)
+}
+
+func (s *messageSigner) SignHash(hash []byte) ([]byte, error) {
+	return s.sign(hash, true)
+}
+
+func (s *messageSigner) sign(data []byte, isHash bool) ([]byte, error) {
+	msg, err := types.Cbor.Marshal(s.msg)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", s.kind, err)
+	}
+	return s.client.sign(&SignRequest{Kind: s.kind, Round: s.rootRound, Data: msg}, data, isHash)
+}
+
+func (s *messageSigner) MarshalPrivateKey() ([]byte, error) {
+	return nil, ErrPrivateKeyNotAvailable
+}
+
+func (s *messageSigner) Verifier() (crypto.Verifier, error) {
+	return s.client.verifier, nil
+}
@@ -0,0 +1,279 @@
+package remotesigner
+
+import (
+	"context"
+	gocrypto "crypto"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	testlogger "github.com/unicitynetwork/bft-core/internal/testutils/logger"
+	testpeer "github.com/unicitynetwork/bft-core/internal/testutils/peer"
+	testsig "github.com/unicitynetwork/bft-core/internal/testutils/sig"
+	"github.com/unicitynetwork/bft-core/internal/testutils/trustbase"
+	"github.com/unicitynetwork/bft-core/keyvaluedb/memorydb"
+	"github.com/unicitynetwork/bft-core/network/protocol/abdrc"
+	"github.com/unicitynetwork/bft-core/network/protocol/blockproposal"
+	"github.com/unicitynetwork/bft-core/network/protocol/certification"
+	drctypes "github.com/unicitynetwork/bft-core/rootchain/consensus/types"
+	"github.com/unicitynetwork/bft-go-base/crypto"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+func newTestServer(t *testing.T, signers ...crypto.Signer) *Server {
+	t.Helper()
+	db, err := memorydb.New()
+	require.NoError(t, err)
+	srv, err := NewServer(signers, db, testlogger.New(t))
+	require.NoError(t, err)
+	return srv
+}
+
+// serveTestServer serves the signer on the unix socket, returns the address of the signer
+func serveTestServer(t *testing.T, srv *Server) string {
+	t.Helper()
+	address := "unix://" + filepath.Join(t.TempDir(), "signer.sock")
+	listener, err := Listen(address)
+	require.NoError(t, err)
+	httpSrv := &http.Server{Handler: srv.Handler()}
+	go func() { _ = httpSrv.Serve(listener) }()
+	t.Cleanup(func() { _ = httpSrv.Close() })
+	return address
+}
+
+func publicKey(t *testing.T, verifier crypto.Verifier) []byte {
+	t.Helper()
+	pubKey, err := verifier.MarshalPublicKey()
+	require.NoError(t, err)
+	return pubKey
+}
+
+// certReq returns the certification request of the partition round
+func certReq(round uint64, hash byte) *certification.BlockCertificationRequest {
+	return &certification.BlockCertificationRequest{
+		PartitionID: 1,
+		NodeID:      "node1",
+		InputRecord: &types.InputRecord{Version: 1, RoundNumber: round, Hash: []byte{hash}},
+	}
+}
+
+// voteMsg returns the vote of the root round
+func voteMsg(t *testing.T, round uint64, hash byte) *abdrc.VoteMsg {
+	t.Helper()
+	voteInfo := &drctypes.RoundInfo{RoundNumber: round, ParentRoundNumber: round - 1, Timestamp: 1, CurrentRootHash: []byte{hash}}
+	h, err := voteInfo.Hash(gocrypto.SHA256)
+	require.NoError(t, err)
+	return &abdrc.VoteMsg{VoteInfo: voteInfo, LedgerCommitInfo: &types.UnicitySeal{Version: 1, PreviousHash: h}, Author: "node1"}
+}
+
+func signReq(req *certification.BlockCertificationRequest, signer crypto.Signer, rootRound uint64) error {
+	return req.Sign(ForMessage(signer, KindCertificationRequest, rootRound, req))
+}
+
+func verifyReq(t *testing.T, req *certification.BlockCertificationRequest, verifier crypto.Verifier) {
+	t.Helper()
+	bs, err := req.Bytes()
+	require.NoError(t, err)
+	require.NoError(t, verifier.VerifyBytes(req.Signature, bs))
+}
+
+func TestClient(t *testing.T) {
+	signer, verifier := testsig.CreateSignerAndVerifier(t)
+	srv := newTestServer(t, signer)
+	address := serveTestServer(t, srv)
+
+	client, err := NewClient(context.Background(), address, nil)
+	require.NoError(t, err)
+	clientVerifier, err := client.Verifier()
+	require.NoError(t, err)
+	require.Equal(t, publicKey(t, verifier), publicKey(t, clientVerifier))
+
+	t.Run("sign message", func(t *testing.T) {
+		vote := voteMsg(t, 2, 1)
+		require.NoError(t, vote.Sign(client.ForMessage(KindRootVote, 2, vote)))
+		sigBytes, err := vote.LedgerCommitInfo.SigBytes()
+		require.NoError(t, err)
+		require.NoError(t, verifier.VerifyBytes(vote.Signature, sigBytes))
+		// re-signing the same message
+		require.NoError(t, vote.Sign(ForMessage(client, KindRootVote, 2, vote)))
+	})
+
+	t.Run("double signing", func(t *testing.T) {
+		vote := voteMsg(t, 2, 2)
+		err := vote.Sign(client.ForMessage(KindRootVote, 2, vote))
+		require.ErrorIs(t, err, ErrDoubleSign)
+		require.ErrorContains(t, err, "remote signer: double signing: conflicting root-vote for round 2")
+		vote = voteMsg(t, 1, 1)
+		require.ErrorIs(t, vote.Sign(client.ForMessage(KindRootVote, 1, vote)), ErrDoubleSign)
+	})
+
+	t.Run("round is derived from the message", func(t *testing.T) {
+		vote := voteMsg(t, 3, 1)
+		err := vote.Sign(client.ForMessage(KindRootVote, 4, vote))
+		require.ErrorContains(t, err, "400 Bad Request: invalid root-vote: message is for round 3, not 4")
+		// the signed commit info must be bound to the vote info of the round
+		vote.VoteInfo.RoundNumber = 4
+		err = vote.Sign(client.ForMessage(KindRootVote, 4, vote))
+		require.ErrorContains(t, err, "vote info hash does not match hash in commit info")
+	})
+
+	t.Run("message of other kind", func(t *testing.T) {
+		req := certReq(1, 1)
+		err := req.Sign(client.ForMessage(KindRootVote, 1, req))
+		require.ErrorContains(t, err, "400 Bad Request: decoding root-vote: ")
+		err = req.Sign(client.ForMessage("foo", 1, req))
+		require.ErrorContains(t, err, `400 Bad Request: unknown message kind "foo"`)
+	})
+
+	t.Run("opaque data and hash are not signed", func(t *testing.T) {
+		_, err := client.ForMessage(KindCertificationRequest, 1, "data").SignBytes([]byte("data"))
+		require.ErrorContains(t, err, "400 Bad Request: decoding certification-request: ")
+
+		body := `{"kind":"certification-request","round":"1","data":"0x0102","isHash":true}`
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/keys/"+keyID(publicKey(t, verifier))+"/sign", strings.NewReader(body)))
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("partition round repeats", func(t *testing.T) {
+		require.NoError(t, signReq(certReq(5, 1), client, 10))
+		// conflicting request in the same root round
+		require.ErrorIs(t, signReq(certReq(5, 2), client, 10), ErrDoubleSign)
+		// the partition round repeats in the later root round
+		require.NoError(t, signReq(certReq(5, 2), client, 11))
+		// but the past partition round does not
+		require.ErrorIs(t, signReq(certReq(4, 3), client, 12), ErrDoubleSign)
+	})
+
+	t.Run("block proposal", func(t *testing.T) {
+		prop := &blockproposal.BlockProposal{
+			PartitionID:        1,
+			NodeID:             testpeer.GeneratePeerIDs(t, 1)[0],
+			UnicityCertificate: &types.UnicityCertificate{Version: 1, UnicitySeal: &types.UnicitySeal{Version: 1, RootChainRoundNumber: 10}},
+			Technical:          certification.TechnicalRecord{Round: 5},
+		}
+		require.NoError(t, prop.Sign(gocrypto.SHA256, client.ForMessage(KindBlockProposal, 10, prop)))
+		require.NoError(t, prop.Verify(gocrypto.SHA256, verifier))
+		// the proposal must extend the UC of the root round
+		err := prop.Sign(gocrypto.SHA256, client.ForMessage(KindBlockProposal, 11, prop))
+		require.ErrorContains(t, err, "400 Bad Request: invalid block-proposal: extends the root round 10, not 11")
+	})
+
+	t.Run("IR change requests are protected per shard", func(t *testing.T) {
+		irReq := func(partition types.PartitionID, hash byte) *abdrc.IrChangeReqMsg {
+			req := certReq(5, hash)
+			req.PartitionID = partition
+			return &abdrc.IrChangeReqMsg{
+				Author:      "node1",
+				IrChangeReq: &drctypes.IRChangeReq{Partition: partition, CertReason: drctypes.Quorum, Requests: []*certification.BlockCertificationRequest{req}},
+			}
+		}
+		for _, msg := range []*abdrc.IrChangeReqMsg{irReq(1, 1), irReq(2, 1)} {
+			require.NoError(t, msg.Sign(client.ForMessage(KindIRChangeRequest, 10, msg)))
+			require.NoError(t, msg.Verify(trustbase.NewTrustBaseFromVerifiers(t, map[string]crypto.Verifier{"node1": verifier})))
+		}
+		msg := irReq(1, 2)
+		require.ErrorIs(t, msg.Sign(client.ForMessage(KindIRChangeRequest, 10, msg)), ErrDoubleSign)
+	})
+
+	t.Run("message is required", func(t *testing.T) {
+		_, err := client.SignBytes([]byte("data"))
+		require.ErrorIs(t, err, ErrMessageRequired)
+		_, err = client.SignHash(make([]byte, 32))
+		require.ErrorIs(t, err, ErrMessageRequired)
+		_, err = client.MarshalPrivateKey()
+		require.ErrorIs(t, err, ErrPrivateKeyNotAvailable)
+	})
+}
+
+func TestClient_keys(t *testing.T) {
+	signer1, verifier1 := testsig.CreateSignerAndVerifier(t)
+	signer2, verifier2 := testsig.CreateSignerAndVerifier(t)
+	address := serveTestServer(t, newTestServer(t, signer1, signer2))
+
+	_, err := NewClient(context.Background(), address, nil)
+	require.EqualError(t, err, "remote signer serves 2 keys, signing key must be specified")
+
+	_, err = NewClient(context.Background(), address, []byte{1, 2, 3})
+	require.EqualError(t, err, "remote signer does not serve the key 010203")
+
+	for _, verifier := range []crypto.Verifier{verifier1, verifier2} {
+		client, err := NewClient(context.Background(), address, publicKey(t, verifier))
+		require.NoError(t, err)
+		req := certReq(1, 1)
+		require.NoError(t, signReq(req, client, 1))
+		verifyReq(t, req, verifier)
+	}
+}
+
+func TestClient_unixSocket(t *testing.T) {
+	signer, verifier := testsig.CreateSignerAndVerifier(t)
+	address := "unix://" + filepath.Join(t.TempDir(), "signer.sock")
+	listener, err := Listen(address)
+	require.NoError(t, err)
+	httpSrv := &http.Server{Handler: newTestServer(t, signer).Handler()}
+	done := make(chan error, 1)
+	go func() { done <- httpSrv.Serve(listener) }()
+
+	client, err := NewClient(context.Background(), address, nil)
+	require.NoError(t, err)
+	req := certReq(1, 1)
+	require.NoError(t, signReq(req, client, 1))
+	verifyReq(t, req, verifier)
+
+	// signer is not reachable, signing must fail
+	require.NoError(t, httpSrv.Close())
+	require.ErrorIs(t, <-done, http.ErrServerClosed)
+	require.ErrorContains(t, signReq(certReq(2, 1), client, 2), "remote signer: ")
+
+	_, err = NewClient(context.Background(), address, nil)
+	require.ErrorContains(t, err, "loading remote signer keys: ")
+}
+
+func TestClient_tcpAddress(t *testing.T) {
+	signer, _ := testsig.CreateSignerAndVerifier(t)
+	httpSrv := httptest.NewServer(newTestServer(t, signer).Handler())
+	defer httpSrv.Close()
+	address := strings.TrimPrefix(httpSrv.URL, "http://")
+
+	// the signer API is not authenticated, it must not be served over TCP
+	_, err := Listen(address)
+	require.ErrorContains(t, err, "TCP is not supported")
+	_, err = NewClient(context.Background(), address, nil)
+	require.ErrorContains(t, err, "TCP is not supported")
+	_, err = Listen("unix://")
+	require.ErrorContains(t, err, "invalid remote signer address")
+}
+
+func TestClient_invalidSignature(t *testing.T) {
+	signer, _ := testsig.CreateSignerAndVerifier(t)
+	otherSigner, _ := testsig.CreateSignerAndVerifier(t)
+	srv := newTestServer(t, signer)
+	// the signer signs with a key different from the advertised key
+	for id, key := range srv.keys {
+		srv.keys[id] = signingKey{signer: otherSigner, pubKey: key.pubKey}
+	}
+	client, err := NewClient(context.Background(), serveTestServer(t, srv), nil)
+	require.NoError(t, err)
+	err = signReq(certReq(1, 1), client, 1)
+	require.ErrorContains(t, err, "invalid signature from remote signer")
+	require.False(t, errors.Is(err, ErrDoubleSign))
+}
+
+func TestNewServer(t *testing.T) {
+	signer, _ := testsig.CreateSignerAndVerifier(t)
+	db, err := memorydb.New()
+	require.NoError(t, err)
+
+	_, err = NewServer(nil, db, testlogger.New(t))
+	require.EqualError(t, err, "no signing keys")
+	_, err = NewServer([]crypto.Signer{signer}, nil, testlogger.New(t))
+	require.EqualError(t, err, "signed rounds db is nil")
+	_, err = NewServer([]crypto.Signer{signer, signer}, db, testlogger.New(t))
+	require.ErrorContains(t, err, "duplicate signing key")
+}
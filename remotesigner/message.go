@@ -0,0 +1,148 @@
+package remotesigner
+
+import (
+	"bytes"
+	gocrypto "crypto"
+	"fmt"
+
+	"github.com/unicitynetwork/bft-core/network/protocol/abdrc"
+	"github.com/unicitynetwork/bft-core/network/protocol/blockproposal"
+	"github.com/unicitynetwork/bft-core/network/protocol/certification"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/util"
+)
+
+/*
+signedMessage is the message of the sign request as decoded by the signer, the
+signer does not sign anything but the data (or hash) derived from the message.
+*/
+type signedMessage struct {
+	// position of the message, see position
+	pos position
+	// scope of the double-sign protection within the kind of messages (ie the
+	// shard of the IR change request), nil when the kind has single scope
+	scope []byte
+	// data to sign, the hash of the data when isHash is set
+	data   []byte
+	isHash bool
+}
+
+/*
+decodeMessage decodes the message of the kind and derives the round and the data
+to sign from it. The rootRound is the round of the root chain the message is
+signed in (see SignRequest), for the messages of the root chain it must match the
+round of the message.
+*/
+func decodeMessage(kind Kind, rootRound uint64, data []byte) (*signedMessage, error) {
+	switch kind {
+	case KindRootProposal:
+		msg := &abdrc.ProposalMsg{}
+		if err := types.Cbor.Unmarshal(data, msg); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", kind, err)
+		}
+		if msg.Block == nil {
+			return nil, fmt.Errorf("invalid %s: block is nil", kind)
+		}
+		hash, err := msg.Block.Hash(gocrypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("hashing %s block: %w", kind, err)
+		}
+		return rootMessage(kind, rootRound, msg.Block.GetRound(), hash, true)
+	case KindRootVote:
+		msg := &abdrc.VoteMsg{}
+		if err := types.Cbor.Unmarshal(data, msg); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", kind, err)
+		}
+		if msg.VoteInfo == nil || msg.LedgerCommitInfo == nil {
+			return nil, fmt.Errorf("invalid %s: vote info or ledger commit info is nil", kind)
+		}
+		// the round is not part of the signed commit info, it is bound by the vote info hash
+		hash, err := msg.VoteInfo.Hash(gocrypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("hashing %s vote info: %w", kind, err)
+		}
+		if !bytes.Equal(hash, msg.LedgerCommitInfo.PreviousHash) {
+			return nil, fmt.Errorf("invalid %s: vote info hash does not match hash in commit info", kind)
+		}
+		sigBytes, err := msg.LedgerCommitInfo.SigBytes()
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s commit info: %w", kind, err)
+		}
+		return rootMessage(kind, rootRound, msg.VoteInfo.RoundNumber, sigBytes, false)
+	case KindRootTimeout:
+		msg := &abdrc.TimeoutMsg{}
+		if err := types.Cbor.Unmarshal(data, msg); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", kind, err)
+		}
+		if err := msg.IsValid(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", kind, err)
+		}
+		return rootMessage(kind, rootRound, msg.GetRound(), msg.Bytes(), false)
+	case KindIRChangeRequest:
+		msg := &abdrc.IrChangeReqMsg{}
+		if err := types.Cbor.Unmarshal(data, msg); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", kind, err)
+		}
+		if err := msg.IsValid(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", kind, err)
+		}
+		msg.Signature = nil
+		sigBytes, err := types.Cbor.Marshal(msg)
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", kind, err)
+		}
+		// the requests of the shard are forwarded once per partition round, the
+		// partition round repeats when the root chain times out the round
+		var round uint64
+		for _, req := range msg.IrChangeReq.Requests {
+			round = max(round, req.IRRound())
+		}
+		return &signedMessage{
+			pos:   position{Round: round, RootRound: rootRound},
+			scope: append(util.Uint32ToBytes(uint32(msg.IrChangeReq.Partition)), msg.IrChangeReq.Shard.Key()...),
+			data:  sigBytes,
+		}, nil
+	case KindBlockProposal:
+		msg := &blockproposal.BlockProposal{}
+		if err := types.Cbor.Unmarshal(data, msg); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", kind, err)
+		}
+		if msg.UnicityCertificate == nil {
+			return nil, fmt.Errorf("invalid %s: unicity certificate is nil", kind)
+		}
+		if r := msg.UnicityCertificate.GetRootRoundNumber(); r != rootRound {
+			return nil, fmt.Errorf("invalid %s: extends the root round %d, not %d", kind, r, rootRound)
+		}
+		hash, err := msg.Hash(gocrypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("hashing %s: %w", kind, err)
+		}
+		return &signedMessage{pos: position{Round: msg.Technical.Round, RootRound: rootRound}, data: hash, isHash: true}, nil
+	case KindCertificationRequest:
+		msg := &certification.BlockCertificationRequest{}
+		if err := types.Cbor.Unmarshal(data, msg); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", kind, err)
+		}
+		if msg.InputRecord == nil {
+			return nil, fmt.Errorf("invalid %s: input record is nil", kind)
+		}
+		sigBytes, err := msg.Bytes()
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", kind, err)
+		}
+		return &signedMessage{pos: position{Round: msg.IRRound(), RootRound: rootRound}, data: sigBytes}, nil
+	default:
+		return nil, fmt.Errorf("unknown message kind %q", kind)
+	}
+}
+
+// rootMessage returns the message of the root chain which is signed in the round.
+func rootMessage(kind Kind, rootRound, round uint64, data []byte, isHash bool) (*signedMessage, error) {
+	if round == 0 {
+		return nil, fmt.Errorf("invalid %s: round is unassigned", kind)
+	}
+	if round != rootRound {
+		return nil, fmt.Errorf("invalid %s: message is for round %d, not %d", kind, round, rootRound)
+	}
+	return &signedMessage{pos: position{Round: round, RootRound: rootRound}, data: data, isHash: isHash}, nil
+}
@@ -0,0 +1,114 @@
+package remotesigner
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/unicitynetwork/bft-core/keyvaluedb"
+)
+
+type (
+	/*
+		position of the signed message. The messages are ordered by the round and then
+		by the root round, for the messages of the root chain both are the same round.
+		The partition round repeats when the root chain times out the round (the
+		partition messages of the repeated round extend the UC of a later root round).
+	*/
+	position struct {
+		_         struct{} `cbor:",toarray"`
+		Round     uint64
+		RootRound uint64
+	}
+
+	// signedRound is the last message of the kind signed with the key.
+	signedRound struct {
+		_        struct{} `cbor:",toarray"`
+		Position position
+		DataHash []byte
+	}
+
+	/*
+		protection keeps track of the last signed round per key and message kind
+		and refuses signing messages which conflict with already signed messages.
+	*/
+	protection struct {
+		mu sync.Mutex
+		db keyvaluedb.KeyValueDB
+	}
+)
+
+func (p position) compare(other position) int {
+	if c := cmp.Compare(p.Round, other.Round); c != 0 {
+		return c
+	}
+	return cmp.Compare(p.RootRound, other.RootRound)
+}
+
+func (p position) String() string {
+	if p.Round == p.RootRound {
+		return fmt.Sprintf("round %d", p.Round)
+	}
+	return fmt.Sprintf("round %d (root round %d)", p.Round, p.RootRound)
+}
+
+/*
+check verifies that signing the message does not conflict with the messages signed
+earlier and records the position of the message as signed. Signing the same data
+again in the last signed position is allowed (ie the node re-sends the message).
+The position is persisted before the check returns, ie before the signature is
+released.
+*/
+func (p *protection) check(pubKey []byte, kind Kind, msg *signedMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := protectionKey(pubKey, kind, msg.scope)
+	dataHash := signedDataHash(msg.data, msg.isHash)
+	var last signedRound
+	found, err := p.db.Read(key, &last)
+	if err != nil {
+		return fmt.Errorf("reading last signed round: %w", err)
+	}
+	if found {
+		switch msg.pos.compare(last.Position) {
+		case -1:
+			return fmt.Errorf("%w: %s %s is older than the last signed %s", ErrDoubleSign, kind, msg.pos, last.Position)
+		case 0:
+			if bytes.Equal(dataHash, last.DataHash) {
+				return nil
+			}
+			return fmt.Errorf("%w: conflicting %s for %s", ErrDoubleSign, kind, msg.pos)
+		}
+	}
+	if err := p.db.Write(key, &signedRound{Position: msg.pos, DataHash: dataHash}); err != nil {
+		return fmt.Errorf("storing last signed round: %w", err)
+	}
+	return nil
+}
+
+/*
+protectionKey returns the DB key of the last signed round, public keys are of the
+same length and the kind is followed by the scope of the message.
+*/
+func protectionKey(pubKey []byte, kind Kind, scope []byte) []byte {
+	key := make([]byte, 0, len(pubKey)+len(kind)+1+len(scope))
+	key = append(append(key, pubKey...), kind...)
+	if scope == nil {
+		return key
+	}
+	return append(append(key, '/'), scope...)
+}
+
+func signedDataHash(data []byte, isHash bool) []byte {
+	h := sha256.New()
+	if isHash {
+		h.Write([]byte{1})
+	} else {
+		h.Write([]byte{0})
+	}
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,87 @@
+package remotesigner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicitynetwork/bft-core/keyvaluedb/boltdb"
+	"github.com/unicitynetwork/bft-core/keyvaluedb/memorydb"
+)
+
+// msg returns the message of the root chain round
+func msg(round uint64, data string) *signedMessage {
+	return &signedMessage{pos: position{Round: round, RootRound: round}, data: []byte(data)}
+}
+
+func Test_protection_check(t *testing.T) {
+	db, err := memorydb.New()
+	require.NoError(t, err)
+	p := &protection{db: db}
+	key1 := []byte{1, 1, 1}
+	key2 := []byte{2, 2, 2}
+
+	require.NoError(t, p.check(key1, KindRootVote, msg(5, "vote 5")))
+	// the same message may be signed again
+	require.NoError(t, p.check(key1, KindRootVote, msg(5, "vote 5")))
+	// but not a different one
+	require.ErrorIs(t, p.check(key1, KindRootVote, msg(5, "vote 5b")), ErrDoubleSign)
+	hashMsg := msg(5, "vote 5")
+	hashMsg.isHash = true
+	require.ErrorIs(t, p.check(key1, KindRootVote, hashMsg), ErrDoubleSign)
+	// nor anything for the past round
+	require.EqualError(t, p.check(key1, KindRootVote, msg(4, "vote 4")),
+		"double signing: root-vote round 4 is older than the last signed round 5")
+
+	// rounds are tracked per key, per kind and per scope
+	require.NoError(t, p.check(key1, KindRootTimeout, msg(5, "timeout 5")))
+	require.NoError(t, p.check(key2, KindRootVote, msg(4, "vote 4")))
+	scoped := msg(4, "request 4")
+	scoped.scope = []byte{1}
+	require.NoError(t, p.check(key1, KindRootVote, scoped))
+
+	require.NoError(t, p.check(key1, KindRootVote, msg(7, "vote 7")))
+	require.EqualError(t, p.check(key1, KindRootVote, msg(7, "vote 7b")),
+		"double signing: conflicting root-vote for round 7")
+}
+
+func Test_protection_repeatedRound(t *testing.T) {
+	db, err := memorydb.New()
+	require.NoError(t, err)
+	p := &protection{db: db}
+	key := []byte{1, 2, 3}
+	req := func(round, rootRound uint64, data string) *signedMessage {
+		return &signedMessage{pos: position{Round: round, RootRound: rootRound}, data: []byte(data)}
+	}
+
+	require.NoError(t, p.check(key, KindCertificationRequest, req(5, 10, "request")))
+	require.EqualError(t, p.check(key, KindCertificationRequest, req(5, 10, "other request")),
+		"double signing: conflicting certification-request for round 5 (root round 10)")
+	// the partition round repeats in the later root round
+	require.NoError(t, p.check(key, KindCertificationRequest, req(5, 12, "other request")))
+	require.EqualError(t, p.check(key, KindCertificationRequest, req(5, 11, "request")),
+		"double signing: certification-request round 5 (root round 11) is older than the last signed round 5 (root round 12)")
+	// the partition round is compared first
+	require.ErrorIs(t, p.check(key, KindCertificationRequest, req(4, 13, "request")), ErrDoubleSign)
+	require.NoError(t, p.check(key, KindCertificationRequest, req(6, 12, "request")))
+}
+
+func Test_protection_persisted(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "signer.db")
+	db, err := boltdb.New(dbFile)
+	require.NoError(t, err)
+	p := &protection{db: db}
+	key := []byte{1, 2, 3}
+	require.NoError(t, p.check(key, KindBlockProposal, msg(10, "proposal")))
+	require.NoError(t, db.Close())
+
+	// signer restart must not reset the protection
+	db, err = boltdb.New(dbFile)
+	require.NoError(t, err)
+	defer db.Close()
+	p = &protection{db: db}
+	require.ErrorIs(t, p.check(key, KindBlockProposal, msg(10, "other proposal")), ErrDoubleSign)
+	require.ErrorIs(t, p.check(key, KindBlockProposal, msg(9, "proposal")), ErrDoubleSign)
+	require.NoError(t, p.check(key, KindBlockProposal, msg(10, "proposal")))
+}
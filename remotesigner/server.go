@@ -0,0 +1,188 @@
+package remotesigner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/unicitynetwork/bft-core/keyvaluedb"
+	"github.com/unicitynetwork/bft-core/logger"
+	"github.com/unicitynetwork/bft-go-base/crypto"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+// max size of the sign request body, the hex encoded root chain proposal (up to 32MB) is the largest message
+const maxRequestSize = 64 << 20
+
+type (
+	// Server serves the signing requests of the nodes, see Handler.
+	Server struct {
+		keys       map[string]signingKey // key is hex encoded public key
+		pubKeys    []hex.Bytes
+		protection *protection
+		log        *slog.Logger
+	}
+
+	signingKey struct {
+		signer crypto.Signer
+		pubKey []byte
+	}
+)
+
+/*
+NewServer returns signer serving the keys of the signers. The last signed round
+of the keys is stored in db, the same db must be used when the server is restarted.
+*/
+func NewServer(signers []crypto.Signer, db keyvaluedb.KeyValueDB, log *slog.Logger) (*Server, error) {
+	if len(signers) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+	if db == nil {
+		return nil, errors.New("signed rounds db is nil")
+	}
+	s := &Server{
+		keys:       make(map[string]signingKey, len(signers)),
+		protection: &protection{db: db},
+		log:        log,
+	}
+	for _, signer := range signers {
+		verifier, err := signer.Verifier()
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key: %w", err)
+		}
+		pubKey, err := verifier.MarshalPublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key: %w", err)
+		}
+		id := keyID(pubKey)
+		if _, ok := s.keys[id]; ok {
+			return nil, fmt.Errorf("duplicate signing key %s", id)
+		}
+		s.keys[id] = signingKey{signer: signer, pubKey: pubKey}
+		s.pubKeys = append(s.pubKeys, pubKey)
+	}
+	return s, nil
+}
+
+/*
+Handler returns the HTTP handler of the signer API:
+  - GET /api/v1/keys returns the public keys of the signing keys;
+  - POST /api/v1/keys/{key}/sign signs the message of the SignRequest with the key,
+    responds with "400 Bad Request" when the message can't be decoded and with
+    "409 Conflict" when the message conflicts with already signed messages.
+*/
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/keys", s.getKeys)
+	mux.HandleFunc("POST /api/v1/keys/{key}/sign", s.sign)
+	return mux
+}
+
+func (s *Server) getKeys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &KeysResponse{Keys: s.pubKeys}, s.log)
+}
+
+func (s *Server) sign(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(strings.ToLower(r.PathValue("key")), "0x")
+	signingKey, ok := s.keys[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, "unknown signing key %s", key)
+		return
+	}
+
+	var req SignRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "decoding request body: %v", err)
+		return
+	}
+	if req.IsHash {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, "signing hash is not supported, data must be the message")
+		return
+	}
+	msg, err := decodeMessage(req.Kind, req.Round, req.Data)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	if err := s.protection.check(signingKey.pubKey, req.Kind, msg); err != nil {
+		if errors.Is(err, ErrDoubleSign) {
+			s.log.WarnContext(r.Context(), fmt.Sprintf("refused to sign for key %s", key), logger.Error(err))
+			w.WriteHeader(http.StatusConflict)
+		} else {
+			s.log.ErrorContext(r.Context(), "double sign protection failed", logger.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprint(w, err)
+		return
+	}
+
+	var sig []byte
+	if msg.isHash {
+		sig, err = signingKey.signer.SignHash(msg.data)
+	} else {
+		sig, err = signingKey.signer.SignBytes(msg.data)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "signing: %v", err)
+		return
+	}
+	writeJSON(w, &SignResponse{Signature: sig}, s.log)
+}
+
+func writeJSON(w http.ResponseWriter, data any, log *slog.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Warn("failed to encode response", logger.Error(err))
+	}
+}
+
+/*
+Listen announces on the signer address which must be "unix://" followed by the
+path of the unix socket. The signer API is not authenticated so it's served only
+on the unix socket which is accessible only by the owner of the signer process,
+TCP addresses are rejected.
+*/
+func Listen(address string) (net.Listener, error) {
+	path, err := socketPath(address)
+	if err != nil {
+		return nil, err
+	}
+	// remove the socket file left behind by the previous run
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		return nil, errors.Join(fmt.Errorf("setting socket permissions: %w", err), l.Close())
+	}
+	return l, nil
+}
+
+// socketPath returns the path of the unix socket of the signer address
+func socketPath(address string) (string, error) {
+	path, ok := strings.CutPrefix(address, "unix://")
+	if !ok || path == "" {
+		return "", fmt.Errorf("invalid remote signer address %q: must be \"unix://\" followed by the socket path, TCP is not supported", address)
+	}
+	return path, nil
+}
+
+func keyID(pubKey []byte) string {
+	return fmt.Sprintf("%x", pubKey)
+}
@@ -0,0 +1,89 @@
+/*
+Package remotesigner delegates signing to a separate signer process.
+
+The signer process (see Server) holds the signing keys and serves signing requests
+over HTTP on a local (unix) socket or TCP address. Every signing request carries the
+message to sign, the signer decodes the message, derives the round and the data to
+sign from it and refuses to sign conflicting messages of the same kind for the
+round it has already signed or for an older round, ie it protects the keys against
+double signing independently of the node. The signer does not sign opaque data or
+hashes.
+
+The node side of the protocol is Client which implements crypto.Signer and can be
+used wherever the in-process signer is used. Any failure to reach the signer
+results in a signing error, ie the node does not sign (vote, propose) rather than
+falling back to some other key.
+*/
+package remotesigner
+
+import (
+	"errors"
+
+	"github.com/unicitynetwork/bft-go-base/crypto"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+// Kind of the signed message. The double-sign protection is kept per key and kind.
+type Kind string
+
+// Kinds of the messages, see decodeMessage for the message type of the kind.
+const (
+	KindRootProposal         Kind = "root-proposal"
+	KindRootVote             Kind = "root-vote"
+	KindRootTimeout          Kind = "root-timeout"
+	KindIRChangeRequest      Kind = "ir-change-request"
+	KindBlockProposal        Kind = "block-proposal"
+	KindCertificationRequest Kind = "certification-request"
+)
+
+var (
+	// ErrDoubleSign is returned when signing would conflict with already signed message.
+	ErrDoubleSign = errors.New("double signing")
+	// ErrMessageRequired is returned when remote signer is used without the message, see ForMessage.
+	ErrMessageRequired = errors.New("remote signer requires the message")
+	// ErrPrivateKeyNotAvailable is returned by MarshalPrivateKey of the remote signer.
+	ErrPrivateKeyNotAvailable = errors.New("private key is not available for remote signer")
+)
+
+// MessageSigner is the Signer which needs to know the message it signs the data of.
+type MessageSigner interface {
+	crypto.Signer
+	/*
+		ForMessage returns Signer for signing the message of given kind in the root
+		round (for the partition messages the root round of the UC the message
+		extends). The message must not be modified before it is signed.
+	*/
+	ForMessage(kind Kind, rootRound uint64, msg any) crypto.Signer
+}
+
+/*
+ForMessage returns the Signer for signing the message of given kind in the root round.
+In-process signers (which do not implement MessageSigner) are returned as is.
+*/
+func ForMessage(s crypto.Signer, kind Kind, rootRound uint64, msg any) crypto.Signer {
+	if ms, ok := s.(MessageSigner); ok {
+		return ms.ForMessage(kind, rootRound, msg)
+	}
+	return s
+}
+
+type (
+	SignRequest struct {
+		Kind Kind `json:"kind"`
+		// the root round the message is signed in, see MessageSigner
+		Round uint64 `json:"round,string"`
+		// CBOR encoding of the message
+		Data hex.Bytes `json:"data"`
+		// Data is the hash to sign, not supported (the hash does not reveal the round)
+		IsHash bool `json:"isHash,omitempty"`
+	}
+
+	SignResponse struct {
+		Signature hex.Bytes `json:"signature"`
+	}
+
+	KeysResponse struct {
+		// compressed secp256k1 public keys of the signing keys
+		Keys []hex.Bytes `json:"keys"`
+	}
+)
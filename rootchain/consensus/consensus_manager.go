@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"slices"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/unicitynetwork/bft-core/logger"
+	"github.com/unicitynetwork/bft-core/network"
 	"github.com/unicitynetwork/bft-core/network/protocol/abdrc"
 	"github.com/unicitynetwork/bft-core/network/protocol/certification"
 	"github.com/unicitynetwork/bft-core/observability"
@@ -63,6 +65,17 @@ type (
 		storage.PersistentStore
 	}
 
+	// Status is the snapshot of the consensus manager's sync status and role
+	Status struct {
+		CurrentRound   uint64    // current round of the pacemaker
+		CommittedRound uint64    // round of the latest committed block
+		CommitTime     time.Time // unicity seal timestamp of the latest committed block
+		InRecovery     bool
+		RecoveryRound  uint64 // round the node is recovering to
+		IsLeader       bool   // node is the leader of the current round
+		RootNodeCount  int
+	}
+
 	certRequest struct {
 		ircr IRChangeRequest
 		rsc  trace.SpanContext
@@ -85,6 +98,7 @@ type (
 		irReqBuffer    *IrReqBuffer
 		safety         *SafetyModule
 		blockStore     *storage.BlockStore
+		certArchive    *storage.CertificateArchive // optional
 		orchestration  Orchestration
 		irReqVerifier  *IRChangeReqVerifier
 		t2Timeouts     *PartitionTimeoutGenerator
@@ -95,6 +109,10 @@ type (
 		voteBuffer map[string]*abdrc.VoteMsg
 		// whether the CM is in recovery mode, trying to get into the same state as other CMs
 		recovery *recoveryState
+		// time source and selection of peers to send recovery requests to,
+		// replaceable for deterministic simulations
+		now                 func() time.Time
+		selectRecoveryPeers func(signatures map[string]hex.Bytes, count int) []peer.ID
 
 		log    *slog.Logger
 		tracer trace.Tracer
@@ -166,25 +184,28 @@ func NewConsensusManager(
 		return nil, fmt.Errorf("failed to create consensus leader selector: %w", err)
 	}
 	consensusManager := &ConsensusManager{
-		certReqCh:      make(chan certRequest),
-		certResultCh:   make(chan *certification.CertificationResponse),
-		ucSink:         make(chan []*certification.CertificationResponse, 1),
-		params:         cParams,
-		id:             nodeID,
-		net:            net,
-		pacemaker:      pm,
-		leaderSelector: ls,
-		trustBase:      trustBase,
-		irReqBuffer:    NewIrReqBuffer(log),
-		safety:         safetyModule,
-		blockStore:     bStore,
-		orchestration:  orchestration,
-		irReqVerifier:  reqVerifier,
-		t2Timeouts:     t2TimeoutGen,
-		voteBuffer:     make(map[string]*abdrc.VoteMsg),
-		recovery:       &recoveryState{},
-		log:            log,
-		tracer:         observe.Tracer("cm.distributed"),
+		certReqCh:           make(chan certRequest),
+		certResultCh:        make(chan *certification.CertificationResponse),
+		ucSink:              make(chan []*certification.CertificationResponse, 1),
+		params:              cParams,
+		id:                  nodeID,
+		net:                 net,
+		pacemaker:           pm,
+		leaderSelector:      ls,
+		trustBase:           trustBase,
+		irReqBuffer:         NewIrReqBuffer(log),
+		safety:              safetyModule,
+		blockStore:          bStore,
+		certArchive:         optional.CertificateArchive,
+		orchestration:       orchestration,
+		irReqVerifier:       reqVerifier,
+		t2Timeouts:          t2TimeoutGen,
+		voteBuffer:          make(map[string]*abdrc.VoteMsg),
+		recovery:            &recoveryState{now: time.Now},
+		now:                 time.Now,
+		selectRecoveryPeers: selectRandomNodeIdsFromSignatureMap,
+		log:                 log,
+		tracer:              observe.Tracer("cm.distributed"),
 	}
 	if err := consensusManager.initMetrics(observe); err != nil {
 		return nil, fmt.Errorf("initializing metrics: %w", err)
@@ -355,8 +376,9 @@ func (x *ConsensusManager) loop(ctx context.Context) error {
 			if !ok {
 				return fmt.Errorf("root network received channel has been closed")
 			}
+			msgCtx, msg := network.UnwrapMsg(ctx, msg)
 			x.log.LogAttrs(ctx, logger.LevelTrace, fmt.Sprintf("received %T", msg), logger.Data(msg))
-			if err := x.handleRootNetMsg(ctx, msg); err != nil {
+			if err := x.handleRootNetMsg(msgCtx, msg); err != nil {
 				x.log.WarnContext(ctx, fmt.Sprintf("processing %T", msg), logger.Error(err))
 			}
 		case req := <-x.certReqCh:
@@ -375,7 +397,7 @@ handleRootNetMsg routes messages from "root net" iow messages sent by other root
 validators to appropriate message handler.
 */
 func (x *ConsensusManager) handleRootNetMsg(ctx context.Context, msg any) (rErr error) {
-	ctx, span := x.tracer.Start(ctx, "ConsensusManager.handleRootNetMsg", trace.WithNewRoot(), trace.WithAttributes(observability.Round(x.pacemaker.GetCurrentRound())), trace.WithSpanKind(trace.SpanKindServer))
+	ctx, span := x.tracer.Start(ctx, "ConsensusManager.handleRootNetMsg", trace.WithAttributes(observability.Round(x.pacemaker.GetCurrentRound())), trace.WithSpanKind(trace.SpanKindServer))
 	defer func(start time.Time) {
 		if rErr != nil {
 			span.RecordError(rErr)
@@ -503,7 +525,7 @@ func (x *ConsensusManager) onPartitionIRChangeReq(ctx context.Context, req *IRCh
 		Author:      x.id.String(),
 		IrChangeReq: irReq,
 	}
-	if err := x.safety.Sign(irMsg); err != nil {
+	if err := x.safety.SignIRChangeReq(irMsg, x.pacemaker.GetCurrentRound()); err != nil {
 		return fmt.Errorf("failed to sign IR change request from partition %s: %w", irReq.Partition, err)
 	}
 	if err := x.net.Send(ctx, irMsg, nextLeader); err != nil {
@@ -761,6 +783,7 @@ func (x *ConsensusManager) processQC(ctx context.Context, qc *drctypes.QuorumCer
 		}
 		return
 	}
+	x.archiveCertificates(ctx, certs)
 	select {
 	case <-ctx.Done():
 		return // node is exiting certificates have been stored and we are done
@@ -779,6 +802,17 @@ func (x *ConsensusManager) processQC(ctx context.Context, qc *drctypes.QuorumCer
 	}
 }
 
+// archiveCertificates stores the committed certificates in the certificate archive (when enabled).
+func (x *ConsensusManager) archiveCertificates(ctx context.Context, certs []*certification.CertificationResponse) {
+	if x.certArchive == nil {
+		return
+	}
+	// the archive is not needed for consensus, failing to store the certificates is not fatal
+	if err := x.certArchive.Add(certs); err != nil {
+		x.log.WarnContext(ctx, "archiving certificates", logger.Error(err))
+	}
+}
+
 // processTC - handles timeout certificate
 func (x *ConsensusManager) processTC(ctx context.Context, tc *drctypes.TimeoutCert) {
 	_, span := x.tracer.Start(ctx, "ConsensusManager.processTC")
@@ -859,8 +893,9 @@ func (x *ConsensusManager) replayVoteBuffer(ctx context.Context) {
 
 	x.log.DebugContext(ctx, fmt.Sprintf("replaying %d buffered votes", voteCnt))
 	var errs []error
-	for _, v := range x.voteBuffer {
-		if err := x.onVoteMsg(ctx, v); err != nil {
+	// replay in the order of authors so that the outcome doesn't depend on map iteration order
+	for _, author := range slices.Sorted(maps.Keys(x.voteBuffer)) {
+		if err := x.onVoteMsg(ctx, x.voteBuffer[author]); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -904,7 +939,7 @@ func (x *ConsensusManager) processNewRoundEvent(ctx context.Context) {
 			Author:    x.id.String(),
 			Round:     round,
 			Epoch:     0,
-			Timestamp: types.NewTimestamp(),
+			Timestamp: uint64(x.now().Unix()), /* #nosec G115 its unlikely that Unix time exceeds uint64 */
 			Payload:   x.irReqBuffer.GeneratePayload(round, timedOutShards, x.blockStore.IsChangeInProgress),
 			Qc:        x.blockStore.GetHighQc(),
 		},
@@ -976,13 +1011,15 @@ func (x *ConsensusManager) onStateResponse(ctx context.Context, rsp *abdrc.State
 	for i, block := range rsp.Pending {
 		// if received block has QC then process it first as with a block received normally
 		if block.Qc != nil {
-			if _, err = blockStore.ProcessQc(block.Qc); err != nil {
+			certs, err := blockStore.ProcessQc(block.Qc)
+			if err != nil {
 				if i != 0 || !errors.Is(err, storage.ErrCommitFailed) {
 					// since history is only kept until the last committed round it is not possible to commit a previous round
 					return fmt.Errorf("block %d for round %v add qc failed: %w", i, block.GetRound(), err)
 				}
 				x.log.DebugContext(ctx, "processing QC from recovery block", logger.Error(err))
 			}
+			x.archiveCertificates(ctx, certs)
 			x.pacemaker.AdvanceRoundQC(ctx, block.Qc)
 		}
 		if _, err = blockStore.Add(block, reqVerifier); err != nil {
@@ -1061,7 +1098,7 @@ func (x *ConsensusManager) sendRecoveryRequests(ctx context.Context, triggerMsg
 	x.recoveryReq.Add(ctx, 1)
 
 	if err = x.net.Send(ctx, &abdrc.StateRequestMsg{NodeId: x.id.String()},
-		selectRandomNodeIdsFromSignatureMap(signatures, 2)...); err != nil {
+		x.selectRecoveryPeers(signatures, 2)...); err != nil {
 		return fmt.Errorf("failed to send recovery request: %w", err)
 	}
 	return nil
@@ -1124,6 +1161,25 @@ func (x *ConsensusManager) GetState() (*abdrc.StateMsg, error) {
 	return x.blockStore.GetState()
 }
 
+// Status returns the sync status and role of the node.
+func (x *ConsensusManager) Status() *Status {
+	currentRound := x.pacemaker.GetCurrentRound()
+	status := &Status{
+		CurrentRound:  currentRound,
+		InRecovery:    x.recovery.InRecovery(),
+		RecoveryRound: x.recovery.ToRound(),
+		IsLeader:      x.leaderSelector.GetLeaderForRound(currentRound) == x.id,
+		RootNodeCount: len(x.leaderSelector.GetNodes()),
+	}
+	if cb := x.blockStore.CommittedBlock(); cb != nil {
+		status.CommittedRound = cb.GetRound()
+		if cb.CommitQc != nil && cb.CommitQc.LedgerCommitInfo != nil {
+			status.CommitTime = time.Unix(int64(cb.CommitQc.LedgerCommitInfo.Timestamp), 0)
+		}
+	}
+	return status
+}
+
 // "constant" (ie without variable part) attribute sets for observability
 var (
 	attrSetQCVoteStale = metric.WithAttributeSet(attribute.NewSet(attribute.String("reason", "stale")))
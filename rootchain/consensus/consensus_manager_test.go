@@ -588,6 +588,27 @@ func TestGetState_WithShards(t *testing.T) {
 	require.Len(t, stateMsg.CommittedHead.ShardInfo, 1)
 }
 
+func Test_ConsensusManager_Status(t *testing.T) {
+	mockNet := testnetwork.NewRootMockNetwork()
+	cm, _, _ := initConsensusManager(t, mockNet)
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go func() { require.ErrorIs(t, cm.Run(ctx), context.Canceled) }()
+
+	// the only root node is the leader of every round
+	require.Eventually(t, func() bool {
+		return cm.Status().CurrentRound > 1
+	}, test.WaitDuration, test.WaitTick)
+	status := cm.Status()
+	require.True(t, status.IsLeader)
+	require.False(t, status.InRecovery)
+	require.Equal(t, 1, status.RootNodeCount)
+	// genesis block is committed
+	require.EqualValues(t, 1, status.CommittedRound)
+	require.False(t, status.CommitTime.IsZero())
+}
+
 func Test_ConsensusManager_onVoteMsg(t *testing.T) {
 	t.Parallel()
 
@@ -867,7 +888,7 @@ func Test_ConsensusManager_messages(t *testing.T) {
 				Requests:   buildBlockCertificationRequest(t, shardNodes[0:2], si.LastCR),
 			},
 		}
-		require.NoError(t, cmOther.safety.Sign(irChReqMsg))
+		require.NoError(t, cmOther.safety.SignIRChangeReq(irChReqMsg, cmOther.pacemaker.GetCurrentRound()))
 		require.NoError(t, cmOther.net.Send(ctx, irChReqMsg, cmLeader.id))
 
 		// IRCR must be included into broadcast proposal, either this or next round
@@ -921,7 +942,7 @@ func Test_ConsensusManager_messages(t *testing.T) {
 				Requests:   buildBlockCertificationRequest(t, shardNodes[0:2], nil),
 			},
 		}
-		require.NoError(t, cmLeader.safety.Sign(irChReqMsg))
+		require.NoError(t, cmLeader.safety.SignIRChangeReq(irChReqMsg, cmLeader.pacemaker.GetCurrentRound()))
 		rootNet.Send(irChReqMsg, nonLeaderNode.id)
 
 		// non-leader is not the next leader and must forward the request to the leader node
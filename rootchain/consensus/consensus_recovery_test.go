@@ -97,8 +97,9 @@ func Test_ConsensusManager_sendRecoveryRequests(t *testing.T) {
 			id:  nodeID,
 			net: nw.Connect(nodeID),
 			// init the sent time so is is older than limit
-			recovery: &recoveryState{triggerMsg: toMsg, toRound: toMsg.Timeout.GetHqcRound(), sent: time.Now().Add(-statusReqShelfLife)},
-			tracer:   tracer,
+			recovery:            &recoveryState{triggerMsg: toMsg, toRound: toMsg.Timeout.GetHqcRound(), sent: time.Now().Add(-statusReqShelfLife)},
+			selectRecoveryPeers: selectRandomNodeIdsFromSignatureMap,
+			tracer:              tracer,
 		}
 		require.NoError(t, cm.initMetrics(observe))
 
@@ -138,7 +139,7 @@ func Test_ConsensusManager_sendRecoveryRequests(t *testing.T) {
 		nodeID, _, _, _ := generatePeerData(t)
 		authID, _, _, _ := generatePeerData(t)
 		nw := newMockNetwork(t)
-		cm := &ConsensusManager{id: nodeID, net: nw.Connect(nodeID), tracer: tracer, recovery: &recoveryState{}}
+		cm := &ConsensusManager{id: nodeID, net: nw.Connect(nodeID), tracer: tracer, recovery: &recoveryState{}, selectRecoveryPeers: selectRandomNodeIdsFromSignatureMap}
 		require.NoError(t, cm.initMetrics(observe))
 
 		// single signature by the author so only that node should receive the request
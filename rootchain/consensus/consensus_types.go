@@ -3,6 +3,8 @@ package consensus
 import (
 	"crypto"
 	"time"
+
+	"github.com/unicitynetwork/bft-core/rootchain/consensus/storage"
 )
 
 const (
@@ -22,7 +24,8 @@ type (
 	}
 	// Optional are common optional parameters for consensus managers
 	Optional struct {
-		Params *Parameters
+		Params             *Parameters
+		CertificateArchive *storage.CertificateArchive
 	}
 
 	Option func(c *Optional)
@@ -42,6 +45,13 @@ func WithConsensusParams(params Parameters) Option {
 	}
 }
 
+// WithCertificateArchive stores the certificates committed by the consensus manager in the archive.
+func WithCertificateArchive(archive *storage.CertificateArchive) Option {
+	return func(c *Optional) {
+		c.CertificateArchive = archive
+	}
+}
+
 func LoadConf(opts []Option) (*Optional, error) {
 	conf := &Optional{}
 	for _, opt := range opts {
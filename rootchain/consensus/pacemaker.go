@@ -45,6 +45,9 @@ type Pacemaker struct {
 	status         atomic.Uint32
 	statusChan     chan paceMakerStatus
 	stopRoundClock context.CancelFunc
+	// starts the round clock, by default startRoundClock which uses wall clock
+	// time, simulations replace it to run the Pacemaker on virtual time
+	roundClock func(ctx context.Context, minRoundLen, maxRoundLen time.Duration) <-chan struct{}
 
 	tracer   trace.Tracer
 	roundDur metric.Float64Histogram
@@ -68,6 +71,7 @@ func NewPacemaker(minRoundLen, maxRoundLen time.Duration, observe Observability)
 		stopRoundClock: func() { /* init as NOP */ },
 		tracer:         observe.Tracer("pacemaker"),
 	}
+	pm.roundClock = pm.startRoundClock
 
 	var err error
 	m := observe.Meter("pacemaker")
@@ -254,7 +258,7 @@ func (x *Pacemaker) startNewRound(ctx context.Context, round uint64) {
 	x.currentRound.Store(round)
 
 	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
-	stopped := x.startRoundClock(ctx, x.minRoundLen, x.maxRoundLen)
+	stopped := x.roundClock(ctx, x.minRoundLen, x.maxRoundLen)
 	x.stopRoundClock = func() {
 		cancel()
 		<-stopped
@@ -18,6 +18,15 @@ type recoveryState struct {
 	triggerMsg any
 	sent       time.Time // when the status request was created/sent
 	m          sync.Mutex
+	// time source, when nil time.Now is used
+	now func() time.Time
+}
+
+func (rs *recoveryState) timeNow() time.Time {
+	if rs.now != nil {
+		return rs.now()
+	}
+	return time.Now()
 }
 
 func (rs *recoveryState) InRecovery() bool {
@@ -49,13 +58,13 @@ func (rs *recoveryState) Set(trigger any) (map[string]hex.Bytes, error) {
 	rs.m.Lock()
 	defer rs.m.Unlock()
 
-	if rs.triggerMsg != nil && rs.toRound >= toRound && time.Since(rs.sent) < statusReqShelfLife {
+	if rs.triggerMsg != nil && rs.toRound >= toRound && rs.timeNow().Sub(rs.sent) < statusReqShelfLife {
 		return nil, fmt.Errorf("already in recovery to round %d, ignoring request to recover to round %d", rs.toRound, toRound)
 	}
 
 	rs.triggerMsg = trigger
 	rs.toRound = toRound
-	rs.sent = time.Now()
+	rs.sent = rs.timeNow()
 	return signatures, nil
 }
 
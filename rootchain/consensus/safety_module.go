@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/unicitynetwork/bft-core/network/protocol/abdrc"
+	"github.com/unicitynetwork/bft-core/remotesigner"
 	drctypes "github.com/unicitynetwork/bft-core/rootchain/consensus/types"
 	"github.com/unicitynetwork/bft-go-base/crypto"
 	"github.com/unicitynetwork/bft-go-base/types"
@@ -12,11 +13,12 @@ import (
 
 type (
 	SafetyModule struct {
-		network  types.NetworkID
-		peerID   string
-		signer   crypto.Signer
-		verifier crypto.Verifier
-		storage  SafetyStorage
+		network   types.NetworkID
+		peerID    string
+		signer    crypto.Signer
+		verifier  crypto.Verifier
+		storage   SafetyStorage
+		watermark uint64 // nothing is signed for the round or earlier, see SafetyStorage.GetSafetyWatermark
 	}
 
 	Signable interface {
@@ -29,6 +31,9 @@ type (
 		SetHighestVotedRound(uint64) error
 		GetHighestQcRound() uint64
 		SetHighestQcRound(qcRound, votedRound uint64) error
+		// GetSafetyWatermark returns the highest voted round imported from the previous
+		// storage of the validator, zero when the state has not been imported.
+		GetSafetyWatermark() (uint64, error)
 	}
 )
 
@@ -42,7 +47,25 @@ func NewSafetyModule(network types.NetworkID, id string, signer crypto.Signer, d
 		return nil, fmt.Errorf("invalid root validator signing key: %w", err)
 	}
 
-	return &SafetyModule{network: network, peerID: id, signer: signer, verifier: ver, storage: db}, nil
+	// the imported voted round can't be lost, the storage must be inconsistent
+	watermark, err := db.GetSafetyWatermark()
+	if err != nil {
+		return nil, err
+	}
+	if watermark > 0 {
+		if hvr := db.GetHighestVotedRound(); hvr < watermark {
+			return nil, fmt.Errorf("highest voted round %d is below the imported safety watermark %d", hvr, watermark)
+		}
+	}
+	return &SafetyModule{network: network, peerID: id, signer: signer, verifier: ver, storage: db, watermark: watermark}, nil
+}
+
+// isAboveWatermark returns error when the round is not above the imported safety watermark.
+func (s *SafetyModule) isAboveWatermark(round uint64) error {
+	if round <= s.watermark {
+		return fmt.Errorf("round %d is not above the imported safety watermark %d", round, s.watermark)
+	}
+	return nil
 }
 
 func (s *SafetyModule) isSafeToVote(block *drctypes.BlockData, lastRoundTC *drctypes.TimeoutCert) error {
@@ -50,6 +73,9 @@ func (s *SafetyModule) isSafeToVote(block *drctypes.BlockData, lastRoundTC *drct
 		return fmt.Errorf("block is nil")
 	}
 	blockRound := block.Round
+	if err := s.isAboveWatermark(blockRound); err != nil {
+		return err
+	}
 	// never vote for the same round twice
 	if hvr := s.storage.GetHighestVotedRound(); blockRound <= hvr {
 		return fmt.Errorf("already voted for round %d, last voted round %d", blockRound, hvr)
@@ -129,7 +155,7 @@ func (s *SafetyModule) MakeVote(block *drctypes.BlockData, execStateID []byte, h
 		Author:           s.peerID,
 	}
 	// signs commit info hash
-	if err := voteMsg.Sign(s.signer); err != nil {
+	if err := voteMsg.Sign(remotesigner.ForMessage(s.signer, remotesigner.KindRootVote, votingRound, voteMsg)); err != nil {
 		return nil, err
 	}
 	return voteMsg, nil
@@ -149,14 +175,31 @@ func (s *SafetyModule) SignTimeout(tmoVote *abdrc.TimeoutMsg, lastRoundTC *drcty
 		return fmt.Errorf("storing voted round: %w", err)
 	}
 	// Sign timeout
-	return tmoVote.Sign(s.signer)
+	return tmoVote.Sign(remotesigner.ForMessage(s.signer, remotesigner.KindRootTimeout, round, tmoVote))
 }
 
 func (s *SafetyModule) Sign(msg Signable) error {
+	// remote signer needs to know what is signed for its double-sign protection
+	if m, ok := msg.(*abdrc.ProposalMsg); ok {
+		if err := s.isAboveWatermark(m.Block.GetRound()); err != nil {
+			return fmt.Errorf("not safe to propose, %w", err)
+		}
+		return msg.Sign(remotesigner.ForMessage(s.signer, remotesigner.KindRootProposal, m.Block.GetRound(), m))
+	}
 	return msg.Sign(s.signer)
 }
 
+// SignIRChangeReq signs the IR change request which is forwarded to the leader in the round.
+func (s *SafetyModule) SignIRChangeReq(msg *abdrc.IrChangeReqMsg, round uint64) error {
+	return msg.Sign(remotesigner.ForMessage(s.signer, remotesigner.KindIRChangeRequest, round, msg))
+}
+
 func (s *SafetyModule) isSafeToTimeout(round, tmoHighQCRound uint64, lastRoundTC *drctypes.TimeoutCert) error {
+	// timeout for the last voted round is allowed below, but what was signed for
+	// the imported round is not known (the vote is not imported)
+	if err := s.isAboveWatermark(round); err != nil {
+		return err
+	}
 	if hqc := s.storage.GetHighestQcRound(); tmoHighQCRound < hqc {
 		// respect highest qc round
 		return fmt.Errorf("timeout high qc round %d is smaller than highest qc round %d seen", tmoHighQCRound, hqc)
@@ -1,12 +1,15 @@
 package consensus
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/unicitynetwork/bft-core/network/protocol/abdrc"
+	"github.com/unicitynetwork/bft-core/remotesigner"
 	drctypes "github.com/unicitynetwork/bft-core/rootchain/consensus/types"
 	abcrypto "github.com/unicitynetwork/bft-go-base/crypto"
 	"github.com/unicitynetwork/bft-go-base/types"
@@ -262,7 +265,7 @@ func TestSafetyModule_MakeVote(t *testing.T) {
 }
 
 func TestSafetyModule_SignProposal(t *testing.T) {
-	s := initSafetyModule(t, "node1", nil)
+	s := initSafetyModule(t, "node1", mockSafetyStorage{})
 	// create a dummy proposal message
 	proposal := &abdrc.ProposalMsg{
 		Block: &drctypes.BlockData{
@@ -530,6 +533,7 @@ type mockSafetyStorage struct {
 	setHighestVotedRound func(uint64) error
 	getHighestQcRound    func() uint64
 	setHighestQcRound    func(qcRound, votedRound uint64) error
+	getSafetyWatermark   func() (uint64, error)
 }
 
 func (m mockSafetyStorage) GetHighestVotedRound() uint64 { return m.getHighestVotedRound() }
@@ -543,3 +547,110 @@ func (m mockSafetyStorage) GetHighestQcRound() uint64 { return m.getHighestQcRou
 func (m mockSafetyStorage) SetHighestQcRound(qcRound, votedRound uint64) error {
 	return m.setHighestQcRound(qcRound, votedRound)
 }
+
+func (m mockSafetyStorage) GetSafetyWatermark() (uint64, error) {
+	if m.getSafetyWatermark == nil {
+		return 0, nil
+	}
+	return m.getSafetyWatermark()
+}
+
+// messageSignerMock records the kinds and rounds of the signed messages
+type messageSignerMock struct {
+	abcrypto.Signer
+	signed []string
+}
+
+func (s *messageSignerMock) ForMessage(kind remotesigner.Kind, round uint64, msg any) abcrypto.Signer {
+	s.signed = append(s.signed, fmt.Sprintf("%s:%d:%T", kind, round, msg))
+	return s.Signer
+}
+
+func TestSafetyModule_messageSigner(t *testing.T) {
+	signer, err := abcrypto.NewInMemorySecp256K1Signer()
+	require.NoError(t, err)
+	rs := &messageSignerMock{Signer: signer}
+	var highVR uint64
+	db := mockSafetyStorage{
+		getHighestVotedRound: func() uint64 { return highVR },
+		getHighestQcRound:    func() uint64 { return 3 },
+		setHighestVotedRound: func(round uint64) error { highVR = round; return nil },
+		setHighestQcRound:    func(_, votedRound uint64) error { highVR = votedRound; return nil },
+	}
+	s, err := NewSafetyModule(types.NetworkLocal, "node1", rs, db)
+	require.NoError(t, err)
+
+	qc, err := newQuorumCertificate(t, NewDummyVoteInfo(3, []byte{0, 1, 2, 3}), nil)
+	require.NoError(t, err)
+	qc.Signatures = map[string]hex.Bytes{"1": {1, 2}, "2": {1, 2}, "3": {1, 2}}
+	block := &drctypes.BlockData{Author: "node1", Round: 4, Payload: &drctypes.Payload{}, Qc: qc}
+
+	require.NoError(t, s.Sign(&abdrc.ProposalMsg{Block: block}))
+	_, err = s.MakeVote(block, []byte{1, 2, 3}, qc, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.SignTimeout(abdrc.NewTimeoutMsg(drctypes.NewTimeout(4, 0, qc), "node1", nil), nil))
+	irReq := &abdrc.IrChangeReqMsg{
+		Author:      "node1",
+		IrChangeReq: &drctypes.IRChangeReq{Partition: 1, CertReason: drctypes.Quorum},
+	}
+	require.NoError(t, s.SignIRChangeReq(irReq, 5))
+
+	require.Equal(t, []string{
+		"root-proposal:4:*abdrc.ProposalMsg",
+		"root-vote:4:*abdrc.VoteMsg",
+		"root-timeout:4:*abdrc.TimeoutMsg",
+		"ir-change-request:5:*abdrc.IrChangeReqMsg",
+	}, rs.signed)
+}
+
+func TestSafetyModule_watermark(t *testing.T) {
+	signer, err := abcrypto.NewInMemorySecp256K1Signer()
+	require.NoError(t, err)
+	var highVR, highQCR uint64 = 2, 1
+	db := mockSafetyStorage{
+		getHighestVotedRound: func() uint64 { return highVR },
+		getHighestQcRound:    func() uint64 { return highQCR },
+		setHighestVotedRound: func(round uint64) error { highVR = round; return nil },
+		setHighestQcRound:    func(qcRound, votedRound uint64) error { highQCR, highVR = qcRound, votedRound; return nil },
+		getSafetyWatermark:   func() (uint64, error) { return 4, nil },
+	}
+
+	// the watermark must be readable
+	failingDB := db
+	failingDB.getSafetyWatermark = func() (uint64, error) { return 0, errors.New("read failed") }
+	_, err = NewSafetyModule(types.NetworkLocal, "node1", signer, failingDB)
+	require.EqualError(t, err, "read failed")
+
+	// storage must not be behind the imported state
+	_, err = NewSafetyModule(types.NetworkLocal, "node1", signer, db)
+	require.EqualError(t, err, "highest voted round 2 is below the imported safety watermark 4")
+
+	highVR = 4
+	s, err := NewSafetyModule(types.NetworkLocal, "node1", signer, db)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, s.watermark)
+
+	qc, err := newQuorumCertificate(t, NewDummyVoteInfo(3, []byte{0, 1, 2, 3}), nil)
+	require.NoError(t, err)
+	qc.Signatures = map[string]hex.Bytes{"1": {1, 2}, "2": {1, 2}, "3": {1, 2}}
+	block := &drctypes.BlockData{Author: "node1", Round: 4, Payload: &drctypes.Payload{}, Qc: qc}
+
+	// nothing is signed for the imported round
+	require.EqualError(t, s.Sign(&abdrc.ProposalMsg{Block: block}),
+		"not safe to propose, round 4 is not above the imported safety watermark 4")
+	_, err = s.MakeVote(block, []byte{1, 2, 3}, qc, nil)
+	require.EqualError(t, err, "not safe to vote, round 4 is not above the imported safety watermark 4")
+	require.EqualError(t, s.SignTimeout(abdrc.NewTimeoutMsg(drctypes.NewTimeout(4, 0, qc), "node1", nil), nil),
+		"not safe to time-out, round 4 is not above the imported safety watermark 4")
+
+	// the next round is fine
+	highQCR = 3
+	qc, err = newQuorumCertificate(t, NewDummyVoteInfo(4, []byte{0, 1, 2, 3}), nil)
+	require.NoError(t, err)
+	qc.Signatures = map[string]hex.Bytes{"1": {1, 2}, "2": {1, 2}, "3": {1, 2}}
+	block = &drctypes.BlockData{Author: "node1", Round: 5, Payload: &drctypes.Payload{}, Qc: qc}
+	require.NoError(t, s.Sign(&abdrc.ProposalMsg{Block: block}))
+	_, err = s.MakeVote(block, []byte{1, 2, 3}, qc, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.SignTimeout(abdrc.NewTimeoutMsg(drctypes.NewTimeout(5, 0, qc), "node1", nil), nil))
+}
@@ -0,0 +1,637 @@
+package consensus
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"fmt"
+	"maps"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	p2pcrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	testobservability "github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	"github.com/unicitynetwork/bft-core/network/protocol/abdrc"
+	"github.com/unicitynetwork/bft-core/network/protocol/certification"
+	"github.com/unicitynetwork/bft-core/rootchain/consensus/storage"
+	drctypes "github.com/unicitynetwork/bft-core/rootchain/consensus/types"
+	"github.com/unicitynetwork/bft-core/rootchain/partitions"
+	abcrypto "github.com/unicitynetwork/bft-go-base/crypto"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+/*
+simulation runs a set of ConsensusManagers on virtual time with in-memory
+message bus. CMs are not "Run", instead the simulation calls message and
+pacemaker event handlers of the CMs one event at a time from the goroutine
+calling "step", so the order of events is determined by the PRNG seed only
+(the message delivery delays and drops are random).
+
+After every step safety invariants are checked:
+  - no two conflicting commits (different state hash committed for the same root round);
+  - highest voted round of the SafetyModule of every node never decreases;
+  - no node signs two different votes for the same round.
+*/
+type simulation struct {
+	t     *testing.T
+	ctx   context.Context
+	rnd   *rand.Rand
+	now   time.Duration // virtual time since the start of the simulation
+	seq   uint64
+	queue simEventQueue
+	nodes map[peer.ID]*simNode
+	ids   []peer.ID
+
+	// network conditions
+	dropRate float64       // probability of dropping a message
+	minDelay time.Duration // minimum message delivery delay
+	maxDelay time.Duration // message delivery delay is random in the range [minDelay, maxDelay)
+	firewall fwFunc        // when returns true the message is dropped
+
+	// state for invariant checks
+	commits map[uint64][]byte // root round -> committed state hash
+	// highest committed round seen on the wire, UCs produced by the CMs are not
+	// taken into account as these are consumed asynchronously
+	committed uint64
+	votes     map[string]map[uint64][]byte // author -> round -> hash of the vote info
+	stats     simStats
+	trace     func(string)
+}
+
+type simStats struct {
+	steps     int
+	delivered int
+	dropped   int
+	msgErrors int
+}
+
+type simNode struct {
+	id    peer.ID
+	sim   *simulation
+	cm    *ConsensusManager
+	clock *simClock
+	// highest voted round seen by the previous invariant check
+	hvr uint64
+
+	m     sync.Mutex
+	certs []*certification.CertificationResponse // UCs produced by the CM, not yet checked
+}
+
+// simClock is the round clock of the node's Pacemaker for single round.
+type simClock struct {
+	minRoundLen, maxRoundLen time.Duration
+	stopped                  bool
+}
+
+type simEvent struct {
+	at    time.Duration
+	seq   uint64 // tie breaker for events scheduled at the same time
+	node  *simNode
+	msg   any       // message to deliver, nil for round clock events
+	clock *simClock // round clock which fired
+}
+
+type simEventQueue []*simEvent
+
+func (q simEventQueue) Len() int { return len(q) }
+
+func (q simEventQueue) Less(i, j int) bool {
+	if q[i].at == q[j].at {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].at < q[j].at
+}
+
+func (q simEventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *simEventQueue) Push(x any) { *q = append(*q, x.(*simEvent)) }
+
+func (q *simEventQueue) Pop() any {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return e
+}
+
+/*
+newSimulation creates simulation of "nodeCount" root validators, using "seed"
+for all random decisions. CMs have been initialized (as done by Run) but no
+event has been processed yet.
+*/
+func newSimulation(t *testing.T, nodeCount int, seed int64) *simulation {
+	t.Helper()
+	observe := testobservability.NOPObservability()
+
+	rnd := rand.New(rand.NewSource(seed))
+	rootSigners := make(map[string]abcrypto.Signer, nodeCount)
+	rootNodeInfos := make([]*types.NodeInfo, nodeCount)
+	for i := range nodeCount {
+		nodeInfo, signer := simNodeKeys(t, rnd)
+		rootSigners[nodeInfo.NodeID] = signer
+		rootNodeInfos[i] = nodeInfo
+	}
+	trustBase, err := types.NewTrustBaseGenesis(5, rootNodeInfos)
+	require.NoError(t, err)
+
+	shardNode, _ := simNodeKeys(t, rnd)
+	shardConf := &types.PartitionDescriptionRecord{
+		Version:         1,
+		NetworkID:       5,
+		PartitionID:     partitionID,
+		ShardID:         shardID,
+		PartitionTypeID: 999,
+		TypeIDLen:       8,
+		UnitIDLen:       256,
+		T2Timeout:       2500 * time.Millisecond,
+		Validators:      []*types.NodeInfo{shardNode},
+		Epoch:           0,
+		EpochStart:      1,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	sim := &simulation{
+		t:        t,
+		ctx:      ctx,
+		rnd:      rnd,
+		nodes:    make(map[peer.ID]*simNode, nodeCount),
+		minDelay: time.Millisecond,
+		maxDelay: 50 * time.Millisecond,
+		commits:  make(map[uint64][]byte),
+		votes:    make(map[string]map[uint64][]byte),
+	}
+
+	for _, v := range trustBase.GetRootNodes() {
+		nodeID, err := peer.Decode(v.NodeID)
+		require.NoError(t, err)
+
+		orchestration, err := partitions.NewOrchestration(5, filepath.Join(t.TempDir(), "orchestration.db"), observe.Logger())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = orchestration.Close() })
+		require.NoError(t, orchestration.AddShardConfig(shardConf))
+		rootDB := newSimStore()
+		require.NoError(t, rootDB.WriteBlock(newTestGenesisBlock(t, shardConf, rootSigners), true))
+		node := &simNode{id: nodeID, sim: sim}
+		cm, err := NewConsensusManager(nodeID, trustBase, orchestration, node, rootSigners[v.NodeID], rootDB, observe)
+		require.NoError(t, err)
+		cm.pacemaker.roundClock = node.startRoundClock
+		cm.now = sim.timeNow
+		cm.recovery.now = sim.timeNow
+		cm.selectRecoveryPeers = sim.selectPeers
+		node.cm = cm
+		sim.nodes[nodeID] = node
+		sim.ids = append(sim.ids, nodeID)
+	}
+
+	for _, id := range sim.ids {
+		node := sim.nodes[id]
+		go node.consumeCertificates(ctx)
+		// the same as Run does before entering the main loop
+		vote, err := node.cm.blockStore.ReadLastVote()
+		require.NoError(t, err)
+		lastTC, err := node.cm.blockStore.GetLastTC()
+		require.NoError(t, err)
+		node.cm.pacemaker.Reset(ctx, node.cm.blockStore.GetHighQc().GetRound(), lastTC, vote)
+		t.Cleanup(node.cm.pacemaker.Stop)
+		sim.processPacemakerEvents(node)
+	}
+	return sim
+}
+
+/*
+simNodeKeys generates node info and signing key for the simulated node, keys
+are derived from the simulation PRNG as leader election depends on node IDs
+and state hashes depend on shard validator keys.
+*/
+func simNodeKeys(t *testing.T, rnd *rand.Rand) (*types.NodeInfo, abcrypto.Signer) {
+	key := make([]byte, 32)
+	rnd.Read(key)
+	authKey, err := p2pcrypto.UnmarshalSecp256k1PrivateKey(key)
+	require.NoError(t, err)
+	nodeID, err := peer.IDFromPrivateKey(authKey)
+	require.NoError(t, err)
+
+	rnd.Read(key)
+	signer, err := abcrypto.NewInMemorySecp256K1SignerFromKey(key)
+	require.NoError(t, err)
+	verifier, err := signer.Verifier()
+	require.NoError(t, err)
+	sigKey, err := verifier.MarshalPublicKey()
+	require.NoError(t, err)
+	return &types.NodeInfo{NodeID: nodeID.String(), SigKey: sigKey, Stake: 1}, signer
+}
+
+/*
+step processes the next event in the queue and checks invariants.
+Returns false when there is no more events.
+*/
+func (s *simulation) step() bool {
+	if s.queue.Len() == 0 {
+		return false
+	}
+	e := heap.Pop(&s.queue).(*simEvent)
+	s.now = e.at
+	s.stats.steps++
+
+	if e.clock != nil {
+		s.tickClock(e.node, e.clock)
+	} else if err := e.node.cm.handleRootNetMsg(s.ctx, e.msg); err != nil {
+		s.stats.msgErrors++
+	}
+	s.processPacemakerEvents(e.node)
+	s.checkInvariants()
+	return true
+}
+
+/*
+runUntil processes events until "cond" returns true or "maxSteps" events have been processed.
+*/
+func (s *simulation) runUntil(maxSteps int, cond func() bool) bool {
+	for range maxSteps {
+		if cond() {
+			return true
+		}
+		if !s.step() {
+			break
+		}
+	}
+	return cond()
+}
+
+func (s *simulation) schedule(delay time.Duration, e *simEvent) {
+	s.seq++
+	e.at, e.seq = s.now+delay, s.seq
+	heap.Push(&s.queue, e)
+}
+
+/*
+tickClock does what the wall clock based Pacemaker.startRoundClock does when it's ticker fires.
+*/
+func (s *simulation) tickClock(node *simNode, clk *simClock) {
+	if clk.stopped {
+		return
+	}
+	pm := node.cm.pacemaker
+	switch paceMakerStatus(pm.status.Load()) {
+	case pmsRoundInProgress:
+		pm.setState(s.ctx, pmsRoundMatured)
+		s.schedule(clk.maxRoundLen-clk.minRoundLen, &simEvent{node: node, clock: clk})
+	case pmsRoundMatured, pmsRoundTimeout:
+		pm.setState(s.ctx, pmsRoundTimeout)
+		s.schedule(clk.maxRoundLen, &simEvent{node: node, clock: clk})
+	}
+}
+
+func (s *simulation) processPacemakerEvents(node *simNode) {
+	for {
+		select {
+		case event := <-node.cm.pacemaker.StatusEvents():
+			node.cm.handlePacemakerEvent(s.ctx, event)
+		default:
+			return
+		}
+	}
+}
+
+// send is called by the CMs to send messages to other nodes.
+func (s *simulation) send(from peer.ID, msg any, receivers []peer.ID) error {
+	if s.trace != nil {
+		s.trace(fmt.Sprintf("send %d %T %v", slices.Index(s.ids, from), msg, receivers))
+	}
+	s.observeMsg(from, msg)
+	data, err := types.Cbor.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshaling %T as CBOR: %w", msg, err)
+	}
+	for _, to := range receivers {
+		node, ok := s.nodes[to]
+		if !ok {
+			return fmt.Errorf("unknown receiver %s", to)
+		}
+		if (s.firewall != nil && s.firewall(from, to, msg)) || (s.dropRate > 0 && s.rnd.Float64() < s.dropRate) {
+			s.stats.dropped++
+			continue
+		}
+		// every receiver gets it's own copy of the message as with real network
+		cpy := reflect.New(reflect.TypeOf(msg).Elem()).Interface()
+		if err := types.Cbor.Unmarshal(data, cpy); err != nil {
+			return fmt.Errorf("unmarshaling %T: %w", msg, err)
+		}
+		delay := s.minDelay
+		if s.maxDelay > s.minDelay {
+			delay += time.Duration(s.rnd.Int63n(int64(s.maxDelay - s.minDelay)))
+		}
+		s.stats.delivered++
+		s.schedule(delay, &simEvent{node: node, msg: cpy})
+	}
+	return nil
+}
+
+/*
+observeMsg records commits and votes seen on the wire and fails the test
+when conflicting commit or vote is detected.
+*/
+func (s *simulation) observeMsg(from peer.ID, msg any) {
+	switch mt := msg.(type) {
+	case *abdrc.ProposalMsg:
+		s.observeQC(mt.Block.Qc)
+	case *abdrc.VoteMsg:
+		s.observeQC(mt.HighQc)
+		h, err := mt.VoteInfo.Hash(HashAlgorithm)
+		require.NoError(s.t, err)
+		votes, ok := s.votes[mt.Author]
+		if !ok {
+			votes = make(map[uint64][]byte)
+			s.votes[mt.Author] = votes
+		}
+		if prev, ok := votes[mt.VoteInfo.RoundNumber]; ok && !bytes.Equal(prev, h) {
+			s.t.Fatalf("node %s voted twice in round %d for different blocks", from, mt.VoteInfo.RoundNumber)
+		}
+		votes[mt.VoteInfo.RoundNumber] = h
+	case *abdrc.TimeoutMsg:
+		s.observeQC(mt.Timeout.HighQc)
+	}
+}
+
+func (s *simulation) observeQC(qc *drctypes.QuorumCert) {
+	if qc == nil || qc.LedgerCommitInfo == nil || qc.LedgerCommitInfo.RootChainRoundNumber == 0 {
+		return
+	}
+	s.observeCommit(qc.LedgerCommitInfo.RootChainRoundNumber, qc.LedgerCommitInfo.Hash)
+	s.committed = max(s.committed, qc.LedgerCommitInfo.RootChainRoundNumber)
+}
+
+func (s *simulation) observeCommit(round uint64, hash []byte) {
+	if prev, ok := s.commits[round]; ok {
+		if !bytes.Equal(prev, hash) {
+			s.t.Fatalf("conflicting commits for round %d: %X and %X", round, prev, hash)
+		}
+		return
+	}
+	s.commits[round] = hash
+}
+
+func (s *simulation) checkInvariants() {
+	for _, id := range s.ids {
+		node := s.nodes[id]
+		hvr := node.cm.safety.storage.GetHighestVotedRound()
+		if hvr < node.hvr {
+			s.t.Fatalf("node %s highest voted round decreased from %d to %d", id, node.hvr, hvr)
+		}
+		node.hvr = hvr
+
+		node.m.Lock()
+		certs := node.certs
+		node.certs = nil
+		node.m.Unlock()
+		for _, cr := range certs {
+			s.observeCommit(cr.UC.UnicitySeal.RootChainRoundNumber, cr.UC.UnicitySeal.Hash)
+		}
+	}
+}
+
+/*
+timeNow returns the virtual time of the simulation as wall clock time. Virtual
+time starts at genesis time as unicity seals with older timestamp are invalid.
+*/
+func (s *simulation) timeNow() time.Time {
+	return time.Unix(int64(types.GenesisTime), 0).Add(s.now)
+}
+
+/*
+selectPeers is the deterministic replacement of selectRandomNodeIdsFromSignatureMap.
+*/
+func (s *simulation) selectPeers(signatures map[string]hex.Bytes, count int) []peer.ID {
+	var ids []peer.ID
+	for _, k := range slices.Sorted(maps.Keys(signatures)) {
+		if id, err := peer.Decode(k); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	s.rnd.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	return ids[:min(count, len(ids))]
+}
+
+// committedRound returns the highest root round the simulation has seen committed.
+func (s *simulation) committedRound() uint64 {
+	return s.committed
+}
+
+/*
+startRoundClock is the virtual time replacement of Pacemaker.startRoundClock.
+*/
+func (n *simNode) startRoundClock(ctx context.Context, minRoundLen, maxRoundLen time.Duration) <-chan struct{} {
+	pm := n.cm.pacemaker
+	pm.status.Store(uint32(pmsRoundInProgress))
+	clk := &simClock{minRoundLen: minRoundLen, maxRoundLen: maxRoundLen}
+	n.clock = clk
+	n.sim.schedule(minRoundLen, &simEvent{node: n, clock: clk})
+
+	stopped := make(chan struct{})
+	context.AfterFunc(ctx, func() {
+		defer close(stopped)
+		clk.stopped = true
+		select {
+		case <-pm.statusChan:
+		default:
+		}
+		pm.status.Store(uint32(pmsRoundNone))
+	})
+	return stopped
+}
+
+/*
+consumeCertificates reads UCs produced by the CM, it must run in it's own
+goroutine as CM blocks on sending certificates otherwise.
+*/
+func (n *simNode) consumeCertificates(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case certs := <-n.cm.ucSink:
+			n.m.Lock()
+			n.certs = append(n.certs, certs...)
+			n.m.Unlock()
+		}
+	}
+}
+
+/*
+simStore is in-memory implementation of the PersistentStore, Bolt DB would
+make the simulation orders of magnitude slower because of the disk syncs.
+Data is stored CBOR encoded so that callers do not share the objects with store.
+*/
+type simStore struct {
+	blocks    map[uint64][]byte
+	tc        []byte
+	vote      any
+	votedR    uint64
+	highestQC uint64
+}
+
+func newSimStore() *simStore {
+	return &simStore{
+		blocks:    make(map[uint64][]byte),
+		votedR:    drctypes.GenesisRootRound,
+		highestQC: drctypes.GenesisRootRound,
+	}
+}
+
+func (db *simStore) LoadBlocks() ([]*storage.ExecutedBlock, error) {
+	blocks := make([]*storage.ExecutedBlock, 0, len(db.blocks))
+	for _, round := range slices.Backward(slices.Sorted(maps.Keys(db.blocks))) {
+		b := &storage.ExecutedBlock{}
+		if err := types.Cbor.Unmarshal(db.blocks[round], b); err != nil {
+			return nil, fmt.Errorf("loading block %d: %w", round, err)
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
+}
+
+func (db *simStore) WriteBlock(block *storage.ExecutedBlock, root bool) error {
+	data, err := types.Cbor.Marshal(block)
+	if err != nil {
+		return fmt.Errorf("serializing block: %w", err)
+	}
+	db.blocks[block.GetRound()] = data
+	if root {
+		maps.DeleteFunc(db.blocks, func(round uint64, _ []byte) bool { return round < block.GetRound() })
+	}
+	return nil
+}
+
+func (db *simStore) WriteVote(vote any) error {
+	data, err := types.Cbor.Marshal(vote)
+	if err != nil {
+		return fmt.Errorf("serializing vote: %w", err)
+	}
+	v := reflect.New(reflect.TypeOf(vote).Elem()).Interface()
+	if err := types.Cbor.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("deserializing vote: %w", err)
+	}
+	db.vote = v
+	return nil
+}
+
+func (db *simStore) ReadLastVote() (any, error) { return db.vote, nil }
+
+func (db *simStore) WriteTC(tc *drctypes.TimeoutCert) (err error) {
+	if db.tc, err = types.Cbor.Marshal(tc); err != nil {
+		return fmt.Errorf("serializing TimeoutCert: %w", err)
+	}
+	delete(db.blocks, tc.GetRound())
+	return nil
+}
+
+func (db *simStore) ReadLastTC() (tc *drctypes.TimeoutCert, _ error) {
+	if db.tc == nil {
+		return nil, nil
+	}
+	return tc, types.Cbor.Unmarshal(db.tc, &tc)
+}
+
+func (db *simStore) GetHighestVotedRound() uint64 { return db.votedR }
+
+func (db *simStore) SetHighestVotedRound(round uint64) error {
+	db.votedR = max(db.votedR, round)
+	return nil
+}
+
+func (db *simStore) GetHighestQcRound() uint64 { return db.highestQC }
+
+func (db *simStore) SetHighestQcRound(qcRound, votedRound uint64) error {
+	db.highestQC = max(db.highestQC, qcRound)
+	db.votedR = max(db.votedR, votedRound)
+	return nil
+}
+
+func (db *simStore) GetSafetyWatermark() (uint64, error) { return 0, nil }
+
+// Send implements RootNet
+func (n *simNode) Send(_ context.Context, msg any, receivers ...peer.ID) error {
+	return n.sim.send(n.id, msg, receivers)
+}
+
+// ReceivedChannel implements RootNet, messages are delivered by the simulation
+// calling CM's message handler directly so the channel is never used.
+func (n *simNode) ReceivedChannel() <-chan any {
+	return nil
+}
+
+func TestSimulation(t *testing.T) {
+	t.Run("no faults", func(t *testing.T) {
+		sim := newSimulation(t, 4, 1)
+		require.True(t, sim.runUntil(100_000, func() bool { return sim.committedRound() >= 200 }),
+			"committed round %d after %d steps", sim.committedRound(), sim.stats.steps)
+		// with no faults there should be no timeouts, ie round duration is round min len (block rate / 2)
+		require.Less(t, sim.now, 210*sim.nodes[sim.ids[0]].cm.pacemaker.maxRoundLen/10)
+		require.Zero(t, sim.stats.dropped)
+	})
+
+	t.Run("same seed, same execution", func(t *testing.T) {
+		run := func() (uint64, time.Duration) {
+			sim := newSimulation(t, 4, 42)
+			sim.dropRate = 0.1
+			sim.maxDelay = time.Second
+			require.True(t, sim.runUntil(100_000, func() bool { return sim.committedRound() >= 20 }))
+			return sim.committedRound(), sim.now
+		}
+		round, now := run()
+		round2, now2 := run()
+		require.Equal(t, round, round2)
+		require.Equal(t, now, now2)
+	})
+
+	t.Run("lossy network", func(t *testing.T) {
+		sim := newSimulation(t, 4, 2)
+		sim.dropRate = 0.2
+		sim.maxDelay = 2 * time.Second
+		require.True(t, sim.runUntil(200_000, func() bool { return sim.committedRound() >= 30 }),
+			"committed round %d after %d steps", sim.committedRound(), sim.stats.steps)
+		require.NotZero(t, sim.stats.dropped)
+	})
+
+	t.Run("one node isolated", func(t *testing.T) {
+		sim := newSimulation(t, 4, 3)
+		isolated := sim.ids[0]
+		sim.firewall = func(from, to peer.ID, msg any) bool { return from == isolated || to == isolated }
+		require.True(t, sim.runUntil(200_000, func() bool { return sim.committedRound() >= 20 }),
+			"committed round %d after %d steps", sim.committedRound(), sim.stats.steps)
+		// heal the network, isolated node must catch up
+		sim.firewall = nil
+		target := sim.committedRound() + 10
+		require.True(t, sim.runUntil(200_000, func() bool {
+			return sim.nodes[isolated].cm.blockStore.GetHighQc().GetRound() >= target
+		}), "isolated node high QC %d", sim.nodes[isolated].cm.blockStore.GetHighQc().GetRound())
+	})
+}
+
+/*
+FuzzSimulation runs root chain simulation with random network conditions, ie
+
+	go test ./rootchain/consensus/ -run=^$ -fuzz=FuzzSimulation
+*/
+func FuzzSimulation(f *testing.F) {
+	f.Add(int64(1), uint8(4), uint8(0), uint16(50))
+	f.Add(int64(2), uint8(4), uint8(30), uint16(1000))
+	f.Add(int64(3), uint8(7), uint8(60), uint16(3000))
+	f.Fuzz(func(t *testing.T, seed int64, nodeCount uint8, dropRate uint8, maxDelayMs uint16) {
+		sim := newSimulation(t, 1+int(nodeCount%7), seed)
+		sim.dropRate = float64(dropRate%80) / 100
+		sim.maxDelay = sim.minDelay + time.Duration(maxDelayMs)*time.Millisecond
+		sim.runUntil(5000, func() bool { return sim.committedRound() >= 50 })
+	})
+}
@@ -184,6 +184,11 @@ func (x *BlockStore) Add(block *rctypes.BlockData, verifier IRChangeReqVerifier)
 	return exeBlock.RootHash, nil
 }
 
+// CommittedBlock returns the latest committed block.
+func (x *BlockStore) CommittedBlock() *ExecutedBlock {
+	return x.blockTree.Root()
+}
+
 func (x *BlockStore) GetHighQc() *rctypes.QuorumCert {
 	return x.blockTree.HighQc()
 }
@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/unicitynetwork/bft-core/keyvaluedb"
+	"github.com/unicitynetwork/bft-core/network/protocol/certification"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+const (
+	// certificate of the shard, the root round is inverted so that the newest certificate comes first
+	archiveCertPrefix = 'c'
+	// shard round index, the value is the root round of the latest certificate of the shard round
+	archiveShardRoundPrefix = 's'
+	// root round index used for pruning, the value is the shard round of the certificate
+	archiveRootRoundPrefix = 'r'
+)
+
+var ErrCertificateNotFound = errors.New("certificate not found")
+
+/*
+CertificateArchive keeps the certification responses (UCs) issued by the root
+chain so that the UC of any past round of a shard can be queried, either by
+the shard round or by the root round.
+
+When a shard round is certified repeatedly (ie repeat UCs) the latest
+certificate of the round is returned by the shard round query.
+*/
+type CertificateArchive struct {
+	db         keyvaluedb.KeyValueDB
+	keepRounds uint64 // number of root rounds the certificates are kept for, 0 keeps all the certificates
+}
+
+// archivedCert identifies the certificate in the archive
+type archivedCert struct {
+	rootRound  uint64
+	shardRound uint64
+	partition  types.PartitionID
+	shard      types.ShardID
+}
+
+/*
+NewCertificateArchive returns the certificate archive backed by the given
+database. The certificates older than keepRounds root rounds are pruned when
+new certificates are added, when keepRounds is 0 the certificates are never
+pruned.
+*/
+func NewCertificateArchive(db keyvaluedb.KeyValueDB, keepRounds uint64) (*CertificateArchive, error) {
+	if db == nil {
+		return nil, errors.New("database is nil")
+	}
+	return &CertificateArchive{db: db, keepRounds: keepRounds}, nil
+}
+
+// Add stores the certificates committed by the root chain and prunes the expired certificates.
+func (a *CertificateArchive) Add(crs []*certification.CertificationResponse) (err error) {
+	if len(crs) == 0 {
+		return nil
+	}
+	var latestRootRound uint64
+	for _, cr := range crs {
+		latestRootRound = max(latestRootRound, cr.UC.GetRootRoundNumber())
+	}
+	// the iterator must be closed before the write transaction is started
+	expired, err := a.expired(latestRootRound)
+	if err != nil {
+		return fmt.Errorf("finding expired certificates: %w", err)
+	}
+
+	dbTx, err := a.db.StartTx()
+	if err != nil {
+		return fmt.Errorf("start DB transaction failed: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if e := dbTx.Rollback(); e != nil {
+				err = errors.Join(err, fmt.Errorf("archive transaction rollback failed: %w", e))
+			}
+		} else if e := dbTx.Commit(); e != nil {
+			err = fmt.Errorf("archive transaction commit failed: %w", e)
+		}
+	}()
+
+	for _, cr := range crs {
+		rootRound, shardRound := cr.UC.GetRootRoundNumber(), cr.UC.GetRoundNumber()
+		if err := dbTx.Write(archiveCertKey(cr.Partition, cr.Shard, rootRound), cr); err != nil {
+			return fmt.Errorf("writing certificate of %s - %s: %w", cr.Partition, cr.Shard, err)
+		}
+		if err := dbTx.Write(archiveShardRoundKey(cr.Partition, cr.Shard, shardRound), rootRound); err != nil {
+			return fmt.Errorf("writing shard round index: %w", err)
+		}
+		if err := dbTx.Write(archiveRootRoundKey(rootRound, cr.Partition, cr.Shard), shardRound); err != nil {
+			return fmt.Errorf("writing root round index: %w", err)
+		}
+	}
+	return a.prune(dbTx, expired)
+}
+
+// ByShardRound returns the certificate of the given shard round.
+func (a *CertificateArchive) ByShardRound(partition types.PartitionID, shard types.ShardID, round uint64) (*certification.CertificationResponse, error) {
+	var rootRound uint64
+	found, err := a.db.Read(archiveShardRoundKey(partition, shard, round), &rootRound)
+	if err != nil {
+		return nil, fmt.Errorf("reading shard round index: %w", err)
+	}
+	if !found {
+		return nil, ErrCertificateNotFound
+	}
+	cr := &certification.CertificationResponse{}
+	found, err = a.db.Read(archiveCertKey(partition, shard, rootRound), cr)
+	if err != nil {
+		return nil, fmt.Errorf("reading certificate: %w", err)
+	}
+	if !found {
+		return nil, ErrCertificateNotFound
+	}
+	return cr, nil
+}
+
+/*
+ByRootRound returns the latest certificate of the shard issued at or before the
+given root round, ie the certificate of the shard which was valid in the root round.
+*/
+func (a *CertificateArchive) ByRootRound(partition types.PartitionID, shard types.ShardID, rootRound uint64) (_ *certification.CertificationResponse, err error) {
+	it := a.db.Find(archiveCertKey(partition, shard, rootRound))
+	defer func() { err = errors.Join(err, it.Close()) }()
+
+	if !it.Valid() || !bytes.HasPrefix(it.Key(), archiveShardPrefix(archiveCertPrefix, partition, shard)) {
+		return nil, ErrCertificateNotFound
+	}
+	cr := &certification.CertificationResponse{}
+	if err := it.Value(cr); err != nil {
+		return nil, fmt.Errorf("reading certificate: %w", err)
+	}
+	return cr, nil
+}
+
+// expired returns the certificates issued before the root round latestRootRound-keepRounds.
+func (a *CertificateArchive) expired(latestRootRound uint64) (certs []archivedCert, err error) {
+	if a.keepRounds == 0 || latestRootRound <= a.keepRounds {
+		return nil, nil
+	}
+	it := a.db.Find([]byte{archiveRootRoundPrefix})
+	defer func() { err = errors.Join(err, it.Close()) }()
+
+	end := archiveRootRoundKey(latestRootRound-a.keepRounds, 0, types.ShardID{})
+	for ; it.Valid() && bytes.Compare(it.Key(), end) < 0; it.Next() {
+		var c archivedCert
+		if c.rootRound, c.partition, c.shard, err = parseArchiveRootRoundKey(it.Key()); err != nil {
+			return nil, err
+		}
+		if err = it.Value(&c.shardRound); err != nil {
+			return nil, fmt.Errorf("reading root round index: %w", err)
+		}
+		certs = append(certs, c)
+	}
+	return certs, nil
+}
+
+// prune deletes the given certificates.
+func (a *CertificateArchive) prune(dbTx keyvaluedb.DBTransaction, certs []archivedCert) error {
+	for _, c := range certs {
+		if err := dbTx.Delete(archiveRootRoundKey(c.rootRound, c.partition, c.shard)); err != nil {
+			return fmt.Errorf("deleting root round index: %w", err)
+		}
+		if err := dbTx.Delete(archiveCertKey(c.partition, c.shard, c.rootRound)); err != nil {
+			return fmt.Errorf("deleting certificate: %w", err)
+		}
+		// the shard round might have been certified again (repeat UC) in a later root round
+		key := archiveShardRoundKey(c.partition, c.shard, c.shardRound)
+		var rootRound uint64
+		if _, err := dbTx.Read(key, &rootRound); err != nil {
+			return fmt.Errorf("reading shard round index: %w", err)
+		}
+		if rootRound == c.rootRound {
+			if err := dbTx.Delete(key); err != nil {
+				return fmt.Errorf("deleting shard round index: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func archiveShardPrefix(prefix byte, partition types.PartitionID, shard types.ShardID) []byte {
+	shardID := shard.Bytes()
+	key := binary.BigEndian.AppendUint32([]byte{prefix}, uint32(partition))
+	key = append(key, byte(len(shardID)))
+	return append(key, shardID...)
+}
+
+func archiveCertKey(partition types.PartitionID, shard types.ShardID, rootRound uint64) []byte {
+	return binary.BigEndian.AppendUint64(archiveShardPrefix(archiveCertPrefix, partition, shard), math.MaxUint64-rootRound)
+}
+
+func archiveShardRoundKey(partition types.PartitionID, shard types.ShardID, shardRound uint64) []byte {
+	return binary.BigEndian.AppendUint64(archiveShardPrefix(archiveShardRoundPrefix, partition, shard), shardRound)
+}
+
+func archiveRootRoundKey(rootRound uint64, partition types.PartitionID, shard types.ShardID) []byte {
+	key := binary.BigEndian.AppendUint64([]byte{archiveRootRoundPrefix}, rootRound)
+	if partition == 0 {
+		return key
+	}
+	return append(key, archiveShardPrefix(archiveRootRoundPrefix, partition, shard)[1:]...)
+}
+
+func parseArchiveRootRoundKey(key []byte) (rootRound uint64, partition types.PartitionID, shard types.ShardID, err error) {
+	if len(key) < 14 || key[0] != archiveRootRoundPrefix || int(key[13]) != len(key)-14 {
+		return 0, 0, shard, fmt.Errorf("invalid root round index key %X", key)
+	}
+	rootRound = binary.BigEndian.Uint64(key[1:9])
+	partition = types.PartitionID(binary.BigEndian.Uint32(key[9:13]))
+	// shard ID has no decoder for its binary serialization
+	data, err := types.Cbor.Marshal(key[14:])
+	if err != nil {
+		return 0, 0, shard, err
+	}
+	if err := shard.UnmarshalCBOR(data); err != nil {
+		return 0, 0, shard, fmt.Errorf("invalid shard ID in root round index key %X: %w", key, err)
+	}
+	return rootRound, partition, shard, nil
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicitynetwork/bft-core/keyvaluedb/memorydb"
+	"github.com/unicitynetwork/bft-core/network/protocol/certification"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+func newArchivedCR(partition types.PartitionID, shard types.ShardID, shardRound, rootRound uint64) *certification.CertificationResponse {
+	return &certification.CertificationResponse{
+		Partition: partition,
+		Shard:     shard,
+		UC: types.UnicityCertificate{
+			InputRecord: &types.InputRecord{RoundNumber: shardRound},
+			UnicitySeal: &types.UnicitySeal{RootChainRoundNumber: rootRound},
+		},
+	}
+}
+
+func newTestCertificateArchive(t *testing.T, keepRounds uint64) *CertificateArchive {
+	db, err := memorydb.New()
+	require.NoError(t, err)
+	archive, err := NewCertificateArchive(db, keepRounds)
+	require.NoError(t, err)
+	return archive
+}
+
+func requireArchivedCR(t *testing.T, cr *certification.CertificationResponse, err error, shardRound, rootRound uint64) {
+	t.Helper()
+	require.NoError(t, err)
+	require.EqualValues(t, shardRound, cr.UC.GetRoundNumber())
+	require.EqualValues(t, rootRound, cr.UC.GetRootRoundNumber())
+}
+
+func TestNewCertificateArchive(t *testing.T) {
+	archive, err := NewCertificateArchive(nil, 0)
+	require.EqualError(t, err, "database is nil")
+	require.Nil(t, archive)
+}
+
+func TestCertificateArchive_Query(t *testing.T) {
+	shard0, shard1 := types.ShardID{}.Split()
+	archive := newTestCertificateArchive(t, 0)
+
+	require.NoError(t, archive.Add(nil))
+	require.NoError(t, archive.Add([]*certification.CertificationResponse{
+		newArchivedCR(1, types.ShardID{}, 5, 10),
+		newArchivedCR(2, shard0, 7, 10),
+		newArchivedCR(2, shard1, 3, 10),
+	}))
+	require.NoError(t, archive.Add([]*certification.CertificationResponse{
+		newArchivedCR(1, types.ShardID{}, 6, 12),
+	}))
+	// repeat UC of the shard round 6
+	require.NoError(t, archive.Add([]*certification.CertificationResponse{
+		newArchivedCR(1, types.ShardID{}, 6, 15),
+		newArchivedCR(2, shard1, 4, 15),
+	}))
+
+	t.Run("by shard round", func(t *testing.T) {
+		cr, err := archive.ByShardRound(1, types.ShardID{}, 5)
+		requireArchivedCR(t, cr, err, 5, 10)
+		// the latest certificate of the round is returned
+		cr, err = archive.ByShardRound(1, types.ShardID{}, 6)
+		requireArchivedCR(t, cr, err, 6, 15)
+		cr, err = archive.ByShardRound(2, shard0, 7)
+		requireArchivedCR(t, cr, err, 7, 10)
+		cr, err = archive.ByShardRound(2, shard1, 3)
+		requireArchivedCR(t, cr, err, 3, 10)
+
+		_, err = archive.ByShardRound(1, types.ShardID{}, 7)
+		require.ErrorIs(t, err, ErrCertificateNotFound)
+		_, err = archive.ByShardRound(2, types.ShardID{}, 7)
+		require.ErrorIs(t, err, ErrCertificateNotFound)
+		_, err = archive.ByShardRound(3, types.ShardID{}, 5)
+		require.ErrorIs(t, err, ErrCertificateNotFound)
+	})
+
+	t.Run("by root round", func(t *testing.T) {
+		cr, err := archive.ByRootRound(1, types.ShardID{}, 10)
+		requireArchivedCR(t, cr, err, 5, 10)
+		cr, err = archive.ByRootRound(1, types.ShardID{}, 11)
+		requireArchivedCR(t, cr, err, 5, 10)
+		cr, err = archive.ByRootRound(1, types.ShardID{}, 14)
+		requireArchivedCR(t, cr, err, 6, 12)
+		cr, err = archive.ByRootRound(1, types.ShardID{}, 100)
+		requireArchivedCR(t, cr, err, 6, 15)
+		cr, err = archive.ByRootRound(2, shard0, 100)
+		requireArchivedCR(t, cr, err, 7, 10)
+		cr, err = archive.ByRootRound(2, shard1, 14)
+		requireArchivedCR(t, cr, err, 3, 10)
+
+		// no certificate issued before the root round
+		_, err = archive.ByRootRound(1, types.ShardID{}, 9)
+		require.ErrorIs(t, err, ErrCertificateNotFound)
+		_, err = archive.ByRootRound(3, types.ShardID{}, 100)
+		require.ErrorIs(t, err, ErrCertificateNotFound)
+	})
+}
+
+func TestCertificateArchive_Prune(t *testing.T) {
+	shard0, shard1 := types.ShardID{}.Split()
+	archive := newTestCertificateArchive(t, 5)
+
+	require.NoError(t, archive.Add([]*certification.CertificationResponse{
+		newArchivedCR(1, types.ShardID{}, 1, 1),
+		newArchivedCR(2, shard0, 1, 1),
+	}))
+	require.NoError(t, archive.Add([]*certification.CertificationResponse{
+		newArchivedCR(1, types.ShardID{}, 2, 3),
+		newArchivedCR(2, shard1, 1, 3),
+	}))
+	// repeat UC of the shard round 2
+	require.NoError(t, archive.Add([]*certification.CertificationResponse{
+		newArchivedCR(1, types.ShardID{}, 2, 5),
+	}))
+	// certificates of the root rounds before 4 expire
+	require.NoError(t, archive.Add([]*certification.CertificationResponse{
+		newArchivedCR(1, types.ShardID{}, 3, 9),
+	}))
+
+	_, err := archive.ByShardRound(1, types.ShardID{}, 1)
+	require.ErrorIs(t, err, ErrCertificateNotFound)
+	_, err = archive.ByShardRound(2, shard0, 1)
+	require.ErrorIs(t, err, ErrCertificateNotFound)
+	_, err = archive.ByShardRound(2, shard1, 1)
+	require.ErrorIs(t, err, ErrCertificateNotFound)
+	_, err = archive.ByRootRound(1, types.ShardID{}, 4)
+	require.ErrorIs(t, err, ErrCertificateNotFound)
+
+	// the repeat UC of the expired certificate is kept
+	cr, err := archive.ByShardRound(1, types.ShardID{}, 2)
+	requireArchivedCR(t, cr, err, 2, 5)
+	cr, err = archive.ByRootRound(1, types.ShardID{}, 8)
+	requireArchivedCR(t, cr, err, 2, 5)
+	cr, err = archive.ByShardRound(1, types.ShardID{}, 3)
+	requireArchivedCR(t, cr, err, 3, 9)
+}
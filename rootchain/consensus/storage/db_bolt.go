@@ -28,6 +28,7 @@ var (
 	keyVote         = []byte("vote")
 	keyHighestVoted = []byte("votedRound")
 	keyHighestQc    = []byte("qcRound")
+	keyWatermark    = []byte("watermark")
 )
 
 /*
@@ -321,6 +322,94 @@ func (db BoltDB) SetHighestQcRound(qcRound, votedRound uint64) error {
 	})
 }
 
+/*
+GetSafetyWatermark returns the highest voted round imported with ImportSafetyState,
+the safety module must not sign anything for the round or any earlier round.
+Returns zero when the safety state has never been imported.
+*/
+func (db BoltDB) GetSafetyWatermark() (uint64, error) {
+	var round uint64
+	err := db.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(bucketSafety)
+		if b == nil {
+			return errNoSafetyBucket
+		}
+		if b.Get(keyWatermark) == nil {
+			return nil
+		}
+		var err error
+		round, err = readUint64(b, keyWatermark)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("reading safety watermark: %w", err)
+	}
+	return round, nil
+}
+
+// SafetyState is the persisted state of the SafetyModule.
+type SafetyState struct {
+	HighestVotedRound uint64
+	HighestQcRound    uint64
+	Watermark         uint64 // see GetSafetyWatermark
+}
+
+// ReadSafetyState returns the persisted state of the SafetyModule.
+func (db BoltDB) ReadSafetyState() (state SafetyState, _ error) {
+	return state, db.db.View(func(tx *bbolt.Tx) (err error) {
+		b := tx.Bucket(bucketSafety)
+		if b == nil {
+			return errNoSafetyBucket
+		}
+		if state.HighestVotedRound, err = readUint64(b, keyHighestVoted); err != nil {
+			return err
+		}
+		if state.HighestQcRound, err = readUint64(b, keyHighestQc); err != nil {
+			return err
+		}
+		if b.Get(keyWatermark) != nil {
+			state.Watermark, err = readUint64(b, keyWatermark)
+		}
+		return err
+	})
+}
+
+/*
+ImportSafetyState imports the safety state exported from another database of the
+same validator (ie when the validator is moved to a new machine). The rounds are
+never lowered and the imported voted round becomes the safety watermark.
+*/
+func (db BoltDB) ImportSafetyState(votedRound, qcRound uint64) error {
+	return db.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(bucketSafety)
+		if b == nil {
+			return errNoSafetyBucket
+		}
+		hQC, err := readUint64(b, keyHighestQc)
+		if err != nil {
+			return err
+		}
+		hVR, err := readUint64(b, keyHighestVoted)
+		if err != nil {
+			return err
+		}
+		var watermark uint64
+		if b.Get(keyWatermark) != nil {
+			if watermark, err = readUint64(b, keyWatermark); err != nil {
+				return err
+			}
+		}
+
+		if err = writeUint64(b, keyHighestQc, max(qcRound, hQC)); err != nil {
+			return err
+		}
+		if err = writeUint64(b, keyHighestVoted, max(votedRound, hVR)); err != nil {
+			return err
+		}
+		return writeUint64(b, keyWatermark, max(votedRound, watermark))
+	})
+}
+
 /*
 migrateTo upgrades database to version "ver" if the current version is older.
 */
@@ -353,3 +353,54 @@ func Test_BoltDB_SafetyModule_API(t *testing.T) {
 	require.Equal(t, rctypes.GenesisRootRound, db.GetHighestVotedRound())
 	require.ErrorIs(t, db.SetHighestQcRound(20, 21), errNoSafetyBucket)
 }
+
+func Test_BoltDB_SafetyState(t *testing.T) {
+	dbName := filepath.Join(t.TempDir(), "rootchain.db")
+	db, err := NewBoltStorage(dbName)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	state, err := db.ReadSafetyState()
+	require.NoError(t, err)
+	require.Equal(t, SafetyState{HighestVotedRound: rctypes.GenesisRootRound, HighestQcRound: rctypes.GenesisRootRound}, state)
+	watermark, err := db.GetSafetyWatermark()
+	require.NoError(t, err)
+	require.Zero(t, watermark)
+
+	// import into the fresh DB
+	require.NoError(t, db.ImportSafetyState(30, 29))
+	require.EqualValues(t, 30, db.GetHighestVotedRound())
+	require.EqualValues(t, 29, db.GetHighestQcRound())
+	watermark, err = db.GetSafetyWatermark()
+	require.NoError(t, err)
+	require.EqualValues(t, 30, watermark)
+
+	// rounds are never lowered by the import
+	require.NoError(t, db.SetHighestQcRound(35, 36))
+	require.NoError(t, db.ImportSafetyState(20, 19))
+	state, err = db.ReadSafetyState()
+	require.NoError(t, err)
+	require.Equal(t, SafetyState{HighestVotedRound: 36, HighestQcRound: 35, Watermark: 30}, state)
+
+	require.NoError(t, db.ImportSafetyState(40, 20))
+	state, err = db.ReadSafetyState()
+	require.NoError(t, err)
+	require.Equal(t, SafetyState{HighestVotedRound: 40, HighestQcRound: 35, Watermark: 40}, state)
+
+	// survives reopening the DB
+	require.NoError(t, db.Close())
+	db, err = NewBoltStorage(dbName)
+	require.NoError(t, err)
+	watermark, err = db.GetSafetyWatermark()
+	require.NoError(t, err)
+	require.EqualValues(t, 40, watermark)
+
+	// invalid db - missing bucket
+	err = db.db.Update(func(tx *bbolt.Tx) error { return tx.DeleteBucket(bucketSafety) })
+	require.NoError(t, err)
+	_, err = db.GetSafetyWatermark()
+	require.ErrorIs(t, err, errNoSafetyBucket)
+	_, err = db.ReadSafetyState()
+	require.ErrorIs(t, err, errNoSafetyBucket)
+	require.ErrorIs(t, db.ImportSafetyState(50, 49), errNoSafetyBucket)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"go.opentelemetry.io/otel/attribute"
@@ -53,6 +54,10 @@ type (
 		subscription     *Subscriptions
 		net              PartitionNet
 		consensusManager ConsensusManager
+		// span context of the certification request per shard, delivering
+		// the certification result continues the trace of the request
+		reqTraces   map[types.PartitionShardID]trace.SpanContext
+		reqTracesMu sync.Mutex
 
 		log    *slog.Logger
 		tracer trace.Tracer
@@ -90,6 +95,7 @@ func New(
 		subscription:     subs,
 		net:              pNet,
 		consensusManager: cm,
+		reqTraces:        make(map[types.PartitionShardID]trace.SpanContext),
 		log:              observe.Logger(),
 		tracer:           observe.Tracer("rootchain.node"),
 	}
@@ -134,8 +140,9 @@ func (v *Node) partitionMsgLoop(ctx context.Context) error {
 			if !ok {
 				return fmt.Errorf("partition channel closed")
 			}
+			msgCtx, msg := network.UnwrapMsg(ctx, msg)
 			v.log.LogAttrs(ctx, logger.LevelTrace, fmt.Sprintf("received %T", msg), logger.Data(msg))
-			if err := v.handlePartitionMsg(ctx, msg); err != nil {
+			if err := v.handlePartitionMsg(msgCtx, msg); err != nil {
 				v.log.WarnContext(ctx, fmt.Sprintf("processing %T", msg), logger.Error(err))
 			}
 		}
@@ -274,6 +281,9 @@ func (v *Node) onBlockCertificationRequest(ctx context.Context, req *certificati
 		v.incomingRequests.Clear(ctx, req.PartitionID, req.ShardID)
 		return fmt.Errorf("requesting certification: %w", err)
 	}
+	v.reqTracesMu.Lock()
+	v.reqTraces[types.PartitionShardID{PartitionID: req.PartitionID, ShardID: req.ShardID.Key()}] = span.SpanContext()
+	v.reqTracesMu.Unlock()
 	return nil
 }
 
@@ -287,8 +297,22 @@ func (v *Node) handleConsensus(ctx context.Context) error {
 			if !ok {
 				return fmt.Errorf("consensus channel closed")
 			}
-			v.subscription.Send(ctx, cr)
-			v.incomingRequests.Clear(ctx, cr.Partition, cr.Shard)
+			v.onCertificationResult(ctx, cr)
 		}
 	}
 }
+
+func (v *Node) onCertificationResult(ctx context.Context, cr *certification.CertificationResponse) {
+	key := types.PartitionShardID{PartitionID: cr.Partition, ShardID: cr.Shard.Key()}
+	v.reqTracesMu.Lock()
+	sc := v.reqTraces[key]
+	delete(v.reqTraces, key)
+	v.reqTracesMu.Unlock()
+
+	// without certification request (ie timeout) span is new root
+	ctx, span := v.tracer.Start(trace.ContextWithSpanContext(ctx, sc), "node.onCertificationResult", trace.WithAttributes(observability.Partition(cr.Partition)))
+	defer span.End()
+
+	v.subscription.Send(ctx, cr)
+	v.incomingRequests.Clear(ctx, cr.Partition, cr.Shard)
+}
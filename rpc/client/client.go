@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/unicitynetwork/bft-core/partition"
+	"github.com/unicitynetwork/bft-core/rpc"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+/*
+StateAPIClient is a JSON-RPC client of the "state" API of a shard node (see rpc.StateAPI).
+*/
+type StateAPIClient struct {
+	rpc *ethrpc.Client
+}
+
+/*
+New dials the JSON-RPC server of a shard node at "url" (ie "http://localhost:26866/rpc").
+*/
+func New(ctx context.Context, url string) (*StateAPIClient, error) {
+	c, err := ethrpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("dialing RPC server %s: %w", url, err)
+	}
+	return NewWithClient(c), nil
+}
+
+// NewWithClient returns state API client using existing RPC client.
+func NewWithClient(c *ethrpc.Client) *StateAPIClient {
+	return &StateAPIClient{rpc: c}
+}
+
+func (c *StateAPIClient) Close() {
+	c.rpc.Close()
+}
+
+// GetRoundInfo returns the current round number and epoch of the shard.
+func (c *StateAPIClient) GetRoundInfo(ctx context.Context) (*partition.RoundInfo, error) {
+	var res *partition.RoundInfo
+	if err := c.rpc.CallContext(ctx, &res, "state_getRoundInfo"); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("round info is nil")
+	}
+	return res, nil
+}
+
+/*
+GetUnit returns the unit with given ID, the unit data is returned as raw JSON.
+Returns nil (and no error) when the unit doesn't exist.
+*/
+func (c *StateAPIClient) GetUnit(ctx context.Context, unitID types.UnitID, includeStateProof bool) (*rpc.Unit[json.RawMessage], error) {
+	var res *rpc.Unit[json.RawMessage]
+	if err := c.rpc.CallContext(ctx, &res, "state_getUnit", unitID, includeStateProof); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+/*
+GetUnitAtRound returns the unit with given ID as it was in the committed state of
+the given round. The shard node must run in archive mode for the state of the round
+to be available. When state proof is requested it is against the UC of the round.
+Returns nil (and no error) when the unit didn't exist in the round.
+*/
+func (c *StateAPIClient) GetUnitAtRound(ctx context.Context, unitID types.UnitID, includeStateProof bool, round uint64) (*rpc.Unit[json.RawMessage], error) {
+	var res *rpc.Unit[json.RawMessage]
+	if err := c.rpc.CallContext(ctx, &res, "state_getUnit", unitID, includeStateProof, hex.Uint64(round)); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SendTransaction submits the transaction to the shard and returns the transaction hash.
+func (c *StateAPIClient) SendTransaction(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+	txBytes, err := tx.MarshalCBOR()
+	if err != nil {
+		return nil, fmt.Errorf("encoding transaction: %w", err)
+	}
+	var res hex.Bytes
+	if err := c.rpc.CallContext(ctx, &res, "state_sendTransaction", hex.Bytes(txBytes)); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+/*
+GetTransactionProof returns the proof of the transaction with given hash.
+Returns nil (and no error) when the transaction is not (yet) included in a block.
+*/
+func (c *StateAPIClient) GetTransactionProof(ctx context.Context, txHash []byte) (*types.TxRecordProof, error) {
+	var res *rpc.TransactionRecordAndProof
+	if err := c.rpc.CallContext(ctx, &res, "state_getTransactionProof", hex.Bytes(txHash)); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	proof := &types.TxRecordProof{}
+	if err := types.Cbor.Unmarshal(res.TxRecordProof, proof); err != nil {
+		return nil, fmt.Errorf("decoding transaction proof: %w", err)
+	}
+	return proof, nil
+}
+
+// GetTrustBase returns the root trust base of the epoch.
+func (c *StateAPIClient) GetTrustBase(ctx context.Context, epoch uint64) (*types.RootTrustBaseV1, error) {
+	var res *types.RootTrustBaseV1
+	if err := c.rpc.CallContext(ctx, &res, "state_getTrustBase", hex.Uint64(epoch)); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("trust base of epoch %d not found", epoch)
+	}
+	return res, nil
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicitynetwork/bft-core/partition"
+	"github.com/unicitynetwork/bft-core/rpc"
+	"github.com/unicitynetwork/bft-core/state"
+	testtransaction "github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/types/hex"
+)
+
+func TestStateAPIClient(t *testing.T) {
+	api := &mockStateAPI{}
+	server := ethrpc.NewServer()
+	require.NoError(t, server.RegisterName("state", api))
+	t.Cleanup(server.Stop)
+	c := NewWithClient(ethrpc.DialInProc(server))
+	t.Cleanup(c.Close)
+	ctx := context.Background()
+
+	t.Run("GetRoundInfo", func(t *testing.T) {
+		ri, err := c.GetRoundInfo(ctx)
+		require.NoError(t, err)
+		require.Equal(t, &partition.RoundInfo{RoundNumber: 42, EpochNumber: 1}, ri)
+	})
+
+	t.Run("GetUnit", func(t *testing.T) {
+		unit, err := c.GetUnit(ctx, types.UnitID{1, 2, 3}, false)
+		require.NoError(t, err)
+		require.EqualValues(t, types.UnitID{1, 2, 3}, unit.UnitID)
+		require.EqualValues(t, []byte{4, 5}, unit.StateLockTx)
+		require.JSONEq(t, `{"value":"10"}`, string(unit.Data))
+
+		unit, err = c.GetUnit(ctx, types.UnitID{0}, false)
+		require.NoError(t, err)
+		require.Nil(t, unit)
+	})
+
+	t.Run("GetUnitAtRound", func(t *testing.T) {
+		unit, err := c.GetUnitAtRound(ctx, types.UnitID{1, 2, 3}, false, 3)
+		require.NoError(t, err)
+		require.EqualValues(t, types.UnitID{1, 2, 3}, unit.UnitID)
+		require.JSONEq(t, `{"value":"3"}`, string(unit.Data))
+
+		unit, err = c.GetUnitAtRound(ctx, types.UnitID{1, 2, 3}, false, 6)
+		require.EqualError(t, err, "failed to load state: state of the round is not archived")
+		require.Nil(t, unit)
+	})
+
+	t.Run("SendTransaction", func(t *testing.T) {
+		tx := testtransaction.NewTransactionOrder(t)
+		txHash, err := c.SendTransaction(ctx, tx)
+		require.NoError(t, err)
+		require.EqualValues(t, []byte{0xAB}, txHash)
+		require.Equal(t, tx, api.tx)
+	})
+
+	t.Run("GetTransactionProof", func(t *testing.T) {
+		proof, err := c.GetTransactionProof(ctx, []byte{1})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, proof.TxProof.Version)
+
+		proof, err = c.GetTransactionProof(ctx, []byte{0})
+		require.NoError(t, err)
+		require.Nil(t, proof)
+	})
+
+	t.Run("GetTrustBase", func(t *testing.T) {
+		tb, err := c.GetTrustBase(ctx, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 5, tb.NetworkID)
+
+		tb, err = c.GetTrustBase(ctx, 2)
+		require.EqualError(t, err, `trust base of epoch 2 not found`)
+		require.Nil(t, tb)
+	})
+}
+
+type mockStateAPI struct {
+	tx *types.TransactionOrder
+}
+
+func (m *mockStateAPI) GetRoundInfo(ctx context.Context) (*partition.RoundInfo, error) {
+	return &partition.RoundInfo{RoundNumber: 42, EpochNumber: 1}, nil
+}
+
+func (m *mockStateAPI) GetUnit(unitID types.UnitID, includeStateProof bool, round *hex.Uint64) (*rpc.Unit[any], error) {
+	if unitID[0] == 0 {
+		return nil, nil
+	}
+	if round != nil {
+		if *round > 5 {
+			return nil, fmt.Errorf("failed to load state: %w", state.ErrRoundNotArchived)
+		}
+		return &rpc.Unit[any]{UnitID: unitID, Data: map[string]string{"value": fmt.Sprint(*round)}}, nil
+	}
+	return &rpc.Unit[any]{UnitID: unitID, Data: map[string]string{"value": "10"}, StateLockTx: []byte{4, 5}}, nil
+}
+
+func (m *mockStateAPI) SendTransaction(ctx context.Context, txBytes hex.Bytes) (hex.Bytes, error) {
+	m.tx = &types.TransactionOrder{}
+	if err := types.Cbor.Unmarshal(txBytes, m.tx); err != nil {
+		return nil, err
+	}
+	return []byte{0xAB}, nil
+}
+
+func (m *mockStateAPI) GetTransactionProof(ctx context.Context, txHash hex.Bytes) (*rpc.TransactionRecordAndProof, error) {
+	if txHash[0] == 0 {
+		return nil, nil
+	}
+	proof, err := types.Cbor.Marshal(&types.TxRecordProof{TxProof: &types.TxProof{Version: 1}})
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.TransactionRecordAndProof{TxRecordProof: proof}, nil
+}
+
+func (m *mockStateAPI) GetTrustBase(epoch hex.Uint64) (*types.RootTrustBaseV1, error) {
+	if epoch != 1 {
+		return nil, nil
+	}
+	return &types.RootTrustBaseV1{Version: 1, NetworkID: 5, Epoch: 1}, nil
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/unicitynetwork/bft-core/logger"
 	"github.com/unicitynetwork/bft-core/observability"
+	"github.com/unicitynetwork/bft-core/state"
 )
 
 func metricsUpdater(mtr metric.Meter, node partitionNode, log *slog.Logger) func(ctx context.Context, method string, start time.Time, apiErr error) {
@@ -72,6 +73,32 @@ func metricsUpdaterTxReceived(mtr metric.Meter, node partitionNode, log *slog.Lo
 	}
 }
 
+/*
+metricsUpdaterInvalidUnitProof returns function which counts unit state proofs
+which failed self-verification, the failure is logged as error as it means that
+the state of the node is corrupted.
+*/
+func metricsUpdaterInvalidUnitProof(mtr metric.Meter, node partitionNode, log *slog.Logger) func(ctx context.Context, proofErr *state.UnitStateProofError) {
+	invalidProofs, err := mtr.Int64Counter(
+		"unit_proof.invalid",
+		metric.WithDescription("Number of generated unit state proofs which failed verification"),
+		metric.WithUnit("{proof}"),
+	)
+	if err != nil {
+		log.Error("creating invalid unit proof counter", logger.Error(err))
+		invalidProofs = nil
+	}
+
+	fixedAttr := observability.Shard(node.PartitionID(), node.ShardID())
+
+	return func(ctx context.Context, proofErr *state.UnitStateProofError) {
+		log.ErrorContext(ctx, "generated unit state proof is invalid", logger.Error(proofErr), logger.UnitID(proofErr.UnitID))
+		if invalidProofs != nil {
+			invalidProofs.Add(ctx, 1, fixedAttr)
+		}
+	}
+}
+
 /*
 instrumentHTTP returns http middleware which instruments the incoming handler with two metrics:
   - number of calls: how many times the endpoint has been called;
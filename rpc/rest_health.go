@@ -0,0 +1,137 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/unicitynetwork/bft-core/logger"
+	"github.com/unicitynetwork/bft-core/partition"
+)
+
+const (
+	HealthStatusInitializing = "initializing"
+	HealthStatusNormal       = "normal"
+	HealthStatusRecovering   = "recovering"
+
+	RoleLeader       = "leader"
+	RoleValidator    = "validator"
+	RoleNonValidator = "non-validator"
+
+	DefaultReadyMaxUCAge = 30 * time.Second
+)
+
+type (
+	// NodeHealth is the response of the health and readiness endpoints of both shard and root nodes.
+	NodeHealth struct {
+		Status          string          `json:"status"` // initializing, normal or recovering
+		Role            string          `json:"role"`   // leader, validator or non-validator
+		CommittedRound  uint64          `json:"committedRound,string"`
+		LastUCTime      time.Time       `json:"lastUcTime"`
+		LastUCAgeMs     int64           `json:"lastUcAgeMs"`
+		Recovery        *RecoveryStatus `json:"recovery,omitempty"`
+		PeerCount       int             `json:"peerCount"`
+		ValidatorCount  int             `json:"validatorCount"`
+		Ready           bool            `json:"ready"`
+		NotReadyReasons []string        `json:"notReadyReasons,omitempty"`
+	}
+
+	RecoveryStatus struct {
+		TargetRound  uint64 `json:"targetRound,string"`
+		CurrentRound uint64 `json:"currentRound,string"`
+	}
+
+	// HealthSource returns the current health of the node, Ready and NotReadyReasons are filled by the handlers.
+	HealthSource func() *NodeHealth
+
+	shardNode interface {
+		Health() *partition.HealthInfo
+	}
+)
+
+/*
+HealthEndpoints registers the liveness ("/health") and readiness ("/ready")
+endpoints. Readiness fails while the node is not in normal status or when the
+last UC is older than maxUCAge.
+*/
+func HealthEndpoints(source HealthSource, maxUCAge time.Duration, log *slog.Logger) RegistrarFunc {
+	return func(r *mux.Router) {
+		r.HandleFunc("/health", HealthHandler(source, maxUCAge, log)).Methods(http.MethodGet)
+		r.HandleFunc("/ready", ReadyHandler(source, maxUCAge, log)).Methods(http.MethodGet)
+	}
+}
+
+// HealthHandler always responds with status OK, the body describes the health of the node.
+func HealthHandler(source HealthSource, maxUCAge time.Duration, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, http.StatusOK, checkHealth(source(), maxUCAge, time.Now()), log)
+	}
+}
+
+// ReadyHandler responds with status OK when the node is ready to serve requests, with status Service Unavailable otherwise.
+func ReadyHandler(source HealthSource, maxUCAge time.Duration, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		health := checkHealth(source(), maxUCAge, time.Now())
+		code := http.StatusOK
+		if !health.Ready {
+			code = http.StatusServiceUnavailable
+		}
+		writeHealth(w, code, health, log)
+	}
+}
+
+// ShardNodeHealth returns the health source of the shard node.
+func ShardNodeHealth(node shardNode) HealthSource {
+	return func() *NodeHealth {
+		info := node.Health()
+		health := &NodeHealth{
+			Status:         info.Status,
+			Role:           RoleNonValidator,
+			CommittedRound: info.CommittedRound,
+			LastUCTime:     info.LatestUCTime,
+			PeerCount:      info.PeerCount,
+			ValidatorCount: info.ValidatorCount,
+		}
+		if info.IsLeader {
+			health.Role = RoleLeader
+		} else if info.IsValidator {
+			health.Role = RoleValidator
+		}
+		if info.Status == HealthStatusRecovering {
+			health.Recovery = &RecoveryStatus{
+				TargetRound:  info.LatestRound,
+				CurrentRound: info.CommittedRound,
+			}
+		}
+		return health
+	}
+}
+
+func checkHealth(health *NodeHealth, maxUCAge time.Duration, now time.Time) *NodeHealth {
+	health.NotReadyReasons = nil
+	if health.Status != HealthStatusNormal {
+		health.NotReadyReasons = append(health.NotReadyReasons, fmt.Sprintf("node status is %s", health.Status))
+	}
+	if health.LastUCTime.IsZero() {
+		health.NotReadyReasons = append(health.NotReadyReasons, "no UC received")
+	} else {
+		age := now.Sub(health.LastUCTime)
+		health.LastUCAgeMs = age.Milliseconds()
+		if age > maxUCAge {
+			health.NotReadyReasons = append(health.NotReadyReasons, fmt.Sprintf("last UC is %s old, max allowed age is %s", age.Truncate(time.Second), maxUCAge))
+		}
+	}
+	health.Ready = len(health.NotReadyReasons) == 0
+	return health
+}
+
+func writeHealth(w http.ResponseWriter, code int, health *NodeHealth, log *slog.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		log.Warn("failed to write health response", logger.Error(err))
+	}
+}
@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicitynetwork/bft-core/internal/testutils/observability"
+	"github.com/unicitynetwork/bft-core/partition"
+)
+
+type mockShardNode struct {
+	info partition.HealthInfo
+}
+
+func (n *mockShardNode) Health() *partition.HealthInfo {
+	info := n.info
+	return &info
+}
+
+func TestRESTServer_Health(t *testing.T) {
+	obs := observability.Default(t)
+	node := &mockShardNode{info: partition.HealthInfo{
+		Status:         HealthStatusNormal,
+		IsValidator:    true,
+		CommittedRound: 10,
+		LatestRound:    10,
+		LatestUCTime:   time.Now().Add(-2 * time.Second),
+		PeerCount:      3,
+		ValidatorCount: 4,
+	}}
+	server := NewRESTServer("", 10, obs, HealthEndpoints(ShardNodeHealth(node), 10*time.Second, obs.Logger()))
+
+	get := func(t *testing.T, path string) (int, *NodeHealth) {
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		health := &NodeHealth{}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(health))
+		return recorder.Result().StatusCode, health
+	}
+
+	t.Run("ready", func(t *testing.T) {
+		code, health := get(t, "/api/v1/ready")
+		require.Equal(t, http.StatusOK, code)
+		require.True(t, health.Ready)
+		require.Empty(t, health.NotReadyReasons)
+		require.Equal(t, HealthStatusNormal, health.Status)
+		require.Equal(t, RoleValidator, health.Role)
+		require.EqualValues(t, 10, health.CommittedRound)
+		require.GreaterOrEqual(t, health.LastUCAgeMs, int64(1000))
+		require.Nil(t, health.Recovery)
+		require.Equal(t, 3, health.PeerCount)
+		require.Equal(t, 4, health.ValidatorCount)
+	})
+
+	t.Run("recovering", func(t *testing.T) {
+		node.info.Status = HealthStatusRecovering
+		node.info.LatestRound = 15
+		defer func() { node.info.Status, node.info.LatestRound = HealthStatusNormal, 10 }()
+
+		code, health := get(t, "/api/v1/ready")
+		require.Equal(t, http.StatusServiceUnavailable, code)
+		require.False(t, health.Ready)
+		require.Equal(t, []string{"node status is recovering"}, health.NotReadyReasons)
+		require.Equal(t, &RecoveryStatus{TargetRound: 15, CurrentRound: 10}, health.Recovery)
+
+		// liveness is not affected
+		code, health = get(t, "/api/v1/health")
+		require.Equal(t, http.StatusOK, code)
+		require.False(t, health.Ready)
+	})
+
+	t.Run("UC too old", func(t *testing.T) {
+		node.info.LatestUCTime = time.Now().Add(-time.Minute)
+		node.info.IsLeader = true
+		code, health := get(t, "/api/v1/ready")
+		require.Equal(t, http.StatusServiceUnavailable, code)
+		require.Equal(t, RoleLeader, health.Role)
+		require.Len(t, health.NotReadyReasons, 1)
+		require.Contains(t, health.NotReadyReasons[0], "last UC is 1m0s old")
+
+		node.info.LatestUCTime = time.Time{}
+		code, health = get(t, "/api/v1/ready")
+		require.Equal(t, http.StatusServiceUnavailable, code)
+		require.Equal(t, []string{"no UC received"}, health.NotReadyReasons)
+	})
+}
@@ -31,6 +31,8 @@ type (
 
 		updMetrics    func(ctx context.Context, method string, start time.Time, apiErr error)
 		updTxReceived func(ctx context.Context, txType uint16, apiErr error)
+		// called when generated unit state proof fails self-verification
+		updInvalidUnitProof func(ctx context.Context, proofErr *state.UnitStateProofError)
 	}
 
 	partitionNode interface {
@@ -90,14 +92,15 @@ func NewStateAPI(node partitionNode, obs Observability, opts ...StateAPIOption)
 	)
 
 	return &StateAPI{
-		node:              node,
-		ownerIndex:        options.ownerIndex,
-		pdr:               options.shardConf,
-		withGetUnits:      options.withGetUnits,
-		updMetrics:        metricsUpdater(m, node, log),
-		updTxReceived:     metricsUpdaterTxReceived(m, node, log),
-		requestLimiter:    requestLimiter,
-		responseItemLimit: options.responseItemLimit,
+		node:                node,
+		ownerIndex:          options.ownerIndex,
+		pdr:                 options.shardConf,
+		withGetUnits:        options.withGetUnits,
+		updMetrics:          metricsUpdater(m, node, log),
+		updTxReceived:       metricsUpdaterTxReceived(m, node, log),
+		updInvalidUnitProof: metricsUpdaterInvalidUnitProof(m, node, log),
+		requestLimiter:      requestLimiter,
+		responseItemLimit:   options.responseItemLimit,
 	}
 }
 
@@ -110,14 +113,21 @@ func (s *StateAPI) GetRoundInfo(ctx context.Context) (_ *partition.RoundInfo, re
 	return s.node.CurrentRoundInfo(ctx)
 }
 
-// GetUnit returns unit data and optionally the state proof for the given unitID.
-func (s *StateAPI) GetUnit(unitID types.UnitID, includeStateProof bool) (_ *Unit[any], retErr error) {
+/*
+GetUnit returns unit data and optionally the state proof for the given unitID.
+When round is given the unit is returned as it was in the committed state of the
+round (requires archive mode) and the state proof is against the UC of the round.
+*/
+func (s *StateAPI) GetUnit(unitID types.UnitID, includeStateProof bool, round *hex.Uint64) (_ *Unit[any], retErr error) {
 	defer func(start time.Time) { s.updMetrics(context.Background(), "getUnit", start, retErr) }(time.Now())
 	if err := s.requestLimiter.CheckRequestAllowed("getUnit"); err != nil {
 		return nil, fmt.Errorf("request not allowed: %w", err)
 	}
 
-	st := s.node.TransactionSystemState()
+	st, err := s.stateAtRound(round)
+	if err != nil {
+		return nil, err
+	}
 	unit, err := st.GetUnit(unitID, true)
 	if err != nil {
 		if errors.Is(err, avl.ErrNotFound) {
@@ -145,6 +155,9 @@ func (s *StateAPI) GetUnit(unitID types.UnitID, includeStateProof bool) (_ *Unit
 			return nil, fmt.Errorf("failed to convert unit to version 1: %w", err)
 		}
 		stateProof, err := st.CreateUnitStateProof(unitID, u.LastLogIndex())
+		if proofErr := (*state.UnitStateProofError)(nil); errors.As(err, &proofErr) {
+			s.updInvalidUnitProof(context.Background(), proofErr)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate unit state proof: %w", err)
 		}
@@ -154,8 +167,12 @@ func (s *StateAPI) GetUnit(unitID types.UnitID, includeStateProof bool) (_ *Unit
 	return resp, nil
 }
 
-// GetUnitsByOwnerID returns list of unit identifiers that belong to the given owner.
-func (s *StateAPI) GetUnitsByOwnerID(ownerID hex.Bytes, sinceUnitID *types.UnitID, limit *int) (_ []types.UnitID, retErr error) {
+/*
+GetUnitsByOwnerID returns list of unit identifiers that belong to the given owner.
+When round is given the units which belonged to the owner in the committed state
+of the round are returned (requires archive mode).
+*/
+func (s *StateAPI) GetUnitsByOwnerID(ownerID hex.Bytes, sinceUnitID *types.UnitID, limit *int, round *hex.Uint64) (_ []types.UnitID, retErr error) {
 	defer func(start time.Time) { s.updMetrics(context.Background(), "getUnitsByOwnerID", start, retErr) }(time.Now())
 	if s.ownerIndex == nil {
 		return nil, errors.New("owner indexer is disabled")
@@ -164,7 +181,14 @@ func (s *StateAPI) GetUnitsByOwnerID(ownerID hex.Bytes, sinceUnitID *types.UnitI
 		return nil, fmt.Errorf("request not allowed: %w", err)
 	}
 	responseLimit := s.responseLimit(limit)
-	return s.ownerIndex.GetOwnerUnits(ownerID, sinceUnitID, responseLimit)
+	if round == nil {
+		return s.ownerIndex.GetOwnerUnits(ownerID, sinceUnitID, responseLimit)
+	}
+	st, err := s.stateAtRound(round)
+	if err != nil {
+		return nil, err
+	}
+	return s.ownerIndex.GetOwnerUnitsInState(st, ownerID, sinceUnitID, responseLimit)
 }
 
 // GetUnits returns list of unit identifiers, optionally filtered by the given unit type identifier.
@@ -265,6 +289,19 @@ func (s *StateAPI) GetTrustBase(epochNumber hex.Uint64) (_ types.RootTrustBase,
 	return trustBase, nil
 }
 
+// stateAtRound returns the committed state of the given round or the latest state when round is not given.
+func (s *StateAPI) stateAtRound(round *hex.Uint64) (txsystem.StateReader, error) {
+	st := s.node.TransactionSystemState()
+	if round == nil {
+		return st, nil
+	}
+	rs, err := st.AtRound(uint64(*round))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	return rs, nil
+}
+
 // startIndex returns next index from sinceUnitID.
 func startIndex(sinceUnitID *types.UnitID, ownerUnitIDs []types.UnitID) int {
 	if sinceUnitID == nil {
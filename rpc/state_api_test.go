@@ -65,7 +65,7 @@ func TestGetUnit(t *testing.T) {
 	api := NewStateAPI(node, observe)
 
 	t.Run("get unit (proof=false)", func(t *testing.T) {
-		unit, err := api.GetUnit(unitID, false)
+		unit, err := api.GetUnit(unitID, false, nil)
 		require.NoError(t, err)
 		require.NotNil(t, unit)
 		require.NotNil(t, unit.Data)
@@ -75,20 +75,36 @@ func TestGetUnit(t *testing.T) {
 		require.EqualValues(t, templates.AlwaysTrueBytes(), d.O)
 	})
 	t.Run("get unit (proof=true)", func(t *testing.T) {
-		unit, err := api.GetUnit(unitID, true)
+		unit, err := api.GetUnit(unitID, true, nil)
 		require.NoError(t, err)
 		require.NotNil(t, unit)
 		require.NotNil(t, unit.Data)
 		require.NotNil(t, unit.StateProof)
 		require.EqualValues(t, unitID, unit.StateProof.UnitID)
 	})
+	t.Run("invalid state proof", func(t *testing.T) {
+		s := prepareState(t, unitID)
+		// corrupted state, committed UC does not certify the state tree
+		s.CommittedUC().InputRecord.SummaryValue = util.Uint64ToBytes(1)
+		api := NewStateAPI(&MockNode{txs: &testtxsystem.CounterTxSystem{FixedState: s}}, observe)
+
+		unit, err := api.GetUnit(unitID, true, nil)
+		var proofErr *state.UnitStateProofError
+		require.ErrorAs(t, err, &proofErr)
+		require.EqualValues(t, unitID, proofErr.UnitID)
+		require.Nil(t, unit)
+
+		unit, err = api.GetUnit(unitID, false, nil)
+		require.NoError(t, err)
+		require.NotNil(t, unit)
+	})
 	t.Run("unit not found", func(t *testing.T) {
-		unit, err := api.GetUnit([]byte{1, 2, 3}, false)
+		unit, err := api.GetUnit([]byte{1, 2, 3}, false, nil)
 		require.NoError(t, err)
 		require.Nil(t, unit)
 	})
 	t.Run("network and partition identifier exist", func(t *testing.T) {
-		unit, err := api.GetUnit(unitID, false)
+		unit, err := api.GetUnit(unitID, false, nil)
 		require.NoError(t, err)
 		require.NotNil(t, unit)
 		require.Equal(t, types.NetworkID(5), unit.NetworkID)
@@ -120,11 +136,42 @@ func TestGetUnit(t *testing.T) {
 		}
 		api := NewStateAPI(node, observe)
 
-		unit, err := api.GetUnit(unitID, false)
+		unit, err := api.GetUnit(unitID, false, nil)
 		require.NoError(t, err)
 		require.NotNil(t, unit)
 		require.EqualValues(t, stateLockTx, unit.StateLockTx)
 	})
+	t.Run("unit at round", func(t *testing.T) {
+		s := state.NewEmptyState(state.WithArchive(5))
+		require.NoError(t, s.Apply(state.AddUnit(unitID, &unitData{I: 10, O: templates.AlwaysTrueBytes()})))
+		require.NoError(t, s.AddUnitLog(unitID, test.RandomBytes(32)))
+		commitState(t, s, 1)
+		require.NoError(t, s.Prune())
+		require.NoError(t, s.Apply(state.UpdateUnitData(unitID, func(data types.UnitData) (types.UnitData, error) {
+			return &unitData{I: 20, O: templates.AlwaysTrueBytes()}, nil
+		})))
+		require.NoError(t, s.AddUnitLog(unitID, test.RandomBytes(32)))
+		commitState(t, s, 2)
+		api := NewStateAPI(&MockNode{txs: &testtxsystem.CounterTxSystem{FixedState: s}}, observe)
+
+		for round, value := range map[hex.Uint64]uint64{1: 10, 2: 20} {
+			unit, err := api.GetUnit(unitID, true, &round)
+			require.NoError(t, err)
+			require.EqualValues(t, value, unit.Data.(*unitData).I)
+			uc := &types.UnicityCertificate{}
+			require.NoError(t, types.Cbor.Unmarshal(unit.StateProof.UnicityCertificate, uc))
+			require.EqualValues(t, round, uc.GetRoundNumber())
+		}
+		// latest state
+		unit, err := api.GetUnit(unitID, false, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 20, unit.Data.(*unitData).I)
+
+		round := hex.Uint64(3)
+		unit, err = api.GetUnit(unitID, false, &round)
+		require.ErrorIs(t, err, state.ErrRoundNotArchived)
+		require.Nil(t, unit)
+	})
 }
 
 func TestGetUnitsByOwnerID(t *testing.T) {
@@ -137,7 +184,7 @@ func TestGetUnitsByOwnerID(t *testing.T) {
 		ownerID := []byte{1}
 		ownerIndex.ownerUnits[string(ownerID)] = []types.UnitID{[]byte{0}, []byte{1}}
 
-		unitIds, err := api.GetUnitsByOwnerID(ownerID, nil, nil)
+		unitIds, err := api.GetUnitsByOwnerID(ownerID, nil, nil, nil)
 		require.NoError(t, err)
 		require.Len(t, unitIds, 2)
 		require.EqualValues(t, []byte{0}, unitIds[0])
@@ -147,7 +194,7 @@ func TestGetUnitsByOwnerID(t *testing.T) {
 		ownerID := []byte{1}
 		ownerIndex.err = errors.New("some error")
 
-		unitIds, err := api.GetUnitsByOwnerID(ownerID, nil, nil)
+		unitIds, err := api.GetUnitsByOwnerID(ownerID, nil, nil, nil)
 		require.ErrorContains(t, err, "some error")
 		require.Nil(t, unitIds)
 		ownerIndex.err = nil
@@ -157,24 +204,24 @@ func TestGetUnitsByOwnerID(t *testing.T) {
 		ownerIndex.ownerUnits[string(ownerID)] = []types.UnitID{[]byte{3}, []byte{1}, []byte{2}, []byte{0}, []byte{4}}
 
 		limit := 2
-		unitIds, err := api.GetUnitsByOwnerID(ownerID, nil, &limit)
+		unitIds, err := api.GetUnitsByOwnerID(ownerID, nil, &limit, nil)
 		require.NoError(t, err)
 		require.Len(t, unitIds, 2)
 		require.EqualValues(t, []byte{3}, unitIds[0])
 		require.EqualValues(t, []byte{1}, unitIds[1])
 
-		unitIds, err = api.GetUnitsByOwnerID(ownerID, &unitIds[1], &limit)
+		unitIds, err = api.GetUnitsByOwnerID(ownerID, &unitIds[1], &limit, nil)
 		require.NoError(t, err)
 		require.Len(t, unitIds, 2)
 		require.EqualValues(t, []byte{2}, unitIds[0])
 		require.EqualValues(t, []byte{0}, unitIds[1])
 
-		unitIds, err = api.GetUnitsByOwnerID(ownerID, &unitIds[1], &limit)
+		unitIds, err = api.GetUnitsByOwnerID(ownerID, &unitIds[1], &limit, nil)
 		require.NoError(t, err)
 		require.Len(t, unitIds, 1)
 		require.EqualValues(t, []byte{4}, unitIds[0])
 
-		unitIds, err = api.GetUnitsByOwnerID(ownerID, &unitIds[0], &limit)
+		unitIds, err = api.GetUnitsByOwnerID(ownerID, &unitIds[0], &limit, nil)
 		require.NoError(t, err)
 		require.Len(t, unitIds, 0)
 	})
@@ -183,17 +230,44 @@ func TestGetUnitsByOwnerID(t *testing.T) {
 		ownerIndex.ownerUnits[string(ownerID)] = []types.UnitID{[]byte{0}, []byte{1}}
 		apiWithLimit := NewStateAPI(node, observe, WithOwnerIndex(ownerIndex), WithResponseItemLimit(1))
 
-		unitIds, err := apiWithLimit.GetUnitsByOwnerID(ownerID, nil, nil)
+		unitIds, err := apiWithLimit.GetUnitsByOwnerID(ownerID, nil, nil, nil)
 		require.NoError(t, err)
 		require.Len(t, unitIds, 1)
 		require.EqualValues(t, []byte{0}, unitIds[0])
 
 		limit := 2
-		unitIds, err = apiWithLimit.GetUnitsByOwnerID(ownerID, nil, &limit)
+		unitIds, err = apiWithLimit.GetUnitsByOwnerID(ownerID, nil, &limit, nil)
 		require.NoError(t, err)
 		require.Len(t, unitIds, 1)
 		require.EqualValues(t, []byte{0}, unitIds[0])
 	})
+	t.Run("at round", func(t *testing.T) {
+		unitID1 := append(make(types.UnitID, 31), 1)
+		unitID2 := append(make(types.UnitID, 31), 2)
+		s := state.NewEmptyState(state.WithArchive(5))
+		require.NoError(t, s.Apply(state.AddUnit(unitID1, &unitData{I: 10, O: templates.AlwaysTrueBytes()})))
+		require.NoError(t, s.AddUnitLog(unitID1, test.RandomBytes(32)))
+		commitState(t, s, 1)
+		require.NoError(t, s.Apply(state.AddUnit(unitID2, &unitData{I: 10, O: templates.AlwaysTrueBytes()})))
+		require.NoError(t, s.AddUnitLog(unitID2, test.RandomBytes(32)))
+		commitState(t, s, 2)
+		api := NewStateAPI(&MockNode{txs: &testtxsystem.CounterTxSystem{FixedState: s}}, observe, WithOwnerIndex(ownerIndex))
+
+		round := hex.Uint64(1)
+		unitIds, err := api.GetUnitsByOwnerID([]byte{1}, nil, nil, &round)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{unitID1}, unitIds)
+
+		round = 2
+		unitIds, err = api.GetUnitsByOwnerID([]byte{1}, nil, nil, &round)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{unitID1, unitID2}, unitIds)
+
+		round = 3
+		unitIds, err = api.GetUnitsByOwnerID([]byte{1}, nil, nil, &round)
+		require.ErrorIs(t, err, state.ErrRoundNotArchived)
+		require.Nil(t, unitIds)
+	})
 }
 
 func TestGetUnits(t *testing.T) {
@@ -382,6 +456,17 @@ func TestGetTrustBase(t *testing.T) {
 	})
 }
 
+func commitState(t *testing.T, s *state.State, round uint64) {
+	summaryValue, summaryHash, err := s.CalculateRoot()
+	require.NoError(t, err)
+	require.NoError(t, s.Commit(&types.UnicityCertificate{Version: 1, InputRecord: &types.InputRecord{
+		Version:      1,
+		RoundNumber:  round,
+		Hash:         summaryHash,
+		SummaryValue: util.Uint64ToBytes(summaryValue),
+	}}))
+}
+
 func prepareState(t *testing.T, unitIDs ...types.UnitID) *state.State {
 	s := state.NewEmptyState()
 	for _, unitID := range unitIDs {
@@ -561,6 +646,14 @@ func (mn *MockOwnerIndex) GetOwnerUnits(ownerID []byte, sinceUnitID *types.UnitI
 	return mn.ownerUnits[string(ownerID)][startIndex:endIndex], nil
 }
 
+// GetOwnerUnitsInState returns all units of the state, ie all units are owned by everyone
+func (mn *MockOwnerIndex) GetOwnerUnitsInState(s txsystem.StateReader, ownerID []byte, sinceUnitID *types.UnitID, limit int) ([]types.UnitID, error) {
+	if mn.err != nil {
+		return nil, mn.err
+	}
+	return s.GetUnits(nil, nil)
+}
+
 func createTransactionOrder(t *testing.T, unitID types.UnitID) []byte {
 	bt := &money.TransferAttributes{
 		NewOwnerPredicate: templates.AlwaysTrueBytes(),
@@ -0,0 +1,332 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/unicitynetwork/bft-core/keyvaluedb"
+	"github.com/unicitynetwork/bft-core/tree/avl"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/util"
+)
+
+const nodeKeyPrefix = 'n'
+
+var (
+	cborNull = []byte{0xf6}
+	headKey  = []byte("head")
+)
+
+var _ avl.NodeStore[types.UnitID, Unit] = (*NodeStore)(nil)
+
+type (
+	/*
+		NodeStore keeps the nodes of the committed state tree in the key-value database
+		instead of the memory, only the recently used nodes are cached in memory. The
+		nodes changed in the round are written to the database when the state is
+		committed, the uncommitted changes (savepoints) are kept in memory.
+
+		The committed state is kept in the database across restarts, see NewStoredState.
+	*/
+	NodeStore struct {
+		db    keyvaluedb.KeyValueDB
+		udc   UnitDataConstructor
+		cache *lru.Cache[string, *node]
+		head  *storedHead
+		// the ids of the failed commit are never reused as the nodes are cached when written
+		nextID uint64
+	}
+
+	// storedHead is the committed state of the store, written in the same database transaction as the nodes.
+	storedHead struct {
+		_      struct{} `cbor:",toarray"`
+		Root   []byte
+		Header *Header // UC and executed transactions of the committed state
+		NextID uint64
+		// committed units with multiple logs, see State.Prune
+		UnitsToPrune []types.UnitID
+		// nodes orphaned by the commit of the round, deleted once the previous trees are not used anymore
+		Orphans []orphanedNodes
+	}
+
+	orphanedNodes struct {
+		_     struct{} `cbor:",toarray"`
+		Round uint64
+		Refs  [][]byte
+	}
+
+	storedNode struct {
+		_                   struct{} `cbor:",toarray"`
+		UnitID              types.UnitID
+		Depth               int64
+		Left                []byte
+		Right               []byte
+		Logs                []*storedLog
+		LogsHash            []byte
+		Data                types.RawCBOR
+		DeletionRound       uint64
+		StateLockTx         []byte
+		SubTreeSummaryValue uint64
+		SubTreeSummaryHash  []byte
+		SubTreeExpiryRound  uint64
+	}
+
+	storedLog struct {
+		_                  struct{} `cbor:",toarray"`
+		TxRecordHash       []byte
+		UnitLedgerHeadHash []byte
+		NewUnitData        types.RawCBOR
+		DeletionRound      uint64
+		NewStateLockTx     []byte
+	}
+)
+
+/*
+NewNodeStore returns the state tree node store backed by the given database, which
+must be either empty or contain the state committed by the node store. Up to cacheSize
+nodes are cached in memory. The unit data constructor is used to decode the unit data
+of the nodes loaded from the database.
+*/
+func NewNodeStore(db keyvaluedb.KeyValueDB, udc UnitDataConstructor, cacheSize int) (*NodeStore, error) {
+	if db == nil {
+		return nil, errors.New("database is nil")
+	}
+	if udc == nil {
+		return nil, errors.New("unit data constructor is nil")
+	}
+	cache, err := lru.New[string, *node](cacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("creating node cache: %w", err)
+	}
+	head := &storedHead{}
+	found, err := db.Read(headKey, head)
+	if err != nil {
+		return nil, fmt.Errorf("reading state head: %w", err)
+	}
+	if !found {
+		empty, err := keyvaluedb.IsEmpty(db)
+		if err != nil {
+			return nil, fmt.Errorf("checking database: %w", err)
+		}
+		if !empty {
+			return nil, errors.New("database is not empty")
+		}
+		return &NodeStore{db: db, udc: udc, cache: cache}, nil
+	}
+	return &NodeStore{db: db, udc: udc, cache: cache, head: head, nextID: head.NextID}, nil
+}
+
+// IsEmpty returns true if no state has been committed to the store.
+func (s *NodeStore) IsEmpty() bool {
+	return s.head == nil
+}
+
+// LoadNode returns the node with given reference from the cache or from the database.
+func (s *NodeStore) LoadNode(ref []byte) (*node, error) {
+	if n, ok := s.cache.Get(string(ref)); ok {
+		return n, nil
+	}
+	var sn storedNode
+	found, err := s.db.Read(nodeKey(ref), &sn)
+	if err != nil {
+		return nil, fmt.Errorf("reading node: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("node %X not found", ref)
+	}
+	unit, err := s.decodeUnit(&sn)
+	if err != nil {
+		return nil, fmt.Errorf("decoding unit %s: %w", sn.UnitID, err)
+	}
+	n := avl.NewPersistedNode[types.UnitID, Unit](s, ref, sn.UnitID, unit, sn.Depth, sn.Left, sn.Right)
+	s.cache.Add(string(ref), n)
+	return n, nil
+}
+
+/*
+commit writes the nodes of the tree t which are not persisted yet and the header of
+the committed state to the database and returns the root node of the persisted tree
+and IDs of the units which have more than one log (ie need pruning). The nodes of the
+previous version of the tree which are not part of t are deleted after keepRounds rounds.
+*/
+func (s *NodeStore) commit(t, prev *tree, header *Header, keepRounds uint64) (*node, []types.UnitID, error) {
+	round := header.UnicityCertificate.GetRoundNumber()
+	head := &storedHead{Header: header}
+	var pending []orphanedNodes
+	if s.head != nil {
+		pending = s.head.Orphans
+	}
+	var orphans [][]byte
+	if prev != nil {
+		var err error
+		if orphans, err = prev.Orphans(t); err != nil {
+			return nil, nil, fmt.Errorf("finding orphaned nodes: %w", err)
+		}
+	}
+	if len(orphans) > 0 {
+		pending = append(slices.Clip(pending), orphanedNodes{Round: round, Refs: orphans})
+	}
+	var deleted [][]byte
+	for len(pending) > 0 && pending[0].Round+keepRounds <= round {
+		deleted = append(deleted, pending[0].Refs...)
+		pending = pending[1:]
+	}
+	head.Orphans = pending
+
+	dbTx, err := s.db.StartTx()
+	if err != nil {
+		return nil, nil, fmt.Errorf("starting database transaction: %w", err)
+	}
+	if err := s.write(dbTx, t, head, deleted); err != nil {
+		if e := dbTx.Rollback(); e != nil {
+			err = errors.Join(err, fmt.Errorf("database transaction rollback failed: %w", e))
+		}
+		return nil, nil, err
+	}
+	if err := dbTx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("committing database transaction: %w", err)
+	}
+	s.head = head
+	for _, ref := range deleted {
+		s.cache.Remove(string(ref))
+	}
+
+	if head.Root == nil {
+		return nil, head.UnitsToPrune, nil
+	}
+	root, err := s.LoadNode(head.Root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading root node: %w", err)
+	}
+	return root, head.UnitsToPrune, nil
+}
+
+/*
+write writes the new nodes of the tree and the head and deletes the given nodes in the
+database transaction, the root reference, the units to prune and the next node ID of
+the head are set.
+*/
+func (s *NodeStore) write(dbTx keyvaluedb.DBTransaction, t *tree, head *storedHead, deleted [][]byte) error {
+	rootRef, err := t.Persist(func(n *node, leftRef, rightRef []byte) ([]byte, error) {
+		unit, err := ToUnitV1(n.Value())
+		if err != nil {
+			return nil, err
+		}
+		sn, err := newStoredNode(n.Key(), unit, n.Depth(), leftRef, rightRef)
+		if err != nil {
+			return nil, fmt.Errorf("encoding unit %s: %w", n.Key(), err)
+		}
+		s.nextID++
+		ref := util.Uint64ToBytes(s.nextID)
+		if err := dbTx.Write(nodeKey(ref), sn); err != nil {
+			return nil, fmt.Errorf("writing node: %w", err)
+		}
+		s.cache.Add(string(ref), avl.NewPersistedNode[types.UnitID, Unit](s, ref, n.Key(), n.Value(), n.Depth(), leftRef, rightRef))
+		if len(unit.logs) > 1 {
+			head.UnitsToPrune = append(head.UnitsToPrune, n.Key())
+		}
+		return ref, nil
+	})
+	if err != nil {
+		return fmt.Errorf("writing nodes: %w", err)
+	}
+	for _, ref := range deleted {
+		if err := dbTx.Delete(nodeKey(ref)); err != nil {
+			return fmt.Errorf("deleting node: %w", err)
+		}
+	}
+	head.Root = rootRef
+	head.NextID = s.nextID
+	if err := dbTx.Write(headKey, head); err != nil {
+		return fmt.Errorf("writing state head: %w", err)
+	}
+	return nil
+}
+
+func (s *NodeStore) decodeUnit(sn *storedNode) (*UnitV1, error) {
+	data, err := s.decodeUnitData(sn.UnitID, sn.Data)
+	if err != nil {
+		return nil, err
+	}
+	unit := &UnitV1{
+		logs:                make([]*Log, len(sn.Logs)),
+		logsHash:            sn.LogsHash,
+		data:                data,
+		deletionRound:       sn.DeletionRound,
+		stateLockTx:         sn.StateLockTx,
+		subTreeSummaryValue: sn.SubTreeSummaryValue,
+		subTreeSummaryHash:  sn.SubTreeSummaryHash,
+		subTreeExpiryRound:  sn.SubTreeExpiryRound,
+		summaryCalculated:   true,
+	}
+	for i, l := range sn.Logs {
+		logData, err := s.decodeUnitData(sn.UnitID, l.NewUnitData)
+		if err != nil {
+			return nil, fmt.Errorf("log %d: %w", i, err)
+		}
+		unit.logs[i] = &Log{
+			TxRecordHash:       l.TxRecordHash,
+			UnitLedgerHeadHash: l.UnitLedgerHeadHash,
+			NewUnitData:        logData,
+			DeletionRound:      l.DeletionRound,
+			NewStateLockTx:     l.NewStateLockTx,
+		}
+	}
+	return unit, nil
+}
+
+func (s *NodeStore) decodeUnitData(id types.UnitID, data types.RawCBOR) (types.UnitData, error) {
+	if len(data) == 0 || bytes.Equal(data, cborNull) {
+		return nil, nil
+	}
+	unitData, err := s.udc(id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct unit data: %w", err)
+	}
+	if err := types.Cbor.Unmarshal(data, &unitData); err != nil {
+		return nil, fmt.Errorf("unable to decode unit data: %w", err)
+	}
+	return unitData, nil
+}
+
+func newStoredNode(id types.UnitID, unit *UnitV1, depth int64, leftRef, rightRef []byte) (*storedNode, error) {
+	data, err := types.Cbor.Marshal(unit.data)
+	if err != nil {
+		return nil, err
+	}
+	sn := &storedNode{
+		UnitID:              id,
+		Depth:               depth,
+		Left:                leftRef,
+		Right:               rightRef,
+		Logs:                make([]*storedLog, len(unit.logs)),
+		LogsHash:            unit.logsHash,
+		Data:                data,
+		DeletionRound:       unit.deletionRound,
+		StateLockTx:         unit.stateLockTx,
+		SubTreeSummaryValue: unit.subTreeSummaryValue,
+		SubTreeSummaryHash:  unit.subTreeSummaryHash,
+		SubTreeExpiryRound:  unit.subTreeExpiryRound,
+	}
+	for i, l := range unit.logs {
+		logData, err := types.Cbor.Marshal(l.NewUnitData)
+		if err != nil {
+			return nil, fmt.Errorf("log %d: %w", i, err)
+		}
+		sn.Logs[i] = &storedLog{
+			TxRecordHash:       l.TxRecordHash,
+			UnitLedgerHeadHash: l.UnitLedgerHeadHash,
+			NewUnitData:        logData,
+			DeletionRound:      l.DeletionRound,
+			NewStateLockTx:     l.NewStateLockTx,
+		}
+	}
+	return sn, nil
+}
+
+func nodeKey(ref []byte) []byte {
+	return append([]byte{nodeKeyPrefix}, ref...)
+}
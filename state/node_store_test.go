@@ -0,0 +1,321 @@
+package state
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicitynetwork/bft-core/keyvaluedb"
+	"github.com/unicitynetwork/bft-core/keyvaluedb/memorydb"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+func TestNewNodeStore(t *testing.T) {
+	db, err := memorydb.New()
+	require.NoError(t, err)
+
+	_, err = NewNodeStore(nil, unitDataConstructor, 10)
+	require.EqualError(t, err, "database is nil")
+	_, err = NewNodeStore(db, nil, 10)
+	require.EqualError(t, err, "unit data constructor is nil")
+	_, err = NewNodeStore(db, unitDataConstructor, 0)
+	require.ErrorContains(t, err, "creating node cache")
+
+	store, err := NewNodeStore(db, unitDataConstructor, 10)
+	require.NoError(t, err)
+	require.True(t, store.IsEmpty())
+	_, _, err = NewStoredState(store)
+	require.EqualError(t, err, "node store is empty")
+
+	require.NoError(t, db.Write([]byte{1}, []byte{1}))
+	_, err = NewNodeStore(db, unitDataConstructor, 10)
+	require.EqualError(t, err, "database is not empty")
+}
+
+func TestNodeStore_StateIsEqualToInMemoryState(t *testing.T) {
+	db, err := memorydb.New()
+	require.NoError(t, err)
+	// small cache so that most of the nodes are loaded from the database
+	store, err := NewNodeStore(db, unitDataConstructor, 8)
+	require.NoError(t, err)
+
+	memState := NewEmptyState()
+	dbState := NewEmptyState(WithNodeStore(store))
+	states := []*State{memState, dbState}
+
+	rnd := rand.New(rand.NewSource(1))
+	unitID := func() types.UnitID { return types.UnitID{0, 0, 0, byte(rnd.Intn(64))} }
+	exists := func(id types.UnitID) bool {
+		_, err := memState.GetUnit(id, false)
+		return err == nil
+	}
+
+	for round := uint64(1); round <= 30; round++ {
+		for _, s := range states {
+			require.NoError(t, s.Prune())
+		}
+		for range 10 {
+			id := unitID()
+			var actions []Action
+			if !exists(id) {
+				switch rnd.Intn(3) {
+				case 0:
+					actions = append(actions, AddDummyUnit(id))
+				default:
+					actions = append(actions, AddUnit(id, &pruneUnitData{I: uint64(rnd.Intn(100))}))
+				}
+			} else {
+				switch rnd.Intn(4) {
+				case 0:
+					actions = append(actions, DeleteUnit(id))
+				case 1:
+					actions = append(actions, SetStateLock(id, []byte{byte(round)}))
+				case 2:
+					actions = append(actions, MarkForDeletion(id, round+100))
+				default:
+					actions = append(actions, UpdateUnitData(id, func(data types.UnitData) (types.UnitData, error) {
+						return &pruneUnitData{I: round}, nil
+					}))
+				}
+			}
+			// failing action must roll back the changes of the preceding actions
+			failing := rnd.Intn(5) == 0
+			if failing {
+				actions = append(actions, func(ShardState, crypto.Hash) error { return errors.New("failed") })
+			}
+			for _, s := range states {
+				err := s.Apply(actions...)
+				if failing {
+					require.Error(t, err)
+					continue
+				}
+				// some actions fail, eg state lock is already set, must fail in both states
+				if err != nil {
+					continue
+				}
+				if u, err := s.GetUnit(id, false); err == nil && !u.(*UnitV1).IsDummy() {
+					require.NoError(t, s.AddUnitLog(id, []byte{byte(round)}))
+				}
+			}
+		}
+
+		var summaryValue uint64
+		var rootHash []byte
+		for i, s := range states {
+			sv, h, err := s.CalculateRoot()
+			require.NoError(t, err)
+			if i > 0 {
+				require.Equal(t, summaryValue, sv)
+				require.Equal(t, rootHash, h)
+			}
+			summaryValue, rootHash = sv, h
+		}
+		if round%7 == 0 {
+			// round is not certified
+			for _, s := range states {
+				s.Revert()
+			}
+			continue
+		}
+		uc := createUC(t, memState, summaryValue, rootHash)
+		uc.InputRecord.RoundNumber = round
+		for _, s := range states {
+			require.NoError(t, s.Commit(uc))
+		}
+		requireEqualStates(t, memState, dbState)
+	}
+
+	// database contains the nodes of the committed tree and the nodes orphaned in the last round
+	units, err := memState.GetUnits(nil, nil)
+	require.NoError(t, err)
+	orphans := 0
+	for _, o := range store.head.Orphans {
+		orphans += len(o.Refs)
+	}
+	require.Equal(t, len(units)+orphans, countNodes(t, db))
+}
+
+func TestNodeStore_RecoveredState(t *testing.T) {
+	s, _, _ := prepareState(t)
+	updateUnits(t, s)
+	buf := &bytes.Buffer{}
+	require.NoError(t, s.Serialize(buf, true, nil))
+
+	db, err := memorydb.New()
+	require.NoError(t, err)
+	store, err := NewNodeStore(db, unitDataConstructor, 4)
+	require.NoError(t, err)
+	recovered, _, err := NewRecoveredState(buf, unitDataConstructor, WithNodeStore(store))
+	require.NoError(t, err)
+	require.Equal(t, len(unitIdentifiers), countNodes(t, db))
+	require.Equal(t, s.CommittedUC(), recovered.CommittedUC())
+
+	// units recovered with multiple logs are pruned
+	require.NoError(t, recovered.Prune())
+	for _, id := range unitIdentifiers {
+		u, err := recovered.GetUnit(id, false)
+		require.NoError(t, err)
+		require.Len(t, u.(*UnitV1).logs, 1)
+	}
+	require.NoError(t, s.Prune())
+	for _, st := range []*State{s, recovered} {
+		require.NoError(t, st.Apply(UpdateUnitData(unitIdentifiers[0], multiply(10))))
+		require.NoError(t, st.AddUnitLog(unitIdentifiers[0], []byte{1}))
+		commitState(t, st)
+	}
+	requireEqualStates(t, s, recovered)
+}
+
+func TestNodeStore_StoredState(t *testing.T) {
+	db, err := memorydb.New()
+	require.NoError(t, err)
+	store, err := NewNodeStore(db, unitDataConstructor, 4)
+	require.NoError(t, err)
+	s, _, _ := prepareState(t)
+	buf := &bytes.Buffer{}
+	require.NoError(t, s.Serialize(buf, true, map[string]uint64{"tx1": 10}))
+	memState, _, err := NewRecoveredState(bytes.NewReader(buf.Bytes()), unitDataConstructor)
+	require.NoError(t, err)
+	dbState, _, err := NewRecoveredState(buf, unitDataConstructor, WithNodeStore(store))
+	require.NoError(t, err)
+	executed := map[string]uint64{"tx1": 10, "tx2": 20}
+	var uc *types.UnicityCertificate
+	for _, st := range []*State{memState, dbState} {
+		require.NoError(t, st.Prune())
+		for _, id := range unitIdentifiers[:3] {
+			require.NoError(t, st.Apply(UpdateUnitData(id, multiply(10))))
+			require.NoError(t, st.AddUnitLog(id, []byte{2}))
+		}
+		summaryValue, rootHash, err := st.CalculateRoot()
+		require.NoError(t, err)
+		if uc == nil {
+			uc = createUC(t, st, summaryValue, rootHash)
+		}
+		require.NoError(t, st.CommitWithExecutedTransactions(uc, executed))
+	}
+
+	// the committed state is recovered from the database, the nodes are loaded on demand
+	store, err = NewNodeStore(db, unitDataConstructor, 4)
+	require.NoError(t, err)
+	require.False(t, store.IsEmpty())
+	recovered, header, err := NewStoredState(store)
+	require.NoError(t, err)
+	require.Equal(t, uc, header.UnicityCertificate)
+	require.Equal(t, executed, header.ExecutedTransactions)
+	require.Equal(t, uc, recovered.CommittedUC())
+	require.NotNil(t, recovered.committedTree.Root().Ref())
+	requireEqualStates(t, memState, recovered)
+
+	// the units changed in the last committed round are pruned
+	for _, st := range []*State{memState, recovered} {
+		require.NoError(t, st.Prune())
+		require.NoError(t, st.Apply(UpdateUnitData(unitIdentifiers[0], multiply(10))))
+		require.NoError(t, st.AddUnitLog(unitIdentifiers[0], []byte{1}))
+		commitState(t, st)
+	}
+	requireEqualStates(t, memState, recovered)
+}
+
+func TestNodeStore_Archive(t *testing.T) {
+	db, err := memorydb.New()
+	require.NoError(t, err)
+	store, err := NewNodeStore(db, unitDataConstructor, 4)
+	require.NoError(t, err)
+	s := NewEmptyState(WithNodeStore(store), WithArchive(2))
+	id := types.UnitID{0, 0, 0, 1}
+	require.NoError(t, s.Apply(AddUnit(id, &pruneUnitData{I: 1})))
+	require.NoError(t, s.AddUnitLog(id, []byte{1}))
+	commitState(t, s)
+	for range 4 {
+		require.NoError(t, s.Prune())
+		require.NoError(t, s.Apply(UpdateUnitData(id, multiply(10))))
+		require.NoError(t, s.AddUnitLog(id, []byte{1}))
+		commitState(t, s)
+	}
+	// nodes of the archived rounds are kept in the database
+	require.Equal(t, 4, countNodes(t, db))
+	for round, value := range map[uint64]uint64{3: 100, 4: 1000, 5: 10000} {
+		rs, err := s.AtRound(round)
+		require.NoError(t, err)
+		u, err := rs.GetUnit(id, true)
+		require.NoError(t, err)
+		require.Equal(t, value, u.Data().SummaryValueInput())
+		_, err = rs.CreateUnitStateProof(id, 0)
+		require.NoError(t, err)
+	}
+}
+
+func TestNodeStore_LoadError(t *testing.T) {
+	db, err := memorydb.New()
+	require.NoError(t, err)
+	store, err := NewNodeStore(db, unitDataConstructor, 1)
+	require.NoError(t, err)
+	s, _, _ := prepareState(t)
+	buf := &bytes.Buffer{}
+	require.NoError(t, s.Serialize(buf, true, nil))
+	s, _, err = NewRecoveredState(buf, unitDataConstructor, WithNodeStore(store))
+	require.NoError(t, err)
+
+	// delete all nodes from the database
+	it := db.First()
+	var keys [][]byte
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	require.NoError(t, it.Close())
+	for _, k := range keys {
+		require.NoError(t, db.Delete(k))
+	}
+	_, err = s.GetUnit(unitIdentifiers[0], true)
+	require.ErrorContains(t, err, "not found")
+	_, err = s.CreateUnitStateProof(unitIdentifiers[0], 0)
+	require.ErrorContains(t, err, "not found")
+	_, err = s.GetUnits(nil, nil)
+	require.ErrorContains(t, err, "not found")
+}
+
+func requireEqualStates(t *testing.T, expected, actual *State) {
+	t.Helper()
+	units, err := expected.GetUnits(nil, nil)
+	require.NoError(t, err)
+	actualUnits, err := actual.GetUnits(nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, units, actualUnits)
+	for _, id := range units {
+		eu, err := expected.GetUnit(id, true)
+		require.NoError(t, err)
+		au, err := actual.GetUnit(id, true)
+		require.NoError(t, err)
+		e, a := eu.(*UnitV1), au.(*UnitV1)
+		require.Equal(t, e.data, a.data)
+		require.Equal(t, e.logs, a.logs)
+		require.Equal(t, e.logsHash, a.logsHash)
+		require.Equal(t, e.stateLockTx, a.stateLockTx)
+		require.Equal(t, e.deletionRound, a.deletionRound)
+		require.Equal(t, e.subTreeSummaryValue, a.subTreeSummaryValue)
+		require.Equal(t, e.subTreeSummaryHash, a.subTreeSummaryHash)
+		if e.IsDummy() {
+			continue
+		}
+		for i := range e.logs {
+			ep, err := expected.CreateUnitStateProof(id, i)
+			require.NoError(t, err)
+			ap, err := actual.CreateUnitStateProof(id, i)
+			require.NoError(t, err)
+			require.Equal(t, ep, ap)
+		}
+	}
+}
+
+func countNodes(t *testing.T, db keyvaluedb.KeyValueDB) int {
+	it := db.Find([]byte{nodeKeyPrefix})
+	defer func() { require.NoError(t, it.Close()) }()
+	count := 0
+	for ; it.Valid() && it.Key()[0] == nodeKeyPrefix; it.Next() {
+		count++
+	}
+	return count
+}
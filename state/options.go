@@ -7,6 +7,9 @@ import (
 type (
 	Options struct {
 		hashAlgorithm crypto.Hash
+		archiveRounds uint64
+		nodeStore     *NodeStore
+		hashWorkers   int
 	}
 
 	Option func(o *Options)
@@ -18,6 +21,37 @@ func WithHashAlgorithm(hashAlgorithm crypto.Hash) Option {
 	}
 }
 
+/*
+WithArchive enables the archive mode of the state - committed state of the
+given number of previous rounds is retained and can be queried using AtRound.
+*/
+func WithArchive(rounds uint64) Option {
+	return func(o *Options) {
+		o.archiveRounds = rounds
+	}
+}
+
+/*
+WithNodeStore keeps the committed state tree in the given node store instead of
+the memory, see NodeStore.
+*/
+func WithNodeStore(store *NodeStore) Option {
+	return func(o *Options) {
+		o.nodeStore = store
+	}
+}
+
+/*
+WithHashWorkers sets the number of goroutines calculating the state root hash,
+the independent subtrees of the changed state tree are hashed concurrently.
+The state is hashed sequentially by default.
+*/
+func WithHashWorkers(workers int) Option {
+	return func(o *Options) {
+		o.hashWorkers = workers
+	}
+}
+
 func loadOptions(opts ...Option) *Options {
 	options := &Options{
 		hashAlgorithm: crypto.SHA256,
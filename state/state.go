@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"sync"
 
 	"github.com/fxamacker/cbor/v2"
@@ -15,6 +16,8 @@ import (
 	"github.com/unicitynetwork/bft-go-base/util"
 )
 
+var ErrRoundNotArchived = errors.New("state of the round is not archived")
+
 type (
 
 	// State is a data structure that keeps track of units, unit ledgers, and calculates global state tree root hash.
@@ -33,6 +36,25 @@ type (
 		// savepoint is a special marker that allows all actions that are executed after tree was established to
 		// be rolled back, restoring the state to what it was at the time of the tree.
 		savepoints []*tree
+
+		// committed trees of the previous rounds (oldest first) when the archive mode is enabled
+		archiveRounds uint64
+		archive       []archivedTree
+
+		// when set the committed tree is kept in the store, unitsToPrune are the committed units with multiple logs
+		nodeStore    *NodeStore
+		unitsToPrune []types.UnitID
+
+		// number of goroutines calculating the root hash, see WithHashWorkers
+		hashWorkers int
+
+		// units accessed since the access tracking was started, nil when not tracking
+		access *UnitAccess
+	}
+
+	archivedTree struct {
+		tree *tree
+		uc   *types.UnicityCertificate
 	}
 
 	Unit interface {
@@ -47,18 +69,35 @@ type (
 
 	// UnitDataConstructor is a function that constructs an empty UnitData structure based on UnitID
 	UnitDataConstructor func(types.UnitID) (types.UnitData, error)
+
+	/*
+		UnitStateProofError is returned by CreateUnitStateProof when the generated
+		proof does not verify against the committed unicity certificate, ie the
+		state is corrupted.
+	*/
+	UnitStateProofError struct {
+		UnitID   types.UnitID
+		LogIndex int
+		Err      error
+	}
+
+	// committedUC is the UC validator of the state's own proofs, the UC is validated before it is committed
+	committedUC struct{}
 )
 
 func NewEmptyState(opts ...Option) *State {
 	options := loadOptions(opts...)
 
-	hasher := newStateHasher(options.hashAlgorithm)
+	hasher := newStateHasher(options.hashAlgorithm, options.hashWorkers)
 	t := avl.NewWithTraverser[types.UnitID, Unit](hasher)
 
 	return &State{
 		hashAlgorithm: options.hashAlgorithm,
 		committedTree: t,
 		savepoints:    []*tree{t.Clone()},
+		archiveRounds: options.archiveRounds,
+		nodeStore:     options.nodeStore,
+		hashWorkers:   options.hashWorkers,
 	}
 }
 
@@ -72,6 +111,39 @@ func NewRecoveredState(stateData io.Reader, udc UnitDataConstructor, opts ...Opt
 	return readState(stateData, udc, opts...)
 }
 
+/*
+NewStoredState returns the state committed to the given node store (must not be empty),
+the nodes of the state tree are loaded from the store on demand.
+*/
+func NewStoredState(store *NodeStore, opts ...Option) (*State, *Header, error) {
+	if store == nil {
+		return nil, nil, fmt.Errorf("node store is nil")
+	}
+	if store.IsEmpty() {
+		return nil, nil, fmt.Errorf("node store is empty")
+	}
+	options := loadOptions(opts...)
+	var root *node
+	if ref := store.head.Root; ref != nil {
+		var err error
+		if root, err = store.LoadNode(ref); err != nil {
+			return nil, nil, fmt.Errorf("unable to load root node: %w", err)
+		}
+	}
+	t := avl.NewWithTraverserAndRoot[types.UnitID, Unit](newStateHasher(options.hashAlgorithm, options.hashWorkers), root)
+	header := store.head.Header
+	return &State{
+		hashAlgorithm:   options.hashAlgorithm,
+		committedTree:   t.Clone(),
+		committedTreeUC: header.UnicityCertificate,
+		savepoints:      []*tree{t},
+		archiveRounds:   options.archiveRounds,
+		nodeStore:       store,
+		unitsToPrune:    store.head.UnitsToPrune,
+		hashWorkers:     options.hashWorkers,
+	}, header, nil
+}
+
 func readNodeRecords(decoder *cbor.Decoder, unitDataConstructor UnitDataConstructor, count uint64, hashAlgorithm crypto.Hash) (*node, error) {
 	if count == 0 {
 		return nil, nil
@@ -79,7 +151,7 @@ func readNodeRecords(decoder *cbor.Decoder, unitDataConstructor UnitDataConstruc
 
 	var nodeStack util.Stack[*node]
 	for i := uint64(0); i < count; i++ {
-		var nodeRecord nodeRecord
+		var nodeRecord NodeRecord
 		err := decoder.Decode(&nodeRecord)
 		if err != nil {
 			return nil, fmt.Errorf("unable to decode node record: %w", err)
@@ -137,6 +209,7 @@ func readNodeRecords(decoder *cbor.Decoder, unitDataConstructor UnitDataConstruc
 
 // Clone returns a clone of the state. The original state and the cloned state can be used by different goroutines but
 // can never be merged. The cloned state is usually used by read only operations (e.g. unit proof generation).
+// Changes committed to the cloned state are not written to the node store of the original state.
 func (s *State) Clone() *State {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -145,7 +218,35 @@ func (s *State) Clone() *State {
 		committedTree:   s.committedTree.Clone(),
 		committedTreeUC: s.committedTreeUC,
 		savepoints:      []*tree{s.latestSavepoint().Clone()},
+		archiveRounds:   s.archiveRounds,
+		archive:         slices.Clone(s.archive),
+	}
+}
+
+/*
+AtRound returns read-only view of the committed state of the given round. The
+state of the latest committed round is always available, the state of the
+previous rounds only when the archive mode is enabled (see WithArchive option).
+Unit state proofs created by the returned state are against the UC of the round.
+*/
+func (s *State) AtRound(round uint64) (*State, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	t, uc := s.committedTree, s.committedTreeUC
+	if uc.GetRoundNumber() != round {
+		i := slices.IndexFunc(s.archive, func(at archivedTree) bool { return at.uc.GetRoundNumber() == round })
+		if i < 0 {
+			return nil, fmt.Errorf("round %d: %w", round, ErrRoundNotArchived)
+		}
+		t, uc = s.archive[i].tree, s.archive[i].uc
 	}
+	return &State{
+		hashAlgorithm:   s.hashAlgorithm,
+		committedTree:   t.Clone(),
+		committedTreeUC: uc,
+		savepoints:      []*tree{t.Clone()},
+	}, nil
 }
 
 func (s *State) GetUnit(id types.UnitID, committed bool) (Unit, error) {
@@ -154,6 +255,9 @@ func (s *State) GetUnit(id types.UnitID, committed bool) (Unit, error) {
 	if committed {
 		return s.committedTree.Get(id)
 	}
+	if s.access != nil {
+		s.access.addRead(id)
+	}
 	u, err := s.latestSavepoint().Get(id)
 	if err != nil {
 		return nil, err
@@ -176,7 +280,14 @@ func (s *State) AddUnitLog(id types.UnitID, txrHash []byte) error {
 	if err := unit.AddUnitLog(s.hashAlgorithm, txrHash); err != nil {
 		return fmt.Errorf("failed to add unit log: %w", err)
 	}
-	return s.latestSavepoint().Update(id, unit)
+	if err := s.latestSavepoint().Update(id, unit); err != nil {
+		return err
+	}
+	if s.access != nil {
+		s.access.addRead(id)
+		s.access.addChange(changeUpdate, id, unit)
+	}
+	return nil
 }
 
 // Apply applies given actions to the state. All Action functions are executed together as a single atomic operation. If
@@ -189,8 +300,12 @@ func (s *State) Apply(actions ...Action) error {
 	if err != nil {
 		return fmt.Errorf("unable to create savepoint: %w", err)
 	}
+	var st ShardState = s.latestSavepoint()
+	if s.access != nil {
+		st = &trackedShardState{t: s.latestSavepoint(), access: s.access}
+	}
 	for _, action := range actions {
-		if err := action(s.latestSavepoint(), s.hashAlgorithm); err != nil {
+		if err := action(st, s.hashAlgorithm); err != nil {
 			s.rollbackToSavepoint(id)
 			return err
 		}
@@ -201,6 +316,16 @@ func (s *State) Apply(actions ...Action) error {
 
 // Commit makes the changes in the latest savepoint permanent.
 func (s *State) Commit(uc *types.UnicityCertificate) error {
+	return s.CommitWithExecutedTransactions(uc, nil)
+}
+
+/*
+CommitWithExecutedTransactions makes the changes in the latest savepoint permanent
+like Commit. When the state tree is kept in the NodeStore the executed transactions
+(see Header) are stored with the committed state, so that the state can be recovered
+from the store, see NewStoredState.
+*/
+func (s *State) CommitWithExecutedTransactions(uc *types.UnicityCertificate, executedTransactions map[string]uint64) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -229,12 +354,38 @@ func (s *State) Commit(uc *types.UnicityCertificate) error {
 		return fmt.Errorf("state summary value is not equal to the summary value in UC")
 	}
 
+	if s.nodeStore != nil {
+		header := &Header{Version: 1, UnicityCertificate: uc, ExecutedTransactions: executedTransactions}
+		root, unitsToPrune, err := s.nodeStore.commit(sp, s.committedTree, header, s.archiveRounds+1)
+		if err != nil {
+			return fmt.Errorf("unable to store state: %w", err)
+		}
+		sp = avl.NewWithTraverserAndRoot[types.UnitID, Unit](newStateHasher(s.hashAlgorithm, s.hashWorkers), root)
+		s.unitsToPrune = unitsToPrune
+	}
+
+	s.archiveCommitted(uc.GetRoundNumber())
 	s.committedTree = sp.Clone()
 	s.committedTreeUC = uc
 	s.savepoints = []*tree{sp}
 	return nil
 }
 
+// archiveCommitted moves the currently committed tree to the archive before committing the given round.
+func (s *State) archiveCommitted(round uint64) {
+	if s.archiveRounds == 0 {
+		return
+	}
+	// the state might have been rolled back, the archive must not contain rounds from the "future"
+	s.archive = slices.DeleteFunc(s.archive, func(at archivedTree) bool { return at.uc.GetRoundNumber() >= round })
+	if s.committedTreeUC != nil && s.committedTreeUC.GetRoundNumber() < round {
+		s.archive = append(s.archive, archivedTree{tree: s.committedTree, uc: s.committedTreeUC})
+	}
+	if n := uint64(len(s.archive)); n > s.archiveRounds {
+		s.archive = slices.Delete(s.archive, 0, int(n-s.archiveRounds)) /* #nosec G115 n is the length of the slice so it fits into int */
+	}
+}
+
 // CommittedUC returns the Unicity Certificate of the committed state.
 func (s *State) CommittedUC() *types.UnicityCertificate {
 	s.mutex.RLock()
@@ -247,6 +398,10 @@ func (s *State) Revert() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	s.savepoints = []*tree{s.committedTree.Clone()}
+	if s.access != nil {
+		s.access.changes = nil
+		s.access.marks = []int{0}
+	}
 }
 
 // Savepoint creates a new savepoint and returns an id of the savepoint. Use RollbackToSavepoint to roll back all
@@ -308,6 +463,10 @@ func (s *State) Prune() error {
 	defer s.mutex.Unlock()
 	sp := s.latestSavepoint()
 	pruner := newStatePruner(sp)
+	if s.nodeStore != nil && s.committedTree.Root().Ref() != nil {
+		// avoid loading the whole tree from the store, only the units changed in the round have multiple logs
+		return pruner.pruneUnits(s.unitsToPrune)
+	}
 	return sp.Traverse(pruner)
 }
 
@@ -393,12 +552,13 @@ func (s *State) CreateUnitStateProof(id types.UnitID, logIndex int) (*types.Unit
 	}
 	// if unit was created then we do not have a previous unit ledger state hash and this variable is nil.
 	var unitLedgerHeadHash []byte
-	if logIndex > 0 {
+	if unit.logs[logIndex].TxRecordHash == nil {
+		// initial state was copied from previous round or the state was changed
+		// without transaction (ie by the round initialization)
+		unitLedgerHeadHash = unit.logs[logIndex].UnitLedgerHeadHash
+	} else if logIndex > 0 {
 		// existing unit was updated by a transaction
 		unitLedgerHeadHash = unit.logs[logIndex-1].UnitLedgerHeadHash
-	} else if unit.logs[0].TxRecordHash == nil {
-		// initial state was copied from previous round
-		unitLedgerHeadHash = unit.logs[0].UnitLedgerHeadHash
 	}
 	unitTreeCert, err := s.createUnitTreeCert(unit, logIndex)
 	if err != nil {
@@ -413,15 +573,22 @@ func (s *State) CreateUnitStateProof(id types.UnitID, logIndex int) (*types.Unit
 		summaryValueInput = unit.data.SummaryValueInput()
 	}
 
-	// TODO verify proof before returning
-	return &types.UnitStateProof{
+	proof := &types.UnitStateProof{
 		UnitID:             id,
 		UnitLedgerHash:     unitLedgerHeadHash,
 		UnitTreeCert:       unitTreeCert,
 		UnitValue:          summaryValueInput,
 		StateTreeCert:      stateTreeCert,
 		UnicityCertificate: ucBytes,
-	}, nil
+	}
+	unitState, err := unit.logs[logIndex].UnitState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create unit state: %w", err)
+	}
+	if err := proof.Verify(s.hashAlgorithm, unitState, committedUC{}, nil); err != nil {
+		return nil, &UnitStateProofError{UnitID: id, LogIndex: logIndex, Err: err}
+	}
+	return proof, nil
 }
 
 func (s *State) HashAlgorithm() crypto.Hash {
@@ -521,14 +688,22 @@ func (s *State) createStateTreeCert(id types.UnitID) (*types.StateTreeCert, erro
 		if err != nil {
 			return nil, fmt.Errorf("unable to extract summary value input for unit %s: %w", id, err)
 		}
+		left, err := n.Left()
+		if err != nil {
+			return nil, err
+		}
+		right, err := n.Right()
+		if err != nil {
+			return nil, err
+		}
 		var item *types.StateTreePathItem
 
 		if id.Compare(nodeKey) == -1 {
-			item, err = getStateTreePathItem(n, n.Right(), v, nodeKey)
-			n = n.Left()
+			item, err = getStateTreePathItem(n, right, v, nodeKey)
+			n = left
 		} else {
-			item, err = getStateTreePathItem(n, n.Left(), v, nodeKey)
-			n = n.Right()
+			item, err = getStateTreePathItem(n, left, v, nodeKey)
+			n = right
 		}
 		if err != nil {
 			return nil, err
@@ -536,8 +711,14 @@ func (s *State) createStateTreeCert(id types.UnitID) (*types.StateTreeCert, erro
 		path = append([]*types.StateTreePathItem{item}, path...)
 	}
 	if id.Eq(n.Key()) {
-		nodeLeft := n.Left()
-		nodeRight := n.Right()
+		nodeLeft, err := n.Left()
+		if err != nil {
+			return nil, err
+		}
+		nodeRight, err := n.Right()
+		if err != nil {
+			return nil, err
+		}
 		lv, lh, err := getSubTreeSummary(nodeLeft)
 		if err != nil {
 			return nil, fmt.Errorf("unable to extract left subtree summary for unit %s: %w", id, err)
@@ -565,6 +746,9 @@ func (s *State) createSavepoint() (int, error) {
 		return 0, fmt.Errorf("unable to mark the tree clean: %w", err)
 	}
 	s.savepoints = append(s.savepoints, clonedSavepoint)
+	if s.access != nil {
+		s.access.savepointCreated()
+	}
 	return len(s.savepoints) - 1, nil
 }
 
@@ -575,6 +759,9 @@ func (s *State) rollbackToSavepoint(id int) {
 		return
 	}
 	s.savepoints = s.savepoints[0:id]
+	if s.access != nil {
+		s.access.rolledBack(id)
+	}
 }
 
 func (s *State) releaseToSavepoint(id int) {
@@ -585,6 +772,9 @@ func (s *State) releaseToSavepoint(id int) {
 	}
 	s.savepoints[id-1] = s.latestSavepoint()
 	s.savepoints = s.savepoints[0:id]
+	if s.access != nil {
+		s.access.released(id)
+	}
 }
 
 func (s *State) isCommitted() (bool, error) {
@@ -669,3 +859,26 @@ func getSubTreeSummary(n *node) (uint64, []byte, error) {
 	}
 	return u.subTreeSummaryValue, u.subTreeSummaryHash, nil
 }
+
+func getSubTreeExpiryRound(n *node) (uint64, error) {
+	if n == nil || n.Value() == nil {
+		return 0, nil
+	}
+	u, err := ToUnitV1(n.Value())
+	if err != nil {
+		return 0, err
+	}
+	return u.subTreeExpiryRound, nil
+}
+
+func (e *UnitStateProofError) Error() string {
+	return fmt.Sprintf("unit %s state proof (log index %d) verification failed: %v", e.UnitID, e.LogIndex, e.Err)
+}
+
+func (e *UnitStateProofError) Unwrap() error {
+	return e.Err
+}
+
+func (committedUC) Validate(*types.UnicityCertificate, []byte) error {
+	return nil
+}
@@ -33,17 +33,20 @@ func readState(stateData io.Reader, udc UnitDataConstructor, opts ...Option) (*S
 		return nil, nil, fmt.Errorf("checksum mismatch")
 	}
 
-	hasher := newStateHasher(options.hashAlgorithm)
+	hasher := newStateHasher(options.hashAlgorithm, options.hashWorkers)
 	t := avl.NewWithTraverserAndRoot[types.UnitID, Unit](hasher, root)
 	state := &State{
 		hashAlgorithm: options.hashAlgorithm,
 		savepoints:    []*tree{t},
+		archiveRounds: options.archiveRounds,
+		nodeStore:     options.nodeStore,
+		hashWorkers:   options.hashWorkers,
 	}
 	if _, _, err := state.CalculateRoot(); err != nil {
 		return nil, nil, err
 	}
 	if header.UnicityCertificate != nil {
-		if err := state.Commit(header.UnicityCertificate); err != nil {
+		if err := state.CommitWithExecutedTransactions(header.UnicityCertificate, header.ExecutedTransactions); err != nil {
 			return nil, nil, fmt.Errorf("unable to commit recovered state: %w", err)
 		}
 	} else {
@@ -53,3 +56,47 @@ func readState(stateData io.Reader, udc UnitDataConstructor, opts ...Option) (*S
 
 	return state, &header, nil
 }
+
+/*
+DecodeStateFile decodes serialized state without restoring the state tree. The
+"header" callback is called with the decoded header and the "node" callback with
+each node record (in the order they were serialized), the checksum of the data is
+verified after all the node records have been read. Callbacks may be nil.
+
+Meant for inspecting state files, use NewRecoveredState to restore the state.
+*/
+func DecodeStateFile(stateData io.Reader, header func(*Header) error, node func(*NodeRecord) error) error {
+	crc32Reader := NewCRC32Reader(stateData, CBORChecksumLength)
+	decoder := types.Cbor.GetDecoder(crc32Reader)
+
+	var h Header
+	if err := decoder.Decode(&h); err != nil {
+		return fmt.Errorf("unable to decode header: %w", err)
+	}
+	if header != nil {
+		if err := header(&h); err != nil {
+			return err
+		}
+	}
+
+	for i := uint64(0); i < h.NodeRecordCount; i++ {
+		var nr NodeRecord
+		if err := decoder.Decode(&nr); err != nil {
+			return fmt.Errorf("unable to decode node record %d: %w", i, err)
+		}
+		if node != nil {
+			if err := node(&nr); err != nil {
+				return err
+			}
+		}
+	}
+
+	var checksum []byte
+	if err := decoder.Decode(&checksum); err != nil {
+		return fmt.Errorf("unable to decode checksum: %w", err)
+	}
+	if util.BytesToUint32(checksum) != crc32Reader.Sum() {
+		return fmt.Errorf("checksum mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+type (
+	/*
+		ExpiryTraverser visits (in order) the units which may expire by the given round,
+		ie the logically deleted units, the fee credit records without balance and the
+		state locked units. The subtrees without such units are skipped, so the nodes of
+		the persisted state tree are not loaded from the NodeStore.
+	*/
+	ExpiryTraverser struct {
+		round uint64
+		visit func(unitID types.UnitID, unit Unit) error
+	}
+)
+
+func NewExpiryTraverser(round uint64, visitFn func(unitID types.UnitID, unit Unit) error) *ExpiryTraverser {
+	return &ExpiryTraverser{round: round, visit: visitFn}
+}
+
+func (s *ExpiryTraverser) Traverse(n *node) error {
+	if n == nil {
+		return nil
+	}
+	unit, err := ToUnitV1(n.Value())
+	if err != nil {
+		return fmt.Errorf("failed to get unit: %w", err)
+	}
+	if unit.subTreeExpiryRound == 0 || unit.subTreeExpiryRound > s.round {
+		return nil
+	}
+	left, err := n.Left()
+	if err != nil {
+		return err
+	}
+	if err := s.Traverse(left); err != nil {
+		return err
+	}
+	if r := unit.expiryRound(); r != 0 && r <= s.round {
+		if err := s.visit(n.Key(), n.Value()); err != nil {
+			return err
+		}
+	}
+	right, err := n.Right()
+	if err != nil {
+		return err
+	}
+	return s.Traverse(right)
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"crypto"
+	"errors"
 	"fmt"
 
 	"github.com/unicitynetwork/bft-core/tree/avl"
@@ -10,20 +11,39 @@ import (
 	"github.com/unicitynetwork/bft-go-base/types"
 )
 
+// minimum height of the subtree hashed concurrently, hashing smaller subtrees does not pay off the goroutine overhead
+const concurrentHashMinDepth = 8
+
 // stateHasher calculates the root hash of the state tree (see "Invariants of the State Tree" chapter from the
 // yellowpaper for more information).
 type stateHasher struct {
 	avl.PostOrderCommitTraverser[types.UnitID, Unit]
 	hashAlgorithm crypto.Hash
+	// number of goroutines hashing the independent subtrees concurrently, the tree is hashed sequentially when <= 1
+	workers int
 }
 
-func newStateHasher(hashAlgorithm crypto.Hash) *stateHasher {
-	return &stateHasher{hashAlgorithm: hashAlgorithm}
+func newStateHasher(hashAlgorithm crypto.Hash, workers int) *stateHasher {
+	return &stateHasher{hashAlgorithm: hashAlgorithm, workers: workers}
 }
 
 // Traverse visits changed nodes in the state tree and recalculates a new root hash of the state tree.
 // Executed when the State.Commit function is called.
 func (p *stateHasher) Traverse(n *avl.Node[types.UnitID, Unit]) error {
+	if p.workers <= 1 {
+		return p.hash(n, nil)
+	}
+	// the calling goroutine is one of the workers
+	return p.hash(n, make(chan struct{}, p.workers-1))
+}
+
+/*
+hash calculates the summary hash of the subtree rooted at n. When a worker is
+available (sem is not full) the left subtree is hashed on a new goroutine
+concurrently with the right subtree, the parent is hashed once both are done.
+The result is the same as when hashing the tree sequentially.
+*/
+func (p *stateHasher) hash(n *avl.Node[types.UnitID, Unit], sem chan struct{}) error {
 	if n == nil {
 		return nil
 	}
@@ -34,12 +54,15 @@ func (p *stateHasher) Traverse(n *avl.Node[types.UnitID, Unit]) error {
 	if n.Clean() && unit.summaryCalculated {
 		return nil
 	}
-	var left = n.Left()
-	var right = n.Right()
-	if err := p.Traverse(left); err != nil {
+	left, err := n.Left()
+	if err != nil {
 		return err
 	}
-	if err := p.Traverse(right); err != nil {
+	right, err := n.Right()
+	if err != nil {
+		return err
+	}
+	if err := p.hashChildren(left, right, sem); err != nil {
 		return err
 	}
 
@@ -75,6 +98,17 @@ func (p *stateHasher) Traverse(n *avl.Node[types.UnitID, Unit]) error {
 	}
 	unit.subTreeSummaryValue = unitDataSummaryInputValue + lv + rv
 
+	// the earliest expiry round of the subtree, see State.ExpiringUnits
+	le, err := getSubTreeExpiryRound(left)
+	if err != nil {
+		return err
+	}
+	re, err := getSubTreeExpiryRound(right)
+	if err != nil {
+		return err
+	}
+	unit.subTreeExpiryRound = minExpiryRound(unit.expiryRound(), minExpiryRound(le, re))
+
 	// h - subtree summary hash
 	hasher := abhash.New(p.hashAlgorithm.New())
 	hasher.Write(n.Key())
@@ -93,3 +127,24 @@ func (p *stateHasher) Traverse(n *avl.Node[types.UnitID, Unit]) error {
 	p.SetClean(n)
 	return nil
 }
+
+func (p *stateHasher) hashChildren(left, right *avl.Node[types.UnitID, Unit], sem chan struct{}) error {
+	if sem != nil && left.Depth() >= concurrentHashMinDepth && right.Depth() >= concurrentHashMinDepth {
+		select {
+		case sem <- struct{}{}:
+			errc := make(chan error, 1)
+			go func() {
+				defer func() { <-sem }()
+				errc <- p.hash(left, sem)
+			}()
+			// wait for the left subtree even when hashing the right one fails
+			rightErr := p.hash(right, sem)
+			return errors.Join(<-errc, rightErr)
+		default:
+		}
+	}
+	if err := p.hash(left, sem); err != nil {
+		return err
+	}
+	return p.hash(right, sem)
+}
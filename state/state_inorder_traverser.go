@@ -18,13 +18,21 @@ func (s *InorderTraverser) Traverse(n *node) error {
 	if n == nil {
 		return nil
 	}
-	if err := s.Traverse(n.Left()); err != nil {
+	left, err := n.Left()
+	if err != nil {
+		return err
+	}
+	if err := s.Traverse(left); err != nil {
 		return err
 	}
 	if err := s.visit(n.Key(), n.Value()); err != nil {
 		return err
 	}
-	if err := s.Traverse(n.Right()); err != nil {
+	right, err := n.Right()
+	if err != nil {
+		return err
+	}
+	if err := s.Traverse(right); err != nil {
 		return err
 	}
 	return nil
@@ -14,10 +14,18 @@ func (s *stateNodeCounter) Traverse(n *node) error {
 	if n == nil {
 		return nil
 	}
-	if err := s.Traverse(n.Left()); err != nil {
+	left, err := n.Left()
+	if err != nil {
 		return err
 	}
-	if err := s.Traverse(n.Right()); err != nil {
+	if err := s.Traverse(left); err != nil {
+		return err
+	}
+	right, err := n.Right()
+	if err != nil {
+		return err
+	}
+	if err := s.Traverse(right); err != nil {
 		return err
 	}
 
@@ -0,0 +1,225 @@
+package state
+
+import (
+	"crypto"
+	"fmt"
+	"sync"
+
+	"github.com/unicitynetwork/bft-core/tree/avl"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+const (
+	changeAdd changeOp = iota
+	changeUpdate
+	changeDelete
+)
+
+type (
+	/*
+		UnitAccess contains the units read and changed in the state since the access
+		tracking was started, see State.StartAccessTracking.
+
+		The changes are recorded in the order they were made to the state tree so that
+		they can be replayed to another state (see State.ApplyChanges) resulting in the
+		same state tree as if the changes had been made to that state directly. The
+		changes which were rolled back are not replayed, but the units are still
+		included in the set of changed units.
+	*/
+	UnitAccess struct {
+		mu      sync.Mutex
+		read    map[string]struct{}
+		changed map[string]struct{}
+		changes []unitChange
+		// length of the change log at the time the savepoint (index) was created
+		marks []int
+	}
+
+	unitChange struct {
+		op   changeOp
+		id   types.UnitID
+		unit Unit
+	}
+
+	changeOp uint8
+
+	// trackedShardState records the units accessed by the actions
+	trackedShardState struct {
+		t      *tree
+		access *UnitAccess
+	}
+)
+
+func NewUnitAccess() *UnitAccess {
+	return &UnitAccess{
+		read:    map[string]struct{}{},
+		changed: map[string]struct{}{},
+	}
+}
+
+/*
+ResetTo discards all changes of the state and makes it an isolated overlay of the
+uncommitted state of base: the state starts from the latest savepoint of base
+and its committed state is the committed state of base. The changes made to the
+overlay do not affect base (the state trees are copy-on-write), the base can be
+used concurrently by multiple overlays as long as the base itself is not changed.
+*/
+func (s *State) ResetTo(base *State) error {
+	base.mutex.Lock()
+	defer base.mutex.Unlock()
+	sp := base.latestSavepoint().Clone()
+	// the nodes shared with the overlay must be clean, ie never changed in place
+	if err := sp.Traverse(&avl.PostOrderCommitTraverser[types.UnitID, Unit]{}); err != nil {
+		return fmt.Errorf("unable to mark the tree clean: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.hashAlgorithm = base.hashAlgorithm
+	s.committedTree = base.committedTree.Clone()
+	s.committedTreeUC = base.committedTreeUC
+	s.savepoints = []*tree{sp}
+	s.access = nil
+	return nil
+}
+
+/*
+StartAccessTracking starts tracking the units read and changed in the (uncommitted)
+state, replacing the previously tracked units. The committed state is not tracked.
+*/
+func (s *State) StartAccessTracking() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.access = NewUnitAccess()
+	s.access.marks = make([]int, len(s.savepoints))
+}
+
+// StopAccessTracking stops tracking the units and returns the units accessed since StartAccessTracking.
+func (s *State) StopAccessTracking() *UnitAccess {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	a := s.access
+	s.access = nil
+	if a == nil {
+		return NewUnitAccess()
+	}
+	a.marks = nil
+	return a
+}
+
+/*
+ApplyChanges replays the changes recorded in the unit access to the state as a
+single atomic operation, see Apply.
+*/
+func (s *State) ApplyChanges(a *UnitAccess) error {
+	return s.Apply(func(st ShardState, _ crypto.Hash) error {
+		for _, c := range a.changes {
+			var err error
+			switch c.op {
+			case changeAdd:
+				err = st.Add(c.id, c.unit)
+			case changeUpdate:
+				err = st.Update(c.id, c.unit)
+			case changeDelete:
+				err = st.Delete(c.id)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to apply change of unit %s: %w", c.id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// DependsOn returns true if any of the units changed in b was read or changed in a.
+func (a *UnitAccess) DependsOn(b *UnitAccess) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id := range b.changed {
+		if _, ok := a.read[id]; ok {
+			return true
+		}
+		if _, ok := a.changed[id]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge adds the units accessed in b to the units accessed in a, the changes of b are not merged.
+func (a *UnitAccess) Merge(b *UnitAccess) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id := range b.read {
+		a.read[id] = struct{}{}
+	}
+	for id := range b.changed {
+		a.changed[id] = struct{}{}
+	}
+}
+
+func (a *UnitAccess) addRead(id types.UnitID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.read[string(id)] = struct{}{}
+}
+
+func (a *UnitAccess) addChange(op changeOp, id types.UnitID, unit Unit) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.changed[string(id)] = struct{}{}
+	a.changes = append(a.changes, unitChange{op: op, id: id, unit: unit})
+}
+
+func (a *UnitAccess) savepointCreated() {
+	a.marks = append(a.marks, len(a.changes))
+}
+
+func (a *UnitAccess) rolledBack(id int) {
+	if id < len(a.marks) {
+		a.changes = a.changes[:a.marks[id]]
+		a.marks = a.marks[:id]
+	}
+}
+
+func (a *UnitAccess) released(id int) {
+	if id < len(a.marks) {
+		a.marks = a.marks[:id]
+	}
+}
+
+func (t *trackedShardState) Add(id types.UnitID, u Unit) error {
+	t.access.addRead(id)
+	if err := t.t.Add(id, u); err != nil {
+		return err
+	}
+	t.access.addChange(changeAdd, id, u)
+	return nil
+}
+
+func (t *trackedShardState) Get(id types.UnitID) (Unit, error) {
+	t.access.addRead(id)
+	return t.t.Get(id)
+}
+
+func (t *trackedShardState) Update(id types.UnitID, u Unit) error {
+	t.access.addRead(id)
+	if err := t.t.Update(id, u); err != nil {
+		return err
+	}
+	t.access.addChange(changeUpdate, id, u)
+	return nil
+}
+
+func (t *trackedShardState) Delete(id types.UnitID) error {
+	t.access.addRead(id)
+	if err := t.t.Delete(id); err != nil {
+		return err
+	}
+	t.access.addChange(changeDelete, id, nil)
+	return nil
+}
@@ -0,0 +1,124 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+func TestState_ResetTo_IsolatedOverlay(t *testing.T) {
+	base := NewEmptyState()
+	require.NoError(t, base.Apply(AddUnit(unitIdentifiers[0], &TestData{Value: 1})))
+	require.NoError(t, base.Apply(AddUnit(unitIdentifiers[1], &TestData{Value: 2})))
+	baseValue, baseRoot, err := base.CalculateRoot()
+	require.NoError(t, err)
+
+	overlay := NewEmptyState()
+	require.NoError(t, overlay.ResetTo(base))
+	require.NoError(t, overlay.Apply(
+		UpdateUnitData(unitIdentifiers[0], func(data types.UnitData) (types.UnitData, error) {
+			data.(*TestData).Value = 10
+			return data, nil
+		}),
+		DeleteUnit(unitIdentifiers[1]),
+		AddUnit(unitIdentifiers[2], &TestData{Value: 3}),
+	))
+
+	// changes of the overlay are not visible in the base
+	value, root, err := base.CalculateRoot()
+	require.NoError(t, err)
+	require.Equal(t, baseValue, value)
+	require.Equal(t, baseRoot, root)
+	u, err := base.GetUnit(unitIdentifiers[0], false)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, u.Data().(*TestData).Value)
+	_, err = base.GetUnit(unitIdentifiers[2], false)
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestState_AccessTracking(t *testing.T) {
+	s := NewEmptyState()
+	require.NoError(t, s.Apply(AddUnit(unitIdentifiers[0], &TestData{Value: 1})))
+	require.NoError(t, s.Apply(AddUnit(unitIdentifiers[1], &TestData{Value: 2})))
+
+	s.StartAccessTracking()
+	_, err := s.GetUnit(unitIdentifiers[0], false)
+	require.NoError(t, err)
+	require.NoError(t, s.Apply(AddUnit(unitIdentifiers[2], &TestData{Value: 3})))
+	id, err := s.Savepoint()
+	require.NoError(t, err)
+	require.NoError(t, s.Apply(DeleteUnit(unitIdentifiers[1])))
+	s.RollbackToSavepoint(id)
+	require.NoError(t, s.AddUnitLog(unitIdentifiers[2], []byte{1}))
+	access := s.StopAccessTracking()
+
+	require.Len(t, access.read, 3)
+	require.Contains(t, access.read, string(unitIdentifiers[0]))
+	// the rolled back changes are not replayed but the unit is still considered changed
+	require.Len(t, access.changed, 2)
+	require.Contains(t, access.changed, string(unitIdentifiers[1]))
+	require.Len(t, access.changes, 2)
+	require.Equal(t, changeAdd, access.changes[0].op)
+	require.Equal(t, changeUpdate, access.changes[1].op)
+
+	// changes after stopping the tracking are not recorded
+	require.NoError(t, s.Apply(AddUnit(unitIdentifiers[3], &TestData{Value: 4})))
+	require.Len(t, access.changes, 2)
+}
+
+func TestState_ApplyChanges(t *testing.T) {
+	base := NewEmptyState()
+	for i := range 5 {
+		require.NoError(t, base.Apply(AddUnit(unitIdentifiers[i], &TestData{Value: uint64(i)})))
+	}
+	changes := func(s *State) error {
+		return s.Apply(
+			UpdateUnitData(unitIdentifiers[1], func(data types.UnitData) (types.UnitData, error) {
+				data.(*TestData).Value = 10
+				return data, nil
+			}),
+			DeleteUnit(unitIdentifiers[3]),
+			AddUnit(unitIdentifiers[6], &TestData{Value: 6}),
+			AddUnit(unitIdentifiers[7], &TestData{Value: 7}),
+		)
+	}
+
+	overlay := NewEmptyState()
+	require.NoError(t, overlay.ResetTo(base))
+	overlay.StartAccessTracking()
+	require.NoError(t, changes(overlay))
+	access := overlay.StopAccessTracking()
+
+	expected := base.Clone()
+	require.NoError(t, changes(expected))
+	require.NoError(t, base.ApplyChanges(access))
+
+	expectedValue, expectedRoot, err := expected.CalculateRoot()
+	require.NoError(t, err)
+	value, root, err := base.CalculateRoot()
+	require.NoError(t, err)
+	require.Equal(t, expectedValue, value)
+	require.Equal(t, expectedRoot, root)
+}
+
+func TestUnitAccess_DependsOn(t *testing.T) {
+	a := NewUnitAccess()
+	a.addRead(unitIdentifiers[0])
+	a.addChange(changeUpdate, unitIdentifiers[1], nil)
+
+	b := NewUnitAccess()
+	b.addRead(unitIdentifiers[0])
+	b.addChange(changeUpdate, unitIdentifiers[2], nil)
+	require.False(t, a.DependsOn(b))
+	// reading the same units does not create dependency
+	require.False(t, b.DependsOn(a))
+
+	b.addChange(changeUpdate, unitIdentifiers[0], nil)
+	require.True(t, a.DependsOn(b))
+
+	c := NewUnitAccess()
+	c.Merge(a)
+	require.True(t, c.DependsOn(b))
+	require.Empty(t, c.changes)
+}
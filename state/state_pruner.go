@@ -1,7 +1,11 @@
 package state
 
 import (
+	"errors"
 	"fmt"
+
+	"github.com/unicitynetwork/bft-core/tree/avl"
+	"github.com/unicitynetwork/bft-go-base/types"
 )
 
 type (
@@ -18,14 +22,43 @@ func (s *statePruner) Traverse(n *node) error {
 	if n == nil {
 		return nil
 	}
-	if err := s.Traverse(n.Left()); err != nil {
+	left, err := n.Left()
+	if err != nil {
+		return err
+	}
+	if err := s.Traverse(left); err != nil {
 		return err
 	}
-	if err := s.Traverse(n.Right()); err != nil {
+	right, err := n.Right()
+	if err != nil {
 		return err
 	}
+	if err := s.Traverse(right); err != nil {
+		return err
+	}
+	return s.prune(n.Key(), n.Value())
+}
+
+// pruneUnits prunes the logs of the given units (instead of traversing the whole tree).
+func (s *statePruner) pruneUnits(ids []types.UnitID) error {
+	for _, id := range ids {
+		u, err := s.prunedTree.Get(id)
+		if errors.Is(err, avl.ErrNotFound) {
+			// deleted after the commit
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get unit %s: %w", id, err)
+		}
+		if err := s.prune(id, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	unit, err := ToUnitV1(n.Value())
+func (s *statePruner) prune(id types.UnitID, u Unit) error {
+	unit, err := ToUnitV1(u)
 	if err != nil {
 		return fmt.Errorf("failed to get unit: %w", err)
 	}
@@ -40,5 +73,5 @@ func (s *statePruner) Traverse(n *node) error {
 		return fmt.Errorf("unable to parse cloned unit: %w", err)
 	}
 	clonedUnit.logs = []*Log{NewUnitLog(nil, latestLog.UnitLedgerHeadHash, unit.Data(), unit.deletionRound, unit.stateLockTx)}
-	return s.prunedTree.Update(n.Key(), clonedUnit)
+	return s.prunedTree.Update(id, clonedUnit)
 }
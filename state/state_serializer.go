@@ -21,7 +21,8 @@ type (
 		ExecutedTransactions map[string]uint64
 	}
 
-	nodeRecord struct {
+	// NodeRecord is a serialized state tree node, records are written in post-order.
+	NodeRecord struct {
 		_                  struct{} `cbor:",toarray"`
 		Version            types.Version
 		UnitID             types.UnitID
@@ -50,10 +51,18 @@ func (s *stateSerializer) Traverse(n *avl.Node[types.UnitID, Unit]) error {
 		return nil
 	}
 
-	if err := s.Traverse(n.Left()); err != nil {
+	left, err := n.Left()
+	if err != nil {
+		return err
+	}
+	if err := s.Traverse(left); err != nil {
+		return err
+	}
+	right, err := n.Right()
+	if err != nil {
 		return err
 	}
-	if err := s.Traverse(n.Right()); err != nil {
+	if err := s.Traverse(right); err != nil {
 		return err
 	}
 
@@ -85,14 +94,14 @@ func (s *stateSerializer) WriteNode(n *avl.Node[types.UnitID, Unit]) error {
 		return fmt.Errorf("unable to extract unit tree path: %w", err)
 	}
 
-	nr := &nodeRecord{
+	nr := &NodeRecord{
 		Version:            unit.GetVersion(),
 		UnitID:             n.Key(),
 		UnitLedgerHeadHash: latestLog.UnitLedgerHeadHash,
 		UnitData:           unitDataBytes,
 		UnitTreePath:       unitTreePath,
-		HasLeft:            n.Left() != nil,
-		HasRight:           n.Right() != nil,
+		HasLeft:            n.HasLeft(),
+		HasRight:           n.HasRight(),
 	}
 	if err = s.encode(nr); err != nil {
 		return fmt.Errorf("unable to encode node record: %w", err)
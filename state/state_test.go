@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	test "github.com/unicitynetwork/bft-core/internal/testutils"
 	abhash "github.com/unicitynetwork/bft-go-base/hash"
+	"github.com/unicitynetwork/bft-go-base/txsystem/fc"
 	"github.com/unicitynetwork/bft-go-base/types"
 	"github.com/unicitynetwork/bft-go-base/util"
 )
@@ -516,6 +517,86 @@ func TestState_PruneState(t *testing.T) {
 	require.Nil(t, unit.logs[0].TxRecordHash)
 }
 
+func TestState_AtRound(t *testing.T) {
+	s := NewEmptyState(WithArchive(2))
+	id := types.UnitID{0, 0, 0, 1}
+	// latest committed state is always available, genesis state is the state of round 0
+	_, err := s.AtRound(0)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Apply(AddUnit(id, &pruneUnitData{I: 1})))
+	require.NoError(t, s.AddUnitLog(id, test.RandomBytes(32)))
+	commitState(t, s)
+	for range 3 {
+		require.NoError(t, s.Prune())
+		require.NoError(t, s.Apply(UpdateUnitData(id, multiply(10))))
+		require.NoError(t, s.AddUnitLog(id, test.RandomBytes(32)))
+		commitState(t, s)
+	}
+	require.EqualValues(t, 4, s.CommittedUC().GetRoundNumber())
+
+	// rounds 2 and 3 are archived, round 1 has been dropped from the archive
+	_, err = s.AtRound(1)
+	require.ErrorIs(t, err, ErrRoundNotArchived)
+	_, err = s.AtRound(5)
+	require.ErrorIs(t, err, ErrRoundNotArchived)
+
+	for round, value := range map[uint64]uint64{2: 10, 3: 100, 4: 1000} {
+		rs, err := s.AtRound(round)
+		require.NoError(t, err)
+		require.EqualValues(t, round, rs.CommittedUC().GetRoundNumber())
+		u, err := rs.GetUnit(id, true)
+		require.NoError(t, err)
+		require.Equal(t, value, u.Data().SummaryValueInput())
+
+		unit, err := ToUnitV1(u)
+		require.NoError(t, err)
+		proof, err := rs.CreateUnitStateProof(id, unit.LastLogIndex())
+		require.NoError(t, err)
+		uc := &types.UnicityCertificate{}
+		require.NoError(t, types.Cbor.Unmarshal(proof.UnicityCertificate, uc))
+		require.EqualValues(t, round, uc.GetRoundNumber())
+	}
+
+	// modifying the state doesn't change the archived state
+	require.NoError(t, s.Apply(UpdateUnitData(id, multiply(10))))
+	rs, err := s.AtRound(4)
+	require.NoError(t, err)
+	u, err := rs.GetUnit(id, true)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, u.Data().SummaryValueInput())
+	s.Revert()
+
+	// state rolled back to earlier round, archive must not contain "future" rounds
+	require.NoError(t, s.Apply(UpdateUnitData(id, multiply(10))))
+	summaryValue, rootHash, err := s.CalculateRoot()
+	require.NoError(t, err)
+	uc := createUC(t, s, summaryValue, rootHash)
+	uc.InputRecord.RoundNumber = 3
+	require.NoError(t, s.Commit(uc))
+	_, err = s.AtRound(4)
+	require.ErrorIs(t, err, ErrRoundNotArchived)
+	rs, err = s.AtRound(2)
+	require.NoError(t, err)
+	u, err = rs.GetUnit(id, true)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, u.Data().SummaryValueInput())
+
+	// archive mode disabled
+	s, _, _ = prepareState(t)
+	updateUnits(t, s)
+	_, err = s.AtRound(1)
+	require.ErrorIs(t, err, ErrRoundNotArchived)
+	_, err = s.AtRound(2)
+	require.NoError(t, err)
+}
+
+func commitState(t *testing.T, s *State) {
+	summaryValue, rootHash, err := s.CalculateRoot()
+	require.NoError(t, err)
+	require.NoError(t, s.Commit(createUC(t, s, summaryValue, rootHash)))
+}
+
 func TestCreateAndVerifyStateProofs_CreateUnits(t *testing.T) {
 	s, stateRootHash, summaryValue := prepareState(t)
 	for _, id := range unitIdentifiers {
@@ -583,6 +664,30 @@ func TestCreateAndVerifyStateProofs_UpdateAndPruneUnits(t *testing.T) {
 	}
 }
 
+func TestCreateAndVerifyStateProofs_LogWithoutTxRecord(t *testing.T) {
+	s, _, _ := prepareState(t)
+	require.NoError(t, s.Prune())
+	value, hash, err := s.CalculateRoot()
+	require.NoError(t, err)
+	require.NoError(t, s.Commit(createUC(t, s, value, hash)))
+	// state change without transaction record, ie done by the round initialization
+	id := types.UnitID{0, 0, 0, 5}
+	require.NoError(t, s.Apply(UpdateUnitData(id, multiply(10))))
+	require.NoError(t, s.AddUnitLog(id, nil))
+	summaryValue, stateRootHash, err := s.CalculateRoot()
+	require.NoError(t, err)
+	require.NoError(t, s.Commit(createUC(t, s, summaryValue, stateRootHash)))
+
+	for logIndex := range 2 {
+		stateProof, err := s.CreateUnitStateProof(id, logIndex)
+		require.NoError(t, err)
+		proofOutputHash, sum, err := stateProof.CalculateStateTreeOutput(s.hashAlgorithm)
+		require.NoError(t, err)
+		require.Equal(t, summaryValue, sum)
+		require.Equal(t, stateRootHash, proofOutputHash, "invalid chain output hash for log index %d", logIndex)
+	}
+}
+
 type alwaysValid struct{}
 
 func (a *alwaysValid) Validate(*types.UnicityCertificate, []byte) error {
@@ -611,6 +716,24 @@ func TestCreateAndVerifyStateProofs_CreateUnitProof(t *testing.T) {
 }
 
 func TestCreateAndVerifyStateProofs_CreateUnitProof_InvalidSummaryValue(t *testing.T) {
+	s, _, _ := prepareState(t)
+	s.committedTreeUC.InputRecord.SummaryValue = util.Uint64ToBytes(1)
+	proof, err := s.CreateUnitStateProof([]byte{0, 0, 0, 5}, 0)
+	var proofErr *UnitStateProofError
+	require.ErrorAs(t, err, &proofErr)
+	require.EqualValues(t, []byte{0, 0, 0, 5}, proofErr.UnitID)
+	require.ErrorContains(t, err, "unit 00000005 state proof (log index 0) verification failed: invalid summary value")
+	require.Nil(t, proof)
+}
+
+func TestCreateAndVerifyStateProofs_CreateUnitProof_InvalidStateHash(t *testing.T) {
+	s, _, _ := prepareState(t)
+	s.committedTreeUC.InputRecord.Hash = make([]byte, 32)
+	proof, err := s.CreateUnitStateProof([]byte{0, 0, 0, 5}, 0)
+	var proofErr *UnitStateProofError
+	require.ErrorAs(t, err, &proofErr)
+	require.ErrorContains(t, err, "invalid state root hash")
+	require.Nil(t, proof)
 }
 
 func TestSerialize_OK(t *testing.T) {
@@ -780,6 +903,56 @@ func TestSerialize_EmptyStateCommitted(t *testing.T) {
 	require.True(t, committed)
 }
 
+func TestDecodeStateFile(t *testing.T) {
+	s, _, _ := prepareState(t)
+	summaryValue, summaryHash, err := s.CalculateRoot()
+	require.NoError(t, err)
+	uc := createUC(t, s, summaryValue, summaryHash)
+	require.NoError(t, s.Commit(uc))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, s.Serialize(buf, true, map[string]uint64{"tx1": 1}))
+	data := buf.Bytes()
+
+	t.Run("ok", func(t *testing.T) {
+		var header *Header
+		var records []*NodeRecord
+		err := DecodeStateFile(bytes.NewReader(data),
+			func(h *Header) error { header = h; return nil },
+			func(nr *NodeRecord) error { records = append(records, nr); return nil },
+		)
+		require.NoError(t, err)
+		require.Equal(t, uc, header.UnicityCertificate)
+		require.Equal(t, map[string]uint64{"tx1": 1}, header.ExecutedTransactions)
+		require.Len(t, records, int(header.NodeRecordCount))
+		// root node is the last record
+		require.True(t, records[len(records)-1].HasLeft)
+		require.True(t, records[len(records)-1].HasRight)
+	})
+
+	t.Run("nil callbacks", func(t *testing.T) {
+		require.NoError(t, DecodeStateFile(bytes.NewReader(data), nil, nil))
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		expErr := fmt.Errorf("stop")
+		err := DecodeStateFile(bytes.NewReader(data), nil, func(nr *NodeRecord) error { return expErr })
+		require.ErrorIs(t, err, expErr)
+	})
+
+	t.Run("invalid checksum", func(t *testing.T) {
+		h := &Header{NodeRecordCount: 11}
+		err := DecodeStateFile(createSerializedState(t, s, h, 1), nil, nil)
+		require.EqualError(t, err, "checksum mismatch")
+	})
+
+	t.Run("missing node records", func(t *testing.T) {
+		h := &Header{NodeRecordCount: 1}
+		err := DecodeStateFile(createSerializedState(t, NewEmptyState(), h, 0), nil, nil)
+		require.ErrorContains(t, err, "unable to decode node record 0")
+	})
+}
+
 func TestState_GetUnits(t *testing.T) {
 	pdr := &types.PartitionDescriptionRecord{
 		TypeIDLen: 8,
@@ -850,6 +1023,46 @@ func TestState_GetUnits(t *testing.T) {
 	})
 }
 
+func TestState_ExpiryTraverser(t *testing.T) {
+	id := func(i byte) types.UnitID { return append(make(types.UnitID, 31), i) }
+	s := NewEmptyState()
+	for i := byte(1); i <= 20; i++ {
+		require.NoError(t, s.Apply(AddUnit(id(i), &TestData{Value: uint64(i)})))
+	}
+	require.NoError(t, s.Apply(
+		MarkForDeletion(id(3), 10),
+		MarkForDeletion(id(17), 5),
+		SetStateLock(id(12), []byte{1}),
+		AddUnit(id(21), &fc.FeeCreditRecord{Balance: 0, MinLifetime: 7}),
+		AddUnit(id(22), &fc.FeeCreditRecord{Balance: 1, MinLifetime: 7}),
+	))
+	commitState(t, s)
+
+	expiring := func(round uint64) (ids []types.UnitID) {
+		require.NoError(t, s.Traverse(NewExpiryTraverser(round, func(unitID types.UnitID, _ Unit) error {
+			ids = append(ids, unitID)
+			return nil
+		})))
+		return ids
+	}
+	require.Equal(t, []types.UnitID{id(12)}, expiring(4))
+	require.Equal(t, []types.UnitID{id(12), id(17)}, expiring(5))
+	require.Equal(t, []types.UnitID{id(12), id(17), id(21)}, expiring(8))
+	require.Equal(t, []types.UnitID{id(3), id(12), id(17), id(21)}, expiring(10))
+
+	// the index is updated when the units change
+	require.NoError(t, s.Apply(
+		RemoveStateLock(id(12)),
+		DeleteUnit(id(17)),
+		UpdateUnitData(id(21), func(data types.UnitData) (types.UnitData, error) {
+			return &fc.FeeCreditRecord{Balance: 10, MinLifetime: 7}, nil
+		}),
+	))
+	commitState(t, s)
+	require.Equal(t, []types.UnitID{id(3)}, expiring(10))
+	require.Empty(t, expiring(9))
+}
+
 func prepareState(t *testing.T) (*State, []byte, uint64) {
 	s := NewEmptyState()
 	//			┌───┤ key=00000100
@@ -932,7 +1145,52 @@ func getUnit(t *testing.T, s *State, id []byte) *UnitV1 {
 	return u
 }
 
-func createUC(t *testing.T, s *State, summaryValue uint64, summaryHash []byte) *types.UnicityCertificate {
+func TestState_CalculateRoot_HashWorkers(t *testing.T) {
+	seq := NewEmptyState()
+	par := NewEmptyState(WithHashWorkers(4))
+	// the tree must be deep enough for the subtrees to be hashed concurrently
+	apply := func(f func(s *State) error) {
+		require.NoError(t, f(seq))
+		require.NoError(t, f(par))
+	}
+	for i := range 5000 {
+		txrHash := test.RandomBytes(32)
+		apply(func(s *State) error {
+			id := util.Uint64ToBytes(uint64(i))
+			if err := s.Apply(AddUnit(id, &TestData{Value: uint64(i)})); err != nil {
+				return err
+			}
+			return s.AddUnitLog(id, txrHash)
+		})
+	}
+	for round := range 3 {
+		seqValue, seqHash, err := seq.CalculateRoot()
+		require.NoError(t, err)
+		parValue, parHash, err := par.CalculateRoot()
+		require.NoError(t, err)
+		require.Equal(t, seqValue, parValue)
+		require.Equal(t, seqHash, parHash)
+		require.NoError(t, seq.Commit(createUC(t, seq, seqValue, seqHash)))
+		require.NoError(t, par.Commit(createUC(t, par, parValue, parHash)))
+
+		// change some of the units and add new ones
+		for i := range 500 {
+			apply(func(s *State) error {
+				id := util.Uint64ToBytes(uint64(i * 7))
+				if err := s.Apply(UpdateUnitData(id, func(data types.UnitData) (types.UnitData, error) {
+					data.(*TestData).Value += uint64(round)
+					return data, nil
+				})); err != nil {
+					return err
+				}
+				newID := util.Uint64ToBytes(uint64(10000 + round*500 + i))
+				return s.Apply(AddUnit(newID, &TestData{Value: 1}))
+			})
+		}
+	}
+}
+
+func createUC(t testing.TB, s *State, summaryValue uint64, summaryHash []byte) *types.UnicityCertificate {
 	roundNumber := uint64(1)
 	committed, err := s.IsCommitted()
 	require.NoError(t, err)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	abhash "github.com/unicitynetwork/bft-go-base/hash"
+	"github.com/unicitynetwork/bft-go-base/txsystem/fc"
 	"github.com/unicitynetwork/bft-go-base/types"
 )
 
@@ -20,6 +21,7 @@ type UnitV1 struct {
 	stateLockTx         []byte         // bytes of transaction that locked the unit
 	subTreeSummaryValue uint64         // current summary value of the subtree rooted at this node
 	subTreeSummaryHash  []byte         // summary hash of the subtree rooted at this node
+	subTreeExpiryRound  uint64         // the earliest expiry round of the units of the subtree rooted at this node, see expiryRound
 	summaryCalculated   bool
 }
 
@@ -105,6 +107,33 @@ func (u *UnitV1) IsExpired(currentRoundNumber uint64) bool {
 	return u.deletionRound > 0 && u.deletionRound <= currentRoundNumber
 }
 
+/*
+expiryRound returns the round starting from which the unit has to be checked by
+the round initialization of the transaction system (0 when never):
+  - the deletion round of the logically deleted unit;
+  - the round after the minimum lifetime of the fee credit record without balance;
+  - any round for the state locked unit, the expiry of the state lock is decided
+    by the transaction system.
+*/
+func (u *UnitV1) expiryRound() uint64 {
+	if u.IsStateLocked() {
+		return 1
+	}
+	round := u.deletionRound
+	if fcr, ok := u.data.(*fc.FeeCreditRecord); ok && fcr.Balance == 0 {
+		round = minExpiryRound(round, fcr.MinLifetime+1)
+	}
+	return round
+}
+
+// minExpiryRound returns the earlier of the expiry rounds, 0 means no expiry.
+func minExpiryRound(a, b uint64) uint64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
 func MarshalUnitData(u types.UnitData) ([]byte, error) {
 	return types.Cbor.Marshal(u)
 }
@@ -0,0 +1,394 @@
+/*
+Package swap implements coordinator of atomic swaps between two partitions.
+
+Both parties of the swap lock their units with state locking transactions (the
+transaction has StateLock with ExecutionPredicate and RollbackPredicate, ie
+hash-lock and time-lock). The coordinator watches both shards, collects the
+proofs of the lock transactions and when both locks succeeded submits the
+transactions which execute the locked transactions, otherwise the locks which
+succeeded are rolled back.
+*/
+package swap
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/unicitynetwork/bft-core/logger"
+	"github.com/unicitynetwork/bft-core/partition"
+	"github.com/unicitynetwork/bft-core/rpc"
+	"github.com/unicitynetwork/bft-core/txsystem"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+var ErrTimeout = errors.New("transaction timed out")
+
+type (
+	/*
+		Client is the RPC API of a shard used by the coordinator, implemented by
+		rpc/client.StateAPIClient.
+	*/
+	Client interface {
+		ProofClient
+		GetUnit(ctx context.Context, unitID types.UnitID, includeStateProof bool) (*rpc.Unit[json.RawMessage], error)
+		SendTransaction(ctx context.Context, tx *types.TransactionOrder) ([]byte, error)
+	}
+
+	// ProofClient is the part of the shard RPC API used to wait for the transaction proofs.
+	ProofClient interface {
+		GetRoundInfo(ctx context.Context) (*partition.RoundInfo, error)
+		GetTransactionProof(ctx context.Context, txHash []byte) (*types.TxRecordProof, error)
+	}
+
+	/*
+		UnlockTxFunc returns the (signed) transaction which resolves the state lock
+		of the leg. The StateUnlock field of the transaction must start with the
+		"kind" byte followed by the proof satisfying the execution or rollback
+		predicate of the lock. The lockProofs are the proofs of the lock transactions
+		of both legs of the swap (in the same order as the legs).
+		When nil transaction is returned the coordinator waits for the partition to
+		release the lock (ie the lock has expiry and it is rolled back by the
+		partition when it expires).
+	*/
+	UnlockTxFunc func(ctx context.Context, kind txsystem.StateUnlockProofKind, lockProofs []*types.TxRecordProof) (*types.TransactionOrder, error)
+
+	// Leg is one side of the swap.
+	Leg struct {
+		Name   string
+		Client Client
+		// state locking transaction of the leg, submitted by the coordinator
+		// unless it's already included in a block
+		LockTx *types.TransactionOrder
+		Unlock UnlockTxFunc
+	}
+
+	LegResult struct {
+		LockProof   *types.TxRecordProof
+		UnlockProof *types.TxRecordProof // nil when the lock was not confirmed or it was released by the partition
+	}
+
+	Result struct {
+		Executed bool // true when the locked transactions were executed, false when rolled back
+		Legs     []LegResult
+	}
+
+	Coordinator struct {
+		legs         []*Leg
+		hashAlgo     crypto.Hash
+		pollInterval time.Duration
+		trustBase    func(epoch uint64) (types.RootTrustBase, error)
+		log          *slog.Logger
+	}
+
+	Option func(*Coordinator)
+)
+
+// WithPollInterval sets how often the shards are polled for the transaction proofs.
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Coordinator) {
+		c.pollInterval = d
+	}
+}
+
+/*
+WithTrustBase enables verification of the transaction proofs, without it the
+coordinator trusts the RPC nodes it talks to.
+*/
+func WithTrustBase(tb func(epoch uint64) (types.RootTrustBase, error)) Option {
+	return func(c *Coordinator) {
+		c.trustBase = tb
+	}
+}
+
+func WithHashAlgorithm(algo crypto.Hash) Option {
+	return func(c *Coordinator) {
+		c.hashAlgo = algo
+	}
+}
+
+func NewCoordinator(legs []*Leg, log *slog.Logger, opts ...Option) (*Coordinator, error) {
+	if len(legs) != 2 {
+		return nil, fmt.Errorf("swap must have two legs, got %d", len(legs))
+	}
+	for i, leg := range legs {
+		if err := leg.isValid(); err != nil {
+			return nil, fmt.Errorf("invalid leg %d: %w", i, err)
+		}
+	}
+	if log == nil {
+		return nil, errors.New("logger is nil")
+	}
+
+	c := &Coordinator{
+		legs:         legs,
+		hashAlgo:     crypto.SHA256,
+		pollInterval: time.Second,
+		log:          log,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+func (l *Leg) isValid() error {
+	switch {
+	case l == nil:
+		return errors.New("leg is nil")
+	case l.Client == nil:
+		return errors.New("client is nil")
+	case l.LockTx == nil:
+		return errors.New("lock transaction is nil")
+	case !l.LockTx.HasStateLock():
+		return errors.New("lock transaction doesn't have state lock")
+	case l.LockTx.Timeout() == 0:
+		return errors.New("lock transaction doesn't have timeout")
+	case l.Unlock == nil:
+		return errors.New("unlock transaction builder is nil")
+	}
+	return nil
+}
+
+/*
+Run drives the swap to completion: submits the lock transactions (unless already
+submitted) and waits for their proofs, then either executes or rolls back the
+locks. Run returns when the unlock transactions are included in a block (or
+the locks are released by the partitions).
+*/
+func (c *Coordinator) Run(ctx context.Context) (*Result, error) {
+	res := &Result{Legs: make([]LegResult, len(c.legs))}
+	// submit locks of both legs first and then wait for the proofs
+	lockHashes := make([][]byte, len(c.legs))
+	for i, leg := range c.legs {
+		txHash, err := c.submitLock(ctx, leg)
+		if err != nil {
+			return nil, fmt.Errorf("locking %s: %w", leg.Name, err)
+		}
+		lockHashes[i] = txHash
+	}
+	lockProofs := make([]*types.TxRecordProof, len(c.legs))
+	for i, leg := range c.legs {
+		proof, err := WaitProof(ctx, leg.Client, lockHashes[i], leg.LockTx.Timeout(), c.pollInterval)
+		if err != nil {
+			if errors.Is(err, ErrTimeout) {
+				continue
+			}
+			return nil, fmt.Errorf("locking %s: %w", leg.Name, err)
+		}
+		if err := c.verify(proof, lockHashes[i]); err != nil {
+			return nil, fmt.Errorf("locking %s: invalid lock transaction proof: %w", leg.Name, err)
+		}
+		lockProofs[i] = proof
+		res.Legs[i].LockProof = proof
+	}
+
+	res.Executed = true
+	for i, proof := range lockProofs {
+		if proof == nil || proof.TxStatus() != types.TxStatusSuccessful {
+			c.log.WarnContext(ctx, fmt.Sprintf("%s: state lock failed, rolling back the swap", c.legs[i].Name))
+			res.Executed = false
+		}
+	}
+	kind := txsystem.StateUnlockRollback
+	if res.Executed {
+		kind = txsystem.StateUnlockExecute
+	}
+
+	for i, leg := range c.legs {
+		if lockProofs[i] == nil || lockProofs[i].TxStatus() != types.TxStatusSuccessful {
+			continue // nothing to unlock
+		}
+		proof, err := c.unlock(ctx, leg, kind, lockProofs)
+		res.Legs[i].UnlockProof = proof
+		if err != nil {
+			return res, fmt.Errorf("unlocking %s: %w", leg.Name, err)
+		}
+	}
+	return res, nil
+}
+
+/*
+submitLock submits the lock transaction of the leg unless it's already included
+in a block, returns the hash of the lock transaction.
+*/
+func (c *Coordinator) submitLock(ctx context.Context, leg *Leg) ([]byte, error) {
+	txHash, err := leg.LockTx.Hash(c.hashAlgo)
+	if err != nil {
+		return nil, fmt.Errorf("hashing lock transaction: %w", err)
+	}
+	proof, err := leg.Client.GetTransactionProof(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("querying lock transaction proof: %w", err)
+	}
+	if proof != nil {
+		c.log.DebugContext(ctx, fmt.Sprintf("%s: lock transaction %X is already in a block", leg.Name, txHash), logger.UnitID(leg.LockTx.UnitID))
+		return txHash, nil
+	}
+	if _, err := leg.Client.SendTransaction(ctx, leg.LockTx); err != nil {
+		return nil, fmt.Errorf("sending lock transaction: %w", err)
+	}
+	c.log.DebugContext(ctx, fmt.Sprintf("%s: lock transaction %X submitted", leg.Name, txHash), logger.UnitID(leg.LockTx.UnitID))
+	return txHash, nil
+}
+
+func (c *Coordinator) unlock(ctx context.Context, leg *Leg, kind txsystem.StateUnlockProofKind, lockProofs []*types.TxRecordProof) (*types.TxRecordProof, error) {
+	tx, err := leg.Unlock(ctx, kind, lockProofs)
+	if err != nil {
+		return nil, fmt.Errorf("creating unlock transaction: %w", err)
+	}
+	if tx == nil {
+		c.log.DebugContext(ctx, fmt.Sprintf("%s: waiting for the partition to release the lock", leg.Name), logger.UnitID(leg.LockTx.UnitID))
+		return nil, c.waitUnlocked(ctx, leg)
+	}
+	if len(tx.StateUnlock) == 0 || txsystem.StateUnlockProofKind(tx.StateUnlock[0]) != kind {
+		return nil, fmt.Errorf("unlock transaction must have state unlock proof of kind %d", kind)
+	}
+
+	txHash, err := tx.Hash(c.hashAlgo)
+	if err != nil {
+		return nil, fmt.Errorf("hashing unlock transaction: %w", err)
+	}
+	if _, err := leg.Client.SendTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("sending unlock transaction: %w", err)
+	}
+	c.log.DebugContext(ctx, fmt.Sprintf("%s: unlock transaction %X submitted", leg.Name, txHash), logger.UnitID(tx.UnitID))
+	proof, err := WaitProof(ctx, leg.Client, txHash, tx.Timeout(), c.pollInterval)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.verify(proof, txHash); err != nil {
+		return nil, fmt.Errorf("invalid unlock transaction proof: %w", err)
+	}
+	if proof.TxStatus() != types.TxStatusSuccessful {
+		return proof, fmt.Errorf("unlock transaction failed with status %d", proof.TxStatus())
+	}
+	return proof, nil
+}
+
+/*
+WaitProof polls the shard for the proof of the transaction until it is found or
+the transaction times out (ErrTimeout is returned). The transaction can be
+included in a block up to (and including) the round of its timeout.
+*/
+func WaitProof(ctx context.Context, client ProofClient, txHash []byte, timeout uint64, pollInterval time.Duration) (*types.TxRecordProof, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		// query round before proof so that we do not miss the proof of the last round
+		ri, err := client.GetRoundInfo(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("querying round info: %w", err)
+		}
+		proof, err := client.GetTransactionProof(ctx, txHash)
+		if err != nil {
+			return nil, fmt.Errorf("querying transaction proof: %w", err)
+		}
+		if proof != nil {
+			return proof, nil
+		}
+		if ri.RoundNumber > timeout {
+			return nil, fmt.Errorf("%w: transaction %X timeout %d, current round %d", ErrTimeout, txHash, timeout, ri.RoundNumber)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitUnlocked waits until the unit targeted by the lock transaction is no longer locked.
+func (c *Coordinator) waitUnlocked(ctx context.Context, leg *Leg) error {
+	lockTx, err := leg.LockTx.MarshalCBOR()
+	if err != nil {
+		return fmt.Errorf("encoding lock transaction: %w", err)
+	}
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	for {
+		unit, err := leg.Client.GetUnit(ctx, leg.LockTx.UnitID, false)
+		if err != nil {
+			return fmt.Errorf("querying unit: %w", err)
+		}
+		if unit == nil || !bytes.Equal(unit.StateLockTx, lockTx) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Coordinator) verify(proof *types.TxRecordProof, txHash []byte) error {
+	if err := proof.IsValid(); err != nil {
+		return err
+	}
+	txo, err := proof.GetTransactionOrderV1()
+	if err != nil {
+		return fmt.Errorf("decoding transaction order: %w", err)
+	}
+	if h, err := txo.Hash(c.hashAlgo); err != nil || !bytes.Equal(h, txHash) {
+		return fmt.Errorf("proof is not for the transaction %X", txHash)
+	}
+	if c.trustBase == nil {
+		return nil
+	}
+	// not using proof.Verify as it also requires the transaction to be successful
+	uc, err := proof.TxProof.GetUC()
+	if err != nil {
+		return fmt.Errorf("reading UC of the proof: %w", err)
+	}
+	if uc.UnicitySeal == nil {
+		return errors.New("invalid UC: missing UnicitySeal")
+	}
+	tb, err := c.trustBase(uc.UnicitySeal.Epoch)
+	if err != nil {
+		return fmt.Errorf("acquiring trust base: %w", err)
+	}
+	return types.VerifyTxInclusion(proof, tb, c.hashAlgo)
+}
+
+/*
+PresignedUnlock returns UnlockTxFunc which returns the "execute" or "rollback"
+transaction prepared in advance for the leg with index "leg". Either of the
+transactions may be nil, ie when the lock has expiry the partition rolls back
+the lock when it expires. The transaction is returned only when it unlocks the
+unit locked by the lock transaction of the leg according to the lock proof.
+*/
+func PresignedUnlock(leg int, execute, rollback *types.TransactionOrder) UnlockTxFunc {
+	return func(ctx context.Context, kind txsystem.StateUnlockProofKind, lockProofs []*types.TxRecordProof) (*types.TransactionOrder, error) {
+		var tx *types.TransactionOrder
+		switch kind {
+		case txsystem.StateUnlockExecute:
+			if execute == nil {
+				return nil, errors.New("execute transaction is not provided")
+			}
+			tx = execute
+		case txsystem.StateUnlockRollback:
+			if rollback == nil {
+				return nil, nil
+			}
+			tx = rollback
+		default:
+			return nil, fmt.Errorf("unknown state unlock kind %d", kind)
+		}
+		if leg < 0 || leg >= len(lockProofs) || lockProofs[leg] == nil {
+			return nil, fmt.Errorf("lock proof of the leg %d is missing", leg)
+		}
+		if !slices.ContainsFunc(lockProofs[leg].TxRecord.TargetUnits(), tx.UnitID.Eq) {
+			return nil, fmt.Errorf("unlock transaction is for unit %s which is not locked by the lock transaction", tx.UnitID)
+		}
+		return tx, nil
+	}
+}
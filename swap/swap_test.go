@@ -0,0 +1,400 @@
+package swap
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	testblock "github.com/unicitynetwork/bft-core/internal/testutils/block"
+	testlogger "github.com/unicitynetwork/bft-core/internal/testutils/logger"
+	testsig "github.com/unicitynetwork/bft-core/internal/testutils/sig"
+	"github.com/unicitynetwork/bft-core/internal/testutils/trustbase"
+	"github.com/unicitynetwork/bft-core/partition"
+	"github.com/unicitynetwork/bft-core/predicates/templates"
+	"github.com/unicitynetwork/bft-core/rpc"
+	"github.com/unicitynetwork/bft-core/txsystem"
+	testtransaction "github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
+	abcrypto "github.com/unicitynetwork/bft-go-base/crypto"
+	"github.com/unicitynetwork/bft-go-base/txsystem/nop"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+func TestNewCoordinator(t *testing.T) {
+	log := testlogger.New(t)
+	shard := newFakeShard(t, nil)
+	validLeg := func() *Leg {
+		return &Leg{Name: "A", Client: shard, LockTx: newLockTx(t, []byte("secret"), make([]byte, 32), 10), Unlock: PresignedUnlock(0, nil, nil)}
+	}
+
+	_, err := NewCoordinator([]*Leg{validLeg()}, log)
+	require.EqualError(t, err, `swap must have two legs, got 1`)
+
+	_, err = NewCoordinator([]*Leg{validLeg(), nil}, log)
+	require.EqualError(t, err, `invalid leg 1: leg is nil`)
+
+	leg := validLeg()
+	leg.Client = nil
+	_, err = NewCoordinator([]*Leg{validLeg(), leg}, log)
+	require.EqualError(t, err, `invalid leg 1: client is nil`)
+
+	leg = validLeg()
+	leg.LockTx.StateLock = nil
+	_, err = NewCoordinator([]*Leg{leg, validLeg()}, log)
+	require.EqualError(t, err, `invalid leg 0: lock transaction doesn't have state lock`)
+
+	leg = validLeg()
+	leg.Unlock = nil
+	_, err = NewCoordinator([]*Leg{leg, validLeg()}, log)
+	require.EqualError(t, err, `invalid leg 0: unlock transaction builder is nil`)
+
+	_, err = NewCoordinator([]*Leg{validLeg(), validLeg()}, nil)
+	require.EqualError(t, err, `logger is nil`)
+
+	c, err := NewCoordinator([]*Leg{validLeg(), validLeg()}, log)
+	require.NoError(t, err)
+	require.NotNil(t, c)
+}
+
+func TestCoordinator_Run(t *testing.T) {
+	secret := []byte("swap secret")
+	owner, ownerPKH := newOwner(t)
+	signer, verifier := testsig.CreateSignerAndVerifier(t)
+	tb := trustbase.NewTrustBase(t, verifier)
+	getTrustBase := func(epoch uint64) (types.RootTrustBase, error) { return tb, nil }
+
+	newLeg := func(t *testing.T, name string, shard *fakeShard) *Leg {
+		lockTx := newLockTx(t, secret, ownerPKH, 10)
+		return &Leg{
+			Name:   name,
+			Client: shard,
+			LockTx: lockTx,
+			Unlock: PresignedUnlock(
+				map[string]int{"A": 0, "B": 1}[name],
+				newExecuteTx(t, lockTx.UnitID, secret, owner),
+				newUnlockTx(t, lockTx.UnitID, txsystem.StateUnlockRollback, nil),
+			),
+		}
+	}
+
+	t.Run("swap is executed", func(t *testing.T) {
+		shardA, shardB := newFakeShard(t, signer), newFakeShard(t, signer)
+		legA, legB := newLeg(t, "A", shardA), newLeg(t, "B", shardB)
+		// lock of the leg B has been already submitted
+		_, err := shardB.SendTransaction(context.Background(), legB.LockTx)
+		require.NoError(t, err)
+		_, err = shardB.GetRoundInfo(context.Background())
+		require.NoError(t, err)
+
+		c, err := NewCoordinator([]*Leg{legA, legB}, testlogger.New(t), WithPollInterval(time.Millisecond), WithTrustBase(getTrustBase))
+		require.NoError(t, err)
+		res, err := c.Run(context.Background())
+		require.NoError(t, err)
+		require.True(t, res.Executed)
+		for i, shard := range []*fakeShard{shardA, shardB} {
+			require.EqualValues(t, types.TxStatusSuccessful, res.Legs[i].LockProof.TxStatus())
+			require.EqualValues(t, types.TxStatusSuccessful, res.Legs[i].UnlockProof.TxStatus())
+			txo, err := res.Legs[i].UnlockProof.GetTransactionOrderV1()
+			require.NoError(t, err)
+			require.EqualValues(t, txsystem.StateUnlockExecute, txo.StateUnlock[0])
+			require.Empty(t, shard.locked)
+		}
+		// lock of the leg B must not have been resubmitted
+		require.Equal(t, 2, shardA.submitted)
+		require.Equal(t, 2, shardB.submitted)
+	})
+
+	t.Run("lock fails, swap is rolled back", func(t *testing.T) {
+		shardA, shardB := newFakeShard(t, signer), newFakeShard(t, signer)
+		shardB.fail = func(tx *types.TransactionOrder) bool { return tx.HasStateLock() }
+		legA, legB := newLeg(t, "A", shardA), newLeg(t, "B", shardB)
+
+		c, err := NewCoordinator([]*Leg{legA, legB}, testlogger.New(t), WithPollInterval(time.Millisecond), WithTrustBase(getTrustBase))
+		require.NoError(t, err)
+		res, err := c.Run(context.Background())
+		require.NoError(t, err)
+		require.False(t, res.Executed)
+		require.EqualValues(t, types.TxStatusSuccessful, res.Legs[0].LockProof.TxStatus())
+		txo, err := res.Legs[0].UnlockProof.GetTransactionOrderV1()
+		require.NoError(t, err)
+		require.EqualValues(t, txsystem.StateUnlockRollback, txo.StateUnlock[0])
+		require.EqualValues(t, types.TxStatusFailed, res.Legs[1].LockProof.TxStatus())
+		require.Nil(t, res.Legs[1].UnlockProof)
+		require.Empty(t, shardA.locked)
+	})
+
+	t.Run("lock times out, partition rolls back the lock", func(t *testing.T) {
+		shardA, shardB := newFakeShard(t, signer), newFakeShard(t, signer)
+		shardA.autoRollback = 15
+		shardB.drop = true
+		legA, legB := newLeg(t, "A", shardA), newLeg(t, "B", shardB)
+		legA.Unlock = PresignedUnlock(0, nil, nil)
+
+		c, err := NewCoordinator([]*Leg{legA, legB}, testlogger.New(t), WithPollInterval(time.Millisecond))
+		require.NoError(t, err)
+		res, err := c.Run(context.Background())
+		require.NoError(t, err)
+		require.False(t, res.Executed)
+		require.NotNil(t, res.Legs[0].LockProof)
+		require.Nil(t, res.Legs[0].UnlockProof)
+		require.Nil(t, res.Legs[1].LockProof)
+		require.Empty(t, shardA.locked)
+		require.GreaterOrEqual(t, shardA.round, shardA.autoRollback)
+	})
+
+	t.Run("unlock transaction fails", func(t *testing.T) {
+		shardA, shardB := newFakeShard(t, signer), newFakeShard(t, signer)
+		shardB.fail = func(tx *types.TransactionOrder) bool { return len(tx.StateUnlock) > 0 }
+		legA, legB := newLeg(t, "A", shardA), newLeg(t, "B", shardB)
+
+		c, err := NewCoordinator([]*Leg{legA, legB}, testlogger.New(t), WithPollInterval(time.Millisecond))
+		require.NoError(t, err)
+		res, err := c.Run(context.Background())
+		require.EqualError(t, err, `unlocking B: unlock transaction failed with status 0`)
+		require.True(t, res.Executed)
+		require.NotNil(t, res.Legs[0].UnlockProof)
+		require.NotNil(t, res.Legs[1].UnlockProof)
+	})
+
+	t.Run("invalid proof", func(t *testing.T) {
+		otherSigner, _ := testsig.CreateSignerAndVerifier(t)
+		shardA, shardB := newFakeShard(t, signer), newFakeShard(t, otherSigner)
+		legA, legB := newLeg(t, "A", shardA), newLeg(t, "B", shardB)
+
+		c, err := NewCoordinator([]*Leg{legA, legB}, testlogger.New(t), WithPollInterval(time.Millisecond), WithTrustBase(getTrustBase))
+		require.NoError(t, err)
+		res, err := c.Run(context.Background())
+		require.ErrorContains(t, err, `locking B: invalid lock transaction proof:`)
+		require.Nil(t, res)
+	})
+
+	t.Run("unlock transaction of wrong kind", func(t *testing.T) {
+		shardA, shardB := newFakeShard(t, signer), newFakeShard(t, signer)
+		legA, legB := newLeg(t, "A", shardA), newLeg(t, "B", shardB)
+		legB.Unlock = PresignedUnlock(1, newUnlockTx(t, legB.LockTx.UnitID, txsystem.StateUnlockRollback, nil), nil)
+
+		c, err := NewCoordinator([]*Leg{legA, legB}, testlogger.New(t), WithPollInterval(time.Millisecond))
+		require.NoError(t, err)
+		_, err = c.Run(context.Background())
+		require.EqualError(t, err, `unlocking B: unlock transaction must have state unlock proof of kind 1`)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		shardA, shardB := newFakeShard(t, signer), newFakeShard(t, signer)
+		shardA.drop = true
+		legA, legB := newLeg(t, "A", shardA), newLeg(t, "B", shardB)
+		legA.LockTx = newLockTx(t, secret, ownerPKH, 1_000_000)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		c, err := NewCoordinator([]*Leg{legA, legB}, testlogger.New(t), WithPollInterval(time.Millisecond))
+		require.NoError(t, err)
+		_, err = c.Run(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestWaitProof(t *testing.T) {
+	signer, _ := testsig.CreateSignerAndVerifier(t)
+	tx := newLockTx(t, []byte("secret"), make([]byte, 32), 10)
+	txHash, err := tx.Hash(crypto.SHA256)
+	require.NoError(t, err)
+
+	t.Run("transaction included in the round of its timeout", func(t *testing.T) {
+		shard := newFakeShard(t, signer)
+		shard.round = 8
+		_, err := shard.SendTransaction(context.Background(), tx)
+		require.NoError(t, err)
+		// the transaction is included into the block of the round 10, the proof
+		// is available when the current round is 11
+		shard.drop = true
+		shard.undropRound = 11
+		proof, err := WaitProof(context.Background(), shard, txHash, tx.Timeout(), time.Millisecond)
+		require.NoError(t, err)
+		require.NotNil(t, proof)
+		require.EqualValues(t, 11, shard.round)
+	})
+
+	t.Run("transaction times out", func(t *testing.T) {
+		shard := newFakeShard(t, signer)
+		shard.drop = true
+		proof, err := WaitProof(context.Background(), shard, txHash, tx.Timeout(), time.Millisecond)
+		require.ErrorIs(t, err, ErrTimeout)
+		require.Nil(t, proof)
+		require.EqualValues(t, 11, shard.round)
+	})
+}
+
+func TestPresignedUnlock(t *testing.T) {
+	signer, _ := testsig.CreateSignerAndVerifier(t)
+	lockTx := newLockTx(t, []byte("secret"), make([]byte, 32), 10)
+	executeTx := newUnlockTx(t, lockTx.UnitID, txsystem.StateUnlockExecute, nil)
+	rollbackTx := newUnlockTx(t, lockTx.UnitID, txsystem.StateUnlockRollback, nil)
+	lockTxBytes, err := lockTx.MarshalCBOR()
+	require.NoError(t, err)
+	lockProof := testblock.CreateTxRecordProof(t, &types.TransactionRecord{
+		Version:          1,
+		TransactionOrder: lockTxBytes,
+		ServerMetadata:   &types.ServerMetadata{TargetUnits: []types.UnitID{lockTx.UnitID}, SuccessIndicator: types.TxStatusSuccessful},
+	}, signer)
+	lockProofs := []*types.TxRecordProof{nil, lockProof}
+
+	unlock := PresignedUnlock(1, executeTx, rollbackTx)
+	tx, err := unlock(context.Background(), txsystem.StateUnlockExecute, lockProofs)
+	require.NoError(t, err)
+	require.Equal(t, executeTx, tx)
+	tx, err = unlock(context.Background(), txsystem.StateUnlockRollback, lockProofs)
+	require.NoError(t, err)
+	require.Equal(t, rollbackTx, tx)
+
+	// no rollback transaction, the partition releases the lock
+	tx, err = PresignedUnlock(1, executeTx, nil)(context.Background(), txsystem.StateUnlockRollback, lockProofs)
+	require.NoError(t, err)
+	require.Nil(t, tx)
+
+	_, err = PresignedUnlock(1, nil, nil)(context.Background(), txsystem.StateUnlockExecute, lockProofs)
+	require.EqualError(t, err, `execute transaction is not provided`)
+
+	_, err = PresignedUnlock(0, executeTx, nil)(context.Background(), txsystem.StateUnlockExecute, lockProofs)
+	require.EqualError(t, err, `lock proof of the leg 0 is missing`)
+
+	otherTx := newUnlockTx(t, append(types.UnitID{1}, lockTx.UnitID[1:]...), txsystem.StateUnlockExecute, nil)
+	_, err = PresignedUnlock(1, otherTx, nil)(context.Background(), txsystem.StateUnlockExecute, lockProofs)
+	require.ErrorContains(t, err, `which is not locked by the lock transaction`)
+}
+
+// newOwner returns signer and its public key hash, the owner can execute the hash-lock.
+func newOwner(t *testing.T) (abcrypto.Signer, []byte) {
+	signer, verifier := testsig.CreateSignerAndVerifier(t)
+	pubKey, err := verifier.MarshalPublicKey()
+	require.NoError(t, err)
+	pkh := sha256.Sum256(pubKey)
+	return signer, pkh[:]
+}
+
+func newLockTx(t *testing.T, secret, ownerPKH []byte, timeout uint64) *types.TransactionOrder {
+	return testtransaction.NewTransactionOrder(t,
+		testtransaction.WithClientMetadata(&types.ClientMetadata{Timeout: timeout}),
+		testtransaction.WithStateLock(&types.StateLock{
+			ExecutionPredicate: templates.NewHashLockBytesFromSecret(secret, ownerPKH),
+			RollbackPredicate:  templates.NewTimeLockBytes(timeout + 5),
+			Expiry:             timeout + 5,
+		}),
+	)
+}
+
+func newUnlockTx(t *testing.T, unitID types.UnitID, kind txsystem.StateUnlockProofKind, proof []byte) *types.TransactionOrder {
+	return testtransaction.NewTransactionOrder(t,
+		testtransaction.WithUnitID(unitID),
+		testtransaction.WithTransactionType(nop.TransactionTypeNOP),
+		testtransaction.WithAttributes(&nop.Attributes{}),
+		testtransaction.WithClientMetadata(&types.ClientMetadata{Timeout: 100}),
+		testtransaction.WithStateUnlock(append([]byte{byte(kind)}, proof...)),
+	)
+}
+
+// newExecuteTx returns transaction executing the hash-lock with the secret, signed by the owner.
+func newExecuteTx(t *testing.T, unitID types.UnitID, secret []byte, owner abcrypto.Signer) *types.TransactionOrder {
+	tx := newUnlockTx(t, unitID, txsystem.StateUnlockExecute, nil)
+	proof, err := templates.NewHashLockProofBytes(secret, testsig.NewStateLockProofSignature(t, tx, owner))
+	require.NoError(t, err)
+	tx.StateUnlock = append([]byte{byte(txsystem.StateUnlockExecute)}, proof...)
+	return tx
+}
+
+/*
+fakeShard includes submitted transactions into "block" of the next round, round
+number is incremented on every GetRoundInfo call.
+*/
+type fakeShard struct {
+	t            *testing.T
+	signer       abcrypto.Signer
+	mu           sync.Mutex
+	round        uint64
+	pending      []*types.TransactionOrder
+	proofs       map[string]*types.TxRecordProof
+	locked       map[string][]byte // unit ID -> lock tx
+	submitted    int
+	fail         func(tx *types.TransactionOrder) bool // tx is included with "failed" status
+	drop         bool                                  // txs are never included into block
+	autoRollback uint64                                // round when the locks are released
+	undropRound  uint64                                // round starting from which the txs are included, despite "drop"
+}
+
+func newFakeShard(t *testing.T, signer abcrypto.Signer) *fakeShard {
+	return &fakeShard{
+		t:      t,
+		signer: signer,
+		round:  1,
+		proofs: make(map[string]*types.TxRecordProof),
+		locked: make(map[string][]byte),
+		fail:   func(tx *types.TransactionOrder) bool { return false },
+	}
+}
+
+func (s *fakeShard) GetRoundInfo(ctx context.Context) (*partition.RoundInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.round++
+	if s.undropRound != 0 && s.round >= s.undropRound {
+		s.drop = false
+	}
+	if !s.drop {
+		for _, tx := range s.pending {
+			txBytes, err := tx.MarshalCBOR()
+			require.NoError(s.t, err)
+			txr := &types.TransactionRecord{
+				Version:          1,
+				TransactionOrder: txBytes,
+				ServerMetadata:   &types.ServerMetadata{TargetUnits: []types.UnitID{tx.UnitID}, SuccessIndicator: types.TxStatusSuccessful},
+			}
+			if s.fail(tx) {
+				txr.ServerMetadata.SuccessIndicator = types.TxStatusFailed
+			} else if tx.HasStateLock() {
+				s.locked[string(tx.UnitID)] = txBytes
+			} else if len(tx.StateUnlock) > 0 {
+				delete(s.locked, string(tx.UnitID))
+			}
+			txHash, err := tx.Hash(crypto.SHA256)
+			require.NoError(s.t, err)
+			s.proofs[string(txHash)] = testblock.CreateTxRecordProof(s.t, txr, s.signer)
+		}
+		s.pending = nil
+	}
+	if s.autoRollback != 0 && s.round >= s.autoRollback {
+		clear(s.locked)
+	}
+	return &partition.RoundInfo{RoundNumber: s.round}, nil
+}
+
+func (s *fakeShard) GetUnit(ctx context.Context, unitID types.UnitID, includeStateProof bool) (*rpc.Unit[json.RawMessage], error) {
+	if _, err := s.GetRoundInfo(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &rpc.Unit[json.RawMessage]{UnitID: unitID, StateLockTx: s.locked[string(unitID)]}, nil
+}
+
+func (s *fakeShard) SendTransaction(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tx.Timeout() <= s.round {
+		return nil, fmt.Errorf("transaction has timed out")
+	}
+	s.submitted++
+	s.pending = append(s.pending, tx)
+	return tx.Hash(crypto.SHA256)
+}
+
+func (s *fakeShard) GetTransactionProof(ctx context.Context, txHash []byte) (*types.TxRecordProof, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.proofs[string(txHash)], nil
+}
@@ -0,0 +1,8 @@
+package nop
+
+type (
+	AuthProof struct {
+		_          struct{} `cbor:",toarray"`
+		OwnerProof []byte
+	}
+)
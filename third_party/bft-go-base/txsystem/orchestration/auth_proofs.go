@@ -0,0 +1,8 @@
+package orchestration
+
+type (
+	AddVarAuthProof struct {
+		_          struct{} `cbor:",toarray"`
+		OwnerProof []byte
+	}
+)
@@ -0,0 +1,56 @@
+package orchestration
+
+import (
+	abhash "github.com/unicitynetwork/bft-go-base/hash"
+	"github.com/unicitynetwork/bft-go-base/types"
+)
+
+var _ types.UnitData = (*VarData)(nil)
+
+// VarData Validator Assignment Record Data
+type VarData struct {
+	_           struct{}      `cbor:",toarray"`
+	Version     types.Version `json:"version"`
+	EpochNumber uint64        // epoch number from the validator assignment record
+}
+
+func (b *VarData) Write(hasher abhash.Hasher) {
+	hasher.Write(b)
+}
+
+func (b *VarData) SummaryValueInput() uint64 {
+	return 0 // no summary value checks in orchestration partition
+}
+
+func (b *VarData) Copy() types.UnitData {
+	return &VarData{
+		EpochNumber: b.EpochNumber,
+	}
+}
+
+func (b *VarData) Owner() []byte {
+	return nil
+}
+
+func (b *VarData) GetVersion() types.Version {
+	if b != nil && b.Version != 0 {
+		return b.Version
+	}
+	return 1
+}
+
+func (b *VarData) MarshalCBOR() ([]byte, error) {
+	type alias VarData
+	if b.Version == 0 {
+		b.Version = b.GetVersion()
+	}
+	return types.Cbor.Marshal((*alias)(b))
+}
+
+func (b *VarData) UnmarshalCBOR(data []byte) error {
+	type alias VarData
+	if err := types.Cbor.Unmarshal(data, (*alias)(b)); err != nil {
+		return err
+	}
+	return types.EnsureVersion(b, b.Version, 1)
+}
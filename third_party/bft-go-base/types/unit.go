@@ -0,0 +1,16 @@
+package types
+
+import (
+	abhash "github.com/unicitynetwork/bft-go-base/hash"
+)
+
+type (
+	// UnitData is a generic data type for the unit state.
+	UnitData interface {
+		Versioned
+		Write(hasher abhash.Hasher)
+		SummaryValueInput() uint64
+		Copy() UnitData
+		Owner() []byte
+	}
+)
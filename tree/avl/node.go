@@ -18,13 +18,17 @@ type (
 	// To enable destructive updates, a node in an AVL tree has a "clean" field. Whenever a new node
 	// is added or an existing node is changed (including rotations), a copy of the node is made with
 	// the clean field set to false (see Tree.Clone function for more information).
+	//
+	// The children of a persisted node (see NodeStore) are not kept in memory, they are loaded
+	// from the store on demand.
 	Node[K Key[K], V Value[V]] struct {
-		key   K
-		value V
-		left  *Node[K, V]
-		right *Node[K, V]
-		clean bool
-		depth int64
+		key       K
+		value     V
+		left      *Node[K, V]
+		right     *Node[K, V]
+		clean     bool
+		depth     int64
+		persisted *persistedRefs[K, V]
 	}
 
 	// Key represents the type of the key and is used to insert, update, search, and delete values
@@ -52,7 +56,7 @@ func NewBalancedNode[K Key[K], V Value[V]](key K, value V, left *Node[K, V], rig
 		right: right,
 		clean: false,
 	}
-	node.depth = calculateDepth(node)
+	node.depth = max(left.Depth(), right.Depth()) + 1
 	return node
 }
 
@@ -78,6 +82,8 @@ func newDirtyNode[K Key[K], V Value[V]](n *Node[K, V]) *Node[K, V] {
 		left:  n.left,
 		right: n.right,
 		clean: false, // we consider a copy dirty to enable destructive updates
+		// the copy is not persisted but the (unchanged) children of the node still are
+		persisted: n.persisted.childRefs(),
 	}
 }
 
@@ -100,18 +106,63 @@ func (n *Node[K, V]) Clean() bool {
 	return n.clean
 }
 
-func (n *Node[K, V]) Left() *Node[K, V] {
+// Left returns the left child of the node, loading it from the NodeStore when necessary.
+func (n *Node[K, V]) Left() (*Node[K, V], error) {
 	if n == nil {
-		return nil
+		return nil, nil
+	}
+	if n.left == nil && n.persisted != nil && n.persisted.left != nil {
+		return n.persisted.load(n.persisted.left)
 	}
-	return n.left
+	return n.left, nil
 }
 
-func (n *Node[K, V]) Right() *Node[K, V] {
+// Right returns the right child of the node, loading it from the NodeStore when necessary.
+func (n *Node[K, V]) Right() (*Node[K, V], error) {
 	if n == nil {
-		return nil
+		return nil, nil
+	}
+	if n.right == nil && n.persisted != nil && n.persisted.right != nil {
+		return n.persisted.load(n.persisted.right)
+	}
+	return n.right, nil
+}
+
+// HasLeft returns true if the node has a left child, the child is not loaded from the NodeStore.
+func (n *Node[K, V]) HasLeft() bool {
+	return n.left != nil || n.persisted.leftRef() != nil
+}
+
+// HasRight returns true if the node has a right child, the child is not loaded from the NodeStore.
+func (n *Node[K, V]) HasRight() bool {
+	return n.right != nil || n.persisted.rightRef() != nil
+}
+
+// children returns both children of the node, see Left and Right.
+func (n *Node[K, V]) children() (*Node[K, V], *Node[K, V], error) {
+	left, err := n.Left()
+	if err != nil {
+		return nil, nil, err
+	}
+	right, err := n.Right()
+	if err != nil {
+		return nil, nil, err
+	}
+	return left, right, nil
+}
+
+func (n *Node[K, V]) setLeft(left *Node[K, V]) {
+	n.left = left
+	if n.persisted != nil {
+		n.persisted.left = nil
+	}
+}
+
+func (n *Node[K, V]) setRight(right *Node[K, V]) {
+	n.right = right
+	if n.persisted != nil {
+		n.persisted.right = nil
 	}
-	return n.right
 }
 
 func (n *Node[K, V]) String() string {
@@ -129,8 +180,12 @@ func (a IntKey) Compare(b IntKey) int {
 	return -1 // go right
 }
 
-func calculateDepth[K Key[K], V Value[V]](n *Node[K, V]) int64 {
-	return max(n.left.Depth(), n.right.Depth()) + 1
+func calculateDepth[K Key[K], V Value[V]](n *Node[K, V]) (int64, error) {
+	left, right, err := n.children()
+	if err != nil {
+		return 0, err
+	}
+	return max(left.Depth(), right.Depth()) + 1, nil
 }
 
 func max(a, b int64) int64 {
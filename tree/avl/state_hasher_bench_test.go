@@ -0,0 +1,71 @@
+package avl_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicitynetwork/bft-core/state"
+	"github.com/unicitynetwork/bft-go-base/txsystem/money"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"github.com/unicitynetwork/bft-go-base/util"
+)
+
+func BenchmarkTree_CommitStateHash(b *testing.B) {
+	benchData := []struct {
+		treeSize  int
+		batchSize int
+	}{
+		{treeSize: 1_000_000, batchSize: 10_000},
+		{treeSize: 1_000_000, batchSize: 100_000},
+		{treeSize: 3_000_000, batchSize: 100_000},
+	}
+
+	for _, bd := range benchData {
+		for _, workers := range []int{1, 4, 8} {
+			s := state.NewEmptyState(state.WithHashWorkers(workers))
+			for i := 0; i < bd.treeSize; i++ {
+				require.NoError(b, s.Apply(state.AddUnit(util.Uint64ToBytes(uint64(i)), money.NewBillData(uint64(i), nil))))
+			}
+			commitState(b, s)
+			idCounter := 0
+			b.ResetTimer()
+			b.Run(fmt.Sprintf("tree size=%d; batch-size=%d; workers=%d", bd.treeSize, bd.batchSize, workers), func(b *testing.B) {
+				b.ReportAllocs()
+				for n := 0; n < b.N; n++ {
+					b.StopTimer()
+					// update the units spread over the whole tree
+					for i := 0; i < bd.batchSize; i++ {
+						id := util.Uint64ToBytes(uint64((idCounter + i*(bd.treeSize/bd.batchSize)) % bd.treeSize))
+						require.NoError(b, s.Apply(state.UpdateUnitData(id, func(data types.UnitData) (types.UnitData, error) {
+							data.(*money.BillData).Value++
+							return data, nil
+						})))
+					}
+					idCounter++
+					b.StartTimer()
+					_, _, err := s.CalculateRoot()
+					require.NoError(b, err)
+					b.StopTimer()
+					commitState(b, s)
+					b.StartTimer()
+				}
+			})
+		}
+	}
+}
+
+func commitState(b *testing.B, s *state.State) {
+	summaryValue, summaryHash, err := s.CalculateRoot()
+	require.NoError(b, err)
+	roundNumber := uint64(1)
+	if uc := s.CommittedUC(); uc != nil {
+		roundNumber = uc.GetRoundNumber() + 1
+	}
+	require.NoError(b, s.Commit(&types.UnicityCertificate{Version: 1, InputRecord: &types.InputRecord{
+		Version:      1,
+		RoundNumber:  roundNumber,
+		Hash:         summaryHash,
+		SummaryValue: util.Uint64ToBytes(summaryValue),
+	}}))
+}
@@ -88,6 +88,7 @@ func (p *PostOrderCommitTraverser[K, V]) Traverse(n *Node[K, V]) error {
 	if n == nil || n.clean {
 		return nil
 	}
+	// the children which are not in memory are persisted and therefore clean
 	if err := p.Traverse(n.left); err != nil {
 		return err
 	}
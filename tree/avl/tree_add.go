@@ -35,66 +35,112 @@ func insert[K Key[K], V Value[V]](p *Node[K, V], key K, value V) (*Node[K, V], e
 	}
 	if i > 0 {
 		// left child
-		l, err := insert(p.left, key, value)
+		l, err := p.Left()
 		if err != nil {
 			return nil, err
 		}
-		p.left = l
+		if l, err = insert(l, key, value); err != nil {
+			return nil, err
+		}
+		p.setLeft(l)
 	} else {
 		// right child
-		right, err := insert(p.right, key, value)
+		right, err := p.Right()
 		if err != nil {
 			return nil, err
 		}
-		p.right = right
+		if right, err = insert(right, key, value); err != nil {
+			return nil, err
+		}
+		p.setRight(right)
 	}
-	return rotate(p), nil
+	return rotate(p)
 }
 
-func rotate[K Key[K], V Value[V]](p *Node[K, V]) *Node[K, V] {
-	ld, rd := p.left.Depth(), p.right.Depth()
+func rotate[K Key[K], V Value[V]](p *Node[K, V]) (*Node[K, V], error) {
+	left, right, err := p.children()
+	if err != nil {
+		return nil, err
+	}
+	ld, rd := left.Depth(), right.Depth()
 	if ld > rd+1 {
-		lld, lrd := p.left.left.Depth(), p.left.right.Depth()
-		if lld < lrd {
-			p.left = rotateLeft(p.left)
+		ll, lr, err := left.children()
+		if err != nil {
+			return nil, err
+		}
+		if ll.Depth() < lr.Depth() {
+			if left, err = rotateLeft(left); err != nil {
+				return nil, err
+			}
+			p.setLeft(left)
+		}
+		if p, err = rotateRight(p); err != nil {
+			return nil, err
 		}
-		p = rotateRight(p)
 	}
 	if rd > ld+1 {
-		rld, rrd := p.right.left.Depth(), p.right.right.Depth()
-		if rld > rrd {
-			p.right = rotateRight(p.right)
+		rl, rr, err := right.children()
+		if err != nil {
+			return nil, err
+		}
+		if rl.Depth() > rr.Depth() {
+			if right, err = rotateRight(right); err != nil {
+				return nil, err
+			}
+			p.setRight(right)
+		}
+		if p, err = rotateLeft(p); err != nil {
+			return nil, err
 		}
-		p = rotateLeft(p)
 	}
-	p.depth = calculateDepth(p)
-	return p
+	if p.depth, err = calculateDepth(p); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
-func rotateRight[K Key[K], V Value[V]](node *Node[K, V]) *Node[K, V] {
-	tmp := node.left
+func rotateRight[K Key[K], V Value[V]](node *Node[K, V]) (*Node[K, V], error) {
+	tmp, err := node.Left()
+	if err != nil {
+		return nil, err
+	}
 	if node.clean {
 		node = newDirtyNode(node)
 	}
 	if tmp.clean {
 		tmp = newDirtyNode(tmp)
 	}
-	node.left = tmp.right
-	node.depth = calculateDepth(node)
-	tmp.right = node
-	return tmp
+	tmpRight, err := tmp.Right()
+	if err != nil {
+		return nil, err
+	}
+	node.setLeft(tmpRight)
+	if node.depth, err = calculateDepth(node); err != nil {
+		return nil, err
+	}
+	tmp.setRight(node)
+	return tmp, nil
 }
 
-func rotateLeft[K Key[K], V Value[V]](node *Node[K, V]) *Node[K, V] {
-	tmp := node.right
+func rotateLeft[K Key[K], V Value[V]](node *Node[K, V]) (*Node[K, V], error) {
+	tmp, err := node.Right()
+	if err != nil {
+		return nil, err
+	}
 	if node.clean {
 		node = newDirtyNode(node)
 	}
 	if tmp.clean {
 		tmp = newDirtyNode(tmp)
 	}
-	node.right = tmp.left
-	node.depth = calculateDepth(node)
-	tmp.left = node
-	return tmp
+	tmpLeft, err := tmp.Left()
+	if err != nil {
+		return nil, err
+	}
+	node.setRight(tmpLeft)
+	if node.depth, err = calculateDepth(node); err != nil {
+		return nil, err
+	}
+	tmp.setLeft(node)
+	return tmp, nil
 }
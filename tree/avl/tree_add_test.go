@@ -49,8 +49,7 @@ func (*IntTreeTraverser) Traverse(n *Node[IntKey, *Int64Value]) error {
 	if n == nil || n.clean {
 		return nil
 	}
-	sum(n)
-	return nil
+	return sum(n)
 }
 
 func TestAdd_KeyExists_LeftChild(t *testing.T) {
@@ -198,21 +197,30 @@ func newIntTree() *Tree[IntKey, *Int64Value] {
 	return t
 }
 
-func sum(n *Node[IntKey, *Int64Value]) {
+func sum(n *Node[IntKey, *Int64Value]) error {
 	if n == nil || n.clean {
-		return
+		return nil
+	}
+	left, right, err := n.children()
+	if err != nil {
+		return err
+	}
+	if err := sum(left); err != nil {
+		return err
+	}
+	if err := sum(right); err != nil {
+		return err
 	}
-	sum(n.left)
-	sum(n.right)
 	lt := int64(0)
-	if n.left != nil {
-		lt = n.left.value.total
+	if left != nil {
+		lt = left.value.total
 	}
 	rt := int64(0)
-	if n.right != nil {
-		rt = n.right.value.total
+	if right != nil {
+		rt = right.value.total
 	}
 
 	n.value.total = n.value.value + lt + rt
 	n.clean = true
+	return nil
 }
@@ -26,74 +26,116 @@ func remove[K Key[K], V Value[V]](node *Node[K, V], key K) (*Node[K, V], error)
 	i := node.key.Compare(key)
 	if i > 0 {
 		// go to left subtree
-		left, err := remove(node.left, key)
+		left, err := node.Left()
 		if err != nil {
 			return nil, err
 		}
-		node.left = left
+		if left, err = remove(left, key); err != nil {
+			return nil, err
+		}
+		node.setLeft(left)
 	} else if i < 0 {
 		// go to right subtree
-		right, err := remove(node.right, key)
+		right, err := node.Right()
 		if err != nil {
 			return nil, err
 		}
-		node.right = right
+		if right, err = remove(right, key); err != nil {
+			return nil, err
+		}
+		node.setRight(right)
 	} else {
 		// keys are equal
-		if node.left == nil && node.right == nil {
+		left, right, err := node.children()
+		if err != nil {
+			return nil, err
+		}
+		if left == nil && right == nil {
 			// node is a leaf
 			return nil, nil
 		}
 
-		if node.left == nil {
+		if left == nil {
 			// node has only right subtree.
-			return node.right, nil
-		} else if node.right == nil {
+			return right, nil
+		} else if right == nil {
 			// node has only left subtree
-			return node.left, nil
+			return left, nil
 		}
 		// Replace the node with its in-order predecessor (e.g. the largest key that is smaller than node.key).
 		// The first move is always to the left followed by moves to the right until a node without a right child is
 		// found.
-		dirtyLeftNode := newDirtyNode(node.left)
-		newLeft, predecessor := replace(dirtyLeftNode.right, dirtyLeftNode)
+		dirtyLeftNode := newDirtyNode(left)
+		leftRight, err := dirtyLeftNode.Right()
+		if err != nil {
+			return nil, err
+		}
+		newLeft, predecessor, err := replace(leftRight, dirtyLeftNode)
+		if err != nil {
+			return nil, err
+		}
 		node.key = predecessor.key
 		node.value = predecessor.value
 		// because of rotations we need to update left child.
-		node.left = newLeft
-		node.depth = calculateDepth(node)
-		return rotate(node), nil
+		node.setLeft(newLeft)
+		if node.depth, err = calculateDepth(node); err != nil {
+			return nil, err
+		}
+		return rotate(node)
 
 	}
-	node.depth = calculateDepth(node)
-	node = rotate(node)
-	return node, nil
+	var err error
+	if node.depth, err = calculateDepth(node); err != nil {
+		return nil, err
+	}
+	return rotate(node)
 }
 
-func replace[K Key[K], V Value[V]](node *Node[K, V], parent *Node[K, V]) (*Node[K, V], *Node[K, V]) {
+func replace[K Key[K], V Value[V]](node *Node[K, V], parent *Node[K, V]) (*Node[K, V], *Node[K, V], error) {
 	if node == nil {
 		// parent is the predecessor
-		return parent.left, parent
+		left, err := parent.Left()
+		if err != nil {
+			return nil, nil, err
+		}
+		return left, parent, nil
 	}
 	if node.clean {
 		node = newDirtyNode(node)
-		parent.right = node
+		parent.setRight(node)
+	}
+	right, err := node.Right()
+	if err != nil {
+		return nil, nil, err
 	}
 	var predecessor *Node[K, V]
 	var replacedNode *Node[K, V]
-	if node.right != nil {
+	if right != nil {
 		// always go to right subtree until a predecessor is found
-		replacedNode, predecessor = replace(node.right, node)
+		if replacedNode, predecessor, err = replace(right, node); err != nil {
+			return nil, nil, err
+		}
 	} else {
 		predecessor = node
-		parent.right = predecessor.left
-		parent.depth = calculateDepth(parent)
+		predecessorLeft, err := predecessor.Left()
+		if err != nil {
+			return nil, nil, err
+		}
+		parent.setRight(predecessorLeft)
+		if parent.depth, err = calculateDepth(parent); err != nil {
+			return nil, nil, err
+		}
 		// the depth changes at only nodes between the root and the predecessor parent node.
-		return rotate(parent), predecessor
+		parent, err = rotate(parent)
+		return parent, predecessor, err
+	}
+	parent.setRight(replacedNode)
+	if parent.depth, err = calculateDepth(parent); err != nil {
+		return nil, nil, err
 	}
-	parent.right = replacedNode
-	parent.depth = calculateDepth(parent)
 	// the depth changes at only nodes between the root and the predecessor parent node.
-	parent = rotate(parent)
-	return parent, predecessor
+	if parent, err = rotate(parent); err != nil {
+		return nil, nil, err
+	}
+	return parent, predecessor, nil
 }
@@ -16,22 +16,33 @@ func (t *Tree[K, V]) Get(key K) (v V, err error) {
 	if t.root == nil {
 		return v, fmt.Errorf("item %v does not exist: %w", key, ErrNotFound)
 	}
-	node := get[K, V](t.root, key)
+	node, err := get[K, V](t.root, key)
+	if err != nil {
+		return v, err
+	}
 	if node == nil {
 		return v, fmt.Errorf("item %v does not exist: %w", key, ErrNotFound)
 	}
 	return node.value, nil
 }
 
-func get[K Key[K], V Value[V]](node *Node[K, V], key K) *Node[K, V] {
+func get[K Key[K], V Value[V]](node *Node[K, V], key K) (*Node[K, V], error) {
 	if node == nil {
-		return nil
+		return nil, nil
 	}
 	i := node.key.Compare(key)
 	if i > 0 {
-		return get(node.left, key)
+		left, err := node.Left()
+		if err != nil {
+			return nil, err
+		}
+		return get(left, key)
 	} else if i < 0 {
-		return get(node.right, key)
+		right, err := node.Right()
+		if err != nil {
+			return nil, err
+		}
+		return get(right, key)
 	}
-	return node
+	return node, nil
 }
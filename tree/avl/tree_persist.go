@@ -0,0 +1,194 @@
+package avl
+
+import (
+	"fmt"
+)
+
+type (
+	// NodeStore is a storage of the persisted nodes of the tree. The nodes are identified by
+	// the references assigned to them by the NodeWriter when the tree was persisted.
+	NodeStore[K Key[K], V Value[V]] interface {
+		// LoadNode returns the persisted node with the given reference, the node must be
+		// created using the NewPersistedNode function.
+		LoadNode(ref []byte) (*Node[K, V], error)
+	}
+
+	// NodeWriter persists the node with the given references to its (already persisted)
+	// children and returns the reference of the node.
+	NodeWriter[K Key[K], V Value[V]] func(n *Node[K, V], leftRef, rightRef []byte) ([]byte, error)
+
+	// LoadError is returned when a node fails to load from the NodeStore.
+	LoadError struct {
+		Ref []byte
+		Err error
+	}
+
+	// persistedRefs contains the references of the persisted node and its children.
+	persistedRefs[K Key[K], V Value[V]] struct {
+		store NodeStore[K, V]
+		ref   []byte // nil when the node itself is not persisted, ie a dirty copy of the persisted node
+		left  []byte
+		right []byte
+	}
+)
+
+// NewPersistedNode returns a clean node loaded from the store. The children of the node are not
+// kept in memory, they are loaded from the store on demand using the given references.
+func NewPersistedNode[K Key[K], V Value[V]](store NodeStore[K, V], ref []byte, key K, value V, depth int64, leftRef, rightRef []byte) *Node[K, V] {
+	return &Node[K, V]{
+		key:   key,
+		value: value,
+		clean: true,
+		depth: depth,
+		persisted: &persistedRefs[K, V]{
+			store: store,
+			ref:   ref,
+			left:  leftRef,
+			right: rightRef,
+		},
+	}
+}
+
+// Ref returns the reference of the persisted node or nil if the node hasn't been persisted.
+func (n *Node[K, V]) Ref() []byte {
+	if n == nil || n.persisted == nil {
+		return nil
+	}
+	return n.persisted.ref
+}
+
+/*
+Persist writes the nodes of the tree which haven't been persisted yet using the
+given writer and returns the reference of the root node (nil when the tree is empty).
+The nodes are written in post-order, ie children are always written before the parent.
+
+The tree itself is not modified, the persisted nodes are used by loading the root
+node from the NodeStore (see NewWithTraverserAndRoot).
+*/
+func (t *Tree[K, V]) Persist(write NodeWriter[K, V]) ([]byte, error) {
+	return persist(t.root, write)
+}
+
+func persist[K Key[K], V Value[V]](n *Node[K, V], write NodeWriter[K, V]) ([]byte, error) {
+	if n == nil {
+		return nil, nil
+	}
+	if ref := n.Ref(); ref != nil {
+		return ref, nil
+	}
+	leftRef, err := persistChild(n.left, n.persisted.leftRef(), write)
+	if err != nil {
+		return nil, err
+	}
+	rightRef, err := persistChild(n.right, n.persisted.rightRef(), write)
+	if err != nil {
+		return nil, err
+	}
+	return write(n, leftRef, rightRef)
+}
+
+func persistChild[K Key[K], V Value[V]](child *Node[K, V], ref []byte, write NodeWriter[K, V]) ([]byte, error) {
+	if child == nil {
+		return ref, nil
+	}
+	return persist(child, write)
+}
+
+/*
+Orphans returns the references of the persisted nodes of the tree which are not
+part of the next version of the tree, ie the nodes which can be removed from the
+NodeStore once the tree is not used anymore. Must be called before the next
+version of the tree is persisted.
+*/
+func (t *Tree[K, V]) Orphans(next *Tree[K, V]) ([][]byte, error) {
+	if t.root.Ref() == nil {
+		// the tree hasn't been persisted
+		return nil, nil
+	}
+	// persisted subtrees of the next tree are shared with the current tree
+	shared := map[string]struct{}{}
+	sharedSubtrees(next.root, shared)
+
+	var orphans [][]byte
+	var collect func(ref []byte) error
+	collect = func(ref []byte) error {
+		if ref == nil {
+			return nil
+		}
+		if _, ok := shared[string(ref)]; ok {
+			return nil
+		}
+		n, err := t.root.persisted.load(ref)
+		if err != nil {
+			return err
+		}
+		orphans = append(orphans, ref)
+		if err := collect(n.persisted.leftRef()); err != nil {
+			return err
+		}
+		return collect(n.persisted.rightRef())
+	}
+	if err := collect(t.root.Ref()); err != nil {
+		return nil, err
+	}
+	return orphans, nil
+}
+
+// sharedSubtrees collects the references of the topmost persisted nodes of the tree.
+func sharedSubtrees[K Key[K], V Value[V]](n *Node[K, V], shared map[string]struct{}) {
+	if n == nil {
+		return
+	}
+	if ref := n.Ref(); ref != nil {
+		shared[string(ref)] = struct{}{}
+		return
+	}
+	if n.left != nil {
+		sharedSubtrees(n.left, shared)
+	} else if ref := n.persisted.leftRef(); ref != nil {
+		shared[string(ref)] = struct{}{}
+	}
+	if n.right != nil {
+		sharedSubtrees(n.right, shared)
+	} else if ref := n.persisted.rightRef(); ref != nil {
+		shared[string(ref)] = struct{}{}
+	}
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("failed to load node %X: %v", e.Ref, e.Err)
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+func (p *persistedRefs[K, V]) load(ref []byte) (*Node[K, V], error) {
+	n, err := p.store.LoadNode(ref)
+	if err != nil {
+		return nil, &LoadError{Ref: ref, Err: err}
+	}
+	return n, nil
+}
+
+// childRefs returns the references of the children of the node for its dirty copy.
+func (p *persistedRefs[K, V]) childRefs() *persistedRefs[K, V] {
+	if p == nil {
+		return nil
+	}
+	return &persistedRefs[K, V]{store: p.store, left: p.left, right: p.right}
+}
+
+func (p *persistedRefs[K, V]) leftRef() []byte {
+	if p == nil {
+		return nil
+	}
+	return p.left
+}
+
+func (p *persistedRefs[K, V]) rightRef() []byte {
+	if p == nil {
+		return nil
+	}
+	return p.right
+}
@@ -0,0 +1,132 @@
+package avl
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	mapStore struct {
+		nodes  map[string]storedIntNode
+		nextID int
+		err    error
+	}
+
+	storedIntNode struct {
+		key         IntKey
+		value       *Int64Value
+		depth       int64
+		left, right []byte
+	}
+)
+
+func newMapStore() *mapStore {
+	return &mapStore{nodes: map[string]storedIntNode{}}
+}
+
+func (s *mapStore) LoadNode(ref []byte) (*Node[IntKey, *Int64Value], error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	n, ok := s.nodes[string(ref)]
+	if !ok {
+		return nil, fmt.Errorf("node %X not found", ref)
+	}
+	return NewPersistedNode[IntKey, *Int64Value](s, ref, n.key, n.value, n.depth, n.left, n.right), nil
+}
+
+func (s *mapStore) write(n *Node[IntKey, *Int64Value], leftRef, rightRef []byte) ([]byte, error) {
+	s.nextID++
+	ref := []byte(fmt.Sprintf("%d", s.nextID))
+	s.nodes[string(ref)] = storedIntNode{key: n.Key(), value: n.Value(), depth: n.Depth(), left: leftRef, right: rightRef}
+	return ref, nil
+}
+
+// persist persists the tree and returns the tree loaded from the store
+func (s *mapStore) persist(t *testing.T, tree *Tree[IntKey, *Int64Value]) *Tree[IntKey, *Int64Value] {
+	require.NoError(t, tree.Commit())
+	ref, err := tree.Persist(s.write)
+	require.NoError(t, err)
+	root, err := s.LoadNode(ref)
+	require.NoError(t, err)
+	return NewWithTraverserAndRoot(tree.traverser, root)
+}
+
+func TestTree_Persist(t *testing.T) {
+	store := newMapStore()
+	tree := newIntTree()
+	for i := 1; i <= 50; i++ {
+		require.NoError(t, tree.Add(IntKey(i), newIntValue(int64(i))))
+	}
+	persisted := store.persist(t, tree)
+	require.Len(t, store.nodes, 50)
+	require.Equal(t, tree.String(), persisted.String())
+	require.Nil(t, persisted.root.left)
+	require.Nil(t, persisted.root.right)
+
+	// apply the same changes to both in-memory and persisted tree
+	next := persisted.Clone()
+	for _, tr := range []*Tree[IntKey, *Int64Value]{tree, next} {
+		for i := 51; i <= 60; i++ {
+			require.NoError(t, tr.Add(IntKey(i), newIntValue(int64(i))))
+		}
+		for i := 1; i <= 10; i++ {
+			require.NoError(t, tr.Delete(IntKey(i)))
+		}
+		require.NoError(t, tr.Update(20, newIntValue(200)))
+		require.NoError(t, tr.Commit())
+	}
+	require.Equal(t, tree.String(), next.String())
+	// the persisted tree is not changed
+	v, err := persisted.Get(20)
+	require.NoError(t, err)
+	require.EqualValues(t, 20, v.value)
+	_, err = persisted.Get(60)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	orphans, err := persisted.Orphans(next)
+	require.NoError(t, err)
+	require.NotEmpty(t, orphans)
+	persistedNext := store.persist(t, next)
+	for _, ref := range orphans {
+		delete(store.nodes, string(ref))
+	}
+	// store contains only the nodes of the latest tree
+	require.Len(t, store.nodes, 50)
+	require.Equal(t, tree.String(), persistedNext.String())
+	v, err = persistedNext.Get(20)
+	require.NoError(t, err)
+	require.EqualValues(t, 200, v.value)
+
+	// nothing is orphaned when the tree is not changed
+	orphans, err = persistedNext.Orphans(persistedNext.Clone())
+	require.NoError(t, err)
+	require.Empty(t, orphans)
+
+	// in-memory tree has no persisted nodes
+	orphans, err = tree.Orphans(next)
+	require.NoError(t, err)
+	require.Empty(t, orphans)
+}
+
+func TestTree_PersistLoadError(t *testing.T) {
+	store := newMapStore()
+	tree := newIntTree()
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, tree.Add(IntKey(i), newIntValue(int64(i))))
+	}
+	persisted := store.persist(t, tree)
+	store.err = errors.New("disk failure")
+
+	_, err := persisted.Get(1)
+	require.ErrorIs(t, err, store.err)
+	var le *LoadError
+	require.ErrorAs(t, err, &le)
+	require.NotNil(t, le.Ref)
+	require.ErrorIs(t, persisted.Add(11, newIntValue(11)), store.err)
+	require.ErrorIs(t, persisted.Update(1, newIntValue(11)), store.err)
+	require.ErrorIs(t, persisted.Delete(1), store.err)
+}
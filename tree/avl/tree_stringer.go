@@ -14,12 +14,16 @@ func (t *Tree[K, V]) String() string {
 }
 
 func print[K Key[K], V Value[V]](node *Node[K, V], prefix string, tail bool, isRoot bool) (str string) {
-	if node.right != nil {
-		str += print(node.right, rightNodePrefix(prefix, tail), false, false)
+	left, right, err := node.children()
+	if err != nil {
+		return fmt.Sprintf("%s─┤ %v\n", perf(prefix, isRoot, tail), err)
+	}
+	if right != nil {
+		str += print(right, rightNodePrefix(prefix, tail), false, false)
 	}
 	str += fmt.Sprintf("%s─┤ %v\n", perf(prefix, isRoot, tail), node)
-	if node.left != nil {
-		str += print(node.left, leftNodePrefix(prefix, tail, isRoot), true, false)
+	if left != nil {
+		str += print(left, leftNodePrefix(prefix, tail, isRoot), true, false)
 	}
 	return
 }
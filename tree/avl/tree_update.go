@@ -26,18 +26,26 @@ func update[K Key[K], V Value[V]](node *Node[K, V], key K, value V) (*Node[K, V]
 	}
 	i := node.key.Compare(key)
 	if i > 0 {
-		left, err := update(node.left, key, value)
+		left, err := node.Left()
 		if err != nil {
 			return nil, err
 		}
-		node.left = left
+		left, err = update(left, key, value)
+		if err != nil {
+			return nil, err
+		}
+		node.setLeft(left)
 		return node, nil
 	} else if i < 0 {
-		right, err := update(node.right, key, value)
+		right, err := node.Right()
+		if err != nil {
+			return nil, err
+		}
+		right, err = update(right, key, value)
 		if err != nil {
 			return nil, err
 		}
-		node.right = right
+		node.setRight(right)
 		return node, nil
 	}
 	node.value = value
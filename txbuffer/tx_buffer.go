@@ -28,7 +28,7 @@ type (
 	TxBuffer struct {
 		mutex          sync.Mutex
 		transactions   map[string]time.Time // index of pending transactions, hash->added_ts
-		transactionsCh chan *types.TransactionOrder
+		transactionsCh chan bufferedTx
 		hashAlgorithm  crypto.Hash
 		log            *slog.Logger
 		tracer         trace.Tracer
@@ -37,6 +37,12 @@ type (
 		shardAttr metric.MeasurementOption
 	}
 
+	// transaction with the span context of the Add call so that its processing continues the same trace
+	bufferedTx struct {
+		tx *types.TransactionOrder
+		sc trace.SpanContext
+	}
+
 	Observability interface {
 		Meter(name string, opts ...metric.MeterOption) metric.Meter
 		Tracer(name string, options ...trace.TracerOption) trace.Tracer
@@ -59,7 +65,7 @@ func New(maxSize uint, hashAlgorithm crypto.Hash, partition types.PartitionID, s
 	buf := &TxBuffer{
 		hashAlgorithm:  hashAlgorithm,
 		transactions:   make(map[string]time.Time),
-		transactionsCh: make(chan *types.TransactionOrder, maxSize),
+		transactionsCh: make(chan bufferedTx, maxSize),
 		log:            obs.Logger(),
 		tracer:         obs.Tracer("txBuffer"),
 	}
@@ -98,7 +104,7 @@ func (buf *TxBuffer) Add(ctx context.Context, tx *types.TransactionOrder) ([]byt
 	}
 
 	select {
-	case buf.transactionsCh <- tx:
+	case buf.transactionsCh <- bufferedTx{tx: tx, sc: span.SpanContext()}:
 		buf.transactions[txId] = time.Now()
 	default:
 		return nil, ErrTxBufferFull
@@ -107,21 +113,28 @@ func (buf *TxBuffer) Add(ctx context.Context, tx *types.TransactionOrder) ([]byt
 	return txHash, nil
 }
 
-func (buf *TxBuffer) Remove(ctx context.Context) (*types.TransactionOrder, error) {
+/*
+Remove blocks until there is a transaction in the buffer (or ctx is cancelled) and
+removes it from the buffer. Returns the transaction and the span context with which
+the transaction was added to the buffer.
+*/
+func (buf *TxBuffer) Remove(ctx context.Context) (*types.TransactionOrder, trace.SpanContext, error) {
 	_, span := buf.tracer.Start(ctx, "TxBuffer.Remove")
 	defer span.End()
 
 	select {
 	case <-ctx.Done():
-		return nil, ctx.Err()
-	case tx := <-buf.transactionsCh:
+		return nil, trace.SpanContext{}, ctx.Err()
+	case btx := <-buf.transactionsCh:
+		tx := btx.tx
 		txHash, err := tx.Hash(buf.hashAlgorithm)
 		if err != nil {
-			return nil, fmt.Errorf("hashing transaction: %w", err)
+			return nil, trace.SpanContext{}, fmt.Errorf("hashing transaction: %w", err)
 		}
 		span.SetAttributes(observability.TxHash(txHash), observability.UnitID(tx.UnitID), observability.TxTypeKey.Int(int(tx.Type)))
+		span.AddLink(trace.Link{SpanContext: btx.sc})
 		buf.removeFromIndex(ctx, string(txHash))
-		return tx, nil
+		return tx, btx.sc, nil
 	}
 }
 
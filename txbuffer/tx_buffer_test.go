@@ -13,6 +13,7 @@ import (
 	"github.com/unicitynetwork/bft-core/internal/testutils/observability"
 	testtransaction "github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
 	"github.com/unicitynetwork/bft-go-base/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -144,7 +145,7 @@ func Test_TxBuffer_Remove(t *testing.T) {
 	go func() {
 		defer close(done)
 		for {
-			_, err := buffer.Remove(ctx)
+			_, _, err := buffer.Remove(ctx)
 			if err != nil {
 				return
 			}
@@ -165,6 +166,32 @@ func Test_TxBuffer_Remove(t *testing.T) {
 	}
 }
 
+func Test_TxBuffer_Remove_spanContext(t *testing.T) {
+	buffer, err := New(testBufferSize, crypto.SHA256, 1, types.ShardID{}, observability.NOPObservability())
+	require.NoError(t, err)
+
+	// tx added without trace context
+	_, err = buffer.Add(context.Background(), testtransaction.NewTransactionOrder(t))
+	require.NoError(t, err)
+	tx, sc, err := buffer.Remove(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+	require.False(t, sc.IsValid())
+
+	// span context of the Add call is returned with the tx
+	addSC := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	_, err = buffer.Add(trace.ContextWithSpanContext(context.Background(), addSC), testtransaction.NewTransactionOrder(t))
+	require.NoError(t, err)
+	tx, sc, err = buffer.Remove(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+	require.Equal(t, addSC.TraceID(), sc.TraceID())
+}
+
 func Test_TxBuffer_concurrency(t *testing.T) {
 	const totalTxCnt = 20 // how many transactions to process
 
@@ -192,7 +219,7 @@ func Test_TxBuffer_concurrency(t *testing.T) {
 	go func() {
 		defer close(done)
 		for {
-			_, err := buffer.Remove(ctx)
+			_, _, err := buffer.Remove(ctx)
 			if err != nil {
 				return
 			}
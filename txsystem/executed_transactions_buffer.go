@@ -3,6 +3,7 @@ package txsystem
 import (
 	"crypto"
 	"fmt"
+	"maps"
 	"sort"
 
 	abhash "github.com/unicitynetwork/bft-go-base/hash"
@@ -62,6 +63,13 @@ func (b *ETBuffer) Commit() {
 	b.pendingTransactions = make(map[string]uint64)
 }
 
+// committed returns the executed transactions as they are after Commit, without committing the pending changes.
+func (b *ETBuffer) committed() map[string]uint64 {
+	txs := maps.Clone(b.executedTransactions)
+	maps.Copy(txs, b.pendingTransactions)
+	return txs
+}
+
 // Revert reverts pending changes.
 func (b *ETBuffer) Revert() {
 	b.pendingTransactions = make(map[string]uint64)
@@ -45,6 +45,20 @@ func TestETBuffer(t *testing.T) {
 		require.Zero(t, timeout)
 	})
 
+	t.Run("committed transactions", func(t *testing.T) {
+		buffer := NewETBuffer()
+		buffer.Add("tx1", 1)
+		buffer.Commit()
+		buffer.Add("tx2", 2)
+
+		require.Equal(t, map[string]uint64{"tx1": 1, "tx2": 2}, buffer.committed())
+		// pending transactions are not committed
+		buffer.Revert()
+		timeout, f := buffer.Get("tx2")
+		require.False(t, f)
+		require.Zero(t, timeout)
+	})
+
 	t.Run("clear expired transactions", func(t *testing.T) {
 		buffer := NewETBuffer()
 		txID1 := "tx1"
@@ -40,6 +40,8 @@ type (
 		pr                  predicates.PredicateRunner
 		unitIDValidator     func(types.UnitID) error
 		etBuffer            *ETBuffer // executed transactions buffer
+		workers             []*GenericTxSystem
+		sequentialTxTypes   map[uint16]struct{}
 	}
 
 	Observability interface {
@@ -78,6 +80,10 @@ func NewGenericTxSystem(shardConf types.PartitionDescriptionRecord, modules []tx
 		pr:                  options.predicateRunner,
 		fees:                options.feeCredit,
 		etBuffer:            NewETBuffer(WithExecutedTxs(options.executedTransactions)),
+		sequentialTxTypes:   make(map[uint16]struct{}),
+	}
+	for _, txType := range options.sequentialTxTypes {
+		txs.sequentialTxTypes[txType] = struct{}{}
 	}
 	txs.log = observe.RoundLogger(txs.CurrentRound)
 	txs.beginBlockFunctions = append([]func(roundNo uint64) error{txs.pruneState, txs.rInit}, txs.beginBlockFunctions...)
@@ -97,6 +103,13 @@ func NewGenericTxSystem(shardConf types.PartitionDescriptionRecord, modules []tx
 	if err := txs.initMetrics(observe.Meter("txsystem"), shardConf.ShardID); err != nil {
 		return nil, fmt.Errorf("initializing metrics: %w", err)
 	}
+	for range options.workers {
+		worker, err := options.newWorker(state.NewEmptyState(state.WithHashAlgorithm(options.hashAlgorithm)), workerObservability{observe})
+		if err != nil {
+			return nil, fmt.Errorf("creating parallel execution worker: %w", err)
+		}
+		txs.workers = append(txs.workers, worker)
+	}
 
 	return txs, nil
 }
@@ -158,10 +171,13 @@ func (m *GenericTxSystem) BeginBlock(roundNo uint64) error {
 //  1. Prune the state change history for all units that were targeted by transactions in the previous round (done in state pruner)
 //  2. Delete all unlocked fee credit records with zero remaining balance and expired lifetime
 //  3. Delete all expired units
+//  4. Roll back all expired state locks
 func (m *GenericTxSystem) rInit(roundNumber uint64) error {
 	var expiredFCRs []types.UnitID
 	var expiredUnits []types.UnitID
-	err := m.state.Traverse(state.NewInorderTraverser(func(unitID types.UnitID, unit state.Unit) error {
+	var lockedUnits []types.UnitID
+	// only the units which may expire are visited, see state.ExpiryTraverser
+	err := m.state.Traverse(state.NewExpiryTraverser(roundNumber, func(unitID types.UnitID, unit state.Unit) error {
 		unitV1, err := state.ToUnitV1(unit)
 		if err != nil {
 			return fmt.Errorf("failed to extract unit v1: %w", err)
@@ -181,6 +197,8 @@ func (m *GenericTxSystem) rInit(roundNumber uint64) error {
 			}
 		} else if unitV1.IsExpired(roundNumber) {
 			expiredUnits = append(expiredUnits, unitID)
+		} else if unitV1.IsStateLocked() {
+			lockedUnits = append(lockedUnits, unitID)
 		}
 		// TODO move state_pruner here?
 		return nil
@@ -194,6 +212,13 @@ func (m *GenericTxSystem) rInit(roundNumber uint64) error {
 	if err := m.deleteUnits(expiredUnits); err != nil {
 		return fmt.Errorf("failed to delete ordinary units: %w", err)
 	}
+	// the lock which can't be rolled back is left in place (it still can be
+	// resolved by the unlock transaction), it must not halt the shard
+	for _, unitID := range lockedUnits {
+		if err := m.rollbackExpiredStateLock(unitID, roundNumber); err != nil {
+			m.log.Warn("failed to roll back expired state lock", logger.Error(err), logger.UnitID(unitID))
+		}
+	}
 	return nil
 }
 
@@ -222,25 +247,37 @@ func (m *GenericTxSystem) snFees(_ *types.TransactionOrder, execCxt txtypes.Exec
 	return execCxt.SpendGas(abfc.GeneralTxCostGasUnits)
 }
 
-func (m *GenericTxSystem) Execute(tx *types.TransactionOrder) (tr *types.TransactionRecord, err error) {
+func (m *GenericTxSystem) Execute(tx *types.TransactionOrder) (*types.TransactionRecord, error) {
 	// discard tx if it is a duplicate
-	txHash, err := tx.Hash(m.hashAlgorithm)
+	txID, err := m.txID(tx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash transaction: %w", err)
+		return nil, err
 	}
-	// encode tx hash to hex string as the transaction buffer is included
-	// in the state file and encoded as CBOR which requires string values to be UTF-8 encoded
-	txID := hex.EncodeToString(txHash)
 	_, f := m.etBuffer.Get(txID)
 	if f {
 		return nil, errors.New("transaction already executed")
 	}
-	defer func() {
-		if tr != nil {
-			m.etBuffer.Add(txID, tx.Timeout())
-		}
-	}()
+	tr, err := m.execute(tx)
+	if err != nil {
+		return nil, err
+	}
+	m.etBuffer.Add(txID, tx.Timeout())
+	return tr, nil
+}
+
+// txID returns the identifier of the transaction in the executed transactions buffer.
+func (m *GenericTxSystem) txID(tx *types.TransactionOrder) (string, error) {
+	txHash, err := tx.Hash(m.hashAlgorithm)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash transaction: %w", err)
+	}
+	// encode tx hash to hex string as the transaction buffer is included
+	// in the state file and encoded as CBOR which requires string values to be UTF-8 encoded
+	return hex.EncodeToString(txHash), nil
+}
 
+// execute executes the transaction without checking (and recording) it in the executed transactions buffer.
+func (m *GenericTxSystem) execute(tx *types.TransactionOrder) (tr *types.TransactionRecord, err error) {
 	// First, check transaction credible and that there are enough fee credits on the FCR?
 	// buy gas according to the maximum tx fee allowed by client -
 	// if fee proof check fails, function will exit tx and tx will not be added to block
@@ -525,7 +562,7 @@ func (m *GenericTxSystem) Revert() {
 }
 
 func (m *GenericTxSystem) Commit(uc *types.UnicityCertificate) error {
-	err := m.state.Commit(uc)
+	err := m.state.CommitWithExecutedTransactions(uc, m.etBuffer.committed())
 	if err == nil {
 		m.roundCommitted = true
 		m.etBuffer.Commit()
@@ -6,6 +6,7 @@ import (
 	"github.com/unicitynetwork/bft-core/txsystem"
 	"github.com/unicitynetwork/bft-core/txsystem/fc"
 	txtypes "github.com/unicitynetwork/bft-core/txsystem/types"
+	fcsdk "github.com/unicitynetwork/bft-go-base/txsystem/fc"
 	"github.com/unicitynetwork/bft-go-base/txsystem/money"
 	basetypes "github.com/unicitynetwork/bft-go-base/types"
 )
@@ -40,5 +41,8 @@ func NewTxSystem(shardConf *basetypes.PartitionDescriptionRecord, observe txsyst
 		txsystem.WithHashAlgorithm(options.hashAlgorithm),
 		txsystem.WithState(options.state),
 		txsystem.WithExecutedTransactions(options.executedTransactions),
+		txsystem.WithParallelExecution(options.workers, options.newWorker),
+		// the fee credit transfers are recorded by the money module
+		txsystem.WithSequentialTxTypes(fcsdk.TransactionTypeTransferFeeCredit, fcsdk.TransactionTypeReclaimFeeCredit),
 	)
 }
@@ -19,6 +19,8 @@ type (
 		hashAlgorithm        crypto.Hash
 		trustBase            types.RootTrustBase
 		exec                 predicates.PredicateExecutor
+		workers              int
+		newWorker            txsystem.WorkerFactory
 	}
 
 	Option func(*Options)
@@ -75,3 +77,14 @@ func WithPredicateExecutor(exec predicates.PredicateExecutor) Option {
 		}
 	}
 }
+
+/*
+WithParallelExecution enables the parallel execution of the transactions by the
+given number of workers, see txsystem.WithParallelExecution.
+*/
+func WithParallelExecution(workers int, newWorker txsystem.WorkerFactory) Option {
+	return func(g *Options) {
+		g.workers = workers
+		g.newWorker = newWorker
+	}
+}
@@ -789,6 +789,74 @@ func TestExecute_FeeCreditSequence_OK(t *testing.T) {
 	require.EqualValues(t, 2, bd.Counter)
 }
 
+func TestExecuteBatch_ParallelExecution(t *testing.T) {
+	pdrs := createPDRs(t)
+	_, verifier := testsig.CreateSignerAndVerifier(t)
+	trustBase := testtb.NewTrustBase(t, verifier)
+
+	// bills with their own fee credit records, ie the transfers of the bills do not conflict
+	const billCount = 8
+	billIDs := make([]types.UnitID, billCount)
+	fcrIDs := make([]types.UnitID, billCount)
+	s := genesisState(t, initialBill, pdrs)
+	for i := range billCount {
+		billIDs[i] = moneyid.BillIDWithSuffix(t, byte(10+i), nil)
+		fcrIDs[i] = moneyid.NewFeeCreditRecordID(t)
+		require.NoError(t, s.Apply(state.AddUnit(billIDs[i], money.NewBillData(10, templates.AlwaysTrueBytes()))))
+		require.NoError(t, s.Apply(unit.AddCredit(fcrIDs[i], &fcsdk.FeeCreditRecord{Balance: 100, MinLifetime: 100, OwnerPredicate: templates.AlwaysTrueBytes()})))
+	}
+	summaryValue, summaryHash, err := s.CalculateRoot()
+	require.NoError(t, err)
+	require.NoError(t, s.Commit(&types.UnicityCertificate{Version: 1, InputRecord: &types.InputRecord{
+		Version:      1,
+		RoundNumber:  1,
+		Hash:         summaryHash,
+		SummaryValue: util.Uint64ToBytes(summaryValue),
+	}}))
+
+	var txs []*types.TransactionOrder
+	for i := range billCount {
+		tx, _, _ := createBillTransfer(t, billIDs[i], fcrIDs[i], 10, templates.AlwaysTrueBytes(), 0)
+		txs = append(txs, tx)
+	}
+	// depends on the first transfer of the bill
+	tx, _, _ := createBillTransfer(t, billIDs[0], fcrIDs[0], 10, templates.AlwaysFalseBytes(), 1)
+	txs = append(txs, tx)
+	// shares the fee credit record with the transfer of the second bill
+	tx, _, _ = createBillTransfer(t, initialBill.ID, fcrIDs[1], initialBill.Value, templates.AlwaysTrueBytes(), 0)
+	txs = append(txs, tx)
+	// invalid counter
+	tx, _, _ = createBillTransfer(t, billIDs[2], fcrIDs[2], 10, templates.AlwaysTrueBytes(), 5)
+	txs = append(txs, tx)
+	// duplicate
+	txs = append(txs, txs[3])
+
+	execute := func(opts ...Option) ([]*types.TransactionRecord, []error, *txsystem.StateSummary) {
+		txSystem, err := NewTxSystem(pdrs[0], observability.Default(t), append([]Option{WithState(s.Clone()), WithTrustBase(trustBase)}, opts...)...)
+		require.NoError(t, err)
+		require.NoError(t, txSystem.BeginBlock(2))
+		trs, durs, errs := txSystem.ExecuteBatch(txs)
+		require.Len(t, durs, len(txs))
+		summary, err := txSystem.EndBlock()
+		require.NoError(t, err)
+		return trs, errs, summary
+	}
+	seqTrs, seqErrs, seqSummary := execute()
+	parTrs, parErrs, parSummary := execute(WithParallelExecution(4, func(s *state.State, observe txsystem.Observability) (*txsystem.GenericTxSystem, error) {
+		return NewTxSystem(pdrs[0], observe, WithState(s), WithTrustBase(trustBase))
+	}))
+
+	require.Equal(t, seqTrs, parTrs)
+	require.Equal(t, seqErrs, parErrs)
+	require.Equal(t, seqSummary.Root(), parSummary.Root())
+	require.Equal(t, seqSummary.Summary(), parSummary.Summary())
+	require.Equal(t, seqSummary.ETHash(), parSummary.ETHash())
+	// sanity check of the test data
+	require.Equal(t, types.TxStatusSuccessful, parTrs[8].ServerMetadata.SuccessIndicator)
+	require.Equal(t, types.TxStatusSuccessful, parTrs[9].ServerMetadata.SuccessIndicator)
+	require.ErrorContains(t, parErrs[11], "transaction already executed")
+}
+
 func getBill(t *testing.T, s *state.State, billID types.UnitID) (state.Unit, *money.BillData) {
 	t.Helper()
 	ib, err := s.GetUnit(billID, false)
@@ -2,6 +2,7 @@ package txsystem
 
 import (
 	"crypto"
+	"errors"
 	"fmt"
 
 	"github.com/unicitynetwork/bft-core/predicates"
@@ -20,6 +21,9 @@ type Options struct {
 	predicateRunner      predicates.PredicateRunner
 	feeCredit            txtypes.FeeCreditModule
 	observe              Observability
+	workers              int
+	newWorker            WorkerFactory
+	sequentialTxTypes    []uint16
 }
 
 type Option func(*Options) error
@@ -77,6 +81,35 @@ func WithFeeCredits(f txtypes.FeeCreditModule) Option {
 	}
 }
 
+/*
+WithParallelExecution enables the parallel execution of the transactions of the
+batch (see GenericTxSystem.ExecuteBatch) by the given number of workers created
+using newWorker. The workers must be configured the same way as the transaction
+system itself but must not share any components (ie predicate engines) with it.
+*/
+func WithParallelExecution(workers int, newWorker WorkerFactory) Option {
+	return func(g *Options) error {
+		if workers > 0 && newWorker == nil {
+			return errors.New("worker factory is nil")
+		}
+		g.workers = workers
+		g.newWorker = newWorker
+		return nil
+	}
+}
+
+/*
+WithSequentialTxTypes sets the transaction types which are never executed in
+parallel (see WithParallelExecution), ie the transactions which change the
+state of the modules in addition to the units.
+*/
+func WithSequentialTxTypes(txTypes ...uint16) Option {
+	return func(g *Options) error {
+		g.sequentialTxTypes = append(g.sequentialTxTypes, txTypes...)
+		return nil
+	}
+}
+
 func (o *Options) initPredicateRunner(observe Observability) (*Options, error) {
 	templEng, err := templates.New(observe)
 	if err != nil {
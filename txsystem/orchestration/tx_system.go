@@ -27,6 +27,7 @@ func NewTxSystem(shardConf types.PartitionDescriptionRecord, observe txsystem.Ob
 		txsystem.WithHashAlgorithm(options.hashAlgorithm),
 		txsystem.WithState(options.state),
 		txsystem.WithExecutedTransactions(options.executedTransactions),
+		txsystem.WithParallelExecution(options.workers, options.newWorker),
 	)
 }
 
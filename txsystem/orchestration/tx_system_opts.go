@@ -18,6 +18,8 @@ type (
 		hashAlgorithm        crypto.Hash
 		ownerPredicate       types.PredicateBytes
 		exec                 predicates.PredicateExecutor
+		workers              int
+		newWorker            txsystem.WorkerFactory
 	}
 
 	Option func(*Options)
@@ -62,3 +64,14 @@ func WithOwnerPredicate(ownerPredicate types.PredicateBytes) Option {
 		g.ownerPredicate = ownerPredicate
 	}
 }
+
+/*
+WithParallelExecution enables the parallel execution of the transactions by the
+given number of workers, see txsystem.WithParallelExecution.
+*/
+func WithParallelExecution(workers int, newWorker txsystem.WorkerFactory) Option {
+	return func(g *Options) {
+		g.workers = workers
+		g.newWorker = newWorker
+	}
+}
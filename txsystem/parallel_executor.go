@@ -0,0 +1,201 @@
+package txsystem
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/unicitynetwork/bft-core/state"
+	txtypes "github.com/unicitynetwork/bft-core/txsystem/types"
+	"github.com/unicitynetwork/bft-go-base/types"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+var _ BatchExecutor = (*GenericTxSystem)(nil)
+
+type (
+	/*
+		WorkerFactory creates the transaction system which executes the transactions
+		on the given (overlay) state, see WithParallelExecution.
+	*/
+	WorkerFactory func(s *state.State, observe Observability) (*GenericTxSystem, error)
+
+	// overlayResult is the result of the transaction executed on an overlay of the state
+	overlayResult struct {
+		tr     *types.TransactionRecord
+		err    error
+		access *state.UnitAccess
+		dur    time.Duration
+	}
+
+	// workerObservability disables the metrics of the workers, ie the state metrics would report the overlay state
+	workerObservability struct {
+		Observability
+	}
+)
+
+/*
+ExecuteBatch executes the transaction orders in the given order, the results
+and the resulting state are the same as if Execute was called for each
+transaction order.
+
+When the parallel execution is enabled (see WithParallelExecution) the
+transactions with disjoint target units and fee credit records are executed
+concurrently on the isolated overlays of the current state. The changes made
+to the overlays are merged to the state in the order of the transactions,
+a transaction which accessed any unit changed by the preceding transactions
+of the batch is executed again on the state, ie sequentially.
+
+The execution time of the transaction is the time of the execution whose result
+is used, ie the execution on the overlay and the merge or the sequential execution.
+*/
+func (m *GenericTxSystem) ExecuteBatch(txs []*types.TransactionOrder) ([]*types.TransactionRecord, []time.Duration, []error) {
+	trs := make([]*types.TransactionRecord, len(txs))
+	durs := make([]time.Duration, len(txs))
+	errs := make([]error, len(txs))
+	if len(m.workers) == 0 || len(txs) < 2 {
+		for i, tx := range txs {
+			start := time.Now()
+			trs[i], errs[i] = m.Execute(tx)
+			durs[i] = time.Since(start)
+		}
+		return trs, durs, errs
+	}
+
+	results := m.executeOnOverlays(txs, m.schedule(txs))
+	// units changed by the transactions merged so far
+	changed := state.NewUnitAccess()
+	for i, tx := range txs {
+		start := time.Now()
+		if r := results[i]; r != nil && r.err == nil && !r.access.DependsOn(changed) {
+			if err := m.merge(tx, r.access); err == nil {
+				trs[i] = r.tr
+				durs[i] = r.dur + time.Since(start)
+				changed.Merge(r.access)
+				continue
+			}
+		}
+		start = time.Now()
+		m.state.StartAccessTracking()
+		trs[i], errs[i] = m.Execute(tx)
+		changed.Merge(m.state.StopAccessTracking())
+		durs[i] = time.Since(start)
+	}
+	return trs, durs, errs
+}
+
+/*
+schedule returns the transactions which can be executed in parallel, ie the
+transactions whose target units and fee credit record are not targeted by
+any preceding transaction of the batch.
+*/
+func (m *GenericTxSystem) schedule(txs []*types.TransactionOrder) []bool {
+	parallel := make([]bool, len(txs))
+	targeted := make(map[string]struct{})
+	for i, tx := range txs {
+		units, err := m.targetUnits(tx)
+		if err != nil {
+			// executed sequentially, ie fails the same way as Execute
+			continue
+		}
+		parallel[i] = true
+		for _, id := range units {
+			if _, ok := targeted[string(id)]; ok {
+				parallel[i] = false
+			}
+			targeted[string(id)] = struct{}{}
+		}
+	}
+	return parallel
+}
+
+// targetUnits returns the units the transaction is expected to change.
+func (m *GenericTxSystem) targetUnits(tx *types.TransactionOrder) ([]types.UnitID, error) {
+	if m.fees.IsFeeCreditTx(tx) {
+		return nil, errors.New("fee credit transaction")
+	}
+	if _, ok := m.sequentialTxTypes[tx.Type]; ok {
+		return nil, fmt.Errorf("transaction type %d is executed sequentially", tx.Type)
+	}
+	exeCtx := txtypes.NewExecutionContext(m, m.fees, tx.MaxFee())
+	_, _, targetUnits, err := m.handlers.UnmarshalTx(tx, exeCtx)
+	if err != nil {
+		return nil, err
+	}
+	if fcrID := tx.FeeCreditRecordID(); len(fcrID) > 0 {
+		targetUnits = append(targetUnits, fcrID)
+	}
+	return targetUnits, nil
+}
+
+/*
+executeOnOverlays executes the scheduled transactions concurrently by the workers,
+the state of each worker is reset to the overlay of the state once per batch.
+*/
+func (m *GenericTxSystem) executeOnOverlays(txs []*types.TransactionOrder, parallel []bool) []*overlayResult {
+	results := make([]*overlayResult, len(txs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for _, w := range m.workers {
+		w.currentRoundNumber = m.currentRoundNumber
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := w.state.ResetTo(m.state)
+			for i := range jobs {
+				if err != nil {
+					results[i] = &overlayResult{err: err}
+					continue
+				}
+				results[i] = w.executeOnOverlay(txs[i])
+			}
+		}()
+	}
+	for i := range txs {
+		if parallel[i] {
+			jobs <- i
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+/*
+executeOnOverlay executes the transaction on the isolated overlay of the base state
+(see state.State.ResetTo), the changes are rolled back after the execution so that
+the next transaction is executed on the same overlay.
+*/
+func (m *GenericTxSystem) executeOnOverlay(tx *types.TransactionOrder) *overlayResult {
+	start := time.Now()
+	id, err := m.state.Savepoint()
+	if err != nil {
+		return &overlayResult{err: err}
+	}
+	defer m.state.RollbackToSavepoint(id)
+	m.state.StartAccessTracking()
+	tr, err := m.execute(tx)
+	return &overlayResult{tr: tr, err: err, access: m.state.StopAccessTracking(), dur: time.Since(start)}
+}
+
+// merge applies the changes made by the transaction on the overlay to the state.
+func (m *GenericTxSystem) merge(tx *types.TransactionOrder, access *state.UnitAccess) error {
+	txID, err := m.txID(tx)
+	if err != nil {
+		return err
+	}
+	if _, f := m.etBuffer.Get(txID); f {
+		return errors.New("transaction already executed")
+	}
+	if err := m.state.ApplyChanges(access); err != nil {
+		return err
+	}
+	m.etBuffer.Add(txID, tx.Timeout())
+	return nil
+}
+
+func (workerObservability) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	return noop.NewMeterProvider().Meter(name, opts...)
+}
@@ -90,8 +90,15 @@ func (m *GenericTxSystem) handleTxOnHold(tx *types.TransactionOrder, txOnHold *t
 	}
 
 	// unlock the existing target units
-	for _, targetUnit := range sm.TargetUnits {
-		if err = m.state.Apply(state.RemoveStateLock(targetUnit)); err != nil {
+	if err = m.removeStateLocks(sm.TargetUnits); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+func (m *GenericTxSystem) removeStateLocks(targetUnits []types.UnitID) error {
+	for _, targetUnit := range targetUnits {
+		if err := m.state.Apply(state.RemoveStateLock(targetUnit)); err != nil {
 			if errors.Is(err, avl.ErrNotFound) {
 				m.log.Debug("not removing state lock, unit does not exist", logger.UnitID(targetUnit))
 				continue
@@ -100,11 +107,72 @@ func (m *GenericTxSystem) handleTxOnHold(tx *types.TransactionOrder, txOnHold *t
 				m.log.Debug("not removing state lock, unit is already unlocked", logger.UnitID(targetUnit))
 				continue
 			}
-			return nil, fmt.Errorf("failed to release state lock for unit %s: %w", targetUnit, err)
+			return fmt.Errorf("failed to release state lock for unit %s: %w", targetUnit, err)
 		}
 		m.log.Debug("unit state lock removed", logger.UnitID(targetUnit))
 	}
-	return sm, nil
+	return nil
+}
+
+/*
+stateLockExpiry returns the round starting from which the state lock is expired,
+ie the locked transaction is rolled back by the round initialization. The second
+return value is false when the lock doesn't expire.
+*/
+func stateLockExpiry(stateLock *types.StateLock) (uint64, bool) {
+	if stateLock == nil || stateLock.Expiry == 0 {
+		return 0, false
+	}
+	return stateLock.Expiry, true
+}
+
+/*
+rollbackExpiredStateLock rolls back the state lock of the unit if the lock has
+expired by the round "roundNumber".
+
+The rollback is not a transaction, it is a state change done by the round
+initialization and thus there is no transaction record in the block for it.
+The rollback is recorded in the unit log of all the target units of the locked
+transaction as an entry without transaction record hash (the same way the state
+pruner records the initial state of the unit in the round), so the unit ledger
+and the unit state proofs remain verifiable.
+*/
+func (m *GenericTxSystem) rollbackExpiredStateLock(unitID types.UnitID, roundNumber uint64) error {
+	// the lock might have been already rolled back as a target unit of another unit's lock
+	txOnHold, err := m.parseTxOnHold(unitID)
+	if err != nil {
+		return fmt.Errorf("failed to parse txOnHold: %w", err)
+	}
+	if txOnHold == nil {
+		return nil
+	}
+	if expiry, ok := stateLockExpiry(txOnHold.StateLock); !ok || roundNumber < expiry {
+		return nil
+	}
+
+	savepointID, err := m.state.Savepoint()
+	if err != nil {
+		return fmt.Errorf("savepoint error: %w", err)
+	}
+	sm, err := m.executeLockedTx(&StateUnlockProof{Kind: StateUnlockRollback}, txOnHold, txtypes.NewExecutionContext(m, m.fees, 0))
+	if err == nil {
+		err = m.removeStateLocks(sm.TargetUnits)
+	}
+	if err == nil {
+		for _, targetID := range sm.TargetUnits {
+			if err = m.state.AddUnitLog(targetID, nil); err != nil {
+				err = fmt.Errorf("adding unit log: %w", err)
+				break
+			}
+		}
+	}
+	if err != nil {
+		m.state.RollbackToSavepoint(savepointID)
+		return fmt.Errorf("rolling back expired state lock of unit %s: %w", unitID, err)
+	}
+	m.state.ReleaseToSavepoint(savepointID)
+	m.log.Debug("expired state lock rolled back", logger.UnitID(unitID), logger.Data(txOnHold))
+	return nil
 }
 
 func (m *GenericTxSystem) parseTxOnHold(unitID types.UnitID) (*types.TransactionOrder, error) {
@@ -178,6 +246,9 @@ func (m *GenericTxSystem) executeLockUnitState(tx *types.TransactionOrder, txByt
 	if err := tx.StateLock.IsValid(); err != nil {
 		return nil, fmt.Errorf("invalid state lock parameter: %w", err)
 	}
+	if expiry, ok := stateLockExpiry(tx.StateLock); ok && expiry <= m.currentRoundNumber {
+		return nil, fmt.Errorf("invalid state lock parameter: lock expired in round %d, current round is %d", expiry, m.currentRoundNumber)
+	}
 	// for each target unit add dummy unit and lock it or lock existing unit
 	for _, targetUnit := range targetUnits {
 		if err := m.state.Apply(state.AddDummyUnit(targetUnit)); err != nil && !errors.Is(err, avl.ErrAlreadyExists) {
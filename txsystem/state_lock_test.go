@@ -9,6 +9,7 @@ import (
 	"github.com/unicitynetwork/bft-core/predicates"
 	"github.com/unicitynetwork/bft-core/predicates/templates"
 	"github.com/unicitynetwork/bft-core/state"
+	"github.com/unicitynetwork/bft-core/tree/avl"
 	abfc "github.com/unicitynetwork/bft-core/txsystem/fc"
 	tt "github.com/unicitynetwork/bft-core/txsystem/testutils/transaction"
 	txtypes "github.com/unicitynetwork/bft-core/txsystem/types"
@@ -468,6 +469,29 @@ func TestGenericTxSystem_executeLockUnitState(t *testing.T) {
 		require.EqualError(t, err, "invalid state lock parameter: missing rollback predicate")
 		require.Nil(t, sm)
 	})
+	t.Run("err - state lock already expired", func(t *testing.T) {
+		unitID := moneyid.NewBillID(t)
+		txSys := NewTestGenericTxSystem(t, nil, withStateUnit(unitID, &money.BillData{Value: 1, Counter: 1, OwnerPredicate: basetemplates.AlwaysTrueBytes()}, nil))
+		txSys.currentRoundNumber = 10
+		tx := tt.NewTransactionOrder(
+			t,
+			tt.WithTransactionType(money.TransactionTypeTransfer),
+			tt.WithUnitID(unitID),
+			tt.WithPartitionID(money.DefaultPartitionID),
+			tt.WithAttributes(&money.TransferAttributes{}),
+			tt.WithStateLock(&types.StateLock{
+				ExecutionPredicate: basetemplates.AlwaysTrueBytes(),
+				RollbackPredicate:  basetemplates.AlwaysTrueBytes(),
+				Expiry:             10,
+			}),
+		)
+		txBytes, err := types.Cbor.Marshal(tx)
+		require.NoError(t, err)
+		execCtx := txtypes.NewExecutionContext(txSys, abfc.NewNoFeeCreditModule(), 10)
+		sm, err := txSys.executeLockUnitState(tx, txBytes, []types.UnitID{unitID}, execCtx)
+		require.EqualError(t, err, "invalid state lock parameter: lock expired in round 10, current round is 10")
+		require.Nil(t, sm)
+	})
 	t.Run("ok", func(t *testing.T) {
 		unitID := moneyid.NewBillID(t)
 		txSys := NewTestGenericTxSystem(t, nil, withStateUnit(unitID, &money.BillData{Value: 1, Counter: 1, OwnerPredicate: basetemplates.AlwaysTrueBytes()}, nil))
@@ -572,3 +596,112 @@ func createLockTransaction(t *testing.T, id types.UnitID, pubkey []byte) []byte
 	require.NoError(t, err)
 	return txBytes
 }
+
+func Test_stateLockExpiry(t *testing.T) {
+	expiry, ok := stateLockExpiry(nil)
+	require.False(t, ok)
+	require.Zero(t, expiry)
+
+	// expiry is not inferred from the rollback predicate
+	expiry, ok = stateLockExpiry(&types.StateLock{RollbackPredicate: templates.NewTimeLockBytes(42)})
+	require.False(t, ok)
+	require.Zero(t, expiry)
+
+	expiry, ok = stateLockExpiry(&types.StateLock{RollbackPredicate: basetemplates.AlwaysTrueBytes(), Expiry: 42})
+	require.True(t, ok)
+	require.EqualValues(t, 42, expiry)
+}
+
+func TestGenericTxSystem_rollbackExpiredStateLocks(t *testing.T) {
+	_, ver1 := testsig.CreateSignerAndVerifier(t)
+	pubKey1, err := ver1.MarshalPublicKey()
+	require.NoError(t, err)
+	unitID := moneyid.NewBillID(t)
+	dummyUnitID := moneyid.NewBillID(t)
+	const expiryRound = 20
+
+	createTxSystem := func(t *testing.T, stateLock *types.StateLock, txType uint16) *GenericTxSystem {
+		txOnHold := tt.NewTransactionOrder(
+			t,
+			tt.WithTransactionType(txType),
+			tt.WithUnitID(unitID),
+			tt.WithPartitionID(money.DefaultPartitionID),
+			tt.WithAttributes(&MockSplitTxAttributes{Value: 10, TargetUnits: []types.UnitID{unitID, dummyUnitID}}),
+			tt.WithAuthProof(&MockTxAuthProof{}),
+			tt.WithStateLock(stateLock),
+		)
+		txOnHoldBytes, err := types.Cbor.Marshal(txOnHold)
+		require.NoError(t, err)
+		txSys := NewTestGenericTxSystem(t,
+			[]txtypes.Module{NewMockTxModule(nil)},
+			withStateUnit(unitID, &money.BillData{Value: 1, Counter: 1, OwnerPredicate: basetemplates.AlwaysTrueBytes()}, txOnHoldBytes),
+			withStateUnit(dummyUnitID, nil, txOnHoldBytes),
+		)
+		commitState(t, txSys)
+		return txSys
+	}
+	requireLocked := func(t *testing.T, txSys *GenericTxSystem, id types.UnitID, locked bool) *state.UnitV1 {
+		u, err := txSys.state.GetUnit(id, false)
+		require.NoError(t, err)
+		unit, err := state.ToUnitV1(u)
+		require.NoError(t, err)
+		require.Equal(t, locked, unit.IsStateLocked())
+		return unit
+	}
+
+	t.Run("lock without expiry is not rolled back", func(t *testing.T) {
+		txSys := createTxSystem(t, &types.StateLock{
+			ExecutionPredicate: basetemplates.NewP2pkh256BytesFromKey(pubKey1),
+			RollbackPredicate:  basetemplates.NewP2pkh256BytesFromKey(pubKey1),
+		}, mockSplitTxType)
+		require.NoError(t, txSys.BeginBlock(expiryRound+100))
+		requireLocked(t, txSys, unitID, true)
+		requireLocked(t, txSys, dummyUnitID, true)
+	})
+
+	t.Run("lock is rolled back when expired", func(t *testing.T) {
+		txSys := createTxSystem(t, &types.StateLock{
+			ExecutionPredicate: basetemplates.NewP2pkh256BytesFromKey(pubKey1),
+			RollbackPredicate:  templates.NewTimeLockBytes(expiryRound),
+			Expiry:             expiryRound,
+		}, mockSplitTxType)
+		// not yet expired
+		require.NoError(t, txSys.BeginBlock(expiryRound-1))
+		unitLogs := len(requireLocked(t, txSys, unitID, true).Logs())
+		dummyLogs := len(requireLocked(t, txSys, dummyUnitID, true).Logs())
+		txSys.Revert()
+
+		require.NoError(t, txSys.BeginBlock(expiryRound))
+		unit := requireLocked(t, txSys, unitID, false)
+		dummy := requireLocked(t, txSys, dummyUnitID, false)
+		// unit data was not changed, dummy unit will be deleted
+		require.EqualValues(t, 1, unit.Data().(*money.BillData).Value)
+		require.EqualValues(t, expiryRound+1, dummy.DeletionRound())
+		// rollback is recorded in the unit logs of both units, without tx record
+		require.Len(t, unit.Logs(), unitLogs+1)
+		require.Len(t, dummy.Logs(), dummyLogs+1)
+		require.Nil(t, unit.Logs()[unit.LastLogIndex()].TxRecordHash)
+		require.Nil(t, dummy.Logs()[dummy.LastLogIndex()].TxRecordHash)
+		require.Nil(t, unit.Logs()[unit.LastLogIndex()].NewStateLockTx)
+
+		// dummy unit is deleted in the next round
+		_, err := txSys.EndBlock()
+		require.NoError(t, err)
+		commitState(t, txSys)
+		require.NoError(t, txSys.BeginBlock(expiryRound+1))
+		_, err = txSys.state.GetUnit(dummyUnitID, false)
+		require.ErrorIs(t, err, avl.ErrNotFound)
+		requireLocked(t, txSys, unitID, false)
+	})
+	t.Run("lock which fails to roll back is left in place", func(t *testing.T) {
+		// transaction of unknown type can't be rolled back
+		txSys := createTxSystem(t, &types.StateLock{
+			ExecutionPredicate: basetemplates.NewP2pkh256BytesFromKey(pubKey1),
+			RollbackPredicate:  templates.NewTimeLockBytes(expiryRound),
+			Expiry:             expiryRound,
+		}, 99)
+		require.NoError(t, txSys.BeginBlock(expiryRound))
+		requireLocked(t, txSys, unitID, true)
+		requireLocked(t, txSys, dummyUnitID, true)
+	})
+}
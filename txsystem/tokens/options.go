@@ -23,6 +23,8 @@ type (
 		exec                 predicates.PredicateExecutor
 		adminOwnerPredicate  []byte
 		feelessMode          bool
+		workers              int
+		newWorker            txsystem.WorkerFactory
 	}
 
 	Option func(*Options)
@@ -98,3 +100,14 @@ func WithPredicateExecutor(exec predicates.PredicateExecutor) Option {
 		}
 	}
 }
+
+/*
+WithParallelExecution enables the parallel execution of the transactions by the
+given number of workers, see txsystem.WithParallelExecution.
+*/
+func WithParallelExecution(workers int, newWorker txsystem.WorkerFactory) Option {
+	return func(c *Options) {
+		c.workers = workers
+		c.newWorker = newWorker
+	}
+}
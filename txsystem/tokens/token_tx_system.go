@@ -81,5 +81,6 @@ func NewTxSystem(shardConf basetypes.PartitionDescriptionRecord, observe txsyste
 		txsystem.WithHashAlgorithm(options.hashAlgorithm),
 		txsystem.WithState(options.state),
 		txsystem.WithExecutedTransactions(options.executedTransactions),
+		txsystem.WithParallelExecution(options.workers, options.newWorker),
 	)
 }
@@ -3,6 +3,7 @@ package txsystem
 import (
 	"errors"
 	"io"
+	"time"
 
 	"github.com/unicitynetwork/bft-core/state"
 	"github.com/unicitynetwork/bft-go-base/types"
@@ -74,6 +75,9 @@ type (
 		Serialize(writer io.Writer, committed bool, executedTransactions map[string]uint64) error
 
 		GetUnits(unitTypeID *uint32, pdr *types.PartitionDescriptionRecord) ([]types.UnitID, error)
+
+		// AtRound returns the committed state of the given round, see state.WithArchive.
+		AtRound(round uint64) (*state.State, error)
 	}
 
 	TransactionExecutor interface {
@@ -82,6 +86,15 @@ type (
 		Execute(order *types.TransactionOrder) (*types.TransactionRecord, error)
 	}
 
+	// BatchExecutor is implemented by the transaction systems which can execute multiple transaction orders at once.
+	BatchExecutor interface {
+		// ExecuteBatch executes the transaction orders in the given order. The results must be the same as if
+		// Execute was called for each transaction order, ie the error of the transaction order is returned in
+		// the corresponding index of the errors slice. The execution time of each transaction order is returned
+		// in the corresponding index of the durations slice.
+		ExecuteBatch(orders []*types.TransactionOrder) ([]*types.TransactionRecord, []time.Duration, []error)
+	}
+
 	// StateSummary represents aggregate state hashes of the transaction system.
 	StateSummary struct {
 		rootHash []byte